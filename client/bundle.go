@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/core/chain"
+)
+
+// BundleHex returns the parameter of bundle.sendBundle and bundle.callBundle
+func BundleHex(bd *chain.Bundle) (string, error) {
+	bs, _, err := bin.WriterToBytes(bd)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bs), nil
+}
+
+// SendBundle queues the bundle to the generator by bundle.sendBundle, the client should be connected to the rpc of a generator
+func (c *Client) SendBundle(ctx context.Context, bd *chain.Bundle) (hash.Hash256, error) {
+	param, err := BundleHex(bd)
+	if err != nil {
+		return hash.Hash256{}, err
+	}
+	var v string
+	if err := c.Call(ctx, &v, "bundle.sendBundle", param); err != nil {
+		return hash.Hash256{}, err
+	}
+	return hash.HexToHash(v), nil
+}
+
+// BundleCallResult is the result of bundle.callBundle, FailIndex and Error are set when a transaction fails the bundle
+type BundleCallResult struct {
+	BundleHash string          `json:"bundleHash"`
+	Height     uint32          `json:"height"`
+	FailIndex  *int            `json:"failIndex"`
+	Error      string          `json:"error"`
+	Txs        json.RawMessage `json:"txs"`
+}
+
+// CallBundle simulates the bundle on the next block by bundle.callBundle, 0 timestamp means the current time of the node
+func (c *Client) CallBundle(ctx context.Context, bd *chain.Bundle, Timestamp uint64) (*BundleCallResult, error) {
+	param, err := BundleHex(bd)
+	if err != nil {
+		return nil, err
+	}
+	result := &BundleCallResult{}
+	if err := c.Call(ctx, result, "bundle.callBundle", param, Timestamp); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,165 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+// Client is the json rpc client of the meverse node
+//
+// the view, search and eth methods of the node are provided as typed methods and the others can be called by Call
+type Client struct {
+	tr Transport
+	id int64
+}
+
+// New returns a Client of the transport
+func New(tr Transport) *Client {
+	return &Client{
+		tr: tr,
+	}
+}
+
+// Dial returns a Client of the url, http(s) urls use the http transport and ws(s) urls use the websocket transport
+func Dial(url string) (*Client, error) {
+	switch {
+	case strings.HasPrefix(url, "http://"), strings.HasPrefix(url, "https://"):
+		return New(NewHTTPTransport(url)), nil
+	case strings.HasPrefix(url, "ws://"), strings.HasPrefix(url, "wss://"):
+		tr, err := DialWebsocket(url)
+		if err != nil {
+			return nil, err
+		}
+		return New(tr), nil
+	default:
+		return nil, errors.Wrap(ErrUnknownScheme, url)
+	}
+}
+
+// Close closes the transport
+func (c *Client) Close() error {
+	return c.tr.Close()
+}
+
+// Request is a json rpc request
+type Request struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      int64         `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+// Response is a json rpc response
+type Response struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  json.RawMessage `json:"error"`
+}
+
+// Err returns the error of the response
+func (r *Response) Err() error {
+	if len(r.Error) == 0 || string(r.Error) == "null" {
+		return nil
+	}
+	e := &Error{}
+	if err := json.Unmarshal(r.Error, e); err == nil && len(e.Message) > 0 {
+		return e
+	}
+	var msg string
+	if err := json.Unmarshal(r.Error, &msg); err == nil {
+		return &Error{Message: msg}
+	}
+	return &Error{Message: string(r.Error)}
+}
+
+// Error is the error returned by the node, the node sends a string or an object with the code for the reverted call
+type Error struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// BatchElem is a call of the batch, Result and Error are filled after the batch
+type BatchElem struct {
+	Method string
+	Params []interface{}
+	Result interface{}
+	Error  error
+}
+
+func (c *Client) newRequest(method string, params []interface{}) *Request {
+	if params == nil {
+		params = []interface{}{}
+	}
+	return &Request{
+		JSONRPC: "2.0",
+		ID:      atomic.AddInt64(&c.id, 1),
+		Method:  method,
+		Params:  params,
+	}
+}
+
+// Call calls the method and decodes the result into out
+func (c *Client) Call(ctx context.Context, out interface{}, method string, params ...interface{}) error {
+	res, err := c.tr.RoundTrip(ctx, []*Request{c.newRequest(method, params)})
+	if err != nil {
+		return err
+	}
+	if len(res) != 1 {
+		return errors.WithStack(ErrInvalidResponse)
+	}
+	return decodeResponse(res[0], out)
+}
+
+// BatchCall sends the calls at once, the error of each call is set to its Error
+func (c *Client) BatchCall(ctx context.Context, elems []*BatchElem) error {
+	if len(elems) == 0 {
+		return nil
+	}
+	reqs := make([]*Request, 0, len(elems))
+	for _, e := range elems {
+		reqs = append(reqs, c.newRequest(e.Method, e.Params))
+	}
+	res, err := c.tr.RoundTrip(ctx, reqs)
+	if err != nil {
+		return err
+	}
+	resMap := map[string]*Response{}
+	for _, r := range res {
+		resMap[string(bytes.Trim(r.ID, `"`))] = r
+	}
+	for i, e := range elems {
+		r, has := resMap[strconv.FormatInt(reqs[i].ID, 10)]
+		if !has {
+			e.Error = errors.WithStack(ErrMissingResponse)
+			continue
+		}
+		e.Error = decodeResponse(r, e.Result)
+	}
+	return nil
+}
+
+func decodeResponse(r *Response, out interface{}) error {
+	if err := r.Err(); err != nil {
+		return err
+	}
+	if out == nil || len(r.Result) == 0 || string(r.Result) == "null" {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(r.Result))
+	dec.UseNumber()
+	if err := dec.Decode(out); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+package client
+
+import "errors"
+
+// errors
+var (
+	ErrUnknownScheme   = errors.New("unknown url scheme")
+	ErrInvalidResponse = errors.New("invalid response")
+	ErrMissingResponse = errors.New("missing response")
+	ErrNotFound        = errors.New("not found")
+	ErrClosedTransport = errors.New("closed transport")
+	ErrInvalidArgument = errors.New("invalid argument")
+)
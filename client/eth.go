@@ -0,0 +1,256 @@
+package client
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"strings"
+
+	etypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/pkg/errors"
+)
+
+// the eth methods are the ethereum compatible methods of the metamask relay, the blocks and the transactions are ethereum formatted maps
+
+func hexUint(v uint64) string {
+	return "0x" + new(big.Int).SetUint64(v).Text(16)
+}
+
+// NetVersion returns net_version
+func (c *Client) NetVersion(ctx context.Context) (string, error) {
+	var v string
+	if err := c.Call(ctx, &v, "net_version"); err != nil {
+		return "", err
+	}
+	return v, nil
+}
+
+// Web3ClientVersion returns web3_clientVersion
+func (c *Client) Web3ClientVersion(ctx context.Context) (string, error) {
+	var v string
+	if err := c.Call(ctx, &v, "web3_clientVersion"); err != nil {
+		return "", err
+	}
+	return v, nil
+}
+
+// EthClientVersion returns eth_clientVersion
+func (c *Client) EthClientVersion(ctx context.Context) (string, error) {
+	var v string
+	if err := c.Call(ctx, &v, "eth_clientVersion"); err != nil {
+		return "", err
+	}
+	return v, nil
+}
+
+// EthSymbol returns eth_symbol which is the symbol of the main token
+func (c *Client) EthSymbol(ctx context.Context) (string, error) {
+	var v string
+	if err := c.Call(ctx, &v, "eth_symbol"); err != nil {
+		return "", err
+	}
+	return v, nil
+}
+
+// EthChainID returns eth_chainId
+func (c *Client) EthChainID(ctx context.Context) (*big.Int, error) {
+	return c.callHexBig(ctx, "eth_chainId")
+}
+
+// EthBlockNumber returns eth_blockNumber
+func (c *Client) EthBlockNumber(ctx context.Context) (uint64, error) {
+	return c.callHexUint64(ctx, "eth_blockNumber")
+}
+
+// EthGasPrice returns eth_gasPrice
+func (c *Client) EthGasPrice(ctx context.Context) (*big.Int, error) {
+	return c.callHexBig(ctx, "eth_gasPrice")
+}
+
+// EthFeeHistory returns eth_feeHistory
+func (c *Client) EthFeeHistory(ctx context.Context, blockCount uint64, newest string) (map[string]interface{}, error) {
+	var v map[string]interface{}
+	if err := c.Call(ctx, &v, "eth_feeHistory", hexUint(blockCount), newest, []interface{}{}); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// EthBlockByNumber returns eth_getBlockByNumber
+func (c *Client) EthBlockByNumber(ctx context.Context, height uint64, full bool) (map[string]interface{}, error) {
+	var v map[string]interface{}
+	if err := c.Call(ctx, &v, "eth_getBlockByNumber", hexUint(height), full); err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, errors.WithStack(ErrNotFound)
+	}
+	return v, nil
+}
+
+// EthBlockByHash returns eth_getBlockByHash
+func (c *Client) EthBlockByHash(ctx context.Context, h hash.Hash256, full bool) (map[string]interface{}, error) {
+	var v map[string]interface{}
+	if err := c.Call(ctx, &v, "eth_getBlockByHash", h.String(), full); err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, errors.WithStack(ErrNotFound)
+	}
+	return v, nil
+}
+
+// EthBlockTransactionCount returns eth_getBlockTransactionCountByNumber
+func (c *Client) EthBlockTransactionCount(ctx context.Context, height uint64) (uint64, error) {
+	return c.callHexUint64(ctx, "eth_getBlockTransactionCountByNumber", hexUint(height))
+}
+
+// EthBalance returns eth_getBalance which is the balance of the main token
+func (c *Client) EthBalance(ctx context.Context, addr common.Address) (*big.Int, error) {
+	return c.callHexBig(ctx, "eth_getBalance", addr.String(), "latest")
+}
+
+// EthTransactionCount returns eth_getTransactionCount which is the next sequence of the address
+func (c *Client) EthTransactionCount(ctx context.Context, addr common.Address) (uint64, error) {
+	return c.callHexUint64(ctx, "eth_getTransactionCount", addr.String(), "latest")
+}
+
+// EthCode returns eth_getCode
+func (c *Client) EthCode(ctx context.Context, addr common.Address) ([]byte, error) {
+	var v string
+	if err := c.Call(ctx, &v, "eth_getCode", addr.String(), "latest"); err != nil {
+		return nil, err
+	}
+	return decodeHex(v)
+}
+
+// CallMsg is the call of eth_call and eth_estimateGas
+type CallMsg struct {
+	From  common.Address
+	To    common.Address
+	Data  []byte
+	Value *big.Int
+}
+
+func (msg *CallMsg) toArg() map[string]interface{} {
+	arg := map[string]interface{}{
+		"from": msg.From.String(),
+		"to":   msg.To.String(),
+		"data": "0x" + hex.EncodeToString(msg.Data),
+	}
+	if msg.Value != nil {
+		arg["value"] = "0x" + msg.Value.Text(16)
+	}
+	return arg
+}
+
+// EthCall returns eth_call
+func (c *Client) EthCall(ctx context.Context, msg *CallMsg) ([]byte, error) {
+	var v string
+	if err := c.Call(ctx, &v, "eth_call", msg.toArg(), "latest"); err != nil {
+		return nil, err
+	}
+	return decodeHex(v)
+}
+
+// EthEstimateGas returns eth_estimateGas
+func (c *Client) EthEstimateGas(ctx context.Context, msg *CallMsg) (uint64, error) {
+	return c.callHexUint64(ctx, "eth_estimateGas", msg.toArg())
+}
+
+// EthSendRawTransaction sends eth_sendRawTransaction with the signed ethereum transaction
+func (c *Client) EthSendRawTransaction(ctx context.Context, etx *etypes.Transaction) (hash.Hash256, error) {
+	bs, err := etx.MarshalBinary()
+	if err != nil {
+		return hash.Hash256{}, errors.WithStack(err)
+	}
+	var v hash.Hash256
+	if err := c.Call(ctx, &v, "eth_sendRawTransaction", "0x"+hex.EncodeToString(bs)); err != nil {
+		return hash.Hash256{}, err
+	}
+	return v, nil
+}
+
+// EthTransactionByHash returns eth_getTransactionByHash
+func (c *Client) EthTransactionByHash(ctx context.Context, h hash.Hash256) (map[string]interface{}, error) {
+	var v map[string]interface{}
+	if err := c.Call(ctx, &v, "eth_getTransactionByHash", h.String()); err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, errors.WithStack(ErrNotFound)
+	}
+	return v, nil
+}
+
+// EthTransactionByBlockNumberAndIndex returns eth_getTransactionByBlockNumberAndIndex
+func (c *Client) EthTransactionByBlockNumberAndIndex(ctx context.Context, height uint64, index uint64) (map[string]interface{}, error) {
+	var v map[string]interface{}
+	if err := c.Call(ctx, &v, "eth_getTransactionByBlockNumberAndIndex", hexUint(height), hexUint(index)); err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, errors.WithStack(ErrNotFound)
+	}
+	return v, nil
+}
+
+// EthTransactionReceipt returns eth_getTransactionReceipt
+func (c *Client) EthTransactionReceipt(ctx context.Context, h hash.Hash256) (map[string]interface{}, error) {
+	var v map[string]interface{}
+	if err := c.Call(ctx, &v, "eth_getTransactionReceipt", h.String()); err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, errors.WithStack(ErrNotFound)
+	}
+	return v, nil
+}
+
+// EthLogs returns eth_getLogs of the filter of bloomservice.ToFilter
+func (c *Client) EthLogs(ctx context.Context, filter map[string]interface{}) ([]*etypes.Log, error) {
+	var v []*etypes.Log
+	if err := c.Call(ctx, &v, "eth_getLogs", filter); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// TraceCall returns debug_traceCall
+func (c *Client) TraceCall(ctx context.Context, msg *CallMsg, config map[string]interface{}) (json.RawMessage, error) {
+	if config == nil {
+		config = map[string]interface{}{}
+	}
+	var v json.RawMessage
+	if err := c.Call(ctx, &v, "debug_traceCall", msg.toArg(), "latest", config); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// TraceTransaction returns debug_traceTransaction
+func (c *Client) TraceTransaction(ctx context.Context, h hash.Hash256, config map[string]interface{}) (json.RawMessage, error) {
+	if config == nil {
+		config = map[string]interface{}{}
+	}
+	var v json.RawMessage
+	if err := c.Call(ctx, &v, "debug_traceTransaction", h.String(), config); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func decodeHex(s string) ([]byte, error) {
+	s = strings.TrimPrefix(s, "0x")
+	if len(s)%2 == 1 {
+		s = "0" + s
+	}
+	bs, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return bs, nil
+}
@@ -0,0 +1,41 @@
+package client
+
+import (
+	"context"
+	"encoding/hex"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/common/threshold"
+	"github.com/meverselabs/meverse/core/chain"
+	"github.com/meverselabs/meverse/core/types"
+)
+
+// FairOrderKey returns the key to seal the transactions by fair.publicKey
+func (c *Client) FairOrderKey(ctx context.Context) (*threshold.PublicKey, error) {
+	var v string
+	if err := c.Call(ctx, &v, "fair.publicKey"); err != nil {
+		return nil, err
+	}
+	return threshold.ParsePublicKey(v)
+}
+
+// SendSealedTx seals the signed transaction by the key and queues it by fair.sendSealedTx, the client should be connected to the rpc of a generator
+//
+// the transaction is included at the front of the block after the block which commits its ordering
+func (c *Client) SendSealedTx(ctx context.Context, pk *threshold.PublicKey, tx *types.Transaction, sig common.Signature) (hash.Hash256, error) {
+	ct, err := chain.SealTransaction(pk, tx, sig)
+	if err != nil {
+		return hash.Hash256{}, err
+	}
+	bs, _, err := bin.WriterToBytes(ct)
+	if err != nil {
+		return hash.Hash256{}, err
+	}
+	var v string
+	if err := c.Call(ctx, &v, "fair.sendSealedTx", hex.EncodeToString(bs)); err != nil {
+		return hash.Hash256{}, err
+	}
+	return hash.HexToHash(v), nil
+}
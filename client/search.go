@@ -0,0 +1,198 @@
+package client
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/meverselabs/meverse/service/txsearch/itxsearch"
+)
+
+// SearchVersion returns search.version
+func (c *Client) SearchVersion(ctx context.Context) (string, error) {
+	var v string
+	if err := c.Call(ctx, &v, "search.version"); err != nil {
+		return "", err
+	}
+	return v, nil
+}
+
+// SearchBlocks returns search.blocks which is the page of the latest blocks
+func (c *Client) SearchBlocks(ctx context.Context, index int) ([]*itxsearch.BlockInfo, error) {
+	var v []*itxsearch.BlockInfo
+	if err := c.Call(ctx, &v, "search.blocks", index); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// SearchBlock is the result of search.block
+type SearchBlock struct {
+	Header    types.Header
+	BlockHash string
+	Body      struct {
+		Transactions          []*types.Transaction
+		TransactionSignatures []common.Signature
+		BlockSignatures       []common.Signature
+		Events                []map[string]interface{}
+	}
+}
+
+// SearchBlock returns search.block of the height
+func (c *Client) SearchBlock(ctx context.Context, height uint32) (*SearchBlock, error) {
+	v := &SearchBlock{}
+	if err := c.Call(ctx, v, "search.block", height); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// SearchBlockByHash returns search.block of the hash
+func (c *Client) SearchBlockByHash(ctx context.Context, h hash.Hash256) (*SearchBlock, error) {
+	v := &SearchBlock{}
+	if err := c.Call(ctx, v, "search.block", h.String()); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// TxSize returns search.txSize
+func (c *Client) TxSize(ctx context.Context) (uint64, error) {
+	var v uint64
+	if err := c.Call(ctx, &v, "search.txSize"); err != nil {
+		return 0, err
+	}
+	return v, nil
+}
+
+// Txs returns search.txs which is the page of the latest transactions
+func (c *Client) Txs(ctx context.Context, index int, size int) ([]itxsearch.TxList, error) {
+	var v []itxsearch.TxList
+	if err := c.Call(ctx, &v, "search.txs", index, size); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Tx returns search.tx of the height and the index
+func (c *Client) Tx(ctx context.Context, height uint32, index uint16) (map[string]interface{}, error) {
+	var v map[string]interface{}
+	if err := c.Call(ctx, &v, "search.tx", height, index); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// TxByID returns search.tx of the transaction id or the transaction hash
+func (c *Client) TxByID(ctx context.Context, id string) (map[string]interface{}, error) {
+	var v map[string]interface{}
+	if err := c.Call(ctx, &v, "search.tx", id); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// AddressSize returns search.addressSize
+func (c *Client) AddressSize(ctx context.Context, addr common.Address) (uint64, error) {
+	var v uint64
+	if err := c.Call(ctx, &v, "search.addressSize", addr.String()); err != nil {
+		return 0, err
+	}
+	return v, nil
+}
+
+// AddressTxs returns search.address which is the page of the transactions of the address
+func (c *Client) AddressTxs(ctx context.Context, addr common.Address, index int, size int) ([]itxsearch.TxList, error) {
+	var v []itxsearch.TxList
+	if err := c.Call(ctx, &v, "search.address", addr.String(), index, size); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// TokenSize returns search.tokenSize
+func (c *Client) TokenSize(ctx context.Context, token common.Address) (uint64, error) {
+	var v uint64
+	if err := c.Call(ctx, &v, "search.tokenSize", token.String()); err != nil {
+		return 0, err
+	}
+	return v, nil
+}
+
+// TokenTxs returns search.token which is the page of the transactions of the token
+func (c *Client) TokenTxs(ctx context.Context, token common.Address, index int, size int) ([]itxsearch.TxList, error) {
+	var v []itxsearch.TxList
+	if err := c.Call(ctx, &v, "search.token", token.String(), index, size); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// TransferTxs returns search.transferList which is the page of the transfers of the token by the address
+func (c *Client) TransferTxs(ctx context.Context, token common.Address, addr common.Address, index int, size int) ([]itxsearch.TxList, error) {
+	var v []itxsearch.TxList
+	if err := c.Call(ctx, &v, "search.transferList", token.String(), addr.String(), index, size); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Reward returns search.reward which is the total reward of the address
+func (c *Client) Reward(ctx context.Context, contract common.Address, addr common.Address) (*amount.Amount, error) {
+	var v *amount.Amount
+	if err := c.Call(ctx, &v, "search.reward", contract.String(), addr.String()); err != nil {
+		return nil, err
+	}
+	if v == nil {
+		v = amount.NewAmount(0, 0)
+	}
+	return v, nil
+}
+
+// DailyReward returns search.dailyReward which is the rewards of the address by the day
+func (c *Client) DailyReward(ctx context.Context, contract common.Address, addr common.Address, index int) (map[string]*amount.Amount, error) {
+	var v map[string]*amount.Amount
+	if err := c.Call(ctx, &v, "search.dailyReward", contract.String(), addr.String(), index); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// TokenOuts returns search.tokenOuts from the height
+func (c *Client) TokenOuts(ctx context.Context, height uint32) ([]map[string]interface{}, error) {
+	var v []map[string]interface{}
+	if err := c.Call(ctx, &v, "search.tokenOuts", height); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// TokenLeaves returns search.tokenLeaves from the height
+func (c *Client) TokenLeaves(ctx context.Context, height uint32) ([]map[string]interface{}, error) {
+	var v []map[string]interface{}
+	if err := c.Call(ctx, &v, "search.tokenLeaves", height); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// BridgeTxs returns search.bridgeTxs of the bridge in [from, to)
+func (c *Client) BridgeTxs(ctx context.Context, bridge common.Address, from uint32, to uint32) ([]map[string]interface{}, error) {
+	var v []map[string]interface{}
+	if err := c.Call(ctx, &v, "search.bridgeTxs", bridge.String(), from, strconv.FormatUint(uint64(to), 10)); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Contracts returns search.contracts which is the type names of the contracts
+func (c *Client) Contracts(ctx context.Context) (map[common.Address]string, error) {
+	var v map[common.Address]string
+	if err := c.Call(ctx, &v, "search.contracts"); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/meverselabs/meverse/core/types"
+)
+
+// Subscription delivers the new blocks until it is unsubscribed
+//
+// the node does not push the blocks, so the subscription polls the height over the transport
+// and a websocket transport keeps one connection for it
+type Subscription struct {
+	cancel context.CancelFunc
+	errCh  chan error
+	once   sync.Once
+}
+
+// Err returns the channel of the error which stops the subscription, it is closed by Unsubscribe
+func (sub *Subscription) Err() <-chan error {
+	return sub.errCh
+}
+
+// Unsubscribe stops the subscription
+func (sub *Subscription) Unsubscribe() {
+	sub.once.Do(func() {
+		sub.cancel()
+	})
+}
+
+// SubscribeBlocks sends the blocks from the height to the channel in order, polling the node at the interval
+func (c *Client) SubscribeBlocks(ctx context.Context, from uint32, ch chan<- *types.Block, interval time.Duration) *Subscription {
+	ctx, cancel := context.WithCancel(ctx)
+	sub := &Subscription{
+		cancel: cancel,
+		errCh:  make(chan error, 1),
+	}
+	go func() {
+		defer close(sub.errCh)
+
+		next := from
+		timer := time.NewTimer(0)
+		defer timer.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+			height, err := c.BlockNumber(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					sub.errCh <- err
+				}
+				return
+			}
+			for ; next <= height; next++ {
+				b, err := c.BlockByNumber(ctx, next)
+				if err != nil {
+					if ctx.Err() == nil {
+						sub.errCh <- err
+					}
+					return
+				}
+				select {
+				case ch <- b:
+				case <-ctx.Done():
+					return
+				}
+			}
+			timer.Reset(interval)
+		}
+	}()
+	return sub
+}
@@ -0,0 +1,223 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/meverselabs/meverse/client"
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/meverselabs/meverse/service/apiserver"
+
+	. "github.com/meverselabs/meverse/tests/lib"
+)
+
+func newTestChain(t *testing.T) (*TestBlockChain, *common.Address) {
+	userKeys, err := GetSingers(ChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice := userKeys[0].PublicKey().Address()
+
+	var mevAddress *common.Address
+	initialize := func(ctx *types.Context, classMap map[string]uint64) error {
+		mevAddress, err = MevInitialize(ctx, classMap, alice, map[common.Address]*amount.Amount{
+			alice: amount.NewAmount(1000000, 0),
+		})
+		return err
+	}
+	tb := NewTestBlockChain(filepath.Join(t.TempDir(), "chain"), true, ChainID, Version, alice, initialize, DefaultInitContextInfo)
+	return tb, mevAddress
+}
+
+func TestClient(t *testing.T) {
+	tb, mev := newTestChain(t)
+	defer tb.Close()
+
+	userKeys, err := GetSingers(ChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aliceKey := userKeys[0]
+	alice, bob := aliceKey.PublicKey().Address(), userKeys[1].PublicKey().Address()
+
+	ctx := context.Background()
+	c := client.New(client.NewHandlerTransport(tb.HandleJRPC))
+	defer c.Close()
+
+	if ChainID, err := c.ChainID(ctx); err != nil {
+		t.Fatal(err)
+	} else if ChainID.Cmp(tb.ChainID) != 0 {
+		t.Fatalf("chain id %v, want %v", ChainID, tb.ChainID)
+	}
+	if addr, err := c.MainToken(ctx); err != nil {
+		t.Fatal(err)
+	} else if addr != *mev {
+		t.Fatalf("main token %v, want %v", addr, mev)
+	}
+	if am, err := c.Balance(ctx, alice); err != nil {
+		t.Fatal(err)
+	} else if am.Cmp(amount.NewAmount(1000000, 0).Int) != 0 {
+		t.Fatalf("balance %v, want 1000000", am)
+	}
+
+	sender, err := c.NewSender(ctx, aliceKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h1, err := sender.Send(ctx, *mev, "Transfer", bob, amount.NewAmount(3, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h2, err := sender.Send(ctx, *mev, "Transfer", bob, amount.NewAmount(4, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tb.MustAddBlock(nil)
+
+	b, err := c.BlockByNumber(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.Body.Transactions) != 2 || b.Body.Transactions[0].HashSig() != h1 || b.Body.Transactions[1].HashSig() != h2 {
+		t.Fatal("sent transactions are not in the block")
+	}
+	if seq, err := c.Seq(ctx, alice); err != nil {
+		t.Fatal(err)
+	} else if seq != 2 {
+		t.Fatalf("seq %v, want 2", seq)
+	}
+	if out, err := c.ViewCall(ctx, *mev, "BalanceOf", []interface{}{bob.String()}, common.Address{}); err != nil {
+		t.Fatal(err)
+	} else if len(out) != 1 {
+		t.Fatalf("invalid call result %v", out)
+	}
+
+	var height uint32
+	bal := &amount.Amount{}
+	elems := []*client.BatchElem{
+		{Method: "view.blockNumber", Result: &height},
+		{Method: "view.getBalance", Params: []interface{}{bob.String()}, Result: bal},
+		{Method: "view.unknown"},
+	}
+	if err := c.BatchCall(ctx, elems); err != nil {
+		t.Fatal(err)
+	}
+	if elems[0].Error != nil || height != 1 {
+		t.Fatalf("batch height %v %v", height, elems[0].Error)
+	}
+	if elems[1].Error != nil {
+		t.Fatal(elems[1].Error)
+	}
+	if elems[2].Error == nil {
+		t.Fatal("unknown method is accepted")
+	}
+
+	// a new sender loads the sequence from the node
+	sender2, err := c.NewSender(ctx, aliceKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sender2.Send(ctx, *mev, "Transfer", bob, amount.NewAmount(1, 0)); err != nil {
+		t.Fatal(err)
+	}
+	tb.MustAddBlock(nil)
+	if seq, err := c.Seq(ctx, alice); err != nil {
+		t.Fatal(err)
+	} else if seq != 3 {
+		t.Fatalf("seq %v, want 3", seq)
+	}
+
+	ch := make(chan *types.Block)
+	sub := c.SubscribeBlocks(ctx, 1, ch, 10*time.Millisecond)
+	defer sub.Unsubscribe()
+	for _, want := range []uint32{1, 2} {
+		select {
+		case b := <-ch:
+			if b.Header.Height != want {
+				t.Fatalf("subscribed height %v, want %v", b.Header.Height, want)
+			}
+		case err := <-sub.Err():
+			t.Fatal(err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("subscription timeout")
+		}
+	}
+	tb.MustAddBlock(nil)
+	select {
+	case b := <-ch:
+		if b.Header.Height != 3 {
+			t.Fatalf("subscribed height %v, want 3", b.Header.Height)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription timeout")
+	}
+}
+
+func TestHTTPTransport(t *testing.T) {
+	tb, _ := newTestChain(t)
+	defer tb.Close()
+
+	// the server decodes a request or a batch like the apiserver
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		dec := func(v interface{}) error {
+			d := json.NewDecoder(bytes.NewReader(body))
+			d.UseNumber()
+			return d.Decode(v)
+		}
+		var reqs []*apiserver.JRPCRequest
+		if err := dec(&reqs); err != nil {
+			req := &apiserver.JRPCRequest{}
+			if err := dec(req); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			json.NewEncoder(w).Encode(tb.HandleJRPC(req))
+			return
+		}
+		ress := []interface{}{}
+		for _, req := range reqs {
+			ress = append(ress, tb.HandleJRPC(req))
+		}
+		json.NewEncoder(w).Encode(ress)
+	}))
+	defer srv.Close()
+
+	c, err := client.Dial(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	ctx := context.Background()
+	if height, err := c.BlockNumber(ctx); err != nil || height != 0 {
+		t.Fatalf("height %v %v", height, err)
+	}
+	var ChainID string
+	var height uint32
+	elems := []*client.BatchElem{
+		{Method: "view.chainId", Result: &ChainID},
+		{Method: "view.blockNumber", Result: &height},
+	}
+	if err := c.BatchCall(ctx, elems); err != nil {
+		t.Fatal(err)
+	}
+	if elems[0].Error != nil || elems[1].Error != nil || len(ChainID) == 0 {
+		t.Fatalf("batch %v %v %v", ChainID, elems[0].Error, elems[1].Error)
+	}
+	if _, err := c.BlockByNumber(ctx, 100); err == nil {
+		t.Fatal("unknown block is returned")
+	}
+	if _, err := client.Dial("ftp://localhost"); err == nil {
+		t.Fatal("unknown scheme is accepted")
+	}
+}
@@ -0,0 +1,218 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/meverselabs/meverse/service/apiserver"
+	"github.com/pkg/errors"
+)
+
+// Transport sends the requests and returns the responses, more than one request is sent as a batch
+type Transport interface {
+	RoundTrip(ctx context.Context, reqs []*Request) ([]*Response, error)
+	Close() error
+}
+
+// encodeRequests encodes a request as an object and requests as a batch array
+func encodeRequests(reqs []*Request) ([]byte, error) {
+	var v interface{} = reqs
+	if len(reqs) == 1 {
+		v = reqs[0]
+	}
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return bs, nil
+}
+
+// decodeResponses decodes a response object or a batch array
+func decodeResponses(bs []byte) ([]*Response, error) {
+	bs = bytes.TrimSpace(bs)
+	if len(bs) == 0 {
+		return nil, errors.WithStack(ErrInvalidResponse)
+	}
+	if bs[0] == '[' {
+		var res []*Response
+		if err := json.Unmarshal(bs, &res); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return res, nil
+	}
+	r := &Response{}
+	if err := json.Unmarshal(bs, r); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return []*Response{r}, nil
+}
+
+// HTTPTransport posts the requests to the url
+type HTTPTransport struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPTransport returns a HTTPTransport
+func NewHTTPTransport(url string) *HTTPTransport {
+	return &HTTPTransport{
+		url:    url,
+		client: &http.Client{},
+	}
+}
+
+// RoundTrip implements Transport
+func (t *HTTPTransport) RoundTrip(ctx context.Context, reqs []*Request) ([]*Response, error) {
+	bs, err := encodeRequests(reqs)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(bs))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := t.client.Do(req)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.Wrapf(ErrInvalidResponse, "%v %v", res.Status, string(body))
+	}
+	return decodeResponses(body)
+}
+
+// Close implements Transport
+func (t *HTTPTransport) Close() error {
+	t.client.CloseIdleConnections()
+	return nil
+}
+
+// WebsocketTransport sends the requests through a websocket connection
+//
+// the node answers the messages of a connection in order, so a request waits the response of the previous one
+type WebsocketTransport struct {
+	sync.Mutex
+	conn     *websocket.Conn
+	isClosed bool
+}
+
+// DialWebsocket connects to the websocket url of the node
+func DialWebsocket(url string) (*WebsocketTransport, error) {
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &WebsocketTransport{
+		conn: conn,
+	}, nil
+}
+
+// RoundTrip implements Transport
+func (t *WebsocketTransport) RoundTrip(ctx context.Context, reqs []*Request) ([]*Response, error) {
+	bs, err := encodeRequests(reqs)
+	if err != nil {
+		return nil, err
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	if t.isClosed {
+		return nil, errors.WithStack(ErrClosedTransport)
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Time{}
+	}
+	t.conn.SetWriteDeadline(deadline)
+	t.conn.SetReadDeadline(deadline)
+	if err := t.conn.WriteMessage(websocket.TextMessage, bs); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	_, data, err := t.conn.ReadMessage()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return decodeResponses(data)
+}
+
+// Close implements Transport
+func (t *WebsocketTransport) Close() error {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.isClosed {
+		return nil
+	}
+	t.isClosed = true
+	return t.conn.Close()
+}
+
+// HandlerTransport calls the json rpc handler in the process like APIServer.HandleJRPC
+//
+// the params and the results are encoded to json as the http transport does
+type HandlerTransport struct {
+	h func(req *apiserver.JRPCRequest) interface{}
+}
+
+// NewHandlerTransport returns a HandlerTransport
+func NewHandlerTransport(h func(req *apiserver.JRPCRequest) interface{}) *HandlerTransport {
+	return &HandlerTransport{
+		h: h,
+	}
+}
+
+// RoundTrip implements Transport
+func (t *HandlerTransport) RoundTrip(ctx context.Context, reqs []*Request) ([]*Response, error) {
+	ress := []*Response{}
+	for _, req := range reqs {
+		bs, err := json.Marshal(req.Params)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		dec := json.NewDecoder(bytes.NewReader(bs))
+		dec.UseNumber()
+		var params []interface{}
+		if err := dec.Decode(&params); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		res := t.h(&apiserver.JRPCRequest{
+			JSONRPC: req.JSONRPC,
+			ID:      json.Number(strconv.FormatInt(req.ID, 10)),
+			Method:  req.Method,
+			Params:  params,
+		})
+		if res == nil {
+			continue
+		}
+		bs, err = json.Marshal(res)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		r := &Response{}
+		if err := json.Unmarshal(bs, r); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		ress = append(ress, r)
+	}
+	return ress, nil
+}
+
+// Close implements Transport
+func (t *HandlerTransport) Close() error {
+	return nil
+}
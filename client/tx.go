@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/common/key"
+	"github.com/meverselabs/meverse/core/types"
+)
+
+// TxBody returns the body of view.srtx of the transaction
+func TxBody(tx *types.Transaction) string {
+	var body []byte
+	if tx.UseSeq {
+		body = bin.TypeWriteAll(tx.Method, tx.To, tx.Timestamp, tx.Args, tx.Seq)
+	} else {
+		body = bin.TypeWriteAll(tx.Method, tx.To, tx.Timestamp, tx.Args)
+	}
+	return hex.EncodeToString(body)
+}
+
+// SendTx sends the signed transaction by view.srtx, the transaction uses the chain id of the node
+func (c *Client) SendTx(ctx context.Context, tx *types.Transaction, sig common.Signature) (hash.Hash256, error) {
+	return c.SendRawTx(ctx, sig, TxBody(tx))
+}
+
+// Sender builds, signs and sends the transactions of the key
+//
+// the sequence is loaded from the node at first and increased by the sent transactions, ResetSeq reloads it
+type Sender struct {
+	sync.Mutex
+	c             *Client
+	key           key.Key
+	chainID       *big.Int
+	seq           uint64
+	hasSeq        bool
+	lastTimestamp uint64
+}
+
+// NewSender returns a Sender of the key for the chain of the node
+func (c *Client) NewSender(ctx context.Context, k key.Key) (*Sender, error) {
+	ChainID, err := c.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Sender{
+		c:       c,
+		key:     k,
+		chainID: ChainID,
+	}, nil
+}
+
+// Address returns the address of the key
+func (s *Sender) Address() common.Address {
+	return s.key.PublicKey().Address()
+}
+
+// ResetSeq makes the next transaction load the sequence from the node
+func (s *Sender) ResetSeq() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.hasSeq = false
+}
+
+// timestamp returns the current time which is greater than the last one
+func (s *Sender) timestamp() uint64 {
+	t := uint64(time.Now().UnixNano())
+	if t <= s.lastTimestamp {
+		t = s.lastTimestamp + 1
+	}
+	s.lastTimestamp = t
+	return t
+}
+
+// NewTx returns the transaction calling the method of the contract with the next sequence
+func (s *Sender) NewTx(ctx context.Context, to common.Address, method string, args ...interface{}) (*types.Transaction, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.newTx(ctx, to, method, args)
+}
+
+func (s *Sender) newTx(ctx context.Context, to common.Address, method string, args []interface{}) (*types.Transaction, error) {
+	if !s.hasSeq {
+		seq, err := s.c.Seq(ctx, s.Address())
+		if err != nil {
+			return nil, err
+		}
+		s.seq = seq
+		s.hasSeq = true
+	}
+	tx := &types.Transaction{
+		ChainID:   s.chainID,
+		Timestamp: s.timestamp(),
+		Seq:       s.seq,
+		To:        to,
+		Method:    method,
+		Args:      bin.TypeWriteAll(args...),
+		UseSeq:    true,
+	}
+	s.seq++
+	return tx, nil
+}
+
+// Sign returns the signature of the transaction
+func (s *Sender) Sign(tx *types.Transaction) (common.Signature, error) {
+	return s.key.Sign(tx.HashSig())
+}
+
+// Send builds, signs and sends the transaction calling the method of the contract
+//
+// the sequence is reloaded at the next transaction when the node rejects it
+func (s *Sender) Send(ctx context.Context, to common.Address, method string, args ...interface{}) (hash.Hash256, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	tx, err := s.newTx(ctx, to, method, args)
+	if err != nil {
+		return hash.Hash256{}, err
+	}
+	sig, err := s.Sign(tx)
+	if err != nil {
+		return hash.Hash256{}, err
+	}
+	h, err := s.c.SendTx(ctx, tx, sig)
+	if err != nil {
+		s.hasSeq = false
+		return hash.Hash256{}, err
+	}
+	return h, nil
+}
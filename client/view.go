@@ -0,0 +1,382 @@
+package client
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/pkg/errors"
+)
+
+func parseHexUint64(s string) (uint64, error) {
+	v, err := strconv.ParseUint(strings.TrimPrefix(s, "0x"), 16, 64)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return v, nil
+}
+
+func parseHexBig(s string) (*big.Int, error) {
+	v, ok := new(big.Int).SetString(strings.TrimPrefix(s, "0x"), 16)
+	if !ok {
+		return nil, errors.Wrap(ErrInvalidResponse, s)
+	}
+	return v, nil
+}
+
+func (c *Client) callHexUint64(ctx context.Context, method string, params ...interface{}) (uint64, error) {
+	var s string
+	if err := c.Call(ctx, &s, method, params...); err != nil {
+		return 0, err
+	}
+	return parseHexUint64(s)
+}
+
+func (c *Client) callHexBig(ctx context.Context, method string, params ...interface{}) (*big.Int, error) {
+	var s string
+	if err := c.Call(ctx, &s, method, params...); err != nil {
+		return nil, err
+	}
+	return parseHexBig(s)
+}
+
+// Version returns view.version which is the chain id
+func (c *Client) Version(ctx context.Context) (*big.Int, error) {
+	return c.callHexBig(ctx, "view.version")
+}
+
+// ChainID returns view.chainId
+func (c *Client) ChainID(ctx context.Context) (*big.Int, error) {
+	return c.callHexBig(ctx, "view.chainId")
+}
+
+// ClientVersion returns view.clientVersion
+func (c *Client) ClientVersion(ctx context.Context) (string, error) {
+	var v string
+	if err := c.Call(ctx, &v, "view.clientVersion"); err != nil {
+		return "", err
+	}
+	return v, nil
+}
+
+// MainToken returns view.maintoken
+func (c *Client) MainToken(ctx context.Context) (common.Address, error) {
+	var v string
+	if err := c.Call(ctx, &v, "view.maintoken"); err != nil {
+		return common.Address{}, err
+	}
+	return common.HexToAddress(v), nil
+}
+
+// Generators returns view.generators
+func (c *Client) Generators(ctx context.Context) ([]common.Address, error) {
+	var v []common.Address
+	if err := c.Call(ctx, &v, "view.generators"); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// ActiveGenerators returns view.activeGenerators
+func (c *Client) ActiveGenerators(ctx context.Context) ([]common.Address, error) {
+	var v []common.Address
+	if err := c.Call(ctx, &v, "view.activeGenerators"); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// BlockNumber returns view.blockNumber
+func (c *Client) BlockNumber(ctx context.Context) (uint32, error) {
+	var v uint32
+	if err := c.Call(ctx, &v, "view.blockNumber"); err != nil {
+		return 0, err
+	}
+	return v, nil
+}
+
+func (c *Client) callBlock(ctx context.Context, method string, param interface{}) (*types.Block, error) {
+	var b *types.Block
+	if err := c.Call(ctx, &b, method, param); err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, errors.WithStack(ErrNotFound)
+	}
+	return b, nil
+}
+
+// BlockByNumber returns view.getBlockByNumber
+func (c *Client) BlockByNumber(ctx context.Context, height uint32) (*types.Block, error) {
+	return c.callBlock(ctx, "view.getBlockByNumber", height)
+}
+
+// LatestBlock returns view.getBlockByNumber of the latest
+func (c *Client) LatestBlock(ctx context.Context) (*types.Block, error) {
+	return c.callBlock(ctx, "view.getBlockByNumber", "latest")
+}
+
+// BlockByHash returns view.getBlockByHash
+func (c *Client) BlockByHash(ctx context.Context, h hash.Hash256) (*types.Block, error) {
+	return c.callBlock(ctx, "view.getBlockByHash", h.String())
+}
+
+// Balance returns view.getBalance which is the balance of the main token
+func (c *Client) Balance(ctx context.Context, addr common.Address) (*amount.Amount, error) {
+	var v string
+	if err := c.Call(ctx, &v, "view.getBalance", addr.String()); err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(v, "0x") {
+		bi, err := parseHexBig(v)
+		if err != nil {
+			return nil, err
+		}
+		return amount.NewAmountFromBytes(bi.Bytes()), nil
+	}
+	am, err := amount.ParseAmount(v)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return am, nil
+}
+
+// GasPrice returns view.gasPrice
+func (c *Client) GasPrice(ctx context.Context) (*big.Int, error) {
+	return c.callHexBig(ctx, "view.gasPrice")
+}
+
+// EstimateGas returns view.estimateGas
+func (c *Client) EstimateGas(ctx context.Context) (uint64, error) {
+	return c.callHexUint64(ctx, "view.estimateGas")
+}
+
+// Code returns view.getCode
+func (c *Client) Code(ctx context.Context, addr common.Address) ([]byte, error) {
+	var v string
+	if err := c.Call(ctx, &v, "view.getCode", addr.String()); err != nil {
+		return nil, err
+	}
+	bs, err := hex.DecodeString(strings.TrimPrefix(v, "0x"))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return bs, nil
+}
+
+// Seq returns view.seq which is the next sequence of the address
+func (c *Client) Seq(ctx context.Context, addr common.Address) (uint64, error) {
+	return c.callHexUint64(ctx, "view.seq", addr.String())
+}
+
+// TxByHash returns view.getTxByHash, the node returns only the ethereum type transaction
+func (c *Client) TxByHash(ctx context.Context, h hash.Hash256) (*types.Transaction, error) {
+	var tx *types.Transaction
+	if err := c.Call(ctx, &tx, "view.getTxByHash", h.String()); err != nil {
+		return nil, err
+	}
+	if tx == nil {
+		return nil, errors.WithStack(ErrNotFound)
+	}
+	return tx, nil
+}
+
+// IsContract returns view.isContract
+func (c *Client) IsContract(ctx context.Context, addr common.Address) (bool, error) {
+	var v bool
+	if err := c.Call(ctx, &v, "view.isContract", addr.String()); err != nil {
+		return false, err
+	}
+	return v, nil
+}
+
+// ContractABI returns view.contractAbi
+func (c *Client) ContractABI(ctx context.Context, addr common.Address) (json.RawMessage, error) {
+	var v json.RawMessage
+	if err := c.Call(ctx, &v, "view.contractAbi", addr.String()); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// ViewCall returns view.call which executes the method of the contract without a transaction
+//
+// the numbers of the result are json.Number and the amounts are decimal strings
+func (c *Client) ViewCall(ctx context.Context, contract common.Address, method string, params []interface{}, from common.Address) ([]interface{}, error) {
+	if params == nil {
+		params = []interface{}{}
+	}
+	var v []interface{}
+	if err := c.Call(ctx, &v, "view.call", contract.String(), method, params, from.String()); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// MultiCall returns view.multi_call which executes the methods of the contracts at once
+func (c *Client) MultiCall(ctx context.Context, contracts []common.Address, methods []string, paramss [][]interface{}, from common.Address) ([][]interface{}, error) {
+	if len(contracts) != len(methods) || len(contracts) != len(paramss) {
+		return nil, errors.WithStack(ErrInvalidArgument)
+	}
+	conts := []string{}
+	for _, addr := range contracts {
+		conts = append(conts, addr.String())
+	}
+	var v [][]interface{}
+	if err := c.Call(ctx, &v, "view.multi_call", conts, methods, paramss, from.String()); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Search returns view.search which finds the method calls of the contracts in the block range
+func (c *Client) Search(ctx context.Context, contracts []common.Address, methods []string, from uint32, to uint32) ([]map[string]string, error) {
+	if len(contracts) != len(methods) {
+		return nil, errors.WithStack(ErrInvalidArgument)
+	}
+	conts := []string{}
+	for _, addr := range contracts {
+		conts = append(conts, addr.String())
+	}
+	var v []map[string]string
+	if err := c.Call(ctx, &v, "view.search", conts, methods, from, to); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// SearchMap returns view.searchMap which finds the method calls of the contract map in the block range
+func (c *Client) SearchMap(ctx context.Context, searchMap map[common.Address][]string, from uint32, to uint32) ([]map[string]string, error) {
+	sm := map[string][]string{}
+	for addr, methods := range searchMap {
+		sm[addr.String()] = methods
+	}
+	var v []map[string]string
+	if err := c.Call(ctx, &v, "view.searchMap", sm, from, to); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// CalcRewardPower returns view.calcRewardPower of the formulator contract
+func (c *Client) CalcRewardPower(ctx context.Context, contract common.Address) (map[string]interface{}, error) {
+	var v map[string]interface{}
+	if err := c.Call(ctx, &v, "view.calcRewardPower", contract.String()); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// RewardPolicy returns view.rewardPolicy which is the efficiencies of the formulator types
+func (c *Client) RewardPolicy(ctx context.Context, contract common.Address) (map[string]int, error) {
+	var v map[string]int
+	if err := c.Call(ctx, &v, "view.rewardPolicy", contract.String()); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// FormulatorCount returns view.formulatorCount
+func (c *Client) FormulatorCount(ctx context.Context, contract common.Address) (uint32, error) {
+	var v uint32
+	if err := c.Call(ctx, &v, "view.formulatorCount", contract.String()); err != nil {
+		return 0, err
+	}
+	return v, nil
+}
+
+// UnsignedTx is the transaction built by the node, Hash is signed and sent with Body
+type UnsignedTx struct {
+	Hash hash.Hash256
+	Body string
+}
+
+func (c *Client) callUnsignedTx(ctx context.Context, method string, params ...interface{}) (*UnsignedTx, error) {
+	var v []string
+	if err := c.Call(ctx, &v, method, params...); err != nil {
+		return nil, err
+	}
+	if len(v) != 2 {
+		return nil, errors.WithStack(ErrInvalidResponse)
+	}
+	return &UnsignedTx{
+		Hash: hash.HexToHash(v[0]),
+		Body: v[1],
+	}, nil
+}
+
+// RawTx returns view.rtx which builds the Burn, Transfer, TokenIndexIn or TokenLeave transaction of the contract
+func (c *Client) RawTx(ctx context.Context, method string, contract common.Address, args ...string) (*UnsignedTx, error) {
+	params := []interface{}{method, contract.String()}
+	for _, a := range args {
+		params = append(params, a)
+	}
+	return c.callUnsignedTx(ctx, "view.rtx", params...)
+}
+
+// SendRawTx sends view.srtx with the signature of the hash of the unsigned transaction
+func (c *Client) SendRawTx(ctx context.Context, sig common.Signature, body string) (hash.Hash256, error) {
+	var v string
+	if err := c.Call(ctx, &v, "view.srtx", hex.EncodeToString(sig), body); err != nil {
+		return hash.Hash256{}, err
+	}
+	return hash.HexToHash(v), nil
+}
+
+// WasmDeployTx returns view.wasmDeployTx which builds the deploy transaction of the wasm code
+func (c *Client) WasmDeployTx(ctx context.Context, code []byte, initArgs []interface{}, seq uint64) (*UnsignedTx, error) {
+	if initArgs == nil {
+		initArgs = []interface{}{}
+	}
+	return c.callUnsignedTx(ctx, "view.wasmDeployTx", "0x"+hex.EncodeToString(code), initArgs, seq)
+}
+
+// SendWasmDeployTx sends view.sendWasmDeployTx with the signature of the hash of the deploy transaction
+func (c *Client) SendWasmDeployTx(ctx context.Context, sig common.Signature, body string) (hash.Hash256, error) {
+	var v string
+	if err := c.Call(ctx, &v, "view.sendWasmDeployTx", hex.EncodeToString(sig), body); err != nil {
+		return hash.Hash256{}, err
+	}
+	return hash.HexToHash(v), nil
+}
+
+// WasmCallResult is the result of view.wasmCall
+type WasmCallResult struct {
+	Result []interface{} `json:"result"`
+	Gas    uint64        `json:"gas"`
+}
+
+// WasmCall returns view.wasmCall which executes the method of the wasm contract without a transaction
+func (c *Client) WasmCall(ctx context.Context, contract common.Address, method string, params []interface{}, from common.Address) (*WasmCallResult, error) {
+	if params == nil {
+		params = []interface{}{}
+	}
+	v := &WasmCallResult{}
+	if err := c.Call(ctx, v, "view.wasmCall", contract.String(), method, params, from.String()); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// WasmCode is the result of view.wasmCode
+type WasmCode struct {
+	CodeHash string `json:"codeHash"`
+	Size     int    `json:"size"`
+	Master   string `json:"master"`
+}
+
+// WasmCode returns view.wasmCode
+func (c *Client) WasmCode(ctx context.Context, contract common.Address) (*WasmCode, error) {
+	v := &WasmCode{}
+	if err := c.Call(ctx, v, "view.wasmCode", contract.String()); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
@@ -18,9 +18,11 @@ import (
 	"github.com/meverselabs/meverse/contract/external/deployer"
 	"github.com/meverselabs/meverse/contract/external/engin"
 	"github.com/meverselabs/meverse/contract/formulator"
+	"github.com/meverselabs/meverse/contract/forwarder"
 	"github.com/meverselabs/meverse/contract/gateway"
 	"github.com/meverselabs/meverse/contract/nft721"
 	"github.com/meverselabs/meverse/contract/token"
+	"github.com/meverselabs/meverse/contract/wasm"
 	"github.com/meverselabs/meverse/contract/whitelist"
 	"github.com/meverselabs/meverse/core/chain"
 	"github.com/meverselabs/meverse/core/types"
@@ -231,6 +233,16 @@ func RegisterContractClass() map[string]uint64 {
 	registerContractClass(&deployer.DeployerContract{}, "EnginDeployer", ClassMap)
 
 	registerContractClass(&mappfarm.FarmContract{}, "MappFarm", ClassMap)
+	registerContractClass(&wasm.WasmContract{}, "Wasm", ClassMap)
+	registerContractClass(&forwarder.ForwarderContract{}, "Forwarder", ClassMap)
+
+	// the contracts which do not share a state out of the context are executed in parallel
+	types.SetConcurrentContract(&token.TokenContract{}, token.AdditiveData()...)
+	types.SetConcurrentContract(&factory.FactoryContract{})
+	types.SetConcurrentContract(&router.RouterContract{})
+	types.SetConcurrentContract(&trade.UniSwap{})
+	types.SetConcurrentContract(&trade.StableSwap{})
+	types.SetConcurrentContract(&nft721.NFT721Contract{})
 	return ClassMap
 }
 func registerContractClass(cont types.Contract, className string, ClassMap map[string]uint64) {
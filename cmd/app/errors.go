@@ -0,0 +1,14 @@
+package app
+
+import "errors"
+
+// genesis errors
+var (
+	ErrInvalidGenesisChainID   = errors.New("invalid genesis chain id")
+	ErrNoGenesisAdmin          = errors.New("no genesis admin")
+	ErrNoGenesisGenerator      = errors.New("no genesis generator")
+	ErrNoGenesisObserver       = errors.New("no genesis observer")
+	ErrInvalidGenesisContract  = errors.New("invalid genesis contract")
+	ErrInvalidGenesisMainToken = errors.New("invalid genesis main token")
+	ErrUnknownGenesisRef       = errors.New("unknown genesis reference")
+)
@@ -0,0 +1,313 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/contract/bridge"
+	"github.com/meverselabs/meverse/contract/connect/depositpool"
+	"github.com/meverselabs/meverse/contract/connect/farm"
+	"github.com/meverselabs/meverse/contract/connect/imo"
+	"github.com/meverselabs/meverse/contract/connect/mappfarm"
+	"github.com/meverselabs/meverse/contract/connect/pool"
+	"github.com/meverselabs/meverse/contract/exchange/factory"
+	"github.com/meverselabs/meverse/contract/exchange/router"
+	"github.com/meverselabs/meverse/contract/exchange/trade"
+	"github.com/meverselabs/meverse/contract/external/deployer"
+	"github.com/meverselabs/meverse/contract/external/engin"
+	"github.com/meverselabs/meverse/contract/formulator"
+	"github.com/meverselabs/meverse/contract/forwarder"
+	"github.com/meverselabs/meverse/contract/gateway"
+	"github.com/meverselabs/meverse/contract/nft721"
+	"github.com/meverselabs/meverse/contract/token"
+	"github.com/meverselabs/meverse/contract/whitelist"
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/pkg/errors"
+)
+
+// GenesisConfig is the declarative genesis of the chain which is loaded from the json file
+//
+// the argument of the contract can refer the address of the contract deployed before by "@" + name of it
+type GenesisConfig struct {
+	ChainID      uint64
+	Version      uint16
+	Admins       []common.Address
+	Generators   []common.Address
+	ObserverKeys []common.PublicKey
+	Contracts    []*GenesisContract
+	MainToken    string
+	Balances     map[common.Address]*amount.Amount
+}
+
+// GenesisContract is the contract deployed at the genesis
+type GenesisContract struct {
+	Name    string
+	Class   string
+	Owner   common.Address
+	Args    json.RawMessage
+	Minters []string // only for the token, set after all contracts are deployed
+}
+
+// constructionMap has the construction argument of the contract class
+var constructionMap = map[string]func() io.WriterTo{
+	"Token":         func() io.WriterTo { return &token.TokenContractConstruction{} },
+	"Formulator":    func() io.WriterTo { return &formulator.FormulatorContractConstruction{} },
+	"Gateway":       func() io.WriterTo { return &gateway.GatewayContractConstruction{} },
+	"Factory":       func() io.WriterTo { return &factory.FactoryContractConstruction{} },
+	"Router":        func() io.WriterTo { return &router.RouterContractConstruction{} },
+	"UniSwap":       func() io.WriterTo { return &trade.UniSwapConstruction{} },
+	"StableSwap":    func() io.WriterTo { return &trade.StableSwapConstruction{} },
+	"Bridge":        func() io.WriterTo { return &bridge.BridgeContractConstruction{} },
+	"ConnectFarm":   func() io.WriterTo { return &farm.FarmContractConstruction{} },
+	"ConnectPool":   func() io.WriterTo { return &pool.PoolContractConstruction{} },
+	"WhiteList":     func() io.WriterTo { return &whitelist.WhiteListContractConstruction{} },
+	"IMO":           func() io.WriterTo { return &imo.ImoContractConstruction{} },
+	"DepositUSDT":   func() io.WriterTo { return &depositpool.DepositPoolContractConstruction{} },
+	"NFT721":        func() io.WriterTo { return &nft721.NFT721ContractConstruction{} },
+	"Engin":         func() io.WriterTo { return &engin.EnginContractConstruction{} },
+	"EnginDeployer": func() io.WriterTo { return &deployer.DeployerContractConstruction{} },
+	"MappFarm":      func() io.WriterTo { return &mappfarm.FarmContractConstruction{} },
+	"Forwarder":     func() io.WriterTo { return &forwarder.ForwarderContractConstruction{} },
+}
+
+// LoadGenesis loads the genesis config from the json file of the path
+func LoadGenesis(path string) (*GenesisConfig, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer file.Close()
+
+	gen := &GenesisConfig{}
+	dec := json.NewDecoder(file)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(gen); err != nil {
+		return nil, errors.Wrap(err, path)
+	}
+	if err := gen.Validate(); err != nil {
+		return nil, err
+	}
+	return gen, nil
+}
+
+// ChainIDInt returns the chain id as the big int
+func (gen *GenesisConfig) ChainIDInt() *big.Int {
+	return new(big.Int).SetUint64(gen.ChainID)
+}
+
+// ChainVersion returns the version of the chain, the default is 1
+func (gen *GenesisConfig) ChainVersion() uint16 {
+	if gen.Version == 0 {
+		return 1
+	}
+	return gen.Version
+}
+
+// Validate checks the config without building the genesis
+func (gen *GenesisConfig) Validate() error {
+	if gen.ChainID == 0 {
+		return errors.WithStack(ErrInvalidGenesisChainID)
+	}
+	if len(gen.Admins) == 0 {
+		return errors.WithStack(ErrNoGenesisAdmin)
+	}
+	if len(gen.Generators) == 0 {
+		return errors.WithStack(ErrNoGenesisGenerator)
+	}
+	if len(gen.ObserverKeys) == 0 {
+		return errors.WithStack(ErrNoGenesisObserver)
+	}
+	nameMap := map[string]bool{}
+	for _, c := range gen.Contracts {
+		if len(c.Name) == 0 || nameMap[c.Name] {
+			return errors.Wrapf(ErrInvalidGenesisContract, "name %v", c.Name)
+		}
+		nameMap[c.Name] = true
+		if _, has := constructionMap[c.Class]; !has {
+			return errors.Wrapf(ErrInvalidGenesisContract, "class %v of %v", c.Class, c.Name)
+		}
+		if len(c.Minters) > 0 && c.Class != "Token" {
+			return errors.Wrapf(ErrInvalidGenesisContract, "minters of %v", c.Name)
+		}
+	}
+	if len(gen.MainToken) > 0 {
+		if c := gen.contract(gen.MainToken); c == nil || c.Class != "Token" {
+			return errors.Wrapf(ErrInvalidGenesisMainToken, "%v", gen.MainToken)
+		}
+	} else if len(gen.Balances) > 0 {
+		return errors.WithStack(ErrInvalidGenesisMainToken)
+	}
+	return nil
+}
+
+func (gen *GenesisConfig) contract(name string) *GenesisContract {
+	for _, c := range gen.Contracts {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// GenesisOf returns the genesis of the config, the mainnet genesis is used when the config is nil
+func GenesisOf(gen *GenesisConfig) (*types.ContextData, error) {
+	if gen == nil {
+		return Genesis(), nil
+	}
+	return gen.ContextData()
+}
+
+// ContextData builds the genesis context data of the config
+func (gen *GenesisConfig) ContextData() (*types.ContextData, error) {
+	ctd, _, err := gen.Build()
+	return ctd, err
+}
+
+// Build builds the genesis context data and returns the addresses of the contracts by the name
+func (gen *GenesisConfig) Build() (*types.ContextData, map[string]common.Address, error) {
+	if err := gen.Validate(); err != nil {
+		return nil, nil, err
+	}
+	ClassMap := RegisterContractClass()
+
+	genesis := types.NewEmptyContext()
+	for _, addr := range gen.Admins {
+		if err := genesis.SetAdmin(addr, true); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, addr := range gen.Generators {
+		if err := genesis.SetGenerator(addr, true); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	AddressMap := map[string]common.Address{}
+	for _, c := range gen.Contracts {
+		arg := constructionMap[c.Class]()
+		if len(c.Args) > 0 {
+			bs, err := replaceGenesisRefs(c.Args, AddressMap)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "args of %v", c.Name)
+			}
+			if err := json.Unmarshal(bs, arg); err != nil {
+				return nil, nil, errors.Wrapf(err, "args of %v", c.Name)
+			}
+		}
+		if c.Name == gen.MainToken {
+			tokenArg := arg.(*token.TokenContractConstruction)
+			if tokenArg.InitialSupplyMap == nil {
+				tokenArg.InitialSupplyMap = map[common.Address]*amount.Amount{}
+			}
+			for addr, am := range gen.Balances {
+				if v, has := tokenArg.InitialSupplyMap[addr]; has {
+					tokenArg.InitialSupplyMap[addr] = v.Add(am)
+				} else {
+					tokenArg.InitialSupplyMap[addr] = am
+				}
+			}
+		}
+		bs, _, err := bin.WriterToBytes(arg)
+		if err != nil {
+			return nil, nil, err
+		}
+		cont, err := genesis.DeployContract(c.Owner, ClassMap[c.Class], bs)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "deploy %v", c.Name)
+		}
+		AddressMap[c.Name] = cont.Address()
+		if c.Name == gen.MainToken {
+			genesis.SetMainToken(cont.Address())
+		}
+	}
+	for _, c := range gen.Contracts {
+		if len(c.Minters) == 0 {
+			continue
+		}
+		v, err := genesis.Contract(AddressMap[c.Name])
+		if err != nil {
+			return nil, nil, err
+		}
+		cont := v.(*token.TokenContract)
+		cc := genesis.ContractContext(cont, c.Owner)
+		for _, m := range c.Minters {
+			addr, err := genesisRef(m, AddressMap)
+			if err != nil {
+				return nil, nil, err
+			}
+			if err := cont.SetMinter(cc, common.HexToAddress(addr), true); err != nil {
+				return nil, nil, errors.Wrapf(err, "minter %v of %v", m, c.Name)
+			}
+		}
+	}
+	return genesis.Top(), AddressMap, nil
+}
+
+// genesisRef returns the address of the contract when the string is "@" + name of it
+func genesisRef(s string, AddressMap map[string]common.Address) (string, error) {
+	if !strings.HasPrefix(s, "@") {
+		return s, nil
+	}
+	addr, has := AddressMap[s[1:]]
+	if !has {
+		return "", errors.Wrapf(ErrUnknownGenesisRef, "%v", s)
+	}
+	return addr.String(), nil
+}
+
+// replaceGenesisRefs replaces "@name" of the json values and keys to the address of the contract
+func replaceGenesisRefs(data []byte, AddressMap map[string]common.Address) ([]byte, error) {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	ref := func(s string) (string, error) {
+		return genesisRef(s, AddressMap)
+	}
+	var replace func(v interface{}) (interface{}, error)
+	replace = func(v interface{}) (interface{}, error) {
+		switch val := v.(type) {
+		case string:
+			return ref(val)
+		case []interface{}:
+			for i, item := range val {
+				r, err := replace(item)
+				if err != nil {
+					return nil, err
+				}
+				val[i] = r
+			}
+			return val, nil
+		case map[string]interface{}:
+			m := map[string]interface{}{}
+			for k, item := range val {
+				key, err := ref(k)
+				if err != nil {
+					return nil, err
+				}
+				r, err := replace(item)
+				if err != nil {
+					return nil, err
+				}
+				m[key] = r
+			}
+			return m, nil
+		default:
+			return v, nil
+		}
+	}
+	r, err := replace(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(r)
+}
@@ -0,0 +1,48 @@
+package test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/meverselabs/meverse/cmd/app"
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/core/chain"
+)
+
+func TestGenesisConfig(t *testing.T) {
+	gen, err := app.LoadGenesis("../../genesis/genesis.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctd, AddressMap, err := gen.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ctd.MainToken() == nil || *ctd.MainToken() != AddressMap["MEV"] {
+		t.Fatalf("main token %v, want %v", ctd.MainToken(), AddressMap["MEV"])
+	}
+	if !ctd.IsAdmin(gen.Admins[0]) || !ctd.IsGenerator(gen.Generators[0]) {
+		t.Fatal("admin or generator is not set")
+	}
+
+	ctd2, err := gen.ContextData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chain.GenesisHash(gen.ChainIDInt(), ctd) != chain.GenesisHash(gen.ChainIDInt(), ctd2) {
+		t.Fatal("genesis hash is not deterministic")
+	}
+
+	gen.Contracts[1].Args = json.RawMessage(`{"TokenAddress": "@Unknown"}`)
+	if _, err := gen.ContextData(); err == nil {
+		t.Fatal("unknown reference is accepted")
+	}
+	gen.Contracts[1].Class = "Unknown"
+	if err := gen.Validate(); err == nil {
+		t.Fatal("unknown class is accepted")
+	}
+	gen.ObserverKeys = []common.PublicKey{}
+	if err := gen.Validate(); err == nil {
+		t.Fatal("genesis without observers is accepted")
+	}
+}
@@ -0,0 +1,382 @@
+package devnet
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/meverselabs/meverse/cmd/app"
+	"github.com/meverselabs/meverse/cmd/closer"
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/common/key"
+	"github.com/pkg/errors"
+)
+
+// member kinds
+const (
+	KindObserver  = "observer"
+	KindGenerator = "generator"
+	KindNode      = "node"
+)
+
+// Config is the layout of the devnet
+type Config struct {
+	ChainID    uint64
+	Observers  int
+	Generators int
+	Nodes      int
+	Accounts   int
+	Balance    string
+	Root       string
+	Host       string
+	BasePort   int
+	RPCPort    int
+	Seed       string
+}
+
+// DefaultConfig returns the config of 3 observers, 1 generator and 1 node
+func DefaultConfig() *Config {
+	return &Config{
+		ChainID:    0xFFFF,
+		Observers:  3,
+		Generators: 1,
+		Nodes:      1,
+		Accounts:   10,
+		Balance:    "1000000",
+		Root:       "./_devnet",
+		Host:       "127.0.0.1",
+		BasePort:   40000,
+		RPCPort:    8541,
+		Seed:       "devnet",
+	}
+}
+
+// Member is an observer, a generator or a full node of the devnet
+//
+// the observer uses Port for the observer mesh and GeneratorPort for the generators
+// the generator and the node use Port for the p2p and RPCPort for the json rpc
+type Member struct {
+	Kind          string
+	Index         int
+	Key           *key.MemoryKey
+	NodeKey       *key.MemoryKey
+	Port          int
+	GeneratorPort int
+	RPCPort       int
+	Dir           string
+}
+
+// Name returns the name of the member
+func (m *Member) Name() string {
+	return m.Kind + "_" + strconv.Itoa(m.Index)
+}
+
+// Network is the devnet made from the config, the keys are derived from the seed so the same config makes the same network
+type Network struct {
+	sync.Mutex
+	Config     *Config
+	ChainID    *big.Int
+	Genesis    *app.GenesisConfig
+	Admin      *key.MemoryKey
+	Accounts   []*key.MemoryKey
+	Observers  []*Member
+	Generators []*Member
+	Nodes      []*Member
+	cm         *closer.Manager
+	procs      []*exec.Cmd
+	started    bool
+}
+
+// New returns the network of the config
+func New(cfg *Config) (*Network, error) {
+	if cfg.Observers < 1 || cfg.Generators < 1 || cfg.Nodes < 0 || cfg.Accounts < 0 {
+		return nil, errors.WithStack(ErrInvalidLayout)
+	}
+	n := &Network{
+		Config:  cfg,
+		ChainID: new(big.Int).SetUint64(cfg.ChainID),
+		cm:      closer.NewManager(),
+	}
+	var err error
+	if n.Admin, err = n.deriveKey("admin", 0); err != nil {
+		return nil, err
+	}
+	for i := 0; i < cfg.Accounts; i++ {
+		k, err := n.deriveKey("account", i)
+		if err != nil {
+			return nil, err
+		}
+		n.Accounts = append(n.Accounts, k)
+	}
+	for i := 0; i < cfg.Observers; i++ {
+		m, err := n.newMember(KindObserver, i, cfg.BasePort+i)
+		if err != nil {
+			return nil, err
+		}
+		m.GeneratorPort = cfg.BasePort + 100 + i
+		n.Observers = append(n.Observers, m)
+	}
+	for i := 0; i < cfg.Generators; i++ {
+		m, err := n.newMember(KindGenerator, i, cfg.BasePort+200+i)
+		if err != nil {
+			return nil, err
+		}
+		if m.NodeKey, err = n.deriveKey("generator_node", i); err != nil {
+			return nil, err
+		}
+		m.RPCPort = cfg.RPCPort + 100 + i
+		n.Generators = append(n.Generators, m)
+	}
+	for i := 0; i < cfg.Nodes; i++ {
+		m, err := n.newMember(KindNode, i, cfg.BasePort+300+i)
+		if err != nil {
+			return nil, err
+		}
+		m.RPCPort = cfg.RPCPort + i
+		n.Nodes = append(n.Nodes, m)
+	}
+	if err := n.buildGenesis(); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+func (n *Network) newMember(Kind string, Index int, Port int) (*Member, error) {
+	k, err := n.deriveKey(Kind, Index)
+	if err != nil {
+		return nil, err
+	}
+	m := &Member{
+		Kind:  Kind,
+		Index: Index,
+		Key:   k,
+		Port:  Port,
+	}
+	m.Dir = filepath.Join(n.Config.Root, m.Name())
+	return m, nil
+}
+
+func (n *Network) deriveKey(role string, i int) (*key.MemoryKey, error) {
+	h := hash.Hash([]byte(n.Config.Seed + "/" + role + "/" + strconv.Itoa(i)))
+	return key.NewMemoryKeyFromBytes(n.ChainID, h[:])
+}
+
+func (n *Network) buildGenesis() error {
+	Balance, err := amount.ParseAmount(n.Config.Balance)
+	if err != nil {
+		return err
+	}
+	adminAddress := n.Admin.PublicKey().Address()
+	gen := &app.GenesisConfig{
+		ChainID:   n.Config.ChainID,
+		Version:   1,
+		Admins:    []common.Address{adminAddress},
+		MainToken: "MEV",
+		Balances: map[common.Address]*amount.Amount{
+			adminAddress: Balance,
+		},
+	}
+	for _, m := range n.Generators {
+		gen.Generators = append(gen.Generators, m.Key.PublicKey().Address())
+	}
+	for _, m := range n.Observers {
+		gen.ObserverKeys = append(gen.ObserverKeys, m.Key.PublicKey())
+	}
+	for _, k := range n.Accounts {
+		gen.Balances[k.PublicKey().Address()] = Balance
+	}
+
+	owner := adminAddress.String()
+	gen.Contracts = []*app.GenesisContract{
+		{
+			Name:    "MEV",
+			Class:   "Token",
+			Owner:   adminAddress,
+			Args:    json.RawMessage(`{"Name": "MEVerse", "Symbol": "MEV"}`),
+			Minters: []string{"@Formulator"},
+		},
+		{
+			Name:  "Gateway",
+			Class: "Gateway",
+			Owner: adminAddress,
+			Args:  json.RawMessage(`{"TokenAddress": "@MEV"}`),
+		},
+		{
+			Name:  "Formulator",
+			Class: "Formulator",
+			Owner: adminAddress,
+			Args: json.RawMessage(`{
+				"TokenAddress": "@MEV",
+				"FormulatorPolicy": {"AlphaAmount": "200000", "SigmaCount": 4, "SigmaBlocks": 5184000, "OmegaCount": 2, "OmegaBlocks": 5184000, "HyperAmount": "0", "MinStakeAmount": "100"},
+				"RewardPolicy": {"RewardPerBlock": "0.4756468797564688", "AlphaEfficiency1000": 1000, "SigmaEfficiency1000": 1150, "OmegaEfficiency1000": 1300, "HyperEfficiency1000": 1300, "StakingEfficiency1000": 700, "CommissionRatio1000": 50, "MiningFeeAddress": "` + owner + `", "MiningFee1000": 300}
+			}`),
+		},
+	}
+	if err := gen.Validate(); err != nil {
+		return err
+	}
+	n.Genesis = gen
+	return nil
+}
+
+// ObserverNetAddressMap returns the observer mesh addresses
+func (n *Network) ObserverNetAddressMap() map[common.PublicKey]string {
+	mp := map[common.PublicKey]string{}
+	for _, m := range n.Observers {
+		mp[m.Key.PublicKey()] = n.Config.Host + ":" + strconv.Itoa(m.Port)
+	}
+	return mp
+}
+
+// GeneratorNetAddressMap returns the observer addresses which the generators connect to
+func (n *Network) GeneratorNetAddressMap() map[common.PublicKey]string {
+	mp := map[common.PublicKey]string{}
+	for _, m := range n.Observers {
+		mp[m.Key.PublicKey()] = n.Config.Host + ":" + strconv.Itoa(m.GeneratorPort)
+	}
+	return mp
+}
+
+// SeedNodeMap returns the p2p addresses of the generators and the nodes
+func (n *Network) SeedNodeMap() map[common.PublicKey]string {
+	mp := map[common.PublicKey]string{}
+	for _, m := range n.Generators {
+		mp[m.NodeKey.PublicKey()] = n.Config.Host + ":" + strconv.Itoa(m.Port)
+	}
+	for _, m := range n.Nodes {
+		mp[m.Key.PublicKey()] = n.Config.Host + ":" + strconv.Itoa(m.Port)
+	}
+	return mp
+}
+
+// RPCEndpoints returns the json rpc urls of the nodes and the generators
+func (n *Network) RPCEndpoints() []string {
+	urls := []string{}
+	for _, m := range append(append([]*Member{}, n.Nodes...), n.Generators...) {
+		urls = append(urls, "http://"+n.Config.Host+":"+strconv.Itoa(m.RPCPort))
+	}
+	return urls
+}
+
+func (n *Network) members() []*Member {
+	ms := append([]*Member{}, n.Observers...)
+	ms = append(ms, n.Generators...)
+	return append(ms, n.Nodes...)
+}
+
+// WriteFiles writes the genesis, the accounts and the config of the members under the root
+func (n *Network) WriteFiles() error {
+	if err := os.MkdirAll(n.Config.Root, 0755); err != nil {
+		return errors.WithStack(err)
+	}
+	bs, err := json.MarshalIndent(n.Genesis, "", "\t")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := os.WriteFile(n.genesisPath(), bs, 0644); err != nil {
+		return errors.WithStack(err)
+	}
+
+	type account struct {
+		Address    common.Address
+		PrivateKey string
+	}
+	accounts := []*account{{Address: n.Admin.PublicKey().Address(), PrivateKey: hex.EncodeToString(n.Admin.Bytes())}}
+	for _, k := range n.Accounts {
+		accounts = append(accounts, &account{Address: k.PublicKey().Address(), PrivateKey: hex.EncodeToString(k.Bytes())})
+	}
+	bs, err = json.MarshalIndent(accounts, "", "\t")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := os.WriteFile(filepath.Join(n.Config.Root, "accounts.json"), bs, 0600); err != nil {
+		return errors.WithStack(err)
+	}
+
+	for _, m := range n.members() {
+		if err := os.MkdirAll(m.Dir, 0755); err != nil {
+			return errors.WithStack(err)
+		}
+		if err := os.WriteFile(filepath.Join(m.Dir, "config.toml"), []byte(n.memberConfig(m)), 0600); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+func (n *Network) genesisPath() string {
+	return filepath.Join(n.Config.Root, "genesis.json")
+}
+
+// memberConfig returns the config.toml of cmd/observer, cmd/generator or cmd/node for the member
+func (n *Network) memberConfig(m *Member) string {
+	var b strings.Builder
+	writeMap := func(name string, mp map[common.PublicKey]string) {
+		fmt.Fprintf(&b, "\n[%v]\n", name)
+		keys := []string{}
+		addrs := map[string]string{}
+		for pubkey, addr := range mp {
+			keys = append(keys, pubkey.String())
+			addrs[pubkey.String()] = addr
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(&b, "%v = %q\n", k, addrs[k])
+		}
+	}
+	fmt.Fprintf(&b, "Genesis = %q\n", "../genesis.json")
+	fmt.Fprintf(&b, "StoreRoot = %q\n", "./data")
+	fmt.Fprintf(&b, "Port = %v\n", m.Port)
+	switch m.Kind {
+	case KindObserver:
+		fmt.Fprintf(&b, "GeneratorPort = %v\n", m.GeneratorPort)
+		fmt.Fprintf(&b, "ObserverKeyHex = %q\n", hex.EncodeToString(m.Key.Bytes()))
+		writeMap("ObserverMap", n.ObserverNetAddressMap())
+	case KindGenerator:
+		fmt.Fprintf(&b, "RPCPort = %v\n", m.RPCPort)
+		fmt.Fprintf(&b, "GeneratorKeyHex = %q\n", hex.EncodeToString(m.Key.Bytes()))
+		fmt.Fprintf(&b, "NodeKeyHex = %q\n", hex.EncodeToString(m.NodeKey.Bytes()))
+		writeMap("ObserverMap", n.GeneratorNetAddressMap())
+		writeMap("SeedNodeMap", n.SeedNodeMap())
+	case KindNode:
+		fmt.Fprintf(&b, "RPCPort = %v\n", m.RPCPort)
+		fmt.Fprintf(&b, "NodeKeyHex = %q\n", hex.EncodeToString(m.Key.Bytes()))
+		writeMap("SeedNodeMap", n.SeedNodeMap())
+	}
+	return b.String()
+}
+
+// Reset removes all data of the network
+func (n *Network) Reset() error {
+	if err := os.RemoveAll(n.Config.Root); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// Close stops the members of the network
+func (n *Network) Close() {
+	n.Lock()
+	defer n.Unlock()
+
+	for _, cmd := range n.procs {
+		if cmd.Process != nil {
+			cmd.Process.Signal(os.Interrupt)
+		}
+	}
+	for _, cmd := range n.procs {
+		cmd.Wait()
+	}
+	n.procs = nil
+	n.cm.CloseAll()
+}
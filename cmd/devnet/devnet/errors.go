@@ -0,0 +1,9 @@
+package devnet
+
+import "errors"
+
+// errors
+var (
+	ErrInvalidLayout  = errors.New("invalid devnet layout")
+	ErrAlreadyStarted = errors.New("devnet already started")
+)
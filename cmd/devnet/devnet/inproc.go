@@ -0,0 +1,170 @@
+package devnet
+
+import (
+	"path/filepath"
+	"strconv"
+
+	"github.com/meverselabs/meverse/cmd/app"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/core/chain"
+	"github.com/meverselabs/meverse/core/piledb"
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/meverselabs/meverse/ethereum/params"
+	"github.com/meverselabs/meverse/node"
+	"github.com/meverselabs/meverse/p2p"
+	"github.com/meverselabs/meverse/service/apiserver"
+	"github.com/meverselabs/meverse/service/apiserver/metamaskrelay"
+	"github.com/meverselabs/meverse/service/apiserver/viewchain"
+	"github.com/meverselabs/meverse/service/bloomservice"
+	"github.com/meverselabs/meverse/service/txsearch"
+	"github.com/pkg/errors"
+)
+
+// StartInProcess runs all members in the current process
+//
+// the json rpc servers of the members can not be stopped, so the process should exit after Close
+func (n *Network) StartInProcess() error {
+	n.Lock()
+	defer n.Unlock()
+
+	if n.started {
+		return errors.WithStack(ErrAlreadyStarted)
+	}
+	n.started = true
+
+	for _, m := range n.Observers {
+		if err := n.startObserver(m); err != nil {
+			return errors.Wrap(err, m.Name())
+		}
+	}
+	for _, m := range n.Generators {
+		if err := n.startGenerator(m); err != nil {
+			return errors.Wrap(err, m.Name())
+		}
+	}
+	for _, m := range n.Nodes {
+		if err := n.startNode(m); err != nil {
+			return errors.Wrap(err, m.Name())
+		}
+	}
+	return nil
+}
+
+// openChain opens the store of the member and connects the stored blocks like the node commands do
+//
+// setup adds the services to the chain before the init
+func (n *Network) openChain(m *Member, setup func(cn *chain.Chain, st *chain.Store) error) (*chain.Chain, error) {
+	StoreRoot := filepath.Join(m.Dir, "data")
+	cdb, err := piledb.Open(StoreRoot+"/chain", hash.Hash256{}, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	cdb.SetSyncMode(true)
+	st, err := chain.NewStore(StoreRoot+"/context", cdb, n.ChainID, n.Genesis.ChainVersion())
+	if err != nil {
+		return nil, err
+	}
+	cn := chain.NewChain(n.Genesis.ObserverKeys, st, "")
+	if setup != nil {
+		if err := setup(cn, st); err != nil {
+			st.Close()
+			return nil, err
+		}
+	}
+	genesis, err := app.GenesisOf(n.Genesis)
+	if err != nil {
+		st.Close()
+		return nil, err
+	}
+	if err := cn.Init(genesis); err != nil {
+		st.Close()
+		return nil, err
+	}
+	if err := st.IterBlockAfterContext(func(b *types.Block) error {
+		return cn.ConnectBlock(b, nil)
+	}); err != nil {
+		cn.Close()
+		return nil, err
+	}
+	return cn, nil
+}
+
+func (n *Network) startObserver(m *Member) error {
+	cn, err := n.openChain(m, nil)
+	if err != nil {
+		return err
+	}
+	ob := node.NewObserverNode(n.ChainID, m.Key, n.ObserverNetAddressMap(), cn, m.Name())
+	if err := ob.Init(); err != nil {
+		cn.Close()
+		return err
+	}
+	n.cm.Add(m.Name(), ob)
+
+	go ob.Run(":"+strconv.Itoa(m.Port), ":"+strconv.Itoa(m.GeneratorPort))
+	return nil
+}
+
+func (n *Network) startGenerator(m *Member) error {
+	rpcapi := apiserver.NewAPIServer()
+	cn, err := n.openChain(m, func(cn *chain.Chain, st *chain.Store) error {
+		cn.MustAddService(rpcapi)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	ObserverNodeMap := n.GeneratorNetAddressMap()
+	for pubkey, addr := range ObserverNodeMap {
+		ObserverNodeMap[pubkey] = "ws://" + addr
+	}
+	fr := node.NewGeneratorNode(n.ChainID, &node.GeneratorConfig{
+		MaxTransactionsPerBlock: 20000,
+	}, cn, m.Key, m.NodeKey, ObserverNodeMap, n.SeedNodeMap(), filepath.Join(m.Dir, "data", "peer"))
+	if err := fr.Init(); err != nil {
+		cn.Close()
+		return err
+	}
+	n.cm.Add(m.Name(), fr)
+
+	go rpcapi.Run(":" + strconv.Itoa(m.RPCPort))
+	go fr.Run(":" + strconv.Itoa(m.Port))
+	return nil
+}
+
+func (n *Network) startNode(m *Member) error {
+	StoreRoot := filepath.Join(m.Dir, "data")
+	rpcapi := apiserver.NewAPIServer()
+	var st *chain.Store
+	var ts *txsearch.TxSearch
+	var bs *bloomservice.BloomBitService
+	cn, err := n.openChain(m, func(cn *chain.Chain, _st *chain.Store) error {
+		var err error
+		bs, err = bloomservice.NewBloomBitService(cn, StoreRoot+"/_bloombits", params.BloomBitsBlocks, params.BloomConfirms)
+		if err != nil {
+			return err
+		}
+		st = _st
+		ts = txsearch.NewTxSearch(StoreRoot+"/_txsearch", rpcapi, st, cn, 0)
+		cn.MustAddService(ts)
+		cn.MustAddService(rpcapi)
+		cn.MustAddService(bs)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	nd := p2p.NewNode(n.ChainID, m.Key, n.SeedNodeMap(), cn, StoreRoot+"/peer")
+	if err := nd.Init(); err != nil {
+		cn.Close()
+		return err
+	}
+	n.cm.Add(m.Name(), nd)
+
+	metamaskrelay.NewMetamaskRelay(rpcapi, ts, bs, cn, nd)
+	viewchain.NewViewchain(rpcapi, ts, cn, st, bs, nd)
+	go rpcapi.Run(":" + strconv.Itoa(m.RPCPort))
+	go nd.Run(":" + strconv.Itoa(m.Port))
+	return nil
+}
@@ -0,0 +1,55 @@
+package devnet
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// StartSubprocess runs the members with the observer, generator and node binaries in BinDir
+//
+// the members read the config.toml written by WriteFiles and log to log.txt of their directory
+func (n *Network) StartSubprocess(BinDir string) error {
+	n.Lock()
+	defer n.Unlock()
+
+	if n.started {
+		return errors.WithStack(ErrAlreadyStarted)
+	}
+	n.started = true
+
+	for _, m := range n.members() {
+		if err := n.startProcess(BinDir, m); err != nil {
+			for _, cmd := range n.procs {
+				cmd.Process.Kill()
+				cmd.Wait()
+			}
+			n.procs = nil
+			return errors.Wrap(err, m.Name())
+		}
+	}
+	return nil
+}
+
+func (n *Network) startProcess(BinDir string, m *Member) error {
+	bin, err := filepath.Abs(filepath.Join(BinDir, m.Kind))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	log, err := os.OpenFile(filepath.Join(m.Dir, "log.txt"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	cmd := exec.Command(bin)
+	cmd.Dir = m.Dir
+	cmd.Stdout = log
+	cmd.Stderr = log
+	if err := cmd.Start(); err != nil {
+		log.Close()
+		return errors.WithStack(err)
+	}
+	n.procs = append(n.procs, cmd)
+	return nil
+}
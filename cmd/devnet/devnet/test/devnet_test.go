@@ -0,0 +1,80 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/meverselabs/meverse/cmd/app"
+	"github.com/meverselabs/meverse/cmd/devnet/devnet"
+	"github.com/meverselabs/meverse/core/chain"
+)
+
+func TestDevnetFiles(t *testing.T) {
+	cfg := devnet.DefaultConfig()
+	cfg.Root = filepath.Join(t.TempDir(), "devnet")
+	cfg.Generators = 2
+	cfg.Accounts = 3
+
+	n, err := devnet.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := n.WriteFiles(); err != nil {
+		t.Fatal(err)
+	}
+	if len(n.ObserverNetAddressMap()) != 3 || len(n.SeedNodeMap()) != 3 || len(n.RPCEndpoints()) != 3 {
+		t.Fatal("invalid address maps")
+	}
+
+	gen, err := app.LoadGenesis(filepath.Join(cfg.Root, "genesis.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctd, _, err := gen.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range n.Generators {
+		if !ctd.IsGenerator(m.Key.PublicKey().Address()) {
+			t.Fatalf("%v is not a generator", m.Name())
+		}
+	}
+	cfgBytes, err := os.ReadFile(filepath.Join(cfg.Root, "generator_1", "config.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the same config makes the same network
+	n2, err := devnet.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctd2, err := n2.Genesis.ContextData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chain.GenesisHash(n.ChainID, ctd) != chain.GenesisHash(n2.ChainID, ctd2) {
+		t.Fatal("genesis is not reproducible")
+	}
+	if err := n2.WriteFiles(); err != nil {
+		t.Fatal(err)
+	}
+	if bs, err := os.ReadFile(filepath.Join(cfg.Root, "generator_1", "config.toml")); err != nil {
+		t.Fatal(err)
+	} else if string(bs) != string(cfgBytes) {
+		t.Fatal("config is not reproducible")
+	}
+
+	if err := n2.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(cfg.Root); !os.IsNotExist(err) {
+		t.Fatal("devnet is not reset")
+	}
+
+	cfg.Observers = 0
+	if _, err := devnet.New(cfg); err == nil {
+		t.Fatal("devnet without observers is accepted")
+	}
+}
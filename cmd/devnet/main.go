@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/meverselabs/meverse/cmd/devnet/devnet"
+)
+
+func main() {
+	cfg := devnet.DefaultConfig()
+	flag.IntVar(&cfg.Observers, "observers", cfg.Observers, "number of observers")
+	flag.IntVar(&cfg.Generators, "generators", cfg.Generators, "number of generators")
+	flag.IntVar(&cfg.Nodes, "nodes", cfg.Nodes, "number of full nodes")
+	flag.IntVar(&cfg.Accounts, "accounts", cfg.Accounts, "number of prefunded accounts")
+	flag.StringVar(&cfg.Balance, "balance", cfg.Balance, "balance of each prefunded account")
+	flag.Uint64Var(&cfg.ChainID, "chainid", cfg.ChainID, "chain id")
+	flag.StringVar(&cfg.Root, "root", cfg.Root, "data directory of the devnet")
+	flag.StringVar(&cfg.Host, "host", cfg.Host, "host of the members")
+	flag.IntVar(&cfg.BasePort, "port", cfg.BasePort, "first p2p port")
+	flag.IntVar(&cfg.RPCPort, "rpc", cfg.RPCPort, "first json rpc port")
+	flag.StringVar(&cfg.Seed, "seed", cfg.Seed, "seed of the keys")
+	mode := flag.String("mode", "inproc", "inproc or subprocess")
+	bin := flag.String("bin", "./bin", "directory of the observer, generator and node binaries for the subprocess mode")
+	reset := flag.Bool("reset", false, "remove the data of the devnet before the start")
+	initOnly := flag.Bool("init", false, "write the genesis and the configs without starting")
+	flag.Parse()
+
+	n, err := devnet.New(cfg)
+	if err != nil {
+		log.Fatalf("%+v", err)
+	}
+	if *reset {
+		if err := n.Reset(); err != nil {
+			log.Fatalf("%+v", err)
+		}
+	}
+	if err := n.WriteFiles(); err != nil {
+		log.Fatalf("%+v", err)
+	}
+	if *initOnly {
+		fmt.Println("devnet written to", cfg.Root)
+		return
+	}
+
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc,
+		syscall.SIGHUP,
+		syscall.SIGINT,
+		syscall.SIGTERM,
+		syscall.SIGQUIT)
+
+	switch *mode {
+	case "inproc":
+		err = n.StartInProcess()
+	case "subprocess":
+		err = n.StartSubprocess(*bin)
+	default:
+		err = fmt.Errorf("unknown mode %v", *mode)
+	}
+	if err != nil {
+		n.Close()
+		log.Fatalf("%+v", err)
+	}
+
+	fmt.Println("ChainID", cfg.ChainID)
+	fmt.Println("Accounts", cfg.Root+"/accounts.json")
+	for _, url := range n.RPCEndpoints() {
+		fmt.Println("RPC", url)
+	}
+
+	<-sigc
+	n.Close()
+}
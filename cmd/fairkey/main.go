@@ -0,0 +1,34 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/meverselabs/meverse/common/threshold"
+)
+
+// fairkey deals the fair ordering key, the key is set as FairOrderKey of the generators and the observers
+// and each share is set as FairOrderShare of an observer
+func main() {
+	count := flag.Int("count", 0, "number of the observers")
+	th := flag.Int("threshold", 0, "number of the shares to decrypt, 0 means the majority of the observers")
+	flag.Parse()
+
+	if *th == 0 {
+		*th = *count/2 + 1
+	}
+	if *th > *count/2+1 {
+		fmt.Fprintln(os.Stderr, "the threshold should not exceed the majority of the observers which sign a block")
+		os.Exit(1)
+	}
+	pk, shares, err := threshold.Deal(*th, *count)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%+v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("FairOrderKey", pk.String())
+	for _, ss := range shares {
+		fmt.Println("FairOrderShare", ss.Index, ss.String())
+	}
+}
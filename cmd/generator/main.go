@@ -17,11 +17,14 @@ import (
 	"github.com/meverselabs/meverse/common"
 	"github.com/meverselabs/meverse/common/hash"
 	"github.com/meverselabs/meverse/common/key"
+	"github.com/meverselabs/meverse/common/threshold"
 	"github.com/meverselabs/meverse/core/chain"
 	"github.com/meverselabs/meverse/core/piledb"
 	"github.com/meverselabs/meverse/core/types"
 	"github.com/meverselabs/meverse/node"
 	"github.com/meverselabs/meverse/service/apiserver"
+	"github.com/meverselabs/meverse/service/apiserver/bundle"
+	"github.com/meverselabs/meverse/service/apiserver/fair"
 	"github.com/meverselabs/meverse/service/apiserver/viewchain"
 	"github.com/meverselabs/meverse/service/apiserver/zipcontext"
 )
@@ -39,7 +42,10 @@ type Config struct {
 	Port            int
 	RPCPort         int
 	StoreRoot       string
+	Genesis         string
 	UseWSS          bool
+	ExecuteWorkers  int
+	FairOrderKey    string
 }
 
 func main() {
@@ -50,6 +56,16 @@ func main() {
 	if err := config.LoadFile("./config.toml", &cfg); err != nil {
 		panic(err)
 	}
+	var gen *app.GenesisConfig
+	if len(cfg.Genesis) > 0 {
+		g, err := app.LoadGenesis(cfg.Genesis)
+		if err != nil {
+			panic(err)
+		}
+		gen = g
+		ChainID = gen.ChainIDInt()
+		Version = gen.ChainVersion()
+	}
 	versionInfo1 := flag.Bool("v", false, "version info")
 	versionInfo2 := flag.Bool("version", false, "version info")
 	flag.Parse()
@@ -175,13 +191,21 @@ func main() {
 		}
 	}
 
+	if gen != nil {
+		ObserverKeys = gen.ObserverKeys
+	}
 	cn := chain.NewChain(ObserverKeys, st, "")
+	cn.SetExecuteWorkers(cfg.ExecuteWorkers)
 	rpcapi := apiserver.NewAPIServer()
 	zipContext := zipcontext.NewZipContextService(rpcapi, st, "./zipcontext/", 172800)
 	cn.MustAddService(rpcapi)
 	cn.MustAddService(zipContext)
 	if cfg.InitHeight == 0 {
-		if err := cn.Init(app.Genesis()); err != nil {
+		genesis, err := app.GenesisOf(gen)
+		if err != nil {
+			panic(err)
+		}
+		if err := cn.Init(genesis); err != nil {
 			panic(err)
 		}
 	} else {
@@ -208,12 +232,24 @@ func main() {
 		panic(err)
 	}
 
+	var FairOrderKey *threshold.PublicKey
+	if len(cfg.FairOrderKey) > 0 {
+		if pk, err := threshold.ParsePublicKey(cfg.FairOrderKey); err != nil {
+			panic(err)
+		} else {
+			FairOrderKey = pk
+		}
+	}
+
 	fr := node.NewGeneratorNode(ChainID, &node.GeneratorConfig{
 		MaxTransactionsPerBlock: 20000,
+		FairOrderKey:            FairOrderKey,
 	}, cn, frkey, ndkey, ObserverNodeMap, SeedNodeMap, cfg.StoreRoot+"/peer")
 	if err := fr.Init(); err != nil {
 		panic(err)
 	}
+	bundle.NewBundleApi(rpcapi, cn, fr)
+	fair.NewFairApi(rpcapi, fr)
 	cm.RemoveAll()
 	cm.Add("formulator", fr)
 
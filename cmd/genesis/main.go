@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/meverselabs/meverse/cmd/app"
+	"github.com/meverselabs/meverse/core/chain"
+)
+
+func main() {
+	path := flag.String("genesis", "./genesis.json", "genesis file path")
+	flag.Parse()
+
+	gen, err := app.LoadGenesis(*path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid genesis: %+v\n", err)
+		os.Exit(1)
+	}
+	ctd, AddressMap, err := gen.Build()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid genesis: %+v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("ChainID", gen.ChainID)
+	fmt.Println("Version", gen.ChainVersion())
+	fmt.Println("Admins", len(gen.Admins))
+	fmt.Println("Generators", len(gen.Generators))
+	fmt.Println("Observers", len(gen.ObserverKeys))
+	for _, c := range gen.Contracts {
+		fmt.Println("Contract", c.Name, c.Class, AddressMap[c.Name].String())
+	}
+	fmt.Println("GenesisHash", chain.GenesisHash(gen.ChainIDInt(), ctd).String())
+}
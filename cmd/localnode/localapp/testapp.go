@@ -19,9 +19,11 @@ import (
 	"github.com/meverselabs/meverse/contract/external/deployer"
 	"github.com/meverselabs/meverse/contract/external/engin"
 	"github.com/meverselabs/meverse/contract/formulator"
+	"github.com/meverselabs/meverse/contract/forwarder"
 	"github.com/meverselabs/meverse/contract/gateway"
 	"github.com/meverselabs/meverse/contract/nft721"
 	"github.com/meverselabs/meverse/contract/token"
+	"github.com/meverselabs/meverse/contract/wasm"
 	"github.com/meverselabs/meverse/contract/whitelist"
 	"github.com/meverselabs/meverse/core/chain"
 	"github.com/meverselabs/meverse/core/types"
@@ -262,6 +264,8 @@ func RegisterContractClass() map[string]uint64 {
 	registerContractClass(&deployer.DeployerContract{}, "EnginDeployer", ClassMap)
 	registerContractClass(&erc20wrapper.Erc20WrapperContract{}, "Erc20Wrapper", ClassMap)
 	registerContractClass(&mappfarm.FarmContract{}, "MappFarm", ClassMap)
+	registerContractClass(&wasm.WasmContract{}, "Wasm", ClassMap)
+	registerContractClass(&forwarder.ForwarderContract{}, "Forwarder", ClassMap)
 	return ClassMap
 }
 
@@ -0,0 +1,578 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/client"
+	"github.com/meverselabs/meverse/cmd/mev/wallet"
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/common/key"
+)
+
+type command struct {
+	usage string
+	run   func(args []string) error
+}
+
+var (
+	rpcURL   = flag.String("rpc", "http://127.0.0.1:8541", "json rpc url of the node")
+	ksDir    = flag.String("keystore", "./keystore", "keystore directory")
+	timeout  = flag.Duration("timeout", time.Minute, "timeout of the command")
+	commands = map[string]*command{}
+	ctx      context.Context
+)
+
+func init() {
+	commands["account"] = &command{"account new | list | import <hex private key> | export <address>", runAccount}
+	commands["balance"] = &command{"balance [-token address] <address>", runBalance}
+	commands["transfer"] = &command{"transfer [-from address] [-token address] [-wait] <to> <amount>", runTransfer}
+	commands["call"] = &command{"call [-from address] <contract> <method> [type:value ...]", runCall}
+	commands["send"] = &command{"send [-from address] [-wait] <contract> <method> [type:value ...]", runSend}
+	commands["deploy"] = &command{"deploy [-from address] [-init json array] [-wait] <wasm file>", runDeploy}
+	commands["status"] = &command{"status [-wait] <tx hash>", runStatus}
+	commands["sign"] = &command{"sign -chainid id -seq seq [-from address] [-out file] (<contract> <method> [type:value ...] | -deploy <wasm file> [-init json array])", runSign}
+	commands["broadcast"] = &command{"broadcast [-wait] <signed tx file>", runBroadcast}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: mev [flags] <command> [command flags] [args]")
+	flag.PrintDefaults()
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, name := range []string{"account", "balance", "transfer", "call", "send", "deploy", "status", "sign", "broadcast"} {
+		fmt.Fprintln(os.Stderr, "  "+commands[name].usage)
+	}
+	fmt.Fprintln(os.Stderr, "arguments are type:value with the types string, address, addresses, amount, uint256, bool, bytes, hash, uint8 ~ uint64 and int ~ int64")
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, has := commands[flag.Arg(0)]
+	if !has {
+		usage()
+		os.Exit(2)
+	}
+	var cancel context.CancelFunc
+	ctx, cancel = context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	if err := cmd.run(flag.Args()[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		cancel()
+		os.Exit(1)
+	}
+}
+
+func dial() (*client.Client, error) {
+	return client.Dial(*rpcURL)
+}
+
+// passphrase reads the passphrase from the file, MEV_PASSWORD or the stdin
+func passphrase(path string) (string, error) {
+	if len(path) > 0 {
+		bs, err := os.ReadFile(path)
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		return strings.TrimRight(string(bs), "\r\n"), nil
+	}
+	if pass, has := os.LookupEnv("MEV_PASSWORD"); has {
+		return pass, nil
+	}
+	fmt.Fprint(os.Stderr, "passphrase: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && len(line) == 0 {
+		return "", errors.WithStack(err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// signer returns the key of the from address, the only account of the keystore is used without the address
+func signer(ks *wallet.Keystore, from string, passFile string, ChainID *big.Int) (*key.MemoryKey, error) {
+	var addr common.Address
+	if len(from) > 0 {
+		a, err := common.ParseAddress(from)
+		if err != nil {
+			return nil, err
+		}
+		addr = a
+	} else {
+		addrs, err := ks.Accounts()
+		if err != nil {
+			return nil, err
+		}
+		if len(addrs) != 1 {
+			return nil, errors.New("need -from address")
+		}
+		addr = addrs[0]
+	}
+	pass, err := passphrase(passFile)
+	if err != nil {
+		return nil, err
+	}
+	return ks.Key(addr, pass, ChainID)
+}
+
+func printJSON(v interface{}) error {
+	bs, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	fmt.Println(string(bs))
+	return nil
+}
+
+func runAccount(args []string) error {
+	fs := flag.NewFlagSet("account", flag.ExitOnError)
+	passFile := fs.String("password", "", "passphrase file")
+	fs.Parse(args)
+
+	ks := wallet.NewKeystore(*ksDir)
+	switch fs.Arg(0) {
+	case "list":
+		addrs, err := ks.Accounts()
+		if err != nil {
+			return err
+		}
+		for _, addr := range addrs {
+			fmt.Println(addr.String())
+		}
+		return nil
+	case "new":
+		pass, err := passphrase(*passFile)
+		if err != nil {
+			return err
+		}
+		addr, err := ks.NewAccount(pass)
+		if err != nil {
+			return err
+		}
+		fmt.Println(addr.String())
+		return nil
+	case "import":
+		priv, err := hex.DecodeString(strings.TrimPrefix(fs.Arg(1), "0x"))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		pass, err := passphrase(*passFile)
+		if err != nil {
+			return err
+		}
+		addr, err := ks.Import(priv, pass)
+		if err != nil {
+			return err
+		}
+		fmt.Println(addr.String())
+		return nil
+	case "export":
+		addr, err := common.ParseAddress(fs.Arg(1))
+		if err != nil {
+			return err
+		}
+		pass, err := passphrase(*passFile)
+		if err != nil {
+			return err
+		}
+		priv, err := ks.Export(addr, pass)
+		if err != nil {
+			return err
+		}
+		fmt.Println(hex.EncodeToString(priv))
+		return nil
+	default:
+		return errors.New("usage: mev " + commands["account"].usage)
+	}
+}
+
+func runBalance(args []string) error {
+	fs := flag.NewFlagSet("balance", flag.ExitOnError)
+	token := fs.String("token", "", "token address, the main token is used without it")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errors.New("usage: mev " + commands["balance"].usage)
+	}
+	addr, err := common.ParseAddress(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	c, err := dial()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if len(*token) == 0 {
+		am, err := c.Balance(ctx, addr)
+		if err != nil {
+			return err
+		}
+		fmt.Println(am.String())
+		return nil
+	}
+	tokenAddr, err := common.ParseAddress(*token)
+	if err != nil {
+		return err
+	}
+	out, err := c.ViewCall(ctx, tokenAddr, "BalanceOf", []interface{}{addr.String()}, common.Address{})
+	if err != nil {
+		return err
+	}
+	if len(out) != 1 {
+		return errors.WithStack(client.ErrInvalidResponse)
+	}
+	fmt.Println(out[0])
+	return nil
+}
+
+// sendAndWait sends the call by the sender of the key and prints the hash and the receipt when it waits
+func sendAndWait(c *client.Client, k key.Key, to common.Address, method string, args []interface{}, wait bool) error {
+	sender, err := c.NewSender(ctx, k)
+	if err != nil {
+		return err
+	}
+	h, err := sender.Send(ctx, to, method, args...)
+	if err != nil {
+		return err
+	}
+	fmt.Println(h.String())
+	if wait {
+		return waitAndPrint(c, h)
+	}
+	return nil
+}
+
+func waitAndPrint(c *client.Client, h hash.Hash256) error {
+	receipt, err := wallet.WaitReceipt(ctx, c, h, time.Second)
+	if receipt != nil {
+		if err := printJSON(receipt); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func runTransfer(args []string) error {
+	fs := flag.NewFlagSet("transfer", flag.ExitOnError)
+	from := fs.String("from", "", "sender address")
+	passFile := fs.String("password", "", "passphrase file")
+	token := fs.String("token", "", "token address, the main token is used without it")
+	wait := fs.Bool("wait", false, "wait until the transaction is included")
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		return errors.New("usage: mev " + commands["transfer"].usage)
+	}
+	to, err := common.ParseAddress(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	am, err := amount.ParseAmount(fs.Arg(1))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	c, err := dial()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	var tokenAddr common.Address
+	if len(*token) > 0 {
+		if tokenAddr, err = common.ParseAddress(*token); err != nil {
+			return err
+		}
+	} else if tokenAddr, err = c.MainToken(ctx); err != nil {
+		return err
+	}
+	ChainID, err := c.ChainID(ctx)
+	if err != nil {
+		return err
+	}
+	k, err := signer(wallet.NewKeystore(*ksDir), *from, *passFile, ChainID)
+	if err != nil {
+		return err
+	}
+	defer k.Clear()
+	return sendAndWait(c, k, tokenAddr, "Transfer", []interface{}{to, am}, *wait)
+}
+
+func runCall(args []string) error {
+	fs := flag.NewFlagSet("call", flag.ExitOnError)
+	from := fs.String("from", "", "caller address")
+	fs.Parse(args)
+	if fs.NArg() < 2 {
+		return errors.New("usage: mev " + commands["call"].usage)
+	}
+	contract, err := common.ParseAddress(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	params, err := wallet.ParseArgs(fs.Args()[2:])
+	if err != nil {
+		return err
+	}
+	var fromAddr common.Address
+	if len(*from) > 0 {
+		if fromAddr, err = common.ParseAddress(*from); err != nil {
+			return err
+		}
+	}
+
+	c, err := dial()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	out, err := wallet.Call(ctx, c, contract, fs.Arg(1), params, fromAddr)
+	if err != nil {
+		return err
+	}
+	return printJSON(out)
+}
+
+func runSend(args []string) error {
+	fs := flag.NewFlagSet("send", flag.ExitOnError)
+	from := fs.String("from", "", "sender address")
+	passFile := fs.String("password", "", "passphrase file")
+	wait := fs.Bool("wait", false, "wait until the transaction is included")
+	fs.Parse(args)
+	if fs.NArg() < 2 {
+		return errors.New("usage: mev " + commands["send"].usage)
+	}
+	contract, err := common.ParseAddress(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	params, err := wallet.ParseArgs(fs.Args()[2:])
+	if err != nil {
+		return err
+	}
+
+	c, err := dial()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	ChainID, err := c.ChainID(ctx)
+	if err != nil {
+		return err
+	}
+	k, err := signer(wallet.NewKeystore(*ksDir), *from, *passFile, ChainID)
+	if err != nil {
+		return err
+	}
+	defer k.Clear()
+	return sendAndWait(c, k, contract, fs.Arg(1), params, *wait)
+}
+
+func readDeploy(path string, initJSON string) ([]byte, []interface{}, error) {
+	code, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	initArgs := []interface{}{}
+	if len(initJSON) > 0 {
+		if err := json.Unmarshal([]byte(initJSON), &initArgs); err != nil {
+			return nil, nil, errors.WithStack(err)
+		}
+	}
+	return code, initArgs, nil
+}
+
+func runDeploy(args []string) error {
+	fs := flag.NewFlagSet("deploy", flag.ExitOnError)
+	from := fs.String("from", "", "sender address")
+	passFile := fs.String("password", "", "passphrase file")
+	initJSON := fs.String("init", "", "json array of the init arguments")
+	wait := fs.Bool("wait", false, "wait until the transaction is included")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errors.New("usage: mev " + commands["deploy"].usage)
+	}
+	code, initArgs, err := readDeploy(fs.Arg(0), *initJSON)
+	if err != nil {
+		return err
+	}
+
+	c, err := dial()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	ChainID, err := c.ChainID(ctx)
+	if err != nil {
+		return err
+	}
+	k, err := signer(wallet.NewKeystore(*ksDir), *from, *passFile, ChainID)
+	if err != nil {
+		return err
+	}
+	defer k.Clear()
+	seq, err := c.Seq(ctx, k.PublicKey().Address())
+	if err != nil {
+		return err
+	}
+	tx, err := wallet.NewDeployTx(ChainID, seq, uint64(time.Now().UnixNano()), code, initArgs)
+	if err != nil {
+		return err
+	}
+	s, err := wallet.Sign(k, tx)
+	if err != nil {
+		return err
+	}
+	h, err := s.Broadcast(ctx, c)
+	if err != nil {
+		return err
+	}
+	fmt.Println(h.String())
+	if *wait {
+		return waitAndPrint(c, h)
+	}
+	return nil
+}
+
+func runStatus(args []string) error {
+	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	wait := fs.Bool("wait", false, "wait until the transaction is included")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errors.New("usage: mev " + commands["status"].usage)
+	}
+	h := hash.HexToHash(fs.Arg(0))
+
+	c, err := dial()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if *wait {
+		return waitAndPrint(c, h)
+	}
+	receipt, err := wallet.Receipt(ctx, c, h)
+	if errors.Cause(err) == client.ErrNotFound {
+		fmt.Println("pending")
+		return nil
+	}
+	if receipt != nil {
+		if err := printJSON(receipt); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// runSign signs the transaction without the node, the chain id and the sequence are given
+func runSign(args []string) error {
+	fs := flag.NewFlagSet("sign", flag.ExitOnError)
+	from := fs.String("from", "", "sender address")
+	passFile := fs.String("password", "", "passphrase file")
+	chainID := fs.String("chainid", "", "chain id")
+	seq := fs.Uint64("seq", 0, "sequence of the sender")
+	deploy := fs.Bool("deploy", false, "sign the wasm deploy transaction")
+	initJSON := fs.String("init", "", "json array of the init arguments of the deploy")
+	out := fs.String("out", "", "signed transaction file, stdout without it")
+	fs.Parse(args)
+
+	ChainID, ok := new(big.Int).SetString(*chainID, 0)
+	if !ok {
+		return errors.New("need -chainid")
+	}
+	k, err := signer(wallet.NewKeystore(*ksDir), *from, *passFile, ChainID)
+	if err != nil {
+		return err
+	}
+	defer k.Clear()
+
+	timestamp := uint64(time.Now().UnixNano())
+	var s *wallet.SignedTx
+	if *deploy {
+		if fs.NArg() != 1 {
+			return errors.New("usage: mev " + commands["sign"].usage)
+		}
+		code, initArgs, err := readDeploy(fs.Arg(0), *initJSON)
+		if err != nil {
+			return err
+		}
+		tx, err := wallet.NewDeployTx(ChainID, *seq, timestamp, code, initArgs)
+		if err != nil {
+			return err
+		}
+		if s, err = wallet.Sign(k, tx); err != nil {
+			return err
+		}
+	} else {
+		if fs.NArg() < 2 {
+			return errors.New("usage: mev " + commands["sign"].usage)
+		}
+		contract, err := common.ParseAddress(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		params, err := wallet.ParseArgs(fs.Args()[2:])
+		if err != nil {
+			return err
+		}
+		if s, err = wallet.Sign(k, wallet.NewTx(ChainID, *seq, timestamp, contract, fs.Arg(1), params)); err != nil {
+			return err
+		}
+	}
+	if len(*out) == 0 {
+		return printJSON(s)
+	}
+	if err := wallet.WriteSignedTx(*out, s); err != nil {
+		return err
+	}
+	fmt.Println(s.Hash.String())
+	return nil
+}
+
+func runBroadcast(args []string) error {
+	fs := flag.NewFlagSet("broadcast", flag.ExitOnError)
+	wait := fs.Bool("wait", false, "wait until the transaction is included")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errors.New("usage: mev " + commands["broadcast"].usage)
+	}
+	s, err := wallet.ReadSignedTx(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	c, err := dial()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	h, err := s.Broadcast(ctx, c)
+	if err != nil {
+		return err
+	}
+	fmt.Println(h.String())
+	if *wait {
+		return waitAndPrint(c, h)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/service/pack"
+)
+
+// ParseArg parses the argument of the contract method which is written as type:value
+//
+// the argument without the type is a string and the native contracts convert the string to the type of the method,
+// the evm contracts need the type because the arguments are encoded by the type
+//
+// types : string, address, addresses(comma separated), amount(decimal of the token unit), uint256(*big.Int), bool, bytes(hex), hash,
+// uint, uint8, uint16, uint32, uint64, int, int16, int32, int64
+func ParseArg(arg string) (interface{}, error) {
+	idx := strings.Index(arg, ":")
+	if idx < 0 {
+		return arg, nil
+	}
+	typ, v := arg[:idx], arg[idx+1:]
+	switch typ {
+	case "string":
+		return v, nil
+	case "address":
+		addr, err := common.ParseAddress(v)
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidArgValue, arg)
+		}
+		return addr, nil
+	case "addresses":
+		addrs := []common.Address{}
+		if len(v) > 0 {
+			for _, s := range strings.Split(v, ",") {
+				addr, err := common.ParseAddress(strings.TrimSpace(s))
+				if err != nil {
+					return nil, errors.Wrap(ErrInvalidArgValue, arg)
+				}
+				addrs = append(addrs, addr)
+			}
+		}
+		return addrs, nil
+	case "amount":
+		am, err := amount.ParseAmount(v)
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidArgValue, arg)
+		}
+		return am, nil
+	case "uint256", "bigint":
+		bi, ok := new(big.Int).SetString(v, 0)
+		if !ok {
+			return nil, errors.Wrap(ErrInvalidArgValue, arg)
+		}
+		return bi, nil
+	case "bool":
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidArgValue, arg)
+		}
+		return b, nil
+	case "bytes":
+		bs, err := hex.DecodeString(strings.TrimPrefix(v, "0x"))
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidArgValue, arg)
+		}
+		return bs, nil
+	case "hash":
+		bs, err := hex.DecodeString(strings.TrimPrefix(v, "0x"))
+		if err != nil || len(bs) != len(hash.Hash256{}) {
+			return nil, errors.Wrap(ErrInvalidArgValue, arg)
+		}
+		return hash.HexToHash(v), nil
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		bits := 64
+		if typ != "uint" {
+			bits, _ = strconv.Atoi(typ[4:])
+		}
+		n, err := strconv.ParseUint(v, 0, bits)
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidArgValue, arg)
+		}
+		switch typ {
+		case "uint":
+			return uint(n), nil
+		case "uint8":
+			return uint8(n), nil
+		case "uint16":
+			return uint16(n), nil
+		case "uint32":
+			return uint32(n), nil
+		default:
+			return n, nil
+		}
+	case "int", "int16", "int32", "int64":
+		bits := 64
+		if typ != "int" {
+			bits, _ = strconv.Atoi(typ[3:])
+		}
+		n, err := strconv.ParseInt(v, 0, bits)
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidArgValue, arg)
+		}
+		switch typ {
+		case "int":
+			return int(n), nil
+		case "int16":
+			return int16(n), nil
+		case "int32":
+			return int32(n), nil
+		default:
+			return n, nil
+		}
+	default:
+		return nil, errors.Wrap(ErrInvalidArgType, typ)
+	}
+}
+
+// ParseArgs parses the arguments by ParseArg
+func ParseArgs(args []string) ([]interface{}, error) {
+	vs := make([]interface{}, 0, len(args))
+	for _, arg := range args {
+		v, err := ParseArg(arg)
+		if err != nil {
+			return nil, err
+		}
+		vs = append(vs, v)
+	}
+	return vs, nil
+}
+
+// ViewParams converts the arguments to the params of view.call which are converted to the types of the method by the node
+func ViewParams(args []interface{}) []interface{} {
+	params := make([]interface{}, 0, len(args))
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case common.Address:
+			params = append(params, v.String())
+		case []common.Address:
+			addrs := make([]interface{}, 0, len(v))
+			for _, addr := range v {
+				addrs = append(addrs, addr.String())
+			}
+			params = append(params, addrs)
+		case *amount.Amount:
+			params = append(params, v.String())
+		case *big.Int:
+			params = append(params, v.String())
+		case bool:
+			params = append(params, strconv.FormatBool(v))
+		case []byte:
+			params = append(params, hex.EncodeToString(v))
+		case hash.Hash256:
+			params = append(params, v.String())
+		default:
+			params = append(params, v)
+		}
+	}
+	return params
+}
+
+// EvmCallData returns the call data of the evm contract by the service/pack encoding, it is the same as the one of the interactor
+func EvmCallData(method string, args []interface{}) ([]byte, error) {
+	if len(method) == 0 {
+		return nil, errors.WithStack(ErrInvalidArgValue)
+	}
+	strArgs, err := pack.ArgsToString2(args)
+	if err != nil {
+		return nil, err
+	}
+	lMethod := strings.ToLower(method[:1]) + method[1:]
+	data := crypto.Keccak256([]byte(lMethod + "(" + strArgs + ")"))[:4]
+	bs, err := pack.Pack(args)
+	if err != nil {
+		return nil, err
+	}
+	return append(data, bs...), nil
+}
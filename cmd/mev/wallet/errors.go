@@ -0,0 +1,16 @@
+package wallet
+
+import "errors"
+
+// errors
+var (
+	ErrNotExistAccount   = errors.New("not exist account")
+	ErrExistAccount      = errors.New("exist account")
+	ErrInvalidArgType    = errors.New("invalid argument type")
+	ErrInvalidArgValue   = errors.New("invalid argument value")
+	ErrInvalidSignedTx   = errors.New("invalid signed transaction")
+	ErrInvalidSignature  = errors.New("invalid signature")
+	ErrMismatchChainID   = errors.New("mismatch chain id")
+	ErrMismatchTxHash    = errors.New("mismatch transaction hash")
+	ErrTransactionFailed = errors.New("transaction failed")
+)
@@ -0,0 +1,182 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/key"
+)
+
+// Keystore stores the keys encrypted by the passphrase, a file per account
+//
+// the files are the web3 secret storage format of go-ethereum, so they are shared with geth and metamask
+type Keystore struct {
+	dir     string
+	scryptN int
+	scryptP int
+}
+
+// NewKeystore returns a Keystore of the directory
+func NewKeystore(dir string) *Keystore {
+	return &Keystore{
+		dir:     dir,
+		scryptN: keystore.StandardScryptN,
+		scryptP: keystore.StandardScryptP,
+	}
+}
+
+// NewLightKeystore returns a Keystore which uses the light scrypt parameters, it is for tests and devnets
+func NewLightKeystore(dir string) *Keystore {
+	return &Keystore{
+		dir:     dir,
+		scryptN: keystore.LightScryptN,
+		scryptP: keystore.LightScryptP,
+	}
+}
+
+type keyFile struct {
+	Address string `json:"address"`
+}
+
+func (ks *Keystore) files() (map[common.Address]string, error) {
+	fis, err := os.ReadDir(ks.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[common.Address]string{}, nil
+		}
+		return nil, errors.WithStack(err)
+	}
+	fileMap := map[common.Address]string{}
+	for _, fi := range fis {
+		if fi.IsDir() || strings.HasPrefix(fi.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(ks.dir, fi.Name())
+		bs, err := os.ReadFile(path)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		var kf keyFile
+		if err := json.Unmarshal(bs, &kf); err != nil || len(kf.Address) == 0 {
+			continue
+		}
+		fileMap[common.HexToAddress(kf.Address)] = path
+	}
+	return fileMap, nil
+}
+
+// Accounts returns the addresses of the stored keys in order
+func (ks *Keystore) Accounts() ([]common.Address, error) {
+	fileMap, err := ks.files()
+	if err != nil {
+		return nil, err
+	}
+	addrs := make([]common.Address, 0, len(fileMap))
+	for addr := range fileMap {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return strings.Compare(addrs[i].String(), addrs[j].String()) < 0
+	})
+	return addrs, nil
+}
+
+// NewAccount generates a key and stores it
+func (ks *Keystore) NewAccount(passphrase string) (common.Address, error) {
+	pk, err := crypto.GenerateKey()
+	if err != nil {
+		return common.Address{}, errors.WithStack(err)
+	}
+	return ks.Import(crypto.FromECDSA(pk), passphrase)
+}
+
+// Import stores the private key
+func (ks *Keystore) Import(priv []byte, passphrase string) (common.Address, error) {
+	pk, err := crypto.ToECDSA(priv)
+	if err != nil {
+		return common.Address{}, errors.WithStack(err)
+	}
+	addr := crypto.PubkeyToAddress(pk.PublicKey)
+
+	fileMap, err := ks.files()
+	if err != nil {
+		return common.Address{}, err
+	}
+	if _, has := fileMap[addr]; has {
+		return common.Address{}, errors.WithStack(ErrExistAccount)
+	}
+
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return common.Address{}, errors.WithStack(err)
+	}
+	bs, err := keystore.EncryptKey(&keystore.Key{
+		Id:         id,
+		Address:    addr,
+		PrivateKey: pk,
+	}, passphrase, ks.scryptN, ks.scryptP)
+	if err != nil {
+		return common.Address{}, errors.WithStack(err)
+	}
+	if err := os.MkdirAll(ks.dir, 0700); err != nil {
+		return common.Address{}, errors.WithStack(err)
+	}
+	name := "UTC--" + time.Now().UTC().Format("2006-01-02T15-04-05.000000000Z") + "--" + hex.EncodeToString(addr[:])
+	if err := os.WriteFile(filepath.Join(ks.dir, name), bs, 0600); err != nil {
+		return common.Address{}, errors.WithStack(err)
+	}
+	return addr, nil
+}
+
+// Export returns the private key of the account
+func (ks *Keystore) Export(addr common.Address, passphrase string) ([]byte, error) {
+	k, err := ks.decrypt(addr, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.FromECDSA(k.PrivateKey), nil
+}
+
+// Key returns the signing key of the account for the chain
+func (ks *Keystore) Key(addr common.Address, passphrase string, ChainID *big.Int) (*key.MemoryKey, error) {
+	k, err := ks.decrypt(addr, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return key.NewMemoryKeyFromBytes(ChainID, crypto.FromECDSA(k.PrivateKey))
+}
+
+func (ks *Keystore) decrypt(addr common.Address, passphrase string) (*keystore.Key, error) {
+	fileMap, err := ks.files()
+	if err != nil {
+		return nil, err
+	}
+	path, has := fileMap[addr]
+	if !has {
+		return nil, errors.WithStack(ErrNotExistAccount)
+	}
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	k, err := keystore.DecryptKey(bs, passphrase)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if k.Address != addr {
+		return nil, errors.WithStack(ErrNotExistAccount)
+	}
+	return k, nil
+}
@@ -0,0 +1,177 @@
+package test
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/client"
+	"github.com/meverselabs/meverse/cmd/mev/wallet"
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/core/types"
+
+	. "github.com/meverselabs/meverse/tests/lib"
+)
+
+func TestKeystore(t *testing.T) {
+	ks := wallet.NewLightKeystore(t.TempDir())
+
+	addr, err := ks.NewAccount("pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	k, err := ks.Key(addr, "pass", ChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k.PublicKey().Address() != addr {
+		t.Fatalf("key address %v, want %v", k.PublicKey().Address(), addr)
+	}
+	if _, err := ks.Key(addr, "wrong", ChainID); err == nil {
+		t.Fatal("wrong passphrase is accepted")
+	}
+
+	priv, err := ks.Export(addr, "pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks2 := wallet.NewLightKeystore(t.TempDir())
+	if addr2, err := ks2.Import(priv, "other"); err != nil {
+		t.Fatal(err)
+	} else if addr2 != addr {
+		t.Fatalf("imported address %v, want %v", addr2, addr)
+	}
+	if _, err := ks2.Import(priv, "other"); errors.Cause(err) != wallet.ErrExistAccount {
+		t.Fatalf("duplicated import %v", err)
+	}
+	if addrs, err := ks2.Accounts(); err != nil || len(addrs) != 1 || addrs[0] != addr {
+		t.Fatalf("accounts %v %v", addrs, err)
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	vs, err := wallet.ParseArgs([]string{
+		"plain",
+		"address:0x0000000000000000000000000000000000000001",
+		"amount:1.5",
+		"uint256:0x10",
+		"uint32:7",
+		"bool:true",
+		"bytes:0x0102",
+		"addresses:0x0000000000000000000000000000000000000001,0x0000000000000000000000000000000000000002",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vs[0].(string) != "plain" {
+		t.Fatal("plain argument is not a string")
+	}
+	if vs[1].(common.Address) != common.HexToAddress("0x01") {
+		t.Fatal("invalid address")
+	}
+	if vs[2].(*amount.Amount).Cmp(amount.MustParseAmount("1.5").Int) != 0 {
+		t.Fatal("invalid amount")
+	}
+	if vs[3].(*big.Int).Int64() != 16 || vs[4].(uint32) != 7 || !vs[5].(bool) || len(vs[6].([]byte)) != 2 || len(vs[7].([]common.Address)) != 2 {
+		t.Fatalf("invalid arguments %v", vs)
+	}
+	if _, err := wallet.ParseArg("float:1.5"); errors.Cause(err) != wallet.ErrInvalidArgType {
+		t.Fatalf("unknown type %v", err)
+	}
+	if _, err := wallet.ParseArg("uint8:300"); errors.Cause(err) != wallet.ErrInvalidArgValue {
+		t.Fatalf("overflow value %v", err)
+	}
+
+	data, err := wallet.EvmCallData("Transfer", []interface{}{common.HexToAddress("0x01"), big.NewInt(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(data[:4]) != "a9059cbb" || len(data) != 4+32*2 {
+		t.Fatalf("invalid call data %x", data)
+	}
+}
+
+func TestSignAndBroadcast(t *testing.T) {
+	userKeys, err := GetSingers(ChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice, bob := userKeys[0].PublicKey().Address(), userKeys[1].PublicKey().Address()
+
+	var mev *common.Address
+	tb := NewTestBlockChain(filepath.Join(t.TempDir(), "chain"), true, ChainID, Version, alice, func(ctx *types.Context, classMap map[string]uint64) error {
+		mev, err = MevInitialize(ctx, classMap, alice, map[common.Address]*amount.Amount{
+			alice: amount.NewAmount(1000000, 0),
+		})
+		return err
+	}, DefaultInitContextInfo)
+	defer tb.Close()
+
+	ctx := context.Background()
+	c := client.New(client.NewHandlerTransport(tb.HandleJRPC))
+	defer c.Close()
+
+	// the cold wallet signs without the node
+	tx := wallet.NewTx(ChainID, 0, uint64(time.Now().UnixNano()), *mev, "Transfer", []interface{}{bob, amount.NewAmount(5, 0)})
+	s, err := wallet.Sign(userKeys[0], tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "tx.json")
+	if err := wallet.WriteSignedTx(path, s); err != nil {
+		t.Fatal(err)
+	}
+	s, err = wallet.ReadSignedTx(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tampered := *s
+	tampered.From = bob
+	if _, err := tampered.Broadcast(ctx, c); errors.Cause(err) != wallet.ErrInvalidSignature {
+		t.Fatalf("tampered sender %v", err)
+	}
+	tampered = *s
+	tampered.ChainID = big.NewInt(1)
+	if _, err := tampered.Broadcast(ctx, c); err == nil {
+		t.Fatal("other chain transaction is broadcast")
+	}
+
+	h, err := s.Broadcast(ctx, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wallet.Receipt(ctx, c, h); errors.Cause(err) != client.ErrNotFound {
+		t.Fatalf("pending receipt %v", err)
+	}
+	tb.MustAddBlock(nil)
+
+	wctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	receipt, err := wallet.WaitReceipt(wctx, c, h, 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt["status"] != "0x1" {
+		t.Fatalf("receipt status %v", receipt["status"])
+	}
+
+	out, err := wallet.Call(ctx, c, *mev, "BalanceOf", []interface{}{bob}, common.Address{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 1 {
+		t.Fatalf("invalid call result %v", out)
+	}
+	if am, err := c.Balance(ctx, bob); err != nil {
+		t.Fatal(err)
+	} else if am.Cmp(amount.NewAmount(5, 0).Int) != 0 {
+		t.Fatalf("balance %v, want 5", am)
+	}
+}
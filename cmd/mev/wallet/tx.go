@@ -0,0 +1,310 @@
+package wallet
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/client"
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/common/key"
+	"github.com/meverselabs/meverse/core/chain"
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/meverselabs/meverse/service/apiserver/viewchain"
+)
+
+// kinds of the signed transaction
+const (
+	KindCall   = "call"
+	KindDeploy = "deploy"
+)
+
+// NewTx returns the transaction calling the method of the contract, it is the same as the one of view.srtx
+func NewTx(ChainID *big.Int, seq uint64, timestamp uint64, to common.Address, method string, args []interface{}) *types.Transaction {
+	return &types.Transaction{
+		ChainID:   ChainID,
+		Timestamp: timestamp,
+		Seq:       seq,
+		To:        to,
+		Method:    method,
+		Args:      bin.TypeWriteAll(args...),
+		UseSeq:    true,
+	}
+}
+
+// NewDeployTx returns the wasm deploy transaction of the code, it is the same as the one of view.sendWasmDeployTx
+func NewDeployTx(ChainID *big.Int, seq uint64, timestamp uint64, code []byte, initArgs []interface{}) (*types.Transaction, error) {
+	if initArgs == nil {
+		initArgs = []interface{}{}
+	}
+	bs, err := chain.WasmDeployArgs(code, initArgs)
+	if err != nil {
+		return nil, err
+	}
+	return &types.Transaction{
+		ChainID:   ChainID,
+		Version:   2,
+		Timestamp: timestamp,
+		Seq:       seq,
+		To:        common.ZeroAddr,
+		Method:    viewchain.WasmDeployMethod,
+		Args:      bs,
+		VmType:    types.Wasm,
+		UseSeq:    true,
+	}, nil
+}
+
+// SignedTx is the signed transaction which is broadcast later, it keeps the cold wallet apart from the network
+type SignedTx struct {
+	Kind    string         `json:"kind"`
+	ChainID *big.Int       `json:"chainId"`
+	From    common.Address `json:"from"`
+	Hash    hash.Hash256   `json:"hash"`
+	Body    string         `json:"body"`
+	Sig     string         `json:"sig"`
+}
+
+// Sign signs the transaction by the key
+func Sign(k key.Key, tx *types.Transaction) (*SignedTx, error) {
+	sig, err := k.Sign(tx.HashSig())
+	if err != nil {
+		return nil, err
+	}
+	s := &SignedTx{
+		Kind:    KindCall,
+		ChainID: tx.ChainID,
+		From:    k.PublicKey().Address(),
+		Hash:    tx.HashSig(),
+		Sig:     hex.EncodeToString(sig),
+	}
+	if tx.VmType == types.Wasm && tx.Method == viewchain.WasmDeployMethod {
+		s.Kind = KindDeploy
+		if tx.UseSeq {
+			s.Body = hex.EncodeToString(bin.TypeWriteAll(tx.Timestamp, tx.Args, tx.Seq))
+		} else {
+			s.Body = hex.EncodeToString(bin.TypeWriteAll(tx.Timestamp, tx.Args))
+		}
+	} else {
+		s.Body = client.TxBody(tx)
+	}
+	return s, nil
+}
+
+// Tx returns the transaction of the body
+func (s *SignedTx) Tx() (*types.Transaction, error) {
+	if s.ChainID == nil {
+		return nil, errors.WithStack(ErrInvalidSignedTx)
+	}
+	bs, err := hex.DecodeString(s.Body)
+	if err != nil {
+		return nil, errors.WithStack(ErrInvalidSignedTx)
+	}
+	is, err := bin.TypeReadAll(bs, -1)
+	if err != nil {
+		return nil, errors.WithStack(ErrInvalidSignedTx)
+	}
+	switch s.Kind {
+	case KindCall:
+		if len(is) != 4 && len(is) != 5 {
+			return nil, errors.WithStack(ErrInvalidSignedTx)
+		}
+		method, ok1 := is[0].(string)
+		to, ok2 := is[1].(common.Address)
+		timestamp, ok3 := is[2].(uint64)
+		args, ok4 := is[3].([]byte)
+		if !ok1 || !ok2 || !ok3 || !ok4 {
+			return nil, errors.WithStack(ErrInvalidSignedTx)
+		}
+		tx := &types.Transaction{
+			ChainID:   s.ChainID,
+			Timestamp: timestamp,
+			To:        to,
+			Method:    method,
+			Args:      args,
+		}
+		if len(is) == 5 {
+			seq, ok := is[4].(uint64)
+			if !ok {
+				return nil, errors.WithStack(ErrInvalidSignedTx)
+			}
+			tx.Seq = seq
+			tx.UseSeq = true
+		}
+		return tx, nil
+	case KindDeploy:
+		if len(is) != 2 && len(is) != 3 {
+			return nil, errors.WithStack(ErrInvalidSignedTx)
+		}
+		timestamp, ok1 := is[0].(uint64)
+		args, ok2 := is[1].([]byte)
+		if !ok1 || !ok2 {
+			return nil, errors.WithStack(ErrInvalidSignedTx)
+		}
+		tx := &types.Transaction{
+			ChainID:   s.ChainID,
+			Version:   2,
+			Timestamp: timestamp,
+			To:        common.ZeroAddr,
+			Method:    viewchain.WasmDeployMethod,
+			Args:      args,
+			VmType:    types.Wasm,
+		}
+		if len(is) == 3 {
+			seq, ok := is[2].(uint64)
+			if !ok {
+				return nil, errors.WithStack(ErrInvalidSignedTx)
+			}
+			tx.Seq = seq
+			tx.UseSeq = true
+		}
+		return tx, nil
+	default:
+		return nil, errors.WithStack(ErrInvalidSignedTx)
+	}
+}
+
+// Verify checks that the body matches the hash and the signature is made by the sender
+func (s *SignedTx) Verify() error {
+	tx, err := s.Tx()
+	if err != nil {
+		return err
+	}
+	if tx.HashSig() != s.Hash {
+		return errors.WithStack(ErrMismatchTxHash)
+	}
+	sig, err := hex.DecodeString(s.Sig)
+	if err != nil {
+		return errors.WithStack(ErrInvalidSignature)
+	}
+	pubkey, err := common.RecoverPubkey(s.ChainID, s.Hash, sig)
+	if err != nil {
+		return errors.WithStack(ErrInvalidSignature)
+	}
+	if pubkey.Address() != s.From {
+		return errors.WithStack(ErrInvalidSignature)
+	}
+	return nil
+}
+
+// Broadcast verifies and sends the transaction to the node of the same chain
+func (s *SignedTx) Broadcast(ctx context.Context, c *client.Client) (hash.Hash256, error) {
+	if err := s.Verify(); err != nil {
+		return hash.Hash256{}, err
+	}
+	ChainID, err := c.ChainID(ctx)
+	if err != nil {
+		return hash.Hash256{}, err
+	}
+	if ChainID.Cmp(s.ChainID) != 0 {
+		return hash.Hash256{}, errors.WithStack(ErrMismatchChainID)
+	}
+	sig, _ := hex.DecodeString(s.Sig)
+
+	var h hash.Hash256
+	if s.Kind == KindDeploy {
+		h, err = c.SendWasmDeployTx(ctx, sig, s.Body)
+	} else {
+		h, err = c.SendRawTx(ctx, sig, s.Body)
+	}
+	if err != nil {
+		return hash.Hash256{}, err
+	}
+	if h != s.Hash {
+		return hash.Hash256{}, errors.WithStack(ErrMismatchTxHash)
+	}
+	return h, nil
+}
+
+// WriteSignedTx writes the signed transaction to the file
+func WriteSignedTx(path string, s *SignedTx) error {
+	bs, err := json.MarshalIndent(s, "", "\t")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := os.WriteFile(path, bs, 0600); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// ReadSignedTx reads the signed transaction of the file
+func ReadSignedTx(path string) (*SignedTx, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	s := &SignedTx{}
+	if err := json.Unmarshal(bs, s); err != nil {
+		return nil, errors.Wrap(ErrInvalidSignedTx, err.Error())
+	}
+	return s, nil
+}
+
+// Receipt returns the receipt of the transaction, it returns client.ErrNotFound until the transaction is included
+//
+// the node does not keep the failed transactions in the blocks, so the error of the node means the failure of the transaction
+func Receipt(ctx context.Context, c *client.Client, h hash.Hash256) (map[string]interface{}, error) {
+	receipt, err := c.EthTransactionReceipt(ctx, h)
+	if err != nil {
+		if e, ok := errors.Cause(err).(*client.Error); ok {
+			return nil, errors.Wrap(ErrTransactionFailed, e.Message)
+		}
+		return nil, err
+	}
+	if status, _ := receipt["status"].(string); status == "0x0" {
+		return receipt, errors.WithStack(ErrTransactionFailed)
+	}
+	return receipt, nil
+}
+
+// WaitReceipt polls the receipt of the transaction at the interval until it is included or failed
+func WaitReceipt(ctx context.Context, c *client.Client, h hash.Hash256, interval time.Duration) (map[string]interface{}, error) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		receipt, err := Receipt(ctx, c, h)
+		if errors.Cause(err) != client.ErrNotFound {
+			return receipt, err
+		}
+		timer.Reset(interval)
+	}
+}
+
+// Call executes the method of the contract without a transaction
+//
+// the native contracts are called by view.call, the evm contracts are called by eth_call with the service/pack encoding
+// and the result is the returned bytes
+func Call(ctx context.Context, c *client.Client, contract common.Address, method string, args []interface{}, from common.Address) ([]interface{}, error) {
+	native, err := c.IsContract(ctx, contract)
+	if err != nil {
+		return nil, err
+	}
+	if native {
+		return c.ViewCall(ctx, contract, method, ViewParams(args), from)
+	}
+	data, err := EvmCallData(method, args)
+	if err != nil {
+		return nil, err
+	}
+	bs, err := c.EthCall(ctx, &client.CallMsg{
+		From: from,
+		To:   contract,
+		Data: data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{"0x" + hex.EncodeToString(bs)}, nil
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"database/sql"
 	"encoding/hex"
 	"flag"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"syscall"
 
 	"github.com/meverselabs/meverse/cmd/app"
@@ -27,7 +29,9 @@ import (
 	"github.com/meverselabs/meverse/service/apiserver/viewchain"
 	"github.com/meverselabs/meverse/service/apiserver/zipcontext"
 	"github.com/meverselabs/meverse/service/bloomservice"
+	"github.com/meverselabs/meverse/service/sqlsink"
 	"github.com/meverselabs/meverse/service/txsearch"
+	"github.com/meverselabs/meverse/service/webhook"
 )
 
 // Config is a configuration for the cmd
@@ -42,6 +46,17 @@ type Config struct {
 	Port            int
 	RPCPort         int
 	StoreRoot       string
+	Genesis         string
+	ExecuteWorkers  int
+	Reindex         []string
+	ReindexFrom     uint32
+	ReindexTo       uint32
+	ReindexWorkers  int
+	SQLSinkDriver   string
+	SQLSinkDSN      string
+	WebhookAPIKey   string
+	WebhookWorkers  int
+	WebhookAttempts int
 }
 
 func main() {
@@ -71,6 +86,16 @@ func main() {
 	if err := config.LoadFile(cfgPath, &cfg); err != nil {
 		panic(err)
 	}
+	var gen *app.GenesisConfig
+	if len(cfg.Genesis) > 0 {
+		g, err := app.LoadGenesis(cfg.Genesis)
+		if err != nil {
+			panic(err)
+		}
+		gen = g
+		ChainID = gen.ChainIDInt()
+		Version = gen.ChainVersion()
+	}
 	if len(cfg.StoreRoot) == 0 {
 		cfg.StoreRoot = "./ndata"
 	}
@@ -165,7 +190,11 @@ func main() {
 		}
 	}
 
+	if gen != nil {
+		ObserverKeys = gen.ObserverKeys
+	}
 	cn := chain.NewChain(ObserverKeys, st, "")
+	cn.SetExecuteWorkers(cfg.ExecuteWorkers)
 	rpcapi := apiserver.NewAPIServer()
 	zipContext := zipcontext.NewZipContextService(rpcapi, st, "./zipcontext/", 172800)
 	bs, err := bloomservice.NewBloomBitService(cn, cfg.StoreRoot+"/_bloombits", params.BloomBitsBlocks, params.BloomConfirms)
@@ -177,9 +206,31 @@ func main() {
 	cn.MustAddService(rpcapi)
 	cn.MustAddService(zipContext)
 	cn.MustAddService(bs)
+	if len(cfg.SQLSinkDriver) > 0 {
+		sk, err := openSQLSink(cfg.SQLSinkDriver, cfg.SQLSinkDSN, st)
+		if err != nil {
+			panic(err)
+		}
+		cn.MustAddService(sk)
+	}
+	if len(cfg.WebhookAPIKey) > 0 {
+		ws, err := webhook.NewWebhookService(cfg.StoreRoot+"/_webhook", rpcapi, st, webhook.Config{
+			APIKey:      cfg.WebhookAPIKey,
+			Workers:     cfg.WebhookWorkers,
+			MaxAttempts: cfg.WebhookAttempts,
+		})
+		if err != nil {
+			panic(err)
+		}
+		cn.MustAddService(ws)
+	}
 
 	if cfg.InitHeight == 0 {
-		if err := cn.Init(app.Genesis()); err != nil {
+		genesis, err := app.GenesisOf(gen)
+		if err != nil {
+			panic(err)
+		}
+		if err := cn.Init(genesis); err != nil {
 			panic(err)
 		}
 	} else {
@@ -206,6 +257,12 @@ func main() {
 		panic(err)
 	}
 
+	for _, target := range cfg.Reindex {
+		if err := startReindex(target, ts, bs, cfg.ReindexFrom, cfg.ReindexTo, cfg.ReindexWorkers); err != nil {
+			fmt.Println("reindex", target, err)
+		}
+	}
+
 	nd := p2p.NewNode(ChainID, ndkey, SeedNodeMap, cn, cfg.StoreRoot+"/peer")
 	if err := nd.Init(); err != nil {
 		panic(err)
@@ -220,3 +277,33 @@ func main() {
 	go nd.Run(":" + strconv.Itoa(cfg.Port))
 	cm.Wait()
 }
+
+// startReindex starts the reindex of the target, which is txsearch, txsearch:<tag> or bloombits
+func startReindex(target string, ts *txsearch.TxSearch, bs *bloomservice.BloomBitService, from, to uint32, workers int) error {
+	if to == 0 {
+		to = ^uint32(0)
+	}
+	switch {
+	case target == "txsearch":
+		return ts.Reindex("", from, to, workers)
+	case strings.HasPrefix(target, "txsearch:"):
+		return ts.Reindex(strings.TrimPrefix(target, "txsearch:"), from, to, workers)
+	case target == "bloombits":
+		return bs.Reindex(from, to, workers)
+	default:
+		return fmt.Errorf("unknown reindex target %v", target)
+	}
+}
+
+// openSQLSink opens the database of the sink, the driver is linked by the build tag of it (sqlite or postgres)
+func openSQLSink(driver string, dsn string, st *chain.Store) (*sqlsink.SQLSink, error) {
+	dialect, err := sqlsink.DialectOf(driver)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	return sqlsink.NewSQLSink(db, dialect, st)
+}
@@ -0,0 +1,6 @@
+//go:build postgres
+
+package main
+
+// the postgres driver of the sql sink
+import _ "github.com/lib/pq"
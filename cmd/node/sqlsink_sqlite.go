@@ -0,0 +1,6 @@
+//go:build sqlite
+
+package main
+
+// the sqlite3 driver of the sql sink
+import _ "github.com/mattn/go-sqlite3"
@@ -16,6 +16,7 @@ import (
 	"github.com/meverselabs/meverse/common"
 	"github.com/meverselabs/meverse/common/hash"
 	"github.com/meverselabs/meverse/common/key"
+	"github.com/meverselabs/meverse/common/threshold"
 	"github.com/meverselabs/meverse/core/chain"
 	"github.com/meverselabs/meverse/core/piledb"
 	"github.com/meverselabs/meverse/core/types"
@@ -35,6 +36,10 @@ type Config struct {
 	Port            int
 	GeneratorPort   int
 	StoreRoot       string
+	Genesis         string
+	ExecuteWorkers  int
+	FairOrderKey    string
+	FairOrderShare  string
 }
 
 func main() {
@@ -45,6 +50,16 @@ func main() {
 	if err := config.LoadFile("./config.toml", &cfg); err != nil {
 		panic(err)
 	}
+	var gen *app.GenesisConfig
+	if len(cfg.Genesis) > 0 {
+		g, err := app.LoadGenesis(cfg.Genesis)
+		if err != nil {
+			panic(err)
+		}
+		gen = g
+		ChainID = gen.ChainIDInt()
+		Version = gen.ChainVersion()
+	}
 	versionInfo1 := flag.Bool("v", false, "version info")
 	versionInfo2 := flag.Bool("version", false, "version info")
 	flag.Parse()
@@ -134,9 +149,17 @@ func main() {
 		}
 	}
 
+	if gen != nil {
+		ObserverKeys = gen.ObserverKeys
+	}
 	cn := chain.NewChain(ObserverKeys, st, "")
+	cn.SetExecuteWorkers(cfg.ExecuteWorkers)
 	if cfg.InitHeight == 0 {
-		if err := cn.Init(app.Genesis()); err != nil {
+		genesis, err := app.GenesisOf(gen)
+		if err != nil {
+			panic(err)
+		}
+		if err := cn.Init(genesis); err != nil {
 			panic(err)
 		}
 	} else {
@@ -167,6 +190,21 @@ func main() {
 	if err := ob.Init(); err != nil {
 		panic(err)
 	}
+	if len(cfg.FairOrderKey) > 0 {
+		pk, err := threshold.ParsePublicKey(cfg.FairOrderKey)
+		if err != nil {
+			panic(err)
+		}
+		var share *threshold.SecretShare
+		if len(cfg.FairOrderShare) > 0 {
+			if share, err = threshold.ParseSecretShare(cfg.FairOrderShare); err != nil {
+				panic(err)
+			}
+		}
+		if err := ob.SetFairOrder(pk, share); err != nil {
+			panic(err)
+		}
+	}
 	cm.RemoveAll()
 	cm.Add("observer", ob)
 
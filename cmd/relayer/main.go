@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"log"
+	"math/big"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/meverselabs/meverse/cmd/config"
+	"github.com/meverselabs/meverse/cmd/relayer/relay"
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/key"
+)
+
+// Config is a configuration for the cmd
+type Config struct {
+	ChainID              uint64
+	RPC                  string
+	KeyHex               string
+	Bridge               string
+	Gateway              string
+	Platforms            map[string]string
+	Confirmations        uint64
+	StartHeight          uint64
+	ForeignChain         string
+	ForeignChainID       uint64
+	ForeignRPC           string
+	ForeignKeyHex        string
+	ForeignBridge        string
+	ForeignGatewayToken  string
+	ForeignGateway       string
+	ForeignPlatform      string
+	ForeignGasLimit      uint64
+	ForeignConfirmations uint64
+	ForeignStartHeight   uint64
+	ToForeignTokens      map[string]string
+	ToMeverseTokens      map[string]string
+	CheckpointPath       string
+	PollInterval         int
+	Retry                int
+}
+
+func main() {
+	var cfg Config
+	if err := config.LoadFile("./config.toml", &cfg); err != nil {
+		panic(err)
+	}
+	if cfg.ChainID == 0 {
+		cfg.ChainID = 0x1D5E
+	}
+	if len(cfg.CheckpointPath) == 0 {
+		cfg.CheckpointPath = "./rdata/checkpoint.json"
+	}
+	if cfg.PollInterval == 0 {
+		cfg.PollInterval = 5
+	}
+	ChainID := new(big.Int).SetUint64(cfg.ChainID)
+
+	if len(cfg.KeyHex) == 0 {
+		panic("not exist relayer key")
+	}
+	var mkey key.Key
+	if bs, err := hex.DecodeString(cfg.KeyHex); err != nil {
+		panic(err)
+	} else if Key, err := key.NewMemoryKeyFromBytes(ChainID, bs); err != nil {
+		panic(err)
+	} else {
+		mkey = Key
+	}
+	if len(cfg.ForeignKeyHex) == 0 {
+		cfg.ForeignKeyHex = cfg.KeyHex
+	}
+	fkey, err := crypto.HexToECDSA(cfg.ForeignKeyHex)
+	if err != nil {
+		panic(err)
+	}
+	if len(cfg.ForeignGateway) == 0 {
+		cfg.ForeignGateway = crypto.PubkeyToAddress(fkey.PublicKey).String()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc,
+		syscall.SIGHUP,
+		syscall.SIGINT,
+		syscall.SIGTERM,
+		syscall.SIGQUIT)
+	go func() {
+		<-sigc
+		cancel()
+	}()
+
+	ec, err := ethclient.DialContext(ctx, cfg.ForeignRPC)
+	if err != nil {
+		panic(err)
+	}
+	defer ec.Close()
+
+	cp, err := relay.LoadCheckpoint(cfg.CheckpointPath)
+	if err != nil {
+		panic(err)
+	}
+
+	foreign := strings.ToUpper(cfg.ForeignChain)
+	rcfg := &relay.Config{
+		StartHeights: map[string]uint64{
+			relay.MeverseChain: cfg.StartHeight,
+			foreign:            cfg.ForeignStartHeight,
+		},
+		Confirmations: map[string]uint64{
+			relay.MeverseChain: cfg.Confirmations,
+			foreign:            cfg.ForeignConfirmations,
+		},
+		Tokens: map[string]map[common.Address]common.Address{
+			relay.MeverseChain: tokenMap(cfg.ToForeignTokens),
+			foreign:            tokenMap(cfg.ToMeverseTokens),
+		},
+		Retry: cfg.Retry,
+	}
+	rl := relay.NewRelayer(rcfg, cp)
+
+	mcfg := &relay.MeverseConfig{
+		ChainID:   ChainID,
+		Bridge:    parseAddress(cfg.Bridge),
+		Gateway:   parseAddress(cfg.Gateway),
+		Platforms: map[string]string{},
+	}
+	for k, v := range cfg.Platforms {
+		mcfg.Platforms[strings.ToLower(k)] = strings.ToUpper(v)
+	}
+	client := relay.NewRPCClient(cfg.RPC)
+	rl.AddWatcher(relay.NewMeverseWatcher(mcfg, client))
+	rl.AddSubmitter(relay.NewMeverseSubmitter(mcfg, client, mkey))
+
+	ecfg := &relay.EVMConfig{
+		Chain:        foreign,
+		ChainID:      new(big.Int).SetUint64(cfg.ForeignChainID),
+		Bridge:       parseAddress(cfg.ForeignBridge),
+		GatewayToken: parseAddress(cfg.ForeignGatewayToken),
+		Gateway:      parseAddress(cfg.ForeignGateway),
+		Platform:     cfg.ForeignPlatform,
+		GasLimit:     cfg.ForeignGasLimit,
+	}
+	rl.AddWatcher(relay.NewEVMWatcher(ecfg, ec))
+	rl.AddSubmitter(relay.NewEVMSubmitter(ecfg, ec, fkey))
+
+	log.Println("relayer", mkey.PublicKey().Address().String(), "MEVERSE <->", foreign)
+	if err := rl.Run(ctx, time.Duration(cfg.PollInterval)*time.Second); err != nil && err != context.Canceled {
+		panic(err)
+	}
+}
+
+func parseAddress(s string) common.Address {
+	if len(s) == 0 {
+		return common.ZeroAddr
+	}
+	addr, err := common.ParseAddress(s)
+	if err != nil {
+		panic(err)
+	}
+	return addr
+}
+
+func tokenMap(m map[string]string) map[common.Address]common.Address {
+	tokens := map[common.Address]common.Address{}
+	for k, v := range m {
+		tokens[parseAddress(k)] = parseAddress(v)
+	}
+	return tokens
+}
@@ -0,0 +1,197 @@
+package relay
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/meverselabs/meverse/common"
+)
+
+type doneMark struct {
+	Chain  string
+	Height uint64
+}
+
+type pendingTx struct {
+	TxID string
+	Time int64
+}
+
+type checkpointData struct {
+	Heights   map[string]uint64
+	Sequences map[string]uint64
+	Pending   map[string]pendingTx
+	Done      map[string]doneMark
+}
+
+// Checkpoint is the progress of the relayer which is saved in the file
+// it keeps the processed height of the chains, the sequences of the bridge transfers which are counted by the relayer
+// and the transactions which are broadcast but not confirmed
+type Checkpoint struct {
+	sync.Mutex
+	path string
+	data checkpointData
+}
+
+// LoadCheckpoint loads the checkpoint of the path, it returns an empty checkpoint if the file is not exist
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	cp := &Checkpoint{
+		path: path,
+		data: checkpointData{
+			Heights:   map[string]uint64{},
+			Sequences: map[string]uint64{},
+			Pending:   map[string]pendingTx{},
+			Done:      map[string]doneMark{},
+		},
+	}
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cp, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(bs, &cp.data); err != nil {
+		return nil, err
+	}
+	if cp.data.Heights == nil {
+		cp.data.Heights = map[string]uint64{}
+	}
+	if cp.data.Sequences == nil {
+		cp.data.Sequences = map[string]uint64{}
+	}
+	if cp.data.Pending == nil {
+		cp.data.Pending = map[string]pendingTx{}
+	}
+	if cp.data.Done == nil {
+		cp.data.Done = map[string]doneMark{}
+	}
+	return cp, nil
+}
+
+// Save writes the checkpoint to the file atomically
+func (cp *Checkpoint) Save() error {
+	cp.Lock()
+	defer cp.Unlock()
+
+	bs, err := json.MarshalIndent(&cp.data, "", "\t")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(cp.path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	tmp := cp.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(bs); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, cp.path)
+}
+
+// Height returns the last processed height of the chain
+func (cp *Checkpoint) Height(chain string) (uint64, bool) {
+	cp.Lock()
+	defer cp.Unlock()
+
+	h, has := cp.data.Heights[strings.ToUpper(chain)]
+	return h, has
+}
+
+// SetHeight sets the last processed height of the chain
+// the done marks of the chain under the height are removed because they are not scanned again
+func (cp *Checkpoint) SetHeight(chain string, height uint64) {
+	cp.Lock()
+	defer cp.Unlock()
+
+	chain = strings.ToUpper(chain)
+	cp.data.Heights[chain] = height
+	for k, m := range cp.data.Done {
+		if m.Chain == chain && m.Height <= height {
+			delete(cp.data.Done, k)
+		}
+	}
+}
+
+func sequenceKey(fromChain string, toChain string, user common.Address) string {
+	return strings.ToUpper(fromChain) + ">" + strings.ToUpper(toChain) + ":" + strings.ToLower(user.String())
+}
+
+// Sequence returns the last sequence of the user to the chain which is counted by the relayer
+func (cp *Checkpoint) Sequence(fromChain string, toChain string, user common.Address) (uint64, bool) {
+	cp.Lock()
+	defer cp.Unlock()
+
+	seq, has := cp.data.Sequences[sequenceKey(fromChain, toChain, user)]
+	return seq, has
+}
+
+// SetSequence sets the last sequence of the user to the chain
+func (cp *Checkpoint) SetSequence(fromChain string, toChain string, user common.Address, seq uint64) {
+	cp.Lock()
+	defer cp.Unlock()
+
+	cp.data.Sequences[sequenceKey(fromChain, toChain, user)] = seq
+}
+
+// Pending returns the transaction which is broadcast for the transfer and the time of it
+func (cp *Checkpoint) Pending(key string) (string, time.Time, bool) {
+	cp.Lock()
+	defer cp.Unlock()
+
+	p, has := cp.data.Pending[key]
+	if !has {
+		return "", time.Time{}, false
+	}
+	return p.TxID, time.Unix(p.Time, 0), true
+}
+
+// SetPending stores the transaction which is broadcast for the transfer
+func (cp *Checkpoint) SetPending(key string, txid string) {
+	cp.Lock()
+	defer cp.Unlock()
+
+	cp.data.Pending[key] = pendingTx{
+		TxID: txid,
+		Time: time.Now().Unix(),
+	}
+}
+
+// IsDone returns true if the transfer is already relayed
+func (cp *Checkpoint) IsDone(key string) bool {
+	cp.Lock()
+	defer cp.Unlock()
+
+	_, has := cp.data.Done[key]
+	return has
+}
+
+// SetDone marks the transfer as relayed
+func (cp *Checkpoint) SetDone(t *Transfer) {
+	cp.Lock()
+	defer cp.Unlock()
+
+	key := t.Key()
+	delete(cp.data.Pending, key)
+	cp.data.Done[key] = doneMark{
+		Chain:  strings.ToUpper(t.FromChain),
+		Height: t.Height,
+	}
+}
@@ -0,0 +1,16 @@
+package relay
+
+import "errors"
+
+// errors
+var (
+	ErrUnknownChain       = errors.New("unknown chain")
+	ErrUnknownToken       = errors.New("unknown token")
+	ErrUnknownPlatform    = errors.New("unknown platform")
+	ErrNotSupportedKind   = errors.New("not supported transfer kind")
+	ErrAlreadyDelivered   = errors.New("already delivered")
+	ErrPendingTransaction = errors.New("pending transaction")
+	ErrFailedTransaction  = errors.New("failed transaction")
+	ErrInvalidResponse    = errors.New("invalid response")
+	ErrInvalidSequence    = errors.New("invalid sequence")
+)
@@ -0,0 +1,303 @@
+package relay
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ecommon "github.com/ethereum/go-ethereum/common"
+	etypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/common"
+)
+
+// BridgeABI is the abi of the bridge in the foreign chain which is used by the relayer
+const BridgeABI = `[
+	{"type":"event","name":"SentToGateway","anonymous":false,"inputs":[
+		{"name":"_token","type":"address","indexed":false},
+		{"name":"_from","type":"address","indexed":false},
+		{"name":"_to","type":"address","indexed":false},
+		{"name":"_amount","type":"uint256","indexed":false},
+		{"name":"_path","type":"address[]","indexed":false},
+		{"name":"_summary","type":"bytes32","indexed":false},
+		{"name":"_sequence","type":"uint256","indexed":false}]},
+	{"type":"function","name":"sendFromGateway","stateMutability":"nonpayable","inputs":[
+		{"name":"token","type":"address"},
+		{"name":"to","type":"address"},
+		{"name":"amount","type":"uint256"},
+		{"name":"path","type":"address[]"},
+		{"name":"fromChain","type":"string"},
+		{"name":"summary","type":"bytes32"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"getSequenceTo","stateMutability":"view","inputs":[
+		{"name":"","type":"address"},
+		{"name":"","type":"string"}],"outputs":[{"name":"","type":"uint256"}]}
+]`
+
+// ERC20ABI is the abi of the gateway token in the foreign chain which is used by the relayer
+const ERC20ABI = `[
+	{"type":"event","name":"Transfer","anonymous":false,"inputs":[
+		{"name":"from","type":"address","indexed":true},
+		{"name":"to","type":"address","indexed":true},
+		{"name":"value","type":"uint256","indexed":false}]},
+	{"type":"function","name":"transfer","stateMutability":"nonpayable","inputs":[
+		{"name":"to","type":"address"},
+		{"name":"value","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]}
+]`
+
+var (
+	bridgeABI = mustParseABI(BridgeABI)
+	erc20ABI  = mustParseABI(ERC20ABI)
+)
+
+func mustParseABI(s string) abi.ABI {
+	a, err := abi.JSON(strings.NewReader(s))
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// EVMBackend is the client of the foreign chain, ethclient.Client and the simulated backend implement it
+type EVMBackend interface {
+	bind.ContractBackend
+	TransactionReceipt(ctx context.Context, txHash ecommon.Hash) (*etypes.Receipt, error)
+	TransactionByHash(ctx context.Context, txHash ecommon.Hash) (*etypes.Transaction, bool, error)
+}
+
+// EVMConfig is the configuration of the foreign evm chain
+type EVMConfig struct {
+	Chain        string         // chain name of the bridge
+	ChainID      *big.Int       // chain id which signs the transaction
+	Bridge       common.Address // bridge contract, zero address disables the bridge transfers
+	GatewayToken common.Address // token of the gateway, zero address disables the gateway transfers
+	Gateway      common.Address // deposit address of the gateway
+	Platform     string         // platform name of the gateway in the meverse
+	GasLimit     uint64         // zero estimates the gas
+}
+
+// EVMWatcher finds the transfers of the foreign evm chain
+type EVMWatcher struct {
+	cfg     *EVMConfig
+	backend EVMBackend
+}
+
+// NewEVMWatcher returns a EVMWatcher
+func NewEVMWatcher(cfg *EVMConfig, backend EVMBackend) *EVMWatcher {
+	return &EVMWatcher{
+		cfg:     cfg,
+		backend: backend,
+	}
+}
+
+// Chain returns the chain name
+func (w *EVMWatcher) Chain() string {
+	return w.cfg.Chain
+}
+
+// Height returns the latest block number
+func (w *EVMWatcher) Height(ctx context.Context) (uint64, error) {
+	h, err := w.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	return h.Number.Uint64(), nil
+}
+
+// Transfers returns SentToGateway of the bridge and the deposits to the gateway in the range
+func (w *EVMWatcher) Transfers(ctx context.Context, from uint64, to uint64) ([]*Transfer, error) {
+	ts := []*Transfer{}
+	if w.cfg.Bridge != common.ZeroAddr {
+		logs, err := w.backend.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(from),
+			ToBlock:   new(big.Int).SetUint64(to),
+			Addresses: []common.Address{w.cfg.Bridge},
+			Topics:    [][]ecommon.Hash{{bridgeABI.Events["SentToGateway"].ID}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range logs {
+			if l.Removed {
+				continue
+			}
+			t, err := w.parseSentToGateway(l)
+			if err != nil {
+				return nil, err
+			}
+			ts = append(ts, t)
+		}
+	}
+	if w.cfg.GatewayToken != common.ZeroAddr && w.cfg.Gateway != common.ZeroAddr {
+		logs, err := w.backend.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(from),
+			ToBlock:   new(big.Int).SetUint64(to),
+			Addresses: []common.Address{w.cfg.GatewayToken},
+			Topics:    [][]ecommon.Hash{{erc20ABI.Events["Transfer"].ID}, nil, {ecommon.BytesToHash(w.cfg.Gateway[:])}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range logs {
+			if l.Removed || len(l.Topics) != 3 {
+				continue
+			}
+			from := common.BytesToAddress(l.Topics[1][:])
+			ts = append(ts, &Transfer{
+				Kind:      GatewayDeposit,
+				ID:        l.TxHash.Hex(),
+				Height:    l.BlockNumber,
+				FromChain: w.cfg.Chain,
+				ToChain:   MeverseChain,
+				Platform:  w.cfg.Platform,
+				Token:     w.cfg.GatewayToken,
+				From:      from,
+				To:        from,
+				Amount:    new(big.Int).SetBytes(l.Data),
+			})
+		}
+	}
+	return ts, nil
+}
+
+func (w *EVMWatcher) parseSentToGateway(l etypes.Log) (*Transfer, error) {
+	var ev struct {
+		Token    common.Address
+		From     common.Address
+		To       common.Address
+		Amount   *big.Int
+		Path     []common.Address
+		Summary  [32]byte
+		Sequence *big.Int
+	}
+	if err := bridgeABI.UnpackIntoInterface(&ev, "SentToGateway", l.Data); err != nil {
+		return nil, err
+	}
+	return &Transfer{
+		Kind:      BridgeTransfer,
+		ID:        fmt.Sprintf("%v:%v", l.TxHash.Hex(), l.Index),
+		Height:    l.BlockNumber,
+		FromChain: w.cfg.Chain,
+		ToChain:   MeverseChain,
+		Token:     ev.Token,
+		From:      ev.From,
+		To:        ev.From,
+		Amount:    ev.Amount,
+		Path:      ev.Path,
+		Summary:   ev.Summary[:],
+		Sequence:  ev.Sequence.Uint64(),
+	}, nil
+}
+
+// EVMSubmitter executes SendFromGateway of the bridge and releases the withdraw of the gateway in the foreign evm chain
+type EVMSubmitter struct {
+	cfg     *EVMConfig
+	backend EVMBackend
+	key     *ecdsa.PrivateKey
+	bridge  *bind.BoundContract
+	token   *bind.BoundContract
+}
+
+// NewEVMSubmitter returns a EVMSubmitter
+// the key should be the banker of the bridge and the holder of the gateway token
+func NewEVMSubmitter(cfg *EVMConfig, backend EVMBackend, key *ecdsa.PrivateKey) *EVMSubmitter {
+	return &EVMSubmitter{
+		cfg:     cfg,
+		backend: backend,
+		key:     key,
+		bridge:  bind.NewBoundContract(cfg.Bridge, bridgeABI, backend, backend, backend),
+		token:   bind.NewBoundContract(cfg.GatewayToken, erc20ABI, backend, backend, backend),
+	}
+}
+
+// Chain returns the chain name
+func (s *EVMSubmitter) Chain() string {
+	return s.cfg.Chain
+}
+
+// SequenceTo returns getSequenceTo of the bridge
+func (s *EVMSubmitter) SequenceTo(ctx context.Context, user common.Address, fromChain string) (uint64, error) {
+	var out []interface{}
+	if err := s.bridge.Call(&bind.CallOpts{Context: ctx}, &out, "getSequenceTo", user, fromChain); err != nil {
+		return 0, err
+	}
+	if len(out) != 1 {
+		return 0, ErrInvalidResponse
+	}
+	seq, ok := out[0].(*big.Int)
+	if !ok {
+		return 0, ErrInvalidResponse
+	}
+	return seq.Uint64(), nil
+}
+
+// TxStatus returns the status of the transaction by the receipt
+func (s *EVMSubmitter) TxStatus(ctx context.Context, txid string) (TxStatus, error) {
+	h := ecommon.HexToHash(txid)
+	receipt, err := s.backend.TransactionReceipt(ctx, h)
+	if err == nil && receipt != nil {
+		if receipt.Status == etypes.ReceiptStatusSuccessful {
+			return TxSuccess, nil
+		}
+		return TxFailed, nil
+	}
+	if err != nil && err != ethereum.NotFound {
+		return TxUnknown, err
+	}
+	if _, isPending, err := s.backend.TransactionByHash(ctx, h); err == nil && isPending {
+		return TxPending, nil
+	} else if err != nil && err != ethereum.NotFound {
+		return TxUnknown, err
+	}
+	return TxUnknown, nil
+}
+
+// Submit sends sendFromGateway for the bridge transfer and the token transfer for the gateway withdraw
+func (s *EVMSubmitter) Submit(ctx context.Context, t *Transfer, sent func(txid string) error) (string, error) {
+	opts, err := bind.NewKeyedTransactorWithChainID(s.key, s.cfg.ChainID)
+	if err != nil {
+		return "", err
+	}
+	opts.Context = ctx
+	opts.GasLimit = s.cfg.GasLimit
+	opts.NoSend = true
+
+	var tx *etypes.Transaction
+	switch t.Kind {
+	case BridgeTransfer:
+		if s.cfg.Bridge == common.ZeroAddr {
+			return "", errors.Wrap(ErrNotSupportedKind, t.Kind.String())
+		}
+		var summary [32]byte
+		copy(summary[:], t.Summary)
+		path := t.Path
+		if path == nil {
+			path = []common.Address{}
+		}
+		tx, err = s.bridge.Transact(opts, "sendFromGateway", t.DestToken, t.To, t.Amount, path, t.FromChain, summary)
+	case GatewayWithdraw:
+		if s.cfg.GatewayToken == common.ZeroAddr {
+			return "", errors.Wrap(ErrNotSupportedKind, t.Kind.String())
+		}
+		tx, err = s.token.Transact(opts, "transfer", t.To, t.Amount)
+	default:
+		return "", errors.Wrap(ErrNotSupportedKind, t.Kind.String())
+	}
+	if err != nil {
+		return "", err
+	}
+
+	txid := tx.Hash().Hex()
+	if err := sent(txid); err != nil {
+		return "", err
+	}
+	if err := s.backend.SendTransaction(ctx, tx); err != nil {
+		return "", err
+	}
+	return txid, nil
+}
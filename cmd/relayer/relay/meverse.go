@@ -0,0 +1,288 @@
+package relay
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/common/key"
+	"github.com/meverselabs/meverse/core/types"
+)
+
+// MeverseClient is the client of the meverse chain, RPCClient implements it by the json rpc of the node
+type MeverseClient interface {
+	Height(ctx context.Context) (uint64, error)
+	// BridgeTxs returns search.bridgeTxs of the bridge from the height to the height
+	BridgeTxs(ctx context.Context, bridge common.Address, from uint64, to uint64) ([]map[string]interface{}, error)
+	// TokenOuts returns search.tokenOuts from the height
+	TokenOuts(ctx context.Context, from uint64) ([]map[string]string, error)
+	Call(ctx context.Context, cont common.Address, from common.Address, method string, params []interface{}) ([]interface{}, error)
+	SendTx(ctx context.Context, tx *types.Transaction, sig common.Signature) error
+	TxStatus(ctx context.Context, txid string) (TxStatus, error)
+}
+
+// MeverseConfig is the configuration of the meverse chain
+type MeverseConfig struct {
+	ChainID   *big.Int
+	Bridge    common.Address    // bridge contract, zero address disables the bridge transfers
+	Gateway   common.Address    // gateway contract, zero address disables the gateway transfers
+	Platforms map[string]string // chain name of the foreign chain by the platform of the gateway
+}
+
+// MeverseWatcher finds the transfers of the meverse chain by the txsearch of the node
+type MeverseWatcher struct {
+	cfg    *MeverseConfig
+	client MeverseClient
+}
+
+// NewMeverseWatcher returns a MeverseWatcher
+func NewMeverseWatcher(cfg *MeverseConfig, client MeverseClient) *MeverseWatcher {
+	return &MeverseWatcher{
+		cfg:    cfg,
+		client: client,
+	}
+}
+
+// Chain returns the chain name
+func (w *MeverseWatcher) Chain() string {
+	return MeverseChain
+}
+
+// Height returns the latest height
+func (w *MeverseWatcher) Height(ctx context.Context) (uint64, error) {
+	return w.client.Height(ctx)
+}
+
+// Transfers returns SendToGateway of the bridge and TokenOut of the gateway in the range
+// the sequence of the bridge transfer is counted by the relayer because the bridge doesn't emit it
+func (w *MeverseWatcher) Transfers(ctx context.Context, from uint64, to uint64) ([]*Transfer, error) {
+	ts := []*Transfer{}
+	if w.cfg.Bridge != common.ZeroAddr {
+		txs, err := w.client.BridgeTxs(ctx, w.cfg.Bridge, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range txs {
+			if m["event"] != "SentToGateway" {
+				continue
+			}
+			t, err := parseSentToGateway(m)
+			if err != nil {
+				return nil, err
+			}
+			ts = append(ts, t)
+		}
+	}
+	if w.cfg.Gateway != common.ZeroAddr {
+		outs, err := w.client.TokenOuts(ctx, from)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range outs {
+			height, err := strconv.ParseUint(m["Height"], 10, 64)
+			if err != nil {
+				return nil, errors.Wrap(ErrInvalidResponse, "Height")
+			}
+			if height > to {
+				continue
+			}
+			chain, has := w.cfg.Platforms[strings.ToLower(m["Platform"])]
+			if !has {
+				continue
+			}
+			bs, err := hex.DecodeString(m["DepositHex"])
+			if err != nil {
+				return nil, errors.Wrap(ErrInvalidResponse, "DepositHex")
+			}
+			ts = append(ts, &Transfer{
+				Kind:      GatewayWithdraw,
+				ID:        m["TxID"],
+				Height:    height,
+				FromChain: MeverseChain,
+				ToChain:   chain,
+				Platform:  m["Platform"],
+				From:      common.HexToAddress(m["From"]),
+				To:        common.HexToAddress(m["To"]),
+				Amount:    new(big.Int).SetBytes(bs),
+			})
+		}
+	}
+	return ts, nil
+}
+
+func parseSentToGateway(m map[string]interface{}) (*Transfer, error) {
+	rv, ok := m["returnValues"].(map[string]interface{})
+	if !ok {
+		return nil, errors.Wrap(ErrInvalidResponse, "returnValues")
+	}
+	height, err := strconv.ParseUint(fmt.Sprint(m["blockNumber"]), 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidResponse, "blockNumber")
+	}
+	toChain, ok := rv["_toChain"].(string)
+	if !ok {
+		return nil, errors.Wrap(ErrInvalidResponse, "_toChain")
+	}
+	am, ok := new(big.Int).SetString(fmt.Sprint(rv["_amount"]), 10)
+	if !ok {
+		return nil, errors.Wrap(ErrInvalidResponse, "_amount")
+	}
+	summary, err := hex.DecodeString(strings.TrimPrefix(fmt.Sprint(rv["_summary"]), "0x"))
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidResponse, "_summary")
+	}
+	path := []common.Address{}
+	if ps, ok := rv["_path"].([]interface{}); ok {
+		for _, p := range ps {
+			path = append(path, common.HexToAddress(fmt.Sprint(p)))
+		}
+	}
+	from := common.HexToAddress(fmt.Sprint(rv["_from"]))
+	return &Transfer{
+		Kind:      BridgeTransfer,
+		ID:        fmt.Sprint(m["hash"]),
+		Height:    height,
+		FromChain: MeverseChain,
+		ToChain:   toChain,
+		Token:     common.HexToAddress(fmt.Sprint(rv["_token"])),
+		From:      from,
+		To:        from,
+		Amount:    am,
+		Path:      path,
+		Summary:   summary,
+	}, nil
+}
+
+// MeverseSubmitter executes SendFromGateway of the bridge and TokenIn, TokenLeave of the gateway in the meverse chain
+type MeverseSubmitter struct {
+	cfg    *MeverseConfig
+	client MeverseClient
+	key    key.Key
+}
+
+// NewMeverseSubmitter returns a MeverseSubmitter
+// the key should be the banker of the bridge and the sender of the gateway
+func NewMeverseSubmitter(cfg *MeverseConfig, client MeverseClient, key key.Key) *MeverseSubmitter {
+	return &MeverseSubmitter{
+		cfg:    cfg,
+		client: client,
+		key:    key,
+	}
+}
+
+// Chain returns the chain name
+func (s *MeverseSubmitter) Chain() string {
+	return MeverseChain
+}
+
+// SequenceTo returns GetSequenceTo of the bridge
+func (s *MeverseSubmitter) SequenceTo(ctx context.Context, user common.Address, fromChain string) (uint64, error) {
+	is, err := s.client.Call(ctx, s.cfg.Bridge, common.ZeroAddr, "GetSequenceTo", []interface{}{user, fromChain})
+	if err != nil {
+		return 0, err
+	}
+	if len(is) != 1 {
+		return 0, ErrInvalidResponse
+	}
+	return toUint64(is[0])
+}
+
+// TxStatus returns the status of the transaction
+func (s *MeverseSubmitter) TxStatus(ctx context.Context, txid string) (TxStatus, error) {
+	return s.client.TxStatus(ctx, txid)
+}
+
+// Submit sends SendFromGateway for the bridge transfer and TokenIn for the gateway deposit
+// the transaction is executed by the view call before it is sent so that the failing transaction is not broadcast
+func (s *MeverseSubmitter) Submit(ctx context.Context, t *Transfer, sent func(txid string) error) (string, error) {
+	am := &amount.Amount{Int: t.Amount}
+	switch t.Kind {
+	case BridgeTransfer:
+		if s.cfg.Bridge == common.ZeroAddr {
+			return "", errors.Wrap(ErrNotSupportedKind, t.Kind.String())
+		}
+		path := t.Path
+		if path == nil {
+			path = []common.Address{}
+		}
+		return s.send(ctx, s.cfg.Bridge, "SendFromGateway", []interface{}{t.DestToken, t.To, am, path, t.FromChain, t.Summary}, sent)
+	case GatewayDeposit:
+		if s.cfg.Gateway == common.ZeroAddr {
+			return "", errors.Wrap(ErrNotSupportedKind, t.Kind.String())
+		}
+		txid, err := s.send(ctx, s.cfg.Gateway, "TokenIn", []interface{}{t.Platform, t.ID, t.To, am}, sent)
+		if err != nil && strings.Contains(err.Error(), "exist hash") {
+			return "", ErrAlreadyDelivered
+		}
+		return txid, err
+	}
+	return "", errors.Wrap(ErrNotSupportedKind, t.Kind.String())
+}
+
+// Leave sends TokenLeave of the withdraw which is released by the txid of the foreign chain
+func (s *MeverseSubmitter) Leave(ctx context.Context, t *Transfer, txid string) error {
+	if s.cfg.Gateway == common.ZeroAddr {
+		return errors.Wrap(ErrNotSupportedKind, t.Kind.String())
+	}
+	_, err := s.send(ctx, s.cfg.Gateway, "TokenLeave", []interface{}{t.ID, txid, t.Platform}, func(string) error { return nil })
+	return err
+}
+
+func (s *MeverseSubmitter) send(ctx context.Context, cont common.Address, method string, params []interface{}, sent func(txid string) error) (string, error) {
+	if _, err := s.client.Call(ctx, cont, s.key.PublicKey().Address(), method, params); err != nil {
+		return "", err
+	}
+	tx := &types.Transaction{
+		ChainID:   s.cfg.ChainID,
+		Timestamp: uint64(time.Now().UnixNano()),
+		To:        cont,
+		Method:    method,
+		Args:      bin.TypeWriteAll(params...),
+	}
+	sig, err := s.key.Sign(tx.HashSig())
+	if err != nil {
+		return "", err
+	}
+	txid := tx.HashSig().String()
+	if err := sent(txid); err != nil {
+		return "", err
+	}
+	if err := s.client.SendTx(ctx, tx, sig); err != nil {
+		return "", err
+	}
+	return txid, nil
+}
+
+func toUint64(v interface{}) (uint64, error) {
+	switch n := v.(type) {
+	case *big.Int:
+		return n.Uint64(), nil
+	case *amount.Amount:
+		return n.Int.Uint64(), nil
+	case uint64:
+		return n, nil
+	case json.Number:
+		bi, ok := new(big.Int).SetString(n.String(), 10)
+		if !ok {
+			return 0, ErrInvalidResponse
+		}
+		return bi.Uint64(), nil
+	case string:
+		bi, ok := new(big.Int).SetString(n, 0)
+		if !ok {
+			return 0, ErrInvalidResponse
+		}
+		return bi.Uint64(), nil
+	}
+	return 0, ErrInvalidResponse
+}
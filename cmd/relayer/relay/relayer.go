@@ -0,0 +1,329 @@
+package relay
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/common"
+)
+
+// Config is the configuration of the relayer
+type Config struct {
+	StartHeights   map[string]uint64                            // first height of the chain which is scanned when the checkpoint doesn't have it
+	Confirmations  map[string]uint64                            // block count which is waited before the transfer of the chain is relayed
+	Tokens         map[string]map[common.Address]common.Address // source token to destination token of the bridge by the source chain
+	MaxRange       uint64                                       // max block count which is scanned at once
+	Retry          int                                          // retry count of the transfer in a step
+	RetryDelay     time.Duration                                // delay before the first retry, it is doubled for each retry
+	PollInterval   time.Duration                                // interval of checking the submitted transaction
+	ConfirmTimeout time.Duration                                // max duration of waiting the submitted transaction
+	PendingTimeout time.Duration                                // duration after which the unknown pending transaction is submitted again
+}
+
+// Relayer relays the transfers which are found by the watchers to the submitters of the destination chains
+type Relayer struct {
+	cfg        *Config
+	cp         *Checkpoint
+	watchers   []Watcher
+	submitters map[string]Submitter
+}
+
+// NewRelayer returns a Relayer
+func NewRelayer(cfg *Config, cp *Checkpoint) *Relayer {
+	if cfg.MaxRange == 0 {
+		cfg.MaxRange = 1000
+	}
+	if cfg.RetryDelay == 0 {
+		cfg.RetryDelay = time.Second
+	}
+	if cfg.PollInterval == 0 {
+		cfg.PollInterval = time.Second
+	}
+	if cfg.ConfirmTimeout == 0 {
+		cfg.ConfirmTimeout = time.Minute
+	}
+	if cfg.PendingTimeout == 0 {
+		cfg.PendingTimeout = 10 * time.Minute
+	}
+	return &Relayer{
+		cfg:        cfg,
+		cp:         cp,
+		submitters: map[string]Submitter{},
+	}
+}
+
+// AddWatcher adds the watcher of the source chain
+func (r *Relayer) AddWatcher(w Watcher) {
+	r.watchers = append(r.watchers, w)
+}
+
+// AddSubmitter adds the submitter of the destination chain
+func (r *Relayer) AddSubmitter(s Submitter) {
+	r.submitters[strings.ToUpper(s.Chain())] = s
+}
+
+// Run relays the transfers at every interval until the context is done
+func (r *Relayer) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := r.Step(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			log.Println("relay step", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Step relays the confirmed transfers of all watchers from their checkpoints
+func (r *Relayer) Step(ctx context.Context) error {
+	var stepErr error
+	for _, w := range r.watchers {
+		if err := r.process(ctx, w); err != nil {
+			err = errors.Wrap(err, w.Chain())
+			if stepErr == nil {
+				stepErr = err
+			} else {
+				log.Println("relay step", err)
+			}
+		}
+	}
+	return stepErr
+}
+
+func (r *Relayer) process(ctx context.Context, w Watcher) error {
+	chain := strings.ToUpper(w.Chain())
+	head, err := w.Height(ctx)
+	if err != nil {
+		return err
+	}
+	conf := r.cfg.Confirmations[chain]
+	if head < conf {
+		return nil
+	}
+	safe := head - conf
+
+	from := r.cfg.StartHeights[chain]
+	if last, has := r.cp.Height(chain); has {
+		from = last + 1
+	}
+	if from > safe {
+		return nil
+	}
+	to := safe
+	if to-from+1 > r.cfg.MaxRange {
+		to = from + r.cfg.MaxRange - 1
+	}
+
+	ts, err := w.Transfers(ctx, from, to)
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(ts, func(i, j int) bool {
+		return ts[i].Height < ts[j].Height
+	})
+
+	// the counted sequences are stored with the height of the range
+	// so that the sequences are counted again from the same point when the range is not finished
+	seqs := map[string]*Transfer{}
+	for _, t := range ts {
+		if err := r.retry(ctx, func() error {
+			return r.assignSequence(ctx, t, seqs)
+		}); err != nil {
+			return err
+		}
+	}
+	for _, t := range ts {
+		if err := r.retry(ctx, func() error {
+			return r.relay(ctx, t)
+		}); err != nil {
+			switch errors.Cause(err) {
+			case ErrUnknownChain, ErrUnknownToken, ErrUnknownPlatform, ErrNotSupportedKind:
+				log.Println("skip transfer", t.Key(), err)
+				continue
+			}
+			return errors.Wrap(err, t.Key())
+		}
+	}
+
+	for _, t := range seqs {
+		r.cp.SetSequence(t.FromChain, t.ToChain, t.From, t.Sequence)
+	}
+	r.cp.SetHeight(chain, to)
+	return r.cp.Save()
+}
+
+func (r *Relayer) retry(ctx context.Context, fn func() error) error {
+	delay := r.cfg.RetryDelay
+	var err error
+	for i := 0; i <= r.cfg.Retry; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+		if err = fn(); err == nil {
+			return nil
+		}
+		switch errors.Cause(err) {
+		case ErrUnknownChain, ErrUnknownToken, ErrUnknownPlatform, ErrNotSupportedKind, ErrInvalidSequence:
+			return err
+		}
+	}
+	return err
+}
+
+// assignSequence counts the sequence of the bridge transfer when the source chain doesn't report it
+// the first sequence of the user is started from the delivered count of the destination chain
+func (r *Relayer) assignSequence(ctx context.Context, t *Transfer, seqs map[string]*Transfer) error {
+	if t.Kind != BridgeTransfer || t.Sequence > 0 {
+		return nil
+	}
+	key := sequenceKey(t.FromChain, t.ToChain, t.From)
+	var last uint64
+	if prev, has := seqs[key]; has {
+		last = prev.Sequence
+	} else if seq, has := r.cp.Sequence(t.FromChain, t.ToChain, t.From); has {
+		last = seq
+	} else {
+		dst, err := r.submitter(t.ToChain)
+		if err != nil {
+			return err
+		}
+		seq, err := dst.SequenceTo(ctx, t.To, t.FromChain)
+		if err != nil {
+			return err
+		}
+		last = seq
+	}
+	t.Sequence = last + 1
+	seqs[key] = t
+	return nil
+}
+
+func (r *Relayer) submitter(chain string) (Submitter, error) {
+	s, has := r.submitters[strings.ToUpper(chain)]
+	if !has {
+		return nil, errors.Wrap(ErrUnknownChain, chain)
+	}
+	return s, nil
+}
+
+func (r *Relayer) relay(ctx context.Context, t *Transfer) error {
+	key := t.Key()
+	if r.cp.IsDone(key) {
+		return nil
+	}
+	dst, err := r.submitter(t.ToChain)
+	if err != nil {
+		return err
+	}
+
+	switch t.Kind {
+	case BridgeTransfer:
+		tokens := r.cfg.Tokens[strings.ToUpper(t.FromChain)]
+		dt, has := tokens[t.Token]
+		if !has {
+			return errors.Wrap(ErrUnknownToken, t.Token.String())
+		}
+		t.DestToken = dt
+
+		seq, err := dst.SequenceTo(ctx, t.To, t.FromChain)
+		if err != nil {
+			return err
+		}
+		if seq >= t.Sequence {
+			r.cp.SetDone(t)
+			return nil
+		}
+		if seq+1 != t.Sequence {
+			return errors.Wrapf(ErrInvalidSequence, "%v is delivered but the transfer is %v", seq, t.Sequence)
+		}
+	case GatewayDeposit, GatewayWithdraw:
+	default:
+		return ErrNotSupportedKind
+	}
+
+	status := TxUnknown
+	txid, at, has := r.cp.Pending(key)
+	if has {
+		if status, err = dst.TxStatus(ctx, txid); err != nil {
+			return err
+		}
+		switch status {
+		case TxPending:
+			return ErrPendingTransaction
+		case TxUnknown:
+			if time.Since(at) < r.cfg.PendingTimeout {
+				return ErrPendingTransaction
+			}
+		}
+	}
+	if status != TxSuccess {
+		txid, err = dst.Submit(ctx, t, func(id string) error {
+			r.cp.SetPending(key, id)
+			return r.cp.Save()
+		})
+		if err != nil {
+			if errors.Cause(err) != ErrAlreadyDelivered {
+				return err
+			}
+		} else if err := r.waitTx(ctx, dst, txid); err != nil {
+			return err
+		}
+	}
+
+	if t.Kind == GatewayWithdraw {
+		src, err := r.submitter(t.FromChain)
+		if err != nil {
+			return err
+		}
+		lv, ok := src.(Leaver)
+		if !ok {
+			return errors.Wrap(ErrNotSupportedKind, src.Chain())
+		}
+		if err := lv.Leave(ctx, t, txid); err != nil {
+			return err
+		}
+	}
+	r.cp.SetDone(t)
+	return r.cp.Save()
+}
+
+func (r *Relayer) waitTx(ctx context.Context, s Submitter, txid string) error {
+	deadline := time.Now().Add(r.cfg.ConfirmTimeout)
+	for {
+		status, err := s.TxStatus(ctx, txid)
+		if err != nil {
+			return err
+		}
+		switch status {
+		case TxSuccess:
+			return nil
+		case TxFailed:
+			return errors.Wrap(ErrFailedTransaction, txid)
+		}
+		if time.Now().After(deadline) {
+			return errors.Wrap(ErrPendingTransaction, txid)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(r.cfg.PollInterval):
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package relay
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/core/types"
+)
+
+// RPCClient is the MeverseClient by the json rpc of the node which enables the txsearch
+type RPCClient struct {
+	url    string
+	client *http.Client
+	id     int64
+}
+
+// NewRPCClient returns a RPCClient
+func NewRPCClient(url string) *RPCClient {
+	return &RPCClient{
+		url:    url,
+		client: &http.Client{},
+	}
+}
+
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      int64         `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+// rpcError is the error which is returned by the node
+type rpcError struct {
+	message string
+}
+
+func (e *rpcError) Error() string {
+	return e.message
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  json.RawMessage `json:"error"`
+}
+
+func (c *RPCClient) call(ctx context.Context, method string, params []interface{}, out interface{}) error {
+	bs, err := json.Marshal(&rpcRequest{
+		JSONRPC: "2.0",
+		ID:      atomic.AddInt64(&c.id, 1),
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(bs))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	var resp rpcResponse
+	dec := json.NewDecoder(res.Body)
+	if err := dec.Decode(&resp); err != nil {
+		return err
+	}
+	if len(resp.Error) > 0 && string(resp.Error) != "null" {
+		var e struct {
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(resp.Error, &e); err == nil && e.Message != "" {
+			return &rpcError{message: e.Message}
+		}
+		return &rpcError{message: string(resp.Error)}
+	}
+	if out == nil {
+		return nil
+	}
+	rd := json.NewDecoder(bytes.NewReader(resp.Result))
+	rd.UseNumber()
+	return rd.Decode(out)
+}
+
+// Height returns view.blockNumber
+func (c *RPCClient) Height(ctx context.Context) (uint64, error) {
+	var n json.Number
+	if err := c.call(ctx, "view.blockNumber", []interface{}{}, &n); err != nil {
+		return 0, err
+	}
+	return toUint64(n)
+}
+
+// BridgeTxs returns search.bridgeTxs, the upper bound of the rpc is exclusive
+func (c *RPCClient) BridgeTxs(ctx context.Context, bridge common.Address, from uint64, to uint64) ([]map[string]interface{}, error) {
+	var txs []map[string]interface{}
+	if err := c.call(ctx, "search.bridgeTxs", []interface{}{bridge.String(), from, strconv.FormatUint(to+1, 10)}, &txs); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+// TokenOuts returns search.tokenOuts
+func (c *RPCClient) TokenOuts(ctx context.Context, from uint64) ([]map[string]string, error) {
+	var outs []map[string]string
+	if err := c.call(ctx, "search.tokenOuts", []interface{}{from}, &outs); err != nil {
+		return nil, err
+	}
+	return outs, nil
+}
+
+// Call returns view.call of the method
+func (c *RPCClient) Call(ctx context.Context, cont common.Address, from common.Address, method string, params []interface{}) ([]interface{}, error) {
+	ps := make([]interface{}, 0, len(params))
+	for _, p := range params {
+		ps = append(ps, rpcParam(p))
+	}
+	var fromStr string
+	if from != common.ZeroAddr {
+		fromStr = from.String()
+	}
+	var out []interface{}
+	if err := c.call(ctx, "view.call", []interface{}{cont.String(), method, ps, fromStr}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func rpcParam(p interface{}) interface{} {
+	switch v := p.(type) {
+	case common.Address:
+		return v.String()
+	case []common.Address:
+		as := make([]interface{}, 0, len(v))
+		for _, a := range v {
+			as = append(as, a.String())
+		}
+		return as
+	case *amount.Amount:
+		return v.Int.String()
+	case *big.Int:
+		return v.String()
+	case []byte:
+		return "0x" + hex.EncodeToString(v)
+	}
+	return p
+}
+
+// SendTx sends the signed transaction by view.srtx
+func (c *RPCClient) SendTx(ctx context.Context, tx *types.Transaction, sig common.Signature) error {
+	body := bin.TypeWriteAll(tx.Method, tx.To, tx.Timestamp, tx.Args)
+	return c.call(ctx, "view.srtx", []interface{}{hex.EncodeToString(sig), hex.EncodeToString(body)}, nil)
+}
+
+// TxStatus returns the status of the transaction by search.tx
+// the transaction which is not indexed is unknown because the pool of the node is not exposed
+func (c *RPCClient) TxStatus(ctx context.Context, txid string) (TxStatus, error) {
+	var out interface{}
+	if err := c.call(ctx, "search.tx", []interface{}{txid}, &out); err != nil {
+		if _, ok := err.(*rpcError); !ok {
+			return TxUnknown, err
+		}
+		if strings.Contains(err.Error(), "not exist") {
+			return TxUnknown, nil
+		}
+		// the failed transaction returns the error of the execution
+		return TxFailed, nil
+	}
+	if out == nil {
+		return TxUnknown, nil
+	}
+	return TxSuccess, nil
+}
@@ -0,0 +1,330 @@
+package test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	ecore "github.com/ethereum/go-ethereum/core"
+	etypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/meverselabs/meverse/cmd/relayer/relay"
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/common/key"
+	"github.com/meverselabs/meverse/contract/bridge"
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/meverselabs/meverse/extern/test/util"
+)
+
+// autoCommit mines the transaction as soon as it is sent
+type autoCommit struct {
+	*backends.SimulatedBackend
+}
+
+func (b *autoCommit) SendTransaction(ctx context.Context, tx *etypes.Transaction) error {
+	if err := b.SimulatedBackend.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	b.Commit()
+	return nil
+}
+
+// mockBridgeCode returns the init code of the foreign bridge mock
+// getSequenceTo returns the counter, sendFromGateway increases the counter
+// and the other call emits SentToGateway with the call data as the event data
+func mockBridgeCode(t *testing.T) []byte {
+	a, err := abi.JSON(strings.NewReader(relay.BridgeABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	emit := []byte{0x36, 0x60, 0x00, 0x60, 0x00, 0x37, 0x7f}
+	emit = append(emit, a.Events["SentToGateway"].ID.Bytes()...)
+	emit = append(emit, 0x36, 0x60, 0x00, 0xa1, 0x00)
+	get := []byte{0x5b, 0x60, 0x00, 0x54, 0x60, 0x00, 0x52, 0x60, 0x20, 0x60, 0x00, 0xf3}
+	send := []byte{0x5b, 0x60, 0x00, 0x54, 0x60, 0x01, 0x01, 0x60, 0x00, 0x55, 0x60, 0x01, 0x60, 0x00, 0x52, 0x60, 0x20, 0x60, 0x00, 0xf3}
+
+	head := []byte{0x60, 0x00, 0x35, 0x60, 0xe0, 0x1c, 0x80, 0x63}
+	head = append(head, a.Methods["getSequenceTo"].ID...)
+	head = append(head, 0x14, 0x60, 0x00, 0x57, 0x63)
+	head = append(head, a.Methods["sendFromGateway"].ID...)
+	head = append(head, 0x14, 0x60, 0x00, 0x57)
+	head[14] = byte(len(head) + len(emit))
+	head[len(head)-2] = byte(len(head) + len(emit) + len(get))
+
+	runtime := append(append(append(head, emit...), get...), send...)
+	code := []byte{0x60, byte(len(runtime)), 0x80, 0x60, 0x0b, 0x60, 0x00, 0x39, 0x60, 0x00, 0xf3}
+	return append(code, runtime...)
+}
+
+type foreignChain struct {
+	backend *autoCommit
+	pk      *ecdsa.PrivateKey
+	key     *bind.TransactOpts
+	bridge  common.Address
+}
+
+func newForeignChain(t *testing.T) (*foreignChain, *relay.EVMConfig) {
+	pk, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := crypto.PubkeyToAddress(pk.PublicKey)
+	sim := backends.NewSimulatedBackend(ecore.GenesisAlloc{
+		addr: {Balance: new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))},
+	}, 10000000)
+	fc := &foreignChain{backend: &autoCommit{sim}, pk: pk}
+
+	chainID := big.NewInt(1337)
+	fc.key, err = bind.NewKeyedTransactorWithChainID(pk, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fc.bridge, _, _, err = bind.DeployContract(fc.key, abi.ABI{}, mockBridgeCode(t), fc.backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fc, &relay.EVMConfig{
+		Chain:   "ETHEREUM",
+		ChainID: chainID,
+		Bridge:  fc.bridge,
+	}
+}
+
+// sendToGateway emits SentToGateway of the user like the foreign bridge
+func (fc *foreignChain) sendToGateway(t *testing.T, token common.Address, user common.Address, am *big.Int, seq int64) {
+	a, err := abi.JSON(strings.NewReader(relay.BridgeABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var summary [32]byte
+	data, err := a.Events["SentToGateway"].Inputs.Pack(token, user, fc.bridge, am, []common.Address{}, summary, big.NewInt(seq))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := bind.NewBoundContract(fc.bridge, abi.ABI{}, fc.backend, fc.backend, fc.backend)
+	if _, err := c.RawTransact(fc.key, data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (fc *foreignChain) sequenceTo(t *testing.T, s *relay.EVMSubmitter, user common.Address) uint64 {
+	seq, err := s.SequenceTo(context.Background(), user, relay.MeverseChain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return seq
+}
+
+// meverseClient executes the transactions of the relayer by the test context
+type meverseClient struct {
+	tc       *util.TestContext
+	mkey     key.Key
+	height   uint64
+	status   map[string]relay.TxStatus
+	bridgeTx []map[string]interface{}
+}
+
+func (c *meverseClient) Height(ctx context.Context) (uint64, error) {
+	return c.height, nil
+}
+
+func (c *meverseClient) BridgeTxs(ctx context.Context, cont common.Address, from uint64, to uint64) ([]map[string]interface{}, error) {
+	txs := []map[string]interface{}{}
+	for _, m := range c.bridgeTx {
+		h := m["height"].(uint64)
+		if h >= from && h <= to {
+			txs = append(txs, m)
+		}
+	}
+	return txs, nil
+}
+
+func (c *meverseClient) TokenOuts(ctx context.Context, from uint64) ([]map[string]string, error) {
+	return nil, nil
+}
+
+func (c *meverseClient) Call(ctx context.Context, cont common.Address, from common.Address, method string, params []interface{}) ([]interface{}, error) {
+	var fromStr string
+	if from != common.ZeroAddr {
+		fromStr = from.String()
+	}
+	return c.tc.Call(cont, fromStr, method, params)
+}
+
+func (c *meverseClient) SendTx(ctx context.Context, tx *types.Transaction, sig common.Signature) error {
+	is, err := bin.TypeReadAll(tx.Args, -1)
+	if err != nil {
+		return err
+	}
+	if _, err := c.tc.SendTx(c.mkey, tx.To, tx.Method, is...); err != nil {
+		c.status[tx.HashSig().String()] = relay.TxFailed
+	} else {
+		c.status[tx.HashSig().String()] = relay.TxSuccess
+	}
+	return nil
+}
+
+func (c *meverseClient) TxStatus(ctx context.Context, txid string) (relay.TxStatus, error) {
+	return c.status[txid], nil
+}
+
+// addSentToGateway adds SendToGateway like the search.bridgeTxs of the json rpc
+func (c *meverseClient) addSentToGateway(height uint64, txHash string, token common.Address, user common.Address, am *big.Int, toChain string) {
+	c.bridgeTx = append(c.bridgeTx, map[string]interface{}{
+		"height":      height,
+		"blockNumber": new(big.Int).SetUint64(height).String(),
+		"hash":        txHash,
+		"event":       "SentToGateway",
+		"returnValues": map[string]interface{}{
+			"_token":   token.String(),
+			"_amount":  am.String(),
+			"_path":    []interface{}{},
+			"_summary": "",
+			"_from":    user.String(),
+			"_toChain": toChain,
+		},
+	})
+}
+
+func newTestRelayer(t *testing.T, path string, tokens map[string]map[common.Address]common.Address) *relay.Relayer {
+	cp, err := relay.LoadCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return relay.NewRelayer(&relay.Config{
+		StartHeights: map[string]uint64{relay.MeverseChain: 1, "ETHEREUM": 1},
+		Tokens:       tokens,
+		Retry:        1,
+	}, cp)
+}
+
+func TestRelayForeignToMeverse(t *testing.T) {
+	tc := util.NewTestContext()
+	tokenAddr := tc.MakeToken("TestToken", "TEST", "10000")
+	bridgeAddr := tc.DeployContract(&bridge.BridgeContract{}, &bridge.BridgeContractConstruction{
+		Bank:         util.Users[0],
+		FeeOwner:     util.Users[1],
+		MeverseToken: tc.MainToken,
+	})
+	tc.MustSendTx(util.AdminKey, tokenAddr, "Transfer", bridgeAddr, amount.MustParseAmount("100"))
+	tc.MustSendTx(util.AdminKey, tc.MainToken, "Transfer", util.Users[0], amount.MustParseAmount("100"))
+
+	fc, ecfg := newForeignChain(t)
+	foreignToken := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	user := util.Users[5]
+	am := amount.MustParseAmount("3")
+
+	client := &meverseClient{tc: tc, mkey: util.UserKeys[0], status: map[string]relay.TxStatus{}}
+	mcfg := &relay.MeverseConfig{ChainID: util.ChainID, Bridge: bridgeAddr}
+	tokens := map[string]map[common.Address]common.Address{"ETHEREUM": {foreignToken: tokenAddr}}
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	run := func() {
+		rl := newTestRelayer(t, path, tokens)
+		rl.AddWatcher(relay.NewEVMWatcher(ecfg, fc.backend))
+		rl.AddSubmitter(relay.NewMeverseSubmitter(mcfg, client, util.UserKeys[0]))
+		if err := rl.Step(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	balance := func() *amount.Amount {
+		is, err := tc.ReadTx(util.AdminKey, tokenAddr, "BalanceOf", user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return is[0].(*amount.Amount)
+	}
+
+	fc.sendToGateway(t, foreignToken, user, am.Int, 1)
+	run()
+	if balance().Cmp(am.Int) != 0 {
+		t.Fatalf("balance %v, want %v", balance(), am)
+	}
+
+	// the relayer which lost the checkpoint scans again but the delivered sequence is not relayed twice
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	run()
+	if balance().Cmp(am.Int) != 0 {
+		t.Fatalf("relayed twice, balance %v, want %v", balance(), am)
+	}
+
+	fc.sendToGateway(t, foreignToken, user, am.Int, 2)
+	run()
+	if balance().Cmp(am.MulC(2).Int) != 0 {
+		t.Fatalf("balance %v, want %v", balance(), am.MulC(2))
+	}
+	is, err := tc.ReadTx(util.AdminKey, bridgeAddr, "GetSequenceTo", user, "ETHEREUM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if is[0].(*big.Int).Uint64() != 2 {
+		t.Errorf("sequence %v, want 2", is[0])
+	}
+}
+
+func TestRelayMeverseToForeign(t *testing.T) {
+	fc, ecfg := newForeignChain(t)
+	submitter := relay.NewEVMSubmitter(ecfg, fc.backend, fc.pk)
+
+	client := &meverseClient{status: map[string]relay.TxStatus{}}
+	mcfg := &relay.MeverseConfig{Bridge: common.HexToAddress("0x2000000000000000000000000000000000000002")}
+	meverseToken := common.HexToAddress("0x3000000000000000000000000000000000000003")
+	foreignToken := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	tokens := map[string]map[common.Address]common.Address{relay.MeverseChain: {meverseToken: foreignToken}}
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	user := util.Users[5]
+	am := big.NewInt(7)
+
+	run := func() {
+		rl := newTestRelayer(t, path, tokens)
+		rl.AddWatcher(relay.NewMeverseWatcher(mcfg, client))
+		rl.AddSubmitter(submitter)
+		if err := rl.Step(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client.height = 10
+	client.addSentToGateway(5, "0x01", meverseToken, user, am, "ETHEREUM")
+	run()
+	if seq := fc.sequenceTo(t, submitter, user); seq != 1 {
+		t.Fatalf("sequence %v, want 1", seq)
+	}
+	run()
+	if seq := fc.sequenceTo(t, submitter, user); seq != 1 {
+		t.Fatalf("relayed twice, sequence %v, want 1", seq)
+	}
+
+	client.height = 20
+	client.addSentToGateway(15, "0x02", meverseToken, user, am, "ETHEREUM")
+	client.addSentToGateway(16, "0x03", meverseToken, user, am, "ETHEREUM")
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	run()
+	if seq := fc.sequenceTo(t, submitter, user); seq != 3 {
+		t.Fatalf("sequence %v, want 3", seq)
+	}
+
+	// the relayer is crashed before the checkpoint is saved, the counted sequences are restored with the height
+	if err := os.WriteFile(path, bs, 0644); err != nil {
+		t.Fatal(err)
+	}
+	run()
+	if seq := fc.sequenceTo(t, submitter, user); seq != 3 {
+		t.Fatalf("relayed twice, sequence %v, want 3", seq)
+	}
+}
@@ -0,0 +1,94 @@
+package relay
+
+import (
+	"context"
+	"math/big"
+	"strings"
+
+	"github.com/meverselabs/meverse/common"
+)
+
+// MeverseChain is the chain name of the meverse in the bridge
+const MeverseChain = "MEVERSE"
+
+// Kind is the kind of the transfer
+type Kind uint8
+
+// kinds of the transfer
+const (
+	// BridgeTransfer is SentToGateway of the bridge which is relayed by SendFromGateway
+	BridgeTransfer Kind = iota + 1
+	// GatewayDeposit is a deposit to the foreign gateway which is relayed by TokenIn
+	GatewayDeposit
+	// GatewayWithdraw is TokenOut of the gateway which is released in the foreign chain and closed by TokenLeave
+	GatewayWithdraw
+)
+
+func (k Kind) String() string {
+	switch k {
+	case BridgeTransfer:
+		return "bridge"
+	case GatewayDeposit:
+		return "deposit"
+	case GatewayWithdraw:
+		return "withdraw"
+	}
+	return "unknown"
+}
+
+// Transfer is a transfer which is found in the source chain
+type Transfer struct {
+	Kind      Kind
+	ID        string // hash of the source transaction
+	Height    uint64
+	FromChain string
+	ToChain   string
+	Platform  string // platform of the gateway
+	Token     common.Address
+	DestToken common.Address // token of the destination chain, it is filled by the relayer
+	From      common.Address
+	To        common.Address // beneficiary in the destination chain
+	Amount    *big.Int
+	Path      []common.Address
+	Summary   []byte
+	Sequence  uint64 // sequence of the From to the ToChain, zero means that it is counted by the relayer
+}
+
+// Key returns the idempotency key of the transfer
+func (t *Transfer) Key() string {
+	return t.Kind.String() + ":" + strings.ToUpper(t.FromChain) + ":" + strings.ToLower(t.ID)
+}
+
+// Watcher finds the transfers of the source chain
+type Watcher interface {
+	Chain() string
+	Height(ctx context.Context) (uint64, error)
+	Transfers(ctx context.Context, from uint64, to uint64) ([]*Transfer, error)
+}
+
+// TxStatus is the status of the transaction which is submitted by the relayer
+type TxStatus uint8
+
+// statuses of the transaction
+const (
+	TxUnknown TxStatus = iota
+	TxPending
+	TxSuccess
+	TxFailed
+)
+
+// Submitter executes the transfers in the destination chain
+type Submitter interface {
+	Chain() string
+	// SequenceTo returns the count of the bridge transfers which are delivered to the user from the chain
+	SequenceTo(ctx context.Context, user common.Address, fromChain string) (uint64, error)
+	// TxStatus returns the status of the transaction which is submitted by the Submit
+	TxStatus(ctx context.Context, txid string) (TxStatus, error)
+	// Submit executes the transfer, sent is called with the transaction id before it is broadcast
+	Submit(ctx context.Context, t *Transfer, sent func(txid string) error) (string, error)
+}
+
+// Leaver closes the withdraw of the gateway after it is released in the foreign chain
+type Leaver interface {
+	Leave(ctx context.Context, t *Transfer, txid string) error
+}
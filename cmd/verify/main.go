@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/meverselabs/meverse/cmd/app"
+	"github.com/meverselabs/meverse/cmd/verify/verify"
+	"github.com/meverselabs/meverse/common"
+)
+
+func main() {
+	var cfg verify.Config
+	flag.StringVar(&cfg.DataDir, "data", "./ndata", "store root of the node")
+	flag.StringVar(&cfg.SnapshotDir, "snapshots", "", "directory of the zipcontext snapshots")
+	flag.StringVar(&cfg.WorkDir, "work", "./verify_work", "scratch directory of the replays")
+	from := flag.Uint("from", 0, "the first block to verify")
+	to := flag.Uint("to", 0, "the last block to verify, 0 means the height of the node")
+	flag.IntVar(&cfg.Workers, "workers", 1, "number of segments replayed in parallel")
+	flag.IntVar(&cfg.DiffLimit, "limit", 20, "maximum divergent keys to report")
+	genesisPath := flag.String("genesis", "", "genesis file of the chain, the built-in genesis is used when empty")
+	observers := flag.String("observers", "", "comma separated observer public keys when the genesis file is not used")
+	noGenesis := flag.Bool("nogenesis", false, "replay only from the snapshots")
+	flag.Parse()
+
+	cfg.From, cfg.To = uint32(*from), uint32(*to)
+	if len(*genesisPath) > 0 {
+		gen, err := app.LoadGenesis(*genesisPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid genesis: %+v\n", err)
+			os.Exit(1)
+		}
+		cfg.ChainID = gen.ChainIDInt()
+		cfg.Version = gen.ChainVersion()
+		cfg.ObserverKeys = gen.ObserverKeys
+		if !*noGenesis {
+			if cfg.Genesis, err = app.GenesisOf(gen); err != nil {
+				fmt.Fprintf(os.Stderr, "invalid genesis: %+v\n", err)
+				os.Exit(1)
+			}
+		}
+	} else {
+		for _, k := range strings.Split(*observers, ",") {
+			if len(k) == 0 {
+				continue
+			}
+			pubkey, err := common.ParsePublicKey(k)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "invalid observer key %v: %+v\n", k, err)
+				os.Exit(1)
+			}
+			cfg.ObserverKeys = append(cfg.ObserverKeys, pubkey)
+		}
+		if !*noGenesis {
+			var err error
+			if cfg.Genesis, err = app.GenesisOf(nil); err != nil {
+				fmt.Fprintf(os.Stderr, "invalid genesis: %+v\n", err)
+				os.Exit(1)
+			}
+		}
+	}
+
+	v, err := verify.New(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "open data: %+v\n", err)
+		os.Exit(1)
+	}
+	segs, err := v.Segments()
+	if err != nil {
+		v.Close()
+		fmt.Fprintf(os.Stderr, "%+v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("Height", v.Height())
+	fmt.Println("Segments", len(segs))
+	results := v.Run(segs)
+	v.Close()
+	if !verify.Report(os.Stdout, results) {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,13 @@
+package verify
+
+import "errors"
+
+// errors
+var (
+	ErrNoReplayBase        = errors.New("no genesis or snapshot to replay from")
+	ErrInvalidRange        = errors.New("invalid block range")
+	ErrInvalidSnapshot     = errors.New("invalid snapshot")
+	ErrMismatchGenesisHash = errors.New("mismatch genesis hash")
+	ErrMismatchInitHash    = errors.New("mismatch snapshot init hash")
+	ErrStateDiverged       = errors.New("state diverged")
+)
@@ -0,0 +1,118 @@
+package verify
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/meverselabs/meverse/cmd/config"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/pkg/errors"
+)
+
+// Snapshot is a zipcontext snapshot of the context at the height
+type Snapshot struct {
+	Height uint32
+	Path   string
+}
+
+// snapshotConfig is the _config.toml of the snapshot
+type snapshotConfig struct {
+	InitGenesisHash string
+	InitHash        string
+	InitHeight      uint32
+	InitTimestamp   uint64
+}
+
+// ListSnapshots returns the meverse_context_<height>.zip files of the directory ordered by the height
+func ListSnapshots(dir string) ([]*Snapshot, error) {
+	if len(dir) == 0 {
+		return nil, nil
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	ss := []*Snapshot{}
+	for _, f := range files {
+		name := f.Name()
+		if f.IsDir() || !strings.HasPrefix(name, "meverse_context_") || !strings.HasSuffix(name, ".zip") {
+			continue
+		}
+		h, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(name, "meverse_context_"), ".zip"), 10, 32)
+		if err != nil {
+			continue
+		}
+		ss = append(ss, &Snapshot{
+			Height: uint32(h),
+			Path:   filepath.Join(dir, name),
+		})
+	}
+	sort.Slice(ss, func(i, j int) bool {
+		return ss[i].Height < ss[j].Height
+	})
+	return ss, nil
+}
+
+// extract writes the context of the snapshot to dir/context and returns the config of the snapshot
+func (s *Snapshot) extract(dir string) (*snapshotConfig, error) {
+	archive, err := zip.OpenReader(s.Path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer archive.Close()
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var cfg *snapshotConfig
+	hasContext := false
+	for _, f := range archive.File {
+		switch f.Name {
+		case "data/context":
+			if err := extractFile(f, filepath.Join(dir, "context")); err != nil {
+				return nil, err
+			}
+			hasContext = true
+		case "_config.toml":
+			p := filepath.Join(dir, "_config.toml")
+			if err := extractFile(f, p); err != nil {
+				return nil, err
+			}
+			cfg = &snapshotConfig{}
+			if err := config.LoadFile(p, cfg); err != nil {
+				return nil, errors.WithStack(err)
+			}
+		}
+	}
+	if !hasContext || cfg == nil || cfg.InitHeight != s.Height {
+		return nil, errors.Wrap(ErrInvalidSnapshot, s.Path)
+	}
+	return cfg, nil
+}
+
+func (cfg *snapshotConfig) hashes() (hash.Hash256, hash.Hash256) {
+	return hash.HexToHash(cfg.InitGenesisHash), hash.HexToHash(cfg.InitHash)
+}
+
+func extractFile(f *zip.File, path string) error {
+	r, err := f.Open()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer r.Close()
+
+	w, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer w.Close()
+	if _, err := io.Copy(w, r); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
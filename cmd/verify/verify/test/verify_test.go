@@ -0,0 +1,261 @@
+package test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/meverselabs/meverse/cmd/verify/verify"
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/common/key"
+	"github.com/meverselabs/meverse/core/keydb"
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/meverselabs/meverse/service/apiserver/zipcontext"
+
+	"github.com/pkg/errors"
+
+	. "github.com/meverselabs/meverse/tests/lib"
+)
+
+// buildChain makes a chain of 6 blocks with a transfer in each block and a snapshot at the height 3
+func buildChain(t *testing.T, dir string) (verify.Config, common.Address, common.Address) {
+	userKeys, err := GetSingers(ChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aliceKey := userKeys[0]
+	alice, bob := aliceKey.PublicKey().Address(), userKeys[1].PublicKey().Address()
+
+	var genesis *types.Context
+	var mev *common.Address
+	initialize := func(ctx *types.Context, classMap map[string]uint64) error {
+		genesis = ctx
+		mev, err = MevInitialize(ctx, classMap, alice, map[common.Address]*amount.Amount{
+			alice: amount.NewAmount(1000000, 0),
+		})
+		return err
+	}
+	path := filepath.Join(dir, "node")
+	tb := NewTestBlockChain(path, true, ChainID, Version, alice, initialize, DefaultInitContextInfo)
+
+	snapshots := filepath.Join(dir, "snapshots")
+	for i := 0; i < 6; i++ {
+		tb.MustAddBlock([]*TxWithSigner{MakeGoTx(aliceKey, tb.Provider, mev, "Transfer", bob, amount.NewAmount(1, 0))})
+		if tb.Store.Height() == 3 {
+			// the store shrinks the keydb in the background after the open
+			var err error
+			for j := 0; j < 50; j++ {
+				if _, err = zipcontext.NewZipContextService(nil, tb.Store, snapshots, 1000).ZipContext(snapshots + "/"); errors.Cause(err) != keydb.ErrShrinkInProcess {
+					break
+				}
+				time.Sleep(100 * time.Millisecond)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	// the data should be kept, so tb.Close is not used
+	tb.Chain.Close()
+	os.RemoveAll(BloomDataPath)
+
+	ObserverKeys := []common.PublicKey{}
+	for i := 0; i < 5; i++ {
+		pk, err := key.NewMemoryKeyFromBytes(ChainID, []byte{1, 1, byte(i), 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ObserverKeys = append(ObserverKeys, pk.PublicKey())
+	}
+	cfg := verify.Config{
+		DataDir:      path,
+		SnapshotDir:  snapshots,
+		WorkDir:      filepath.Join(dir, "work"),
+		Workers:      2,
+		ChainID:      ChainID,
+		Version:      Version,
+		ObserverKeys: ObserverKeys,
+		Genesis:      genesis.Top(),
+	}
+	return cfg, *mev, alice
+}
+
+// tamperContext changes the balance of the addr in the keydb file
+func tamperContext(t *testing.T, path string, mev common.Address, addr common.Address) {
+	db, err := keydb.Open(path, func(key []byte, value []byte) (interface{}, error) {
+		return value, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	prefix := append(append([]byte{0x50}, mev[:]...), addr[:]...)
+	keys := [][]byte{}
+	if err := db.View(func(txn *keydb.Tx) error {
+		return txn.Iterate(prefix, func(key []byte, value interface{}) error {
+			keys = append(keys, append([]byte{}, key...))
+			return nil
+		})
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) == 0 {
+		t.Fatal("no data of the address")
+	}
+	bs := amount.NewAmount(999999999, 0).Bytes()
+	if err := db.Update(func(txn *keydb.Tx) error {
+		for _, k := range keys {
+			if err := txn.Set(k, bs, bs); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// tamperSnapshot rewrites the snapshot with the tampered context
+func tamperSnapshot(t *testing.T, zipPath string, mev common.Address, addr common.Address) {
+	dir := t.TempDir()
+	archive, err := zip.OpenReader(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{}
+	for _, f := range archive.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		bs, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name] = bs
+	}
+	archive.Close()
+
+	contextPath := filepath.Join(dir, "context")
+	if err := os.WriteFile(contextPath, files["data/context"], 0644); err != nil {
+		t.Fatal(err)
+	}
+	tamperContext(t, contextPath, mev, addr)
+	if files["data/context"], err = os.ReadFile(contextPath); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	w := zip.NewWriter(out)
+	for name, bs := range files {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write(bs)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func run(t *testing.T, cfg verify.Config) ([]*verify.Segment, []*verify.Result, string) {
+	v, err := verify.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+	segs, err := v.Segments()
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := v.Run(segs)
+	var buffer bytes.Buffer
+	verify.Report(&buffer, results)
+	return segs, results, buffer.String()
+}
+
+func TestVerify(t *testing.T) {
+	cfg, _, _ := buildChain(t, t.TempDir())
+
+	segs, results, report := run(t, cfg)
+	if len(segs) != 2 {
+		t.Fatalf("segments %v, want 2", len(segs))
+	}
+	if segs[0].Base != nil || segs[0].From != 1 || segs[0].To != 3 || segs[0].Check == nil || segs[0].CheckNode {
+		t.Fatalf("invalid first segment %v", segs[0])
+	}
+	if segs[1].Base == nil || segs[1].Base.Height != 3 || segs[1].From != 4 || segs[1].To != 6 || !segs[1].CheckNode {
+		t.Fatalf("invalid second segment %v", segs[1])
+	}
+	for _, r := range results {
+		if !r.OK() {
+			t.Fatal(report)
+		}
+	}
+	if results[0].Blocks != 3 || results[1].Blocks != 3 {
+		t.Fatal(report)
+	}
+
+	// the range after the snapshot starts from the snapshot
+	cfg.From, cfg.To = 5, 5
+	segs, results, report = run(t, cfg)
+	if len(segs) != 1 || segs[0].Base == nil || segs[0].From != 4 || segs[0].To != 5 || segs[0].CheckNode {
+		t.Fatalf("invalid segments %v", segs)
+	}
+	if !results[0].OK() {
+		t.Fatal(report)
+	}
+
+	// the range without the genesis and the snapshot can not be replayed
+	cfg.From, cfg.To = 1, 0
+	cfg.Genesis = nil
+	v, err := verify.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+	if _, err := v.Segments(); err == nil {
+		t.Fatal("segments without the replay base")
+	}
+}
+
+func TestVerifyDivergence(t *testing.T) {
+	cfg, mev, alice := buildChain(t, t.TempDir())
+	tamperSnapshot(t, filepath.Join(cfg.SnapshotDir, "meverse_context_3.zip"), mev, alice)
+	tamperContext(t, filepath.Join(cfg.DataDir, "context"), mev, alice)
+
+	_, results, report := run(t, cfg)
+	if len(results) != 2 {
+		t.Fatalf("results %v, want 2", len(results))
+	}
+
+	// the replay from the genesis is different with the tampered snapshot
+	if results[0].OK() || results[0].Blocks != 3 || len(results[0].Diffs) == 0 {
+		t.Fatal(report)
+	}
+	// the replay from the tampered snapshot does not derive the context hash of the next block
+	r := results[1]
+	if r.OK() || r.Divergence == nil || r.Divergence.Height != 4 || r.Divergence.Field != "ContextHash" || len(r.Divergence.Keys) == 0 {
+		t.Fatal(report)
+	}
+	want := "data/" + mev.String() + "/" + alice.String() + "/"
+	if !strings.HasPrefix(r.Divergence.Keys[0].Key, want) && !strings.HasPrefix(results[0].Diffs[0].Key, want) {
+		t.Fatal(report)
+	}
+	if !strings.Contains(report, "first divergent key "+want) {
+		t.Fatal(report)
+	}
+}
@@ -0,0 +1,349 @@
+package verify
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/meverselabs/meverse/cmd/app"
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/core/chain"
+	"github.com/meverselabs/meverse/core/piledb"
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/pkg/errors"
+)
+
+// Config is the config of the verification
+type Config struct {
+	DataDir      string // store root of the node which has the chain and the context
+	SnapshotDir  string // directory of the meverse_context_<height>.zip snapshots
+	WorkDir      string // scratch directory of the replayed chains
+	From         uint32 // the first block to verify, 0 means 1
+	To           uint32 // the last block to verify, 0 means the height of the node
+	Workers      int
+	DiffLimit    int
+	ChainID      *big.Int
+	Version      uint16
+	ObserverKeys []common.PublicKey
+	Genesis      *types.ContextData // nil means that the replays should start from the snapshots
+}
+
+// Segment is a block range which is replayed independently from the genesis or a snapshot
+type Segment struct {
+	Base      *Snapshot // nil means the genesis
+	From      uint32
+	To        uint32
+	Check     *Snapshot // the snapshot which is compared with the replayed state after To
+	CheckNode bool      // the context of the node is compared with the replayed state after To
+}
+
+func (seg *Segment) String() string {
+	base := "genesis"
+	if seg.Base != nil {
+		base = fmt.Sprintf("snapshot %v", seg.Base.Height)
+	}
+	return fmt.Sprintf("%v-%v from %v", seg.From, seg.To, base)
+}
+
+// Result is the verification result of the segment
+type Result struct {
+	Segment    *Segment
+	Blocks     uint32
+	Err        error
+	Divergence *chain.DivergenceError
+	Diffs      []*chain.KeyDiff
+}
+
+// OK returns the segment is verified or not
+func (r *Result) OK() bool {
+	return r.Err == nil
+}
+
+// Verifier replays the blocks of the node data and checks the headers, the signatures and the derived states
+//
+// the node should be stopped while the verification because the data of the node is opened
+type Verifier struct {
+	cfg       Config
+	src       *chain.Store
+	snapshots []*Snapshot
+}
+
+// New returns a Verifier of the node data
+func New(cfg Config) (*Verifier, error) {
+	if cfg.Workers < 1 {
+		cfg.Workers = 1
+	}
+	if cfg.ChainID == nil {
+		cfg.ChainID = big.NewInt(0x1D5E)
+	}
+	if cfg.Version == 0 {
+		cfg.Version = 1
+	}
+	if len(cfg.WorkDir) == 0 {
+		cfg.WorkDir = filepath.Join(os.TempDir(), "meverse_verify")
+	}
+	snapshots, err := ListSnapshots(cfg.SnapshotDir)
+	if err != nil {
+		return nil, err
+	}
+	app.RegisterContractClass()
+
+	cdb, err := piledb.Open(filepath.Join(cfg.DataDir, "chain"), hash.Hash256{}, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	src, err := chain.NewStore(filepath.Join(cfg.DataDir, "context"), cdb, cfg.ChainID, cfg.Version)
+	if err != nil {
+		cdb.Close()
+		return nil, err
+	}
+	v := &Verifier{
+		cfg:       cfg,
+		src:       src,
+		snapshots: snapshots,
+	}
+	return v, nil
+}
+
+// Close closes the node data
+func (v *Verifier) Close() {
+	v.src.Close()
+}
+
+// Height returns the height of the node data
+func (v *Verifier) Height() uint32 {
+	return v.src.Height()
+}
+
+// Segments splits the range by the snapshots, the first segment starts from the last snapshot before the range or the genesis
+func (v *Verifier) Segments() ([]*Segment, error) {
+	tip := v.src.Height()
+	from, to := v.cfg.From, v.cfg.To
+	if from == 0 {
+		from = 1
+	}
+	if to == 0 {
+		to = tip
+	}
+	if from > to || to > tip {
+		return nil, errors.Wrapf(ErrInvalidRange, "%v-%v of %v", from, to, tip)
+	}
+
+	var base *Snapshot
+	for _, s := range v.snapshots {
+		if s.Height < from {
+			base = s
+		}
+	}
+	if base == nil && (v.cfg.Genesis == nil || v.src.InitHeight() > 0) {
+		return nil, errors.WithStack(ErrNoReplayBase)
+	}
+
+	seg := &Segment{Base: base, From: 1}
+	if base != nil {
+		seg.From = base.Height + 1
+	}
+	segs := []*Segment{}
+	for _, s := range v.snapshots {
+		if s.Height < seg.From || s.Height > to {
+			continue
+		}
+		seg.To = s.Height
+		seg.Check = s
+		if s.Height == to {
+			break
+		}
+		segs = append(segs, seg)
+		seg = &Segment{Base: s, From: s.Height + 1}
+	}
+	seg.To = to
+	seg.CheckNode = to == tip
+	segs = append(segs, seg)
+	return segs, nil
+}
+
+// Run replays the segments by the workers and returns the results in the order of the segments
+func (v *Verifier) Run(segs []*Segment) []*Result {
+	results := make([]*Result, len(segs))
+	idxCh := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < v.cfg.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range idxCh {
+				results[idx] = v.replay(segs[idx], filepath.Join(v.cfg.WorkDir, fmt.Sprintf("segment_%v", segs[idx].From)))
+			}
+		}()
+	}
+	for i := range segs {
+		idxCh <- i
+	}
+	close(idxCh)
+	wg.Wait()
+	return results
+}
+
+func (v *Verifier) replay(seg *Segment, dir string) *Result {
+	res := &Result{Segment: seg}
+	if err := os.RemoveAll(dir); err != nil {
+		res.Err = errors.WithStack(err)
+		return res
+	}
+	defer os.RemoveAll(dir)
+
+	cn, err := v.openBase(seg.Base, dir)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	defer cn.Close()
+
+	for h := seg.From; h <= seg.To; h++ {
+		b, err := v.src.Block(h)
+		if err != nil {
+			res.Err = errors.Wrapf(err, "block %v", h)
+			return res
+		}
+		if err := cn.ReplayBlock(b); err != nil {
+			if de, is := err.(*chain.DivergenceError); is {
+				res.Divergence = de
+			}
+			res.Err = errors.Wrapf(err, "block %v", h)
+			return res
+		}
+		res.Blocks++
+	}
+
+	if seg.Check != nil {
+		cfg, err := seg.Check.extract(filepath.Join(dir, "check"))
+		if err != nil {
+			res.Err = err
+			return res
+		}
+		_, InitHash := cfg.hashes()
+		if h, err := cn.Store().Hash(seg.To); err != nil {
+			res.Err = err
+			return res
+		} else if h != InitHash {
+			res.Err = errors.Wrapf(ErrMismatchInitHash, "snapshot %v", seg.Check.Height)
+			return res
+		}
+		st, err := chain.NewStore(filepath.Join(dir, "check", "context"), nil, v.cfg.ChainID, v.cfg.Version)
+		if err != nil {
+			res.Err = err
+			return res
+		}
+		diffs, err := chain.DiffStore(st, cn.Store(), v.cfg.DiffLimit)
+		st.Close()
+		if err != nil {
+			res.Err = err
+			return res
+		}
+		if len(diffs) > 0 {
+			res.Diffs = diffs
+			res.Err = errors.Wrapf(ErrStateDiverged, "snapshot %v", seg.Check.Height)
+			return res
+		}
+	}
+	if seg.CheckNode {
+		diffs, err := chain.DiffStore(v.src, cn.Store(), v.cfg.DiffLimit)
+		if err != nil {
+			res.Err = err
+			return res
+		}
+		if len(diffs) > 0 {
+			res.Diffs = diffs
+			res.Err = errors.Wrap(ErrStateDiverged, "node context")
+			return res
+		}
+	}
+	return res
+}
+
+// openBase initializes the replayed chain from the genesis or the snapshot and checks it with the node data
+func (v *Verifier) openBase(base *Snapshot, dir string) (*chain.Chain, error) {
+	GenesisHash, err := v.src.Hash(0)
+	if err != nil {
+		return nil, err
+	}
+
+	var InitGenesisHash, InitHash hash.Hash256
+	var InitHeight uint32
+	var InitTimestamp uint64
+	if base != nil {
+		cfg, err := base.extract(dir)
+		if err != nil {
+			return nil, err
+		}
+		InitGenesisHash, InitHash = cfg.hashes()
+		InitHeight, InitTimestamp = cfg.InitHeight, cfg.InitTimestamp
+		if InitGenesisHash != GenesisHash {
+			return nil, errors.Wrapf(ErrMismatchGenesisHash, "snapshot %v", base.Height)
+		}
+		if h, err := v.src.Hash(InitHeight); err != nil {
+			return nil, err
+		} else if h != InitHash {
+			return nil, errors.Wrapf(ErrMismatchInitHash, "snapshot %v", base.Height)
+		}
+	}
+
+	cdb, err := piledb.Open(filepath.Join(dir, "chain"), InitHash, InitHeight, InitTimestamp)
+	if err != nil {
+		return nil, err
+	}
+	st, err := chain.NewStore(filepath.Join(dir, "context"), cdb, v.cfg.ChainID, v.cfg.Version)
+	if err != nil {
+		cdb.Close()
+		return nil, err
+	}
+	cn := chain.NewChain(v.cfg.ObserverKeys, st, "")
+	if base == nil {
+		err = cn.Init(v.cfg.Genesis)
+	} else {
+		err = cn.InitWith(InitGenesisHash, InitHash, InitHeight, InitTimestamp)
+	}
+	if err != nil {
+		cn.Close()
+		return nil, err
+	}
+	if h, err := st.Hash(0); err != nil {
+		cn.Close()
+		return nil, err
+	} else if h != GenesisHash {
+		cn.Close()
+		return nil, errors.WithStack(ErrMismatchGenesisHash)
+	}
+	return cn, nil
+}
+
+// Report writes the results and returns false when a segment is failed
+func Report(w io.Writer, results []*Result) bool {
+	ok := true
+	for _, r := range results {
+		if r.OK() {
+			fmt.Fprintf(w, "segment %v: ok, %v blocks\n", r.Segment, r.Blocks)
+			continue
+		}
+		ok = false
+		fmt.Fprintf(w, "segment %v: FAIL after %v blocks: %v\n", r.Segment, r.Blocks, r.Err)
+		if r.Divergence != nil {
+			// the header has the hash only, so the keys changed by the block are the candidates
+			for _, d := range r.Divergence.Keys {
+				fmt.Fprintf(w, "  changed key %v\n", d)
+			}
+		}
+		for i, d := range r.Diffs {
+			if i == 0 {
+				fmt.Fprintf(w, "  first divergent key %v\n", d)
+			} else {
+				fmt.Fprintf(w, "  divergent key %v\n", d)
+			}
+		}
+	}
+	return ok
+}
@@ -0,0 +1,14 @@
+package threshold
+
+import "errors"
+
+// threshold errors
+var (
+	ErrInvalidThreshold   = errors.New("invalid threshold")
+	ErrInvalidPoint       = errors.New("invalid point")
+	ErrInvalidShareIndex  = errors.New("invalid share index")
+	ErrInvalidShareProof  = errors.New("invalid share proof")
+	ErrInvalidSecretShare = errors.New("invalid secret share")
+	ErrInsufficientShares = errors.New("insufficient shares")
+	ErrInvalidCiphertext  = errors.New("invalid ciphertext")
+)
@@ -0,0 +1,83 @@
+package test
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/common/threshold"
+)
+
+func TestThreshold(t *testing.T) {
+	pk, shares, err := threshold.Deal(3, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, err = threshold.ParsePublicKey(pk.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss, err := threshold.ParseSecretShare(shares[4].String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares[4] = ss
+
+	msg := []byte("swap 100 MEV to USDT")
+	ct, err := pk.Encrypt(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dss := []*threshold.DecryptionShare{}
+	for _, ss := range shares {
+		ds, err := ss.Decrypt(ct)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := pk.VerifyShare(ct, ds); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		dss = append(dss, ds)
+	}
+
+	if _, err := pk.Combine(ct, dss[:2]); errors.Cause(err) != threshold.ErrInsufficientShares {
+		t.Fatalf("combined by the insufficient shares %v", err)
+	}
+	for _, sub := range [][]*threshold.DecryptionShare{dss[:3], dss[2:], {dss[4], dss[0], dss[3]}} {
+		bs, err := pk.Combine(ct, sub)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		if !bytes.Equal(bs, msg) {
+			t.Fatal("invalid message")
+		}
+	}
+
+	forged := *dss[1]
+	forged.Response = new(big.Int).Add(forged.Response, big.NewInt(1))
+	if err := pk.VerifyShare(ct, &forged); errors.Cause(err) != threshold.ErrInvalidShareProof {
+		t.Fatalf("forged share is verified %v", err)
+	}
+	moved := *dss[1]
+	moved.Index = 3
+	if err := pk.VerifyShare(ct, &moved); errors.Cause(err) != threshold.ErrInvalidShareProof {
+		t.Fatalf("moved share is verified %v", err)
+	}
+	if bs, err := pk.Combine(ct, []*threshold.DecryptionShare{&forged, dss[0], dss[0], dss[2], dss[3]}); err != nil || !bytes.Equal(bs, msg) {
+		t.Fatalf("invalid shares are not ignored %v", err)
+	}
+
+	other, err := pk.Encrypt(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pk.VerifyShare(other, dss[0]); errors.Cause(err) != threshold.ErrInvalidShareProof {
+		t.Fatalf("share of the other ciphertext is verified %v", err)
+	}
+	ct.Data[0] ^= 1
+	if _, err := pk.Combine(ct, dss); errors.Cause(err) != threshold.ErrInvalidCiphertext {
+		t.Fatalf("tampered ciphertext is opened %v", err)
+	}
+}
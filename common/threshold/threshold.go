@@ -0,0 +1,494 @@
+package threshold
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/common/hash"
+)
+
+// PublicKey is the public parameters of the dealt key, Verifiers[i] verifies the decryption share of the index i+1
+type PublicKey struct {
+	Threshold uint16
+	Key       common.PublicKey
+	Verifiers []common.PublicKey
+}
+
+// Deal creates the key which is decrypted by the threshold shares of the count shares
+//
+// the dealer should discard the secret after the shares are delivered to the holders
+func Deal(Threshold int, Count int) (*PublicKey, []*SecretShare, error) {
+	if Threshold < 1 || Threshold > Count || Count > 65535 {
+		return nil, nil, errors.WithStack(ErrInvalidThreshold)
+	}
+	N := curve().Params().N
+	coeffs := make([]*big.Int, 0, Threshold)
+	for i := 0; i < Threshold; i++ {
+		v, err := randScalar()
+		if err != nil {
+			return nil, nil, err
+		}
+		coeffs = append(coeffs, v)
+	}
+	pk := &PublicKey{
+		Threshold: uint16(Threshold),
+		Key:       baseMult(coeffs[0]),
+		Verifiers: make([]common.PublicKey, 0, Count),
+	}
+	shares := make([]*SecretShare, 0, Count)
+	for i := 1; i <= Count; i++ {
+		x := big.NewInt(int64(i))
+		v := new(big.Int)
+		for j := len(coeffs) - 1; j >= 0; j-- {
+			v.Mul(v, x)
+			v.Add(v, coeffs[j])
+			v.Mod(v, N)
+		}
+		pk.Verifiers = append(pk.Verifiers, baseMult(v))
+		shares = append(shares, &SecretShare{
+			Index: uint16(i),
+			Value: v,
+		})
+	}
+	return pk, shares, nil
+}
+
+// Validate checks the parameters and the points of the key
+func (pk *PublicKey) Validate() error {
+	if pk.Threshold < 1 || int(pk.Threshold) > len(pk.Verifiers) {
+		return errors.WithStack(ErrInvalidThreshold)
+	}
+	if _, _, err := unmarshalPoint(pk.Key); err != nil {
+		return err
+	}
+	for _, v := range pk.Verifiers {
+		if _, _, err := unmarshalPoint(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Encrypt seals the message by the key, the ephemeral point is used once so the nonce is fixed
+func (pk *PublicKey) Encrypt(msg []byte) (*Ciphertext, error) {
+	x, y, err := unmarshalPoint(pk.Key)
+	if err != nil {
+		return nil, err
+	}
+	r, err := randScalar()
+	if err != nil {
+		return nil, err
+	}
+	ct := &Ciphertext{
+		Ephemeral: baseMult(r),
+	}
+	kx, ky := curve().ScalarMult(x, y, r.Bytes())
+	aead, err := newAEAD(marshalPoint(kx, ky), ct.Ephemeral)
+	if err != nil {
+		return nil, err
+	}
+	ct.Data = aead.Seal(nil, make([]byte, aead.NonceSize()), msg, ct.Ephemeral[:])
+	return ct, nil
+}
+
+// VerifyShare checks the proof that the decryption share is made by the secret share of its index
+func (pk *PublicKey) VerifyShare(ct *Ciphertext, ds *DecryptionShare) error {
+	if ds.Index < 1 || int(ds.Index) > len(pk.Verifiers) {
+		return errors.WithStack(ErrInvalidShareIndex)
+	}
+	N := curve().Params().N
+	if ds.Challenge == nil || ds.Response == nil || ds.Challenge.Cmp(N) >= 0 || ds.Response.Cmp(N) >= 0 {
+		return errors.WithStack(ErrInvalidShareProof)
+	}
+	rx, ry, err := unmarshalPoint(ct.Ephemeral)
+	if err != nil {
+		return err
+	}
+	vx, vy, err := unmarshalPoint(pk.Verifiers[ds.Index-1])
+	if err != nil {
+		return err
+	}
+	dx, dy, err := unmarshalPoint(ds.Point)
+	if err != nil {
+		return err
+	}
+	negC := new(big.Int).Sub(N, ds.Challenge)
+	// A1 = z*G - c*V, A2 = z*R - c*D
+	zgx, zgy := pointOf(curve().ScalarBaseMult(ds.Response.Bytes()))
+	cvx, cvy := pointOf(curve().ScalarMult(vx, vy, negC.Bytes()))
+	a1x, a1y := curve().Add(zgx, zgy, cvx, cvy)
+	zrx, zry := pointOf(curve().ScalarMult(rx, ry, ds.Response.Bytes()))
+	cdx, cdy := pointOf(curve().ScalarMult(dx, dy, negC.Bytes()))
+	a2x, a2y := curve().Add(zrx, zry, cdx, cdy)
+	c := challenge(ct.Ephemeral, pk.Verifiers[ds.Index-1], ds.Point, marshalPoint(a1x, a1y), marshalPoint(a2x, a2y))
+	if c.Cmp(ds.Challenge) != 0 {
+		return errors.WithStack(ErrInvalidShareProof)
+	}
+	return nil
+}
+
+// Combine decrypts the ciphertext by the threshold valid shares, the invalid shares are ignored
+func (pk *PublicKey) Combine(ct *Ciphertext, dss []*DecryptionShare) ([]byte, error) {
+	used := make([]*DecryptionShare, 0, pk.Threshold)
+	usedMap := map[uint16]bool{}
+	for _, ds := range dss {
+		if len(used) >= int(pk.Threshold) {
+			break
+		}
+		if ds == nil || usedMap[ds.Index] {
+			continue
+		}
+		if err := pk.VerifyShare(ct, ds); err != nil {
+			continue
+		}
+		usedMap[ds.Index] = true
+		used = append(used, ds)
+	}
+	if len(used) < int(pk.Threshold) {
+		return nil, errors.WithStack(ErrInsufficientShares)
+	}
+
+	N := curve().Params().N
+	var kx, ky *big.Int
+	for i, ds := range used {
+		num := big.NewInt(1)
+		den := big.NewInt(1)
+		xi := big.NewInt(int64(ds.Index))
+		for j, o := range used {
+			if i == j {
+				continue
+			}
+			xj := big.NewInt(int64(o.Index))
+			num.Mul(num, xj)
+			num.Mod(num, N)
+			den.Mul(den, new(big.Int).Sub(xj, xi))
+			den.Mod(den, N)
+		}
+		lambda := num.Mul(num, den.ModInverse(den, N))
+		lambda.Mod(lambda, N)
+
+		dx, dy, err := unmarshalPoint(ds.Point)
+		if err != nil {
+			return nil, err
+		}
+		px, py := pointOf(curve().ScalarMult(dx, dy, lambda.Bytes()))
+		if kx == nil {
+			kx, ky = px, py
+		} else {
+			kx, ky = curve().Add(kx, ky, px, py)
+		}
+	}
+	aead, err := newAEAD(marshalPoint(kx, ky), ct.Ephemeral)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := aead.Open(nil, make([]byte, aead.NonceSize()), ct.Data, ct.Ephemeral[:])
+	if err != nil {
+		return nil, errors.WithStack(ErrInvalidCiphertext)
+	}
+	return msg, nil
+}
+
+// String returns the hex string of the key
+func (pk *PublicKey) String() string {
+	bs, _, err := bin.WriterToBytes(pk)
+	if err != nil {
+		return ""
+	}
+	return hex.EncodeToString(bs)
+}
+
+// ParsePublicKey parses the hex string of the key
+func ParsePublicKey(str string) (*PublicKey, error) {
+	bs, err := hex.DecodeString(str)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	pk := &PublicKey{}
+	if _, err := pk.ReadFrom(bytes.NewReader(bs)); err != nil {
+		return nil, err
+	}
+	if err := pk.Validate(); err != nil {
+		return nil, err
+	}
+	return pk, nil
+}
+
+func (pk *PublicKey) WriteTo(w io.Writer) (int64, error) {
+	sw := bin.NewSumWriter()
+	if sum, err := sw.Uint16(w, pk.Threshold); err != nil {
+		return sum, err
+	}
+	if sum, err := sw.PublicKey(w, pk.Key); err != nil {
+		return sum, err
+	}
+	if sum, err := sw.Uint16(w, uint16(len(pk.Verifiers))); err != nil {
+		return sum, err
+	}
+	for _, v := range pk.Verifiers {
+		if sum, err := sw.PublicKey(w, v); err != nil {
+			return sum, err
+		}
+	}
+	return sw.Sum(), nil
+}
+
+func (pk *PublicKey) ReadFrom(r io.Reader) (int64, error) {
+	sr := bin.NewSumReader()
+	if sum, err := sr.Uint16(r, &pk.Threshold); err != nil {
+		return sum, err
+	}
+	if sum, err := sr.PublicKey(r, &pk.Key); err != nil {
+		return sum, err
+	}
+	if Len, sum, err := sr.GetUint16(r); err != nil {
+		return sum, err
+	} else {
+		pk.Verifiers = make([]common.PublicKey, Len)
+		for i := range pk.Verifiers {
+			if sum, err := sr.PublicKey(r, &pk.Verifiers[i]); err != nil {
+				return sum, err
+			}
+		}
+	}
+	return sr.Sum(), nil
+}
+
+// SecretShare is the key share of a holder
+type SecretShare struct {
+	Index uint16
+	Value *big.Int
+}
+
+// Decrypt makes the decryption share of the ciphertext with the proof of the share
+func (ss *SecretShare) Decrypt(ct *Ciphertext) (*DecryptionShare, error) {
+	if ss.Index < 1 || ss.Value == nil || ss.Value.Sign() <= 0 {
+		return nil, errors.WithStack(ErrInvalidSecretShare)
+	}
+	rx, ry, err := unmarshalPoint(ct.Ephemeral)
+	if err != nil {
+		return nil, err
+	}
+	k, err := randScalar()
+	if err != nil {
+		return nil, err
+	}
+	ds := &DecryptionShare{
+		Index: ss.Index,
+		Point: marshalPoint(curve().ScalarMult(rx, ry, ss.Value.Bytes())),
+	}
+	a1 := baseMult(k)
+	a2 := marshalPoint(curve().ScalarMult(rx, ry, k.Bytes()))
+	ds.Challenge = challenge(ct.Ephemeral, baseMult(ss.Value), ds.Point, a1, a2)
+	N := curve().Params().N
+	ds.Response = new(big.Int).Mul(ds.Challenge, ss.Value)
+	ds.Response.Add(ds.Response, k)
+	ds.Response.Mod(ds.Response, N)
+	return ds, nil
+}
+
+// String returns the hex string of the share
+func (ss *SecretShare) String() string {
+	bs, _, err := bin.WriterToBytes(ss)
+	if err != nil {
+		return ""
+	}
+	return hex.EncodeToString(bs)
+}
+
+// ParseSecretShare parses the hex string of the share
+func ParseSecretShare(str string) (*SecretShare, error) {
+	bs, err := hex.DecodeString(str)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	ss := &SecretShare{}
+	if _, err := ss.ReadFrom(bytes.NewReader(bs)); err != nil {
+		return nil, err
+	}
+	return ss, nil
+}
+
+func (ss *SecretShare) WriteTo(w io.Writer) (int64, error) {
+	sw := bin.NewSumWriter()
+	if sum, err := sw.Uint16(w, ss.Index); err != nil {
+		return sum, err
+	}
+	if sum, err := sw.BigInt(w, ss.Value); err != nil {
+		return sum, err
+	}
+	return sw.Sum(), nil
+}
+
+func (ss *SecretShare) ReadFrom(r io.Reader) (int64, error) {
+	sr := bin.NewSumReader()
+	if sum, err := sr.Uint16(r, &ss.Index); err != nil {
+		return sum, err
+	}
+	if sum, err := sr.BigInt(r, &ss.Value); err != nil {
+		return sum, err
+	}
+	return sr.Sum(), nil
+}
+
+// Ciphertext is the message sealed by the key
+type Ciphertext struct {
+	Ephemeral common.PublicKey
+	Data      []byte
+}
+
+// Hash returns the hash of the ciphertext
+func (ct *Ciphertext) Hash() hash.Hash256 {
+	return bin.MustWriterToHash(ct)
+}
+
+// Validate checks the ephemeral point of the ciphertext
+func (ct *Ciphertext) Validate() error {
+	if _, _, err := unmarshalPoint(ct.Ephemeral); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ct *Ciphertext) WriteTo(w io.Writer) (int64, error) {
+	sw := bin.NewSumWriter()
+	if sum, err := sw.PublicKey(w, ct.Ephemeral); err != nil {
+		return sum, err
+	}
+	if sum, err := sw.Bytes(w, ct.Data); err != nil {
+		return sum, err
+	}
+	return sw.Sum(), nil
+}
+
+func (ct *Ciphertext) ReadFrom(r io.Reader) (int64, error) {
+	sr := bin.NewSumReader()
+	if sum, err := sr.PublicKey(r, &ct.Ephemeral); err != nil {
+		return sum, err
+	}
+	if sum, err := sr.Bytes(r, &ct.Data); err != nil {
+		return sum, err
+	}
+	return sr.Sum(), nil
+}
+
+// DecryptionShare is the share of the ephemeral secret with the proof of the equal discrete logarithm
+type DecryptionShare struct {
+	Index     uint16
+	Point     common.PublicKey
+	Challenge *big.Int
+	Response  *big.Int
+}
+
+func (ds *DecryptionShare) WriteTo(w io.Writer) (int64, error) {
+	sw := bin.NewSumWriter()
+	if sum, err := sw.Uint16(w, ds.Index); err != nil {
+		return sum, err
+	}
+	if sum, err := sw.PublicKey(w, ds.Point); err != nil {
+		return sum, err
+	}
+	if sum, err := sw.BigInt(w, ds.Challenge); err != nil {
+		return sum, err
+	}
+	if sum, err := sw.BigInt(w, ds.Response); err != nil {
+		return sum, err
+	}
+	return sw.Sum(), nil
+}
+
+func (ds *DecryptionShare) ReadFrom(r io.Reader) (int64, error) {
+	sr := bin.NewSumReader()
+	if sum, err := sr.Uint16(r, &ds.Index); err != nil {
+		return sum, err
+	}
+	if sum, err := sr.PublicKey(r, &ds.Point); err != nil {
+		return sum, err
+	}
+	if sum, err := sr.BigInt(r, &ds.Challenge); err != nil {
+		return sum, err
+	}
+	if sum, err := sr.BigInt(r, &ds.Response); err != nil {
+		return sum, err
+	}
+	return sr.Sum(), nil
+}
+
+func curve() elliptic.Curve {
+	return crypto.S256()
+}
+
+func randScalar() (*big.Int, error) {
+	N := curve().Params().N
+	for {
+		v, err := rand.Int(rand.Reader, N)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if v.Sign() > 0 {
+			return v, nil
+		}
+	}
+}
+
+func pointOf(x, y *big.Int) (*big.Int, *big.Int) {
+	if x == nil {
+		return new(big.Int), new(big.Int)
+	}
+	return x, y
+}
+
+func baseMult(k *big.Int) common.PublicKey {
+	return marshalPoint(curve().ScalarBaseMult(k.Bytes()))
+}
+
+func marshalPoint(x, y *big.Int) common.PublicKey {
+	var p common.PublicKey
+	if x == nil {
+		return p
+	}
+	copy(p[:], elliptic.Marshal(curve(), x, y))
+	return p
+}
+
+func unmarshalPoint(p common.PublicKey) (*big.Int, *big.Int, error) {
+	x, y := elliptic.Unmarshal(curve(), p[:])
+	if x == nil {
+		return nil, nil, errors.WithStack(ErrInvalidPoint)
+	}
+	return x, y, nil
+}
+
+func challenge(points ...common.PublicKey) *big.Int {
+	data := make([][]byte, 0, len(points))
+	for i := range points {
+		data = append(data, points[i][:])
+	}
+	h := hash.Hash(data...)
+	c := new(big.Int).SetBytes(h[:])
+	return c.Mod(c, curve().Params().N)
+}
+
+func newAEAD(shared common.PublicKey, Ephemeral common.PublicKey) (cipher.AEAD, error) {
+	h := hash.Hash(shared[:], Ephemeral[:])
+	block, err := aes.NewCipher(h[:])
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return aead, nil
+}
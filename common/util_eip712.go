@@ -0,0 +1,64 @@
+package common
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/common/hash"
+)
+
+// EIP712DomainTypeHash is the type hash of the EIP-712 domain
+var EIP712DomainTypeHash = crypto.Keccak256Hash([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"))
+
+// EIP712DomainSeparator returns the EIP-712 domain separator of the contract
+func EIP712DomainSeparator(name string, version string, chainID *big.Int, contract Address) hash.Hash256 {
+	return crypto.Keccak256Hash(
+		EIP712DomainTypeHash[:],
+		crypto.Keccak256([]byte(name)),
+		crypto.Keccak256([]byte(version)),
+		EIP712Uint256(chainID),
+		EIP712Address(contract),
+	)
+}
+
+// EIP712Hash returns the hash to be signed of the typed data
+func EIP712Hash(domainSeparator hash.Hash256, structHash hash.Hash256) hash.Hash256 {
+	return crypto.Keccak256Hash([]byte{0x19, 0x01}, domainSeparator[:], structHash[:])
+}
+
+// EIP712Address returns the encoded word of the address
+func EIP712Address(addr Address) []byte {
+	bs := make([]byte, 32)
+	copy(bs[32-AddressLength:], addr[:])
+	return bs
+}
+
+// EIP712Uint256 returns the encoded word of the uint256 value
+func EIP712Uint256(v *big.Int) []byte {
+	if v == nil {
+		return make([]byte, 32)
+	}
+	return math.U256Bytes(new(big.Int).Set(v))
+}
+
+// RecoverTypedSigner recovers the signer of the typed data hash
+// it accepts the 27/28 recovery id of eth_signTypedData in addition to the ones of RecoverPubkey
+func RecoverTypedSigner(chainID *big.Int, h hash.Hash256, sig Signature) (Address, error) {
+	if len(sig) < 65 {
+		return ZeroAddr, errors.WithStack(ErrInvalidSignature)
+	}
+	if len(sig) == 65 && (sig[64] == 27 || sig[64] == 28) {
+		s := make(Signature, 65)
+		copy(s, sig)
+		s[64] -= 27
+		sig = s
+	}
+	pubkey, err := RecoverPubkey(chainID, h, sig)
+	if err != nil {
+		return ZeroAddr, err
+	}
+	return pubkey.Address(), nil
+}
@@ -0,0 +1,10 @@
+package vrf
+
+import "errors"
+
+// vrf errors
+var (
+	ErrInvalidPoint      = errors.New("invalid point")
+	ErrInvalidProof      = errors.New("invalid proof")
+	ErrInvalidPrivateKey = errors.New("invalid private key")
+)
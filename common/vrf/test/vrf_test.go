@@ -0,0 +1,92 @@
+package test
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/common/key"
+	"github.com/meverselabs/meverse/common/vrf"
+)
+
+func TestVRF(t *testing.T) {
+	ChainID := big.NewInt(1)
+	k, err := key.NewMemoryKey(ChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := key.NewMemoryKey(ChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := []byte("height 100")
+	p, err := vrf.Prove(k.PrivateKey(), msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := vrf.Verify(k.PublicKey(), msg, p)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	// the output is unique for the key and the message though the proof is randomized
+	p2, err := vrf.Prove(k.PrivateKey(), msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p2.Challenge.Cmp(p.Challenge) == 0 {
+		t.Fatal("proof is not randomized")
+	}
+	if output2, err := vrf.Verify(k.PublicKey(), msg, p2); err != nil {
+		t.Fatalf("%+v", err)
+	} else if output2 != output {
+		t.Fatal("output is not unique")
+	}
+	if p3, err := vrf.Prove(k.PrivateKey(), []byte("height 101")); err != nil {
+		t.Fatal(err)
+	} else if p3.Output() == output {
+		t.Fatal("output of the other message is same")
+	}
+
+	bs, _, err := bin.WriterToBytes(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rp := &vrf.Proof{}
+	if _, err := rp.ReadFrom(bytes.NewReader(bs)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vrf.Verify(k.PublicKey(), msg, rp); err != nil {
+		t.Fatalf("read proof is not verified %+v", err)
+	}
+
+	if _, err := vrf.Verify(other.PublicKey(), msg, p); err == nil {
+		t.Fatal("proof is verified by the other key")
+	}
+	if _, err := vrf.Verify(k.PublicKey(), []byte("height 101"), p); err == nil {
+		t.Fatal("proof is verified by the other message")
+	}
+	forged := &vrf.Proof{
+		Gamma:     p.Gamma,
+		Challenge: p.Challenge,
+		Response:  new(big.Int).Add(p.Response, big.NewInt(1)),
+	}
+	if _, err := vrf.Verify(k.PublicKey(), msg, forged); err == nil {
+		t.Fatal("forged response is verified")
+	}
+	// the gamma of the other key cannot be claimed with the proof
+	po, err := vrf.Prove(other.PrivateKey(), msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged = &vrf.Proof{
+		Gamma:     po.Gamma,
+		Challenge: p.Challenge,
+		Response:  p.Response,
+	}
+	if _, err := vrf.Verify(k.PublicKey(), msg, forged); err == nil {
+		t.Fatal("replaced gamma is verified")
+	}
+}
@@ -0,0 +1,190 @@
+package vrf
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/common/hash"
+)
+
+// Proof is the verifiable random output of the message with the proof of the equal discrete logarithm to the public key
+//
+// Gamma = sk*H(msg), the output is unique for the key and the message
+type Proof struct {
+	Gamma     common.PublicKey
+	Challenge *big.Int
+	Response  *big.Int
+}
+
+// Prove makes the output of the message and its proof by the private key
+func Prove(sk *ecdsa.PrivateKey, msg []byte) (*Proof, error) {
+	N := curve().Params().N
+	if sk == nil || sk.D == nil || sk.D.Sign() <= 0 || sk.D.Cmp(N) >= 0 {
+		return nil, errors.WithStack(ErrInvalidPrivateKey)
+	}
+	hx, hy := hashToPoint(msg)
+	k, err := randScalar()
+	if err != nil {
+		return nil, err
+	}
+	p := &Proof{
+		Gamma: marshalPoint(curve().ScalarMult(hx, hy, sk.D.Bytes())),
+	}
+	a1 := marshalPoint(curve().ScalarBaseMult(k.Bytes()))
+	a2 := marshalPoint(curve().ScalarMult(hx, hy, k.Bytes()))
+	pub := marshalPoint(curve().ScalarBaseMult(sk.D.Bytes()))
+	p.Challenge = challenge(marshalPoint(hx, hy), pub, p.Gamma, a1, a2)
+	p.Response = new(big.Int).Mul(p.Challenge, sk.D)
+	p.Response.Add(p.Response, k)
+	p.Response.Mod(p.Response, N)
+	return p, nil
+}
+
+// Verify checks the proof of the message by the public key and returns the output
+func Verify(pub common.PublicKey, msg []byte, p *Proof) (hash.Hash256, error) {
+	N := curve().Params().N
+	if p.Challenge == nil || p.Response == nil || p.Challenge.Cmp(N) >= 0 || p.Response.Cmp(N) >= 0 {
+		return hash.Hash256{}, errors.WithStack(ErrInvalidProof)
+	}
+	px, py, err := unmarshalPoint(pub)
+	if err != nil {
+		return hash.Hash256{}, err
+	}
+	gx, gy, err := unmarshalPoint(p.Gamma)
+	if err != nil {
+		return hash.Hash256{}, err
+	}
+	hx, hy := hashToPoint(msg)
+	negC := new(big.Int).Sub(N, p.Challenge)
+	// A1 = z*G - c*P, A2 = z*H - c*Gamma
+	zgx, zgy := pointOf(curve().ScalarBaseMult(p.Response.Bytes()))
+	cpx, cpy := pointOf(curve().ScalarMult(px, py, negC.Bytes()))
+	a1x, a1y := curve().Add(zgx, zgy, cpx, cpy)
+	zhx, zhy := pointOf(curve().ScalarMult(hx, hy, p.Response.Bytes()))
+	cgx, cgy := pointOf(curve().ScalarMult(gx, gy, negC.Bytes()))
+	a2x, a2y := curve().Add(zhx, zhy, cgx, cgy)
+	c := challenge(marshalPoint(hx, hy), pub, p.Gamma, marshalPoint(a1x, a1y), marshalPoint(a2x, a2y))
+	if c.Cmp(p.Challenge) != 0 {
+		return hash.Hash256{}, errors.WithStack(ErrInvalidProof)
+	}
+	return p.Output(), nil
+}
+
+// Output returns the random output of the proof, it should be used after Verify
+func (p *Proof) Output() hash.Hash256 {
+	return hash.Hash([]byte("meverse.vrf"), p.Gamma[:])
+}
+
+func (p *Proof) WriteTo(w io.Writer) (int64, error) {
+	sw := bin.NewSumWriter()
+	if sum, err := sw.PublicKey(w, p.Gamma); err != nil {
+		return sum, err
+	}
+	if sum, err := sw.BigInt(w, p.Challenge); err != nil {
+		return sum, err
+	}
+	if sum, err := sw.BigInt(w, p.Response); err != nil {
+		return sum, err
+	}
+	return sw.Sum(), nil
+}
+
+func (p *Proof) ReadFrom(r io.Reader) (int64, error) {
+	sr := bin.NewSumReader()
+	if sum, err := sr.PublicKey(r, &p.Gamma); err != nil {
+		return sum, err
+	}
+	if sum, err := sr.BigInt(r, &p.Challenge); err != nil {
+		return sum, err
+	}
+	if sum, err := sr.BigInt(r, &p.Response); err != nil {
+		return sum, err
+	}
+	return sr.Sum(), nil
+}
+
+func curve() elliptic.Curve {
+	return crypto.S256()
+}
+
+// hashToPoint maps the message to the point by the try and increment, y is chosen to be even
+func hashToPoint(msg []byte) (*big.Int, *big.Int) {
+	params := curve().Params()
+	seven := big.NewInt(7)
+	ctr := make([]byte, 4)
+	for i := uint32(0); ; i++ {
+		binary.BigEndian.PutUint32(ctr, i)
+		h := hash.Hash([]byte("meverse.vrf.h2c"), msg, ctr)
+		x := new(big.Int).SetBytes(h[:])
+		if x.Cmp(params.P) >= 0 {
+			continue
+		}
+		y2 := new(big.Int).Exp(x, big.NewInt(3), params.P)
+		y2.Add(y2, seven)
+		y2.Mod(y2, params.P)
+		y := new(big.Int).ModSqrt(y2, params.P)
+		if y == nil {
+			continue
+		}
+		if y.Bit(0) == 1 {
+			y.Sub(params.P, y)
+		}
+		return x, y
+	}
+}
+
+func randScalar() (*big.Int, error) {
+	N := curve().Params().N
+	for {
+		v, err := rand.Int(rand.Reader, N)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if v.Sign() > 0 {
+			return v, nil
+		}
+	}
+}
+
+func pointOf(x, y *big.Int) (*big.Int, *big.Int) {
+	if x == nil {
+		return new(big.Int), new(big.Int)
+	}
+	return x, y
+}
+
+func marshalPoint(x, y *big.Int) common.PublicKey {
+	var p common.PublicKey
+	if x == nil {
+		return p
+	}
+	copy(p[:], elliptic.Marshal(curve(), x, y))
+	return p
+}
+
+func unmarshalPoint(p common.PublicKey) (*big.Int, *big.Int, error) {
+	x, y := elliptic.Unmarshal(curve(), p[:])
+	if x == nil {
+		return nil, nil, errors.WithStack(ErrInvalidPoint)
+	}
+	return x, y, nil
+}
+
+func challenge(points ...common.PublicKey) *big.Int {
+	data := make([][]byte, 0, len(points))
+	for i := range points {
+		data = append(data, points[i][:])
+	}
+	h := hash.Hash(data...)
+	c := new(big.Int).SetBytes(h[:])
+	return c.Mod(c, curve().Params().N)
+}
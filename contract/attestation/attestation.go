@@ -0,0 +1,197 @@
+package attestation
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/core/types"
+)
+
+// Version is the chain version from which the single sender paths check the attestation threshold of the platform
+const Version = uint16(5)
+
+// MaxRelayers is the max count of the relayer set
+const MaxRelayers = 64
+
+// MinThreshold is the min count of the relayer signatures of the attestation mode
+// a single signature trusts one key as the single sender path does, so the attestation requires two signatures at least
+const MinThreshold = uint16(2)
+
+var hashPrefix = []byte("MeverseAttestation")
+
+// sub keys under the tag of the contract
+var (
+	subRelayers  = byte(0x01)
+	subEpoch     = byte(0x02)
+	subThreshold = byte(0x03)
+	subPlatforms = byte(0x04)
+)
+
+func makeKey(tag byte, sub byte, body []byte) []byte {
+	bs := make([]byte, 2+len(body))
+	bs[0] = tag
+	bs[1] = sub
+	copy(bs[2:], body)
+	return bs
+}
+
+// SetRelayers replaces the relayer set of the contract
+// the epoch is increased so that the signatures of the previous set are not accepted anymore
+// the set can not be smaller than the threshold of any platform, otherwise the platform can not be attested at all
+func SetRelayers(cc *types.ContractContext, tag byte, relayers []common.Address) error {
+	if len(relayers) > MaxRelayers {
+		return ErrInvalidRelayer
+	}
+	for _, p := range platforms(cc, tag) {
+		if int(Threshold(cc, tag, p)) > len(relayers) {
+			return ErrInvalidThreshold
+		}
+	}
+	bs := make([]byte, 0, len(relayers)*common.AddressLength)
+	has := map[common.Address]bool{}
+	for _, r := range relayers {
+		if r == common.ZeroAddr {
+			return ErrInvalidRelayer
+		}
+		if has[r] {
+			return ErrDuplicatedRelayer
+		}
+		has[r] = true
+		bs = append(bs, r[:]...)
+	}
+	cc.SetContractData(makeKey(tag, subRelayers, nil), bs)
+	cc.SetContractData(makeKey(tag, subEpoch, nil), bin.Uint64Bytes(Epoch(cc, tag)+1))
+	return nil
+}
+
+// SetThreshold sets the count of the relayer signatures which is required for the platform
+// zero threshold disables the attestation mode of the platform
+func SetThreshold(cc *types.ContractContext, tag byte, Platform string, threshold uint16) error {
+	if threshold != 0 && threshold < MinThreshold {
+		return ErrInvalidThreshold
+	}
+	if int(threshold) > len(Relayers(cc, tag)) {
+		return ErrInvalidThreshold
+	}
+	Platform = strings.ToLower(Platform)
+	key := makeKey(tag, subThreshold, []byte(Platform))
+	ps := []string{}
+	for _, p := range platforms(cc, tag) {
+		if p != Platform {
+			ps = append(ps, p)
+		}
+	}
+	if threshold == 0 {
+		cc.SetContractData(key, nil)
+	} else {
+		cc.SetContractData(key, bin.Uint16Bytes(threshold))
+		ps = append(ps, Platform)
+	}
+	cc.SetContractData(makeKey(tag, subPlatforms, nil), []byte(strings.Join(ps, "\x00")))
+	return nil
+}
+
+// platforms returns the platforms which are in the attestation mode
+func platforms(cc types.ContractLoader, tag byte) []string {
+	bs := cc.ContractData(makeKey(tag, subPlatforms, nil))
+	if len(bs) == 0 {
+		return nil
+	}
+	return strings.Split(string(bs), "\x00")
+}
+
+// Relayers returns the relayer set of the contract
+func Relayers(cc types.ContractLoader, tag byte) []common.Address {
+	bs := cc.ContractData(makeKey(tag, subRelayers, nil))
+	relayers := make([]common.Address, 0, len(bs)/common.AddressLength)
+	for i := 0; i+common.AddressLength <= len(bs); i += common.AddressLength {
+		relayers = append(relayers, common.BytesToAddress(bs[i:i+common.AddressLength]))
+	}
+	return relayers
+}
+
+// Epoch returns the rotation count of the relayer set
+func Epoch(cc types.ContractLoader, tag byte) uint64 {
+	bs := cc.ContractData(makeKey(tag, subEpoch, nil))
+	if len(bs) != 8 {
+		return 0
+	}
+	return bin.Uint64(bs)
+}
+
+// Threshold returns the count of the relayer signatures which is required for the platform
+func Threshold(cc types.ContractLoader, tag byte, Platform string) uint16 {
+	bs := cc.ContractData(makeKey(tag, subThreshold, []byte(strings.ToLower(Platform))))
+	if len(bs) != 2 {
+		return 0
+	}
+	return bin.Uint16(bs)
+}
+
+// CheckSinglePath returns ErrAttestationRequired when the platform is in the attestation mode
+// it doesn't read the threshold before the Version to keep the gas of the past blocks
+func CheckSinglePath(cc *types.ContractContext, tag byte, Platform string) error {
+	if cc.Version(cc.TargetHeight()) < Version {
+		return nil
+	}
+	if Threshold(cc, tag, Platform) > 0 {
+		return ErrAttestationRequired
+	}
+	return nil
+}
+
+// Hash returns the hash which is signed by the relayers for the deposit
+// it is bound to the chain, the contract and the epoch of the relayer set
+func Hash(chainID *big.Int, cont common.Address, epoch uint64, Platform string, ercHash string, asset common.Address, to common.Address, Amount *amount.Amount) hash.Hash256 {
+	var value *big.Int
+	if Amount != nil {
+		value = Amount.Int
+	}
+	eh := hash.HexToHash(ercHash)
+	return crypto.Keccak256Hash(
+		hashPrefix,
+		common.EIP712Uint256(chainID),
+		common.EIP712Address(cont),
+		common.EIP712Uint256(new(big.Int).SetUint64(epoch)),
+		crypto.Keccak256([]byte(strings.ToLower(Platform))),
+		eh[:],
+		common.EIP712Address(asset),
+		common.EIP712Address(to),
+		common.EIP712Uint256(value),
+	)
+}
+
+// Verify checks that the distinct relayers of the current set signed the deposit as many as the threshold of the platform
+func Verify(cc *types.ContractContext, tag byte, cont common.Address, Platform string, ercHash string, asset common.Address, to common.Address, Amount *amount.Amount, Sigs [][]byte) error {
+	threshold := Threshold(cc, tag, Platform)
+	if threshold == 0 {
+		return ErrNotEnabled
+	}
+	relayers := Relayers(cc, tag)
+	isRelayer := make(map[common.Address]bool, len(relayers))
+	for _, r := range relayers {
+		isRelayer[r] = true
+	}
+
+	h := Hash(cc.ChainID(), cont, Epoch(cc, tag), Platform, ercHash, asset, to, Amount)
+	signed := map[common.Address]bool{}
+	for _, sig := range Sigs {
+		signer, err := common.RecoverTypedSigner(cc.ChainID(), h, common.Signature(sig))
+		if err != nil {
+			continue
+		}
+		if isRelayer[signer] {
+			signed[signer] = true
+		}
+	}
+	if len(signed) < int(threshold) {
+		return ErrNotEnoughSignatures
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+package attestation
+
+import "errors"
+
+// attestation errors
+var (
+	ErrNotEnabled          = errors.New("attestation is not enabled")
+	ErrAttestationRequired = errors.New("attestation is required")
+	ErrInvalidRelayer      = errors.New("invalid relayer")
+	ErrDuplicatedRelayer   = errors.New("duplicated relayer")
+	ErrInvalidThreshold    = errors.New("invalid threshold")
+	ErrNotEnoughSignatures = errors.New("not enough relayer signatures")
+)
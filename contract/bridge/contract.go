@@ -10,6 +10,8 @@ import (
 	"github.com/meverselabs/meverse/common"
 	"github.com/meverselabs/meverse/common/amount"
 	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/contract/attestation"
+	"github.com/meverselabs/meverse/contract/ratelimit"
 	"github.com/meverselabs/meverse/core/types"
 )
 
@@ -120,6 +122,9 @@ func (cont *BridgeContract) sendToGateway(cc *types.ContractContext, token commo
 	if !amt.IsPlus() {
 		return errors.New("sendToGateway: plus amount")
 	}
+	if err := ratelimit.Consume(cc, tagRateLimit, ratelimit.Outbound, token, toChain, amt); err != nil {
+		return err
+	}
 	mt := *cc.MainToken()
 	transferFee := cont.transferFeeInfoToChain(cc, toChain)
 	isDelegateFee := cont.isDelegateTransferTokenFee(cc, toChain)
@@ -148,6 +153,11 @@ func (cont *BridgeContract) sendToGateway(cc *types.ContractContext, token commo
 			return err
 		}
 	}
+	// the large transfer is locked in the contract and the relayers deliver it when the queued transfer is executed
+	if ratelimit.IsLarge(cc, tagRateLimit, ratelimit.Outbound, token, toChain, amt) {
+		ratelimit.Enqueue(cc, tagRateLimit, ratelimit.Outbound, token, cc.From(), cc.From(), amt, toChain)
+		return nil
+	}
 	cont.addSequenceFrom(cc, cc.From(), toChain)
 	return nil
 }
@@ -240,6 +250,59 @@ func (cont *BridgeContract) sendFromGateway(cc *types.ContractContext, token com
 			return errors.New("not banker")
 		}
 	}
+	if err := attestation.CheckSinglePath(cc, tagAttestation, fromChain); err != nil {
+		return err
+	}
+	return cont._sendFromGateway(cc, token, to, amt, path, fromChain, summary)
+}
+
+// attestedSendFromGateway releases the deposit which is signed by the relayers as many as the threshold of the fromChain
+func (cont *BridgeContract) attestedSendFromGateway(cc *types.ContractContext, token common.Address, to common.Address, amt *amount.Amount, path []common.Address, fromChain string, ercHash string, summary []byte, Sigs [][]byte) error {
+	if err := attestation.Verify(cc, tagAttestation, cont.addr, fromChain, ercHash, token, to, amt, Sigs); err != nil {
+		return err
+	}
+	key := makeAttestedDepositKey(fromChain, ercHash)
+	if bs := cc.ContractData(key); len(bs) == 1 && bs[0] == 1 {
+		return errors.New("sendFromGateway: already processed " + ercHash)
+	}
+	cc.SetContractData(key, []byte{1})
+	return cont._sendFromGateway(cc, token, to, amt, path, fromChain, summary)
+}
+
+func (cont *BridgeContract) _sendFromGateway(cc *types.ContractContext, token common.Address, to common.Address, amt *amount.Amount, path []common.Address, fromChain string, summary []byte) error {
+	if err := ratelimit.Consume(cc, tagRateLimit, ratelimit.Inbound, token, fromChain, amt); err != nil {
+		return err
+	}
+	// the large transfer is counted as delivered for the relayer but it is released after the delay
+	if ratelimit.IsLarge(cc, tagRateLimit, ratelimit.Inbound, token, fromChain, amt) {
+		ratelimit.Enqueue(cc, tagRateLimit, ratelimit.Inbound, token, cc.From(), to, amt, fromChain)
+		cont.addSequenceTo(cc, to, fromChain)
+		return nil
+	}
+	return cont.release(cc, token, to, amt, fromChain, true)
+}
+
+// executeQueued releases the queued transfer after the delay, anyone can execute it
+// the pause of the route is checked again at the execution so that the guardian can hold the queued transfers
+func (cont *BridgeContract) executeQueued(cc *types.ContractContext, id uint64) error {
+	t, err := ratelimit.Queued(cc, tagRateLimit, id)
+	if err != nil {
+		return err
+	}
+	if ratelimit.IsPaused(cc, tagRateLimit, t.Dir, t.Token, t.Chain) {
+		return ratelimit.ErrPaused
+	}
+	if _, err := ratelimit.Dequeue(cc, tagRateLimit, id, false); err != nil {
+		return err
+	}
+	if t.Dir == ratelimit.Outbound {
+		cont.addSequenceFrom(cc, t.From, t.Chain)
+		return nil
+	}
+	return cont.release(cc, t.Token, t.To, t.Amount, t.Chain, false)
+}
+
+func (cont *BridgeContract) release(cc *types.ContractContext, token common.Address, to common.Address, amt *amount.Amount, fromChain string, countSequence bool) error {
 	// uint256 amountChangedDecimal = getTokenAmount(fromChain, token, amount);
 	// require(
 	// 	IERC20(token).balanceOf(address(this)) >= amountChangedDecimal,
@@ -278,7 +341,9 @@ func (cont *BridgeContract) sendFromGateway(cc *types.ContractContext, token com
 			return err
 		}
 		// getSequenceTo[to][fromChain]++;
-		cont.addSequenceTo(cc, to, fromChain)
+		if countSequence {
+			cont.addSequenceTo(cc, to, fromChain)
+		}
 	}
 	err := cont.sendMainToken(cc, fromChain, to, amt)
 	if err != nil {
@@ -298,6 +363,83 @@ func (cont *BridgeContract) checkOwner(cc *types.ContractContext) bool {
 	return true
 }
 
+func (cont *BridgeContract) setRelayers(cc *types.ContractContext, relayers []common.Address) error {
+	if cc.From() != cont.Master() {
+		return errors.New("not owner")
+	}
+	return attestation.SetRelayers(cc, tagAttestation, relayers)
+}
+
+func (cont *BridgeContract) setAttestationThreshold(cc *types.ContractContext, chain string, threshold uint16) error {
+	if cc.From() != cont.Master() {
+		return errors.New("not owner")
+	}
+	return attestation.SetThreshold(cc, tagAttestation, chain, threshold)
+}
+
+func (cont *BridgeContract) setGuardian(cc *types.ContractContext, guardian common.Address) error {
+	if cc.From() != cont.Master() {
+		return errors.New("not owner")
+	}
+	ratelimit.SetGuardian(cc, tagRateLimit, guardian)
+	return nil
+}
+
+// pauseRoute stops the transfers of the route instantly, the zero token pauses all tokens of the chain
+func (cont *BridgeContract) pauseRoute(cc *types.ContractContext, token common.Address, chain string, inbound bool) error {
+	if cc.From() != cont.Master() && cc.From() != ratelimit.Guardian(cc, tagRateLimit) {
+		return ratelimit.ErrNotGuardian
+	}
+	ratelimit.SetPaused(cc, tagRateLimit, routeDirection(inbound), token, chain, true)
+	return nil
+}
+
+func (cont *BridgeContract) unpauseRoute(cc *types.ContractContext, token common.Address, chain string, inbound bool) error {
+	if cc.From() != cont.Master() {
+		return errors.New("not owner")
+	}
+	ratelimit.SetPaused(cc, tagRateLimit, routeDirection(inbound), token, chain, false)
+	return nil
+}
+
+func (cont *BridgeContract) setRateLimit(cc *types.ContractContext, token common.Address, chain string, inbound bool, limit *amount.Amount, window uint64) error {
+	if cc.From() != cont.Master() {
+		return errors.New("not owner")
+	}
+	return ratelimit.SetLimit(cc, tagRateLimit, routeDirection(inbound), token, chain, limit, window)
+}
+
+// setLargeTransferDelay queues the transfer of the route for the delay in seconds when the amount is over the threshold
+// the inbound route queues the sendFromGateway and the outbound route queues the sendToGateway
+func (cont *BridgeContract) setLargeTransferDelay(cc *types.ContractContext, token common.Address, chain string, inbound bool, threshold *amount.Amount, delay uint64) error {
+	if cc.From() != cont.Master() {
+		return errors.New("not owner")
+	}
+	return ratelimit.SetDelay(cc, tagRateLimit, routeDirection(inbound), token, chain, threshold, delay)
+}
+
+// cancelQueued drops the queued transfer, the inbound token stays in the contract and the outbound token is refunded to the sender
+func (cont *BridgeContract) cancelQueued(cc *types.ContractContext, id uint64) error {
+	if cc.From() != cont.Master() {
+		return errors.New("not owner")
+	}
+	t, err := ratelimit.Dequeue(cc, tagRateLimit, id, true)
+	if err != nil {
+		return err
+	}
+	if t.Dir == ratelimit.Outbound {
+		return safeTransfer(cc, t.Token, t.From, t.Amount)
+	}
+	return nil
+}
+
+func routeDirection(inbound bool) byte {
+	if inbound {
+		return ratelimit.Inbound
+	}
+	return ratelimit.Outbound
+}
+
 func (cont *BridgeContract) setTransferFeeInfo(cc *types.ContractContext, chain string, transferFee *amount.Amount) error {
 	if !cont.checkOwner(cc) {
 		return errors.New("not owner")
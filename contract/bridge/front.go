@@ -5,6 +5,8 @@ import (
 
 	"github.com/meverselabs/meverse/common"
 	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/contract/attestation"
+	"github.com/meverselabs/meverse/contract/ratelimit"
 	"github.com/meverselabs/meverse/core/types"
 )
 
@@ -24,6 +26,9 @@ type front struct {
 func (f *front) SendToGateway(cc *types.ContractContext, token common.Address, amt *amount.Amount, path []common.Address, toChain string, summary []byte) error {
 	return f.cont.sendToGateway(cc, token, amt, path, toChain, summary)
 }
+func (f *front) ExecuteQueued(cc *types.ContractContext, id uint64) error {
+	return f.cont.executeQueued(cc, id)
+}
 
 //////////////////////////////////////////////////
 // Public Writer only owner Functions
@@ -64,6 +69,38 @@ func (f *front) TransferTokenFeeOwnership(cc *types.ContractContext, newFeeOwner
 	return f.cont.transferTokenFeeOwnership(cc, newFeeOwner)
 }
 
+func (f *front) SetRelayers(cc *types.ContractContext, relayers []common.Address) error {
+	return f.cont.setRelayers(cc, relayers)
+}
+
+func (f *front) SetAttestationThreshold(cc *types.ContractContext, chain string, threshold uint16) error {
+	return f.cont.setAttestationThreshold(cc, chain, threshold)
+}
+
+func (f *front) SetGuardian(cc *types.ContractContext, guardian common.Address) error {
+	return f.cont.setGuardian(cc, guardian)
+}
+
+func (f *front) PauseRoute(cc *types.ContractContext, token common.Address, chain string, inbound bool) error {
+	return f.cont.pauseRoute(cc, token, chain, inbound)
+}
+
+func (f *front) UnpauseRoute(cc *types.ContractContext, token common.Address, chain string, inbound bool) error {
+	return f.cont.unpauseRoute(cc, token, chain, inbound)
+}
+
+func (f *front) SetRateLimit(cc *types.ContractContext, token common.Address, chain string, inbound bool, limit *amount.Amount, window uint64) error {
+	return f.cont.setRateLimit(cc, token, chain, inbound, limit, window)
+}
+
+func (f *front) SetLargeTransferDelay(cc *types.ContractContext, token common.Address, chain string, inbound bool, threshold *amount.Amount, delay uint64) error {
+	return f.cont.setLargeTransferDelay(cc, token, chain, inbound, threshold, delay)
+}
+
+func (f *front) CancelQueued(cc *types.ContractContext, id uint64) error {
+	return f.cont.cancelQueued(cc, id)
+}
+
 func (f *front) ReclaimToken(cc *types.ContractContext, token common.Address, amt *amount.Amount) error {
 	return f.cont.reclaimToken(cc, token, amt)
 }
@@ -75,6 +112,10 @@ func (f *front) SendFromGateway(cc *types.ContractContext, token common.Address,
 	return f.cont.sendFromGateway(cc, token, to, amt, path, fromChain, summary)
 }
 
+func (f *front) AttestedSendFromGateway(cc *types.ContractContext, token common.Address, to common.Address, amt *amount.Amount, path []common.Address, fromChain string, ercHash string, summary []byte, Sigs [][]byte) error {
+	return f.cont.attestedSendFromGateway(cc, token, to, amt, path, fromChain, ercHash, summary, Sigs)
+}
+
 //////////////////////////////////////////////////
 // Public Reader Functions
 //////////////////////////////////////////////////
@@ -117,6 +158,58 @@ func (f *front) StringToBytes32(source string) []byte {
 	return f.cont.stringToBytes32(source)
 }
 
+func (f *front) Relayers(cc types.ContractLoader) []common.Address {
+	return attestation.Relayers(cc, tagAttestation)
+}
+
+func (f *front) RelayerEpoch(cc types.ContractLoader) uint64 {
+	return attestation.Epoch(cc, tagAttestation)
+}
+
+func (f *front) AttestationThreshold(cc types.ContractLoader, chain string) uint16 {
+	return attestation.Threshold(cc, tagAttestation, chain)
+}
+
+func (f *front) Guardian(cc types.ContractLoader) common.Address {
+	return ratelimit.Guardian(cc, tagRateLimit)
+}
+
+func (f *front) IsRoutePaused(cc types.ContractLoader, token common.Address, chain string, inbound bool) bool {
+	return ratelimit.IsPaused(cc, tagRateLimit, routeDirection(inbound), token, chain)
+}
+
+// RateLimit returns the limit and the window in seconds of the route
+func (f *front) RateLimit(cc types.ContractLoader, token common.Address, chain string, inbound bool) (*amount.Amount, uint64) {
+	return ratelimit.Limit(cc, tagRateLimit, routeDirection(inbound), token, chain)
+}
+
+// AvailableRateLimit returns the amount which can be transferred through the route now and false when the route is not limited
+func (f *front) AvailableRateLimit(cc *types.ContractContext, token common.Address, chain string, inbound bool) (*amount.Amount, bool) {
+	avail, limited := ratelimit.Available(cc, tagRateLimit, routeDirection(inbound), token, chain, cc.LastTimestamp())
+	if !limited {
+		return amount.NewAmount(0, 0), false
+	}
+	return avail, true
+}
+
+// LargeTransferDelay returns the threshold and the delay in seconds of the route
+func (f *front) LargeTransferDelay(cc types.ContractLoader, token common.Address, chain string, inbound bool) (*amount.Amount, uint64) {
+	return ratelimit.Delay(cc, tagRateLimit, routeDirection(inbound), token, chain)
+}
+
+func (f *front) QueuedTransferIDs(cc types.ContractLoader) []uint64 {
+	return ratelimit.QueuedIDs(cc, tagRateLimit)
+}
+
+// QueuedTransfer returns the token, the receiver, the amount, the chain, the direction and the ready timestamp of the queued transfer
+func (f *front) QueuedTransfer(cc types.ContractLoader, id uint64) (common.Address, common.Address, *amount.Amount, string, bool, uint64, error) {
+	t, err := ratelimit.Queued(cc, tagRateLimit, id)
+	if err != nil {
+		return common.ZeroAddr, common.ZeroAddr, nil, "", false, 0, err
+	}
+	return t.Token, t.To, t.Amount, t.Chain, t.Dir == ratelimit.Inbound, t.ReadyAt, nil
+}
+
 func (f *front) SetSendMaintoken(cc *types.ContractContext, store common.Address, fromChains []string, overthens, amts []*amount.Amount) error {
 	return f.cont.setSendMaintoken(cc, store, fromChains, overthens, amts)
 }
@@ -0,0 +1,201 @@
+package test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/contract/ratelimit"
+	"github.com/meverselabs/meverse/core/chain"
+	"github.com/meverselabs/meverse/extern/test/util"
+)
+
+func sendFromGateway(tc *util.TestContext, to common.Address, am *amount.Amount) error {
+	_, err := tc.SendTx(bankerKey, bridgeAddr, "SendFromGateway", testToken, to, am, []common.Address{}, "ETHEREUM", []byte("ETHEREUM/MEVERSE/TTEST"))
+	return err
+}
+
+func TestRateLimit(t *testing.T) {
+	chain.SetVersion(1, ratelimit.Version)
+	defer chain.SetVersion(1, 2)
+
+	tc := setupTest()
+	to := util.Users[9]
+	tc.MustSendTx(util.AdminKey, bridgeAddr, "SetRateLimit", testToken, "ETHEREUM", true, amount.NewAmount(100, 0), uint64(3600))
+
+	if err := sendFromGateway(tc, to, amount.NewAmount(60, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := sendFromGateway(tc, to, amount.NewAmount(50, 0)); err == nil {
+		t.Fatal("transfer over the limit is sent")
+	}
+	// the other chain has its own limit
+	if _, err := tc.SendTx(bankerKey, bridgeAddr, "SendFromGateway", testToken, to, amount.NewAmount(50, 0), []common.Address{}, "BSC", []byte("BSC/MEVERSE/TTEST")); err != nil {
+		t.Fatal(err)
+	}
+
+	// the half of the limit is recovered after the half of the window
+	if err := tc.Sleep(1800, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	is, err := tc.ReadTx(util.AdminKey, bridgeAddr, "AvailableRateLimit", testToken, "ETHEREUM", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if avail := is[0].(*amount.Amount); avail.Cmp(amount.NewAmount(90, 0).Int) < 0 || avail.Cmp(amount.NewAmount(91, 0).Int) > 0 {
+		t.Fatalf("available %v, want about 90", avail)
+	}
+	if err := sendFromGateway(tc, to, amount.NewAmount(50, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if bal := getBal(tc, testToken, to, t, "TestRateLimit"); bal.Cmp(amount.NewAmount(160, 0).Int) != 0 {
+		t.Fatalf("balance %v, want 160", bal)
+	}
+}
+
+func TestPauseRoute(t *testing.T) {
+	chain.SetVersion(1, ratelimit.Version)
+	defer chain.SetVersion(1, 2)
+
+	tc := setupTest()
+	guardian := util.Users[5]
+	tc.MustSendTx(util.AdminKey, bridgeAddr, "SetGuardian", guardian)
+	tc.MustSendTx(util.AdminKey, tc.MainToken, "Transfer", guardian, amount.NewAmount(100, 0))
+	tc.MustSendTx(util.AdminKey, tc.MainToken, "Transfer", util.Users[6], amount.NewAmount(100, 0))
+
+	if _, err := tc.SendTx(util.UserKeys[6], bridgeAddr, "PauseRoute", testToken, "ETHEREUM", true); err == nil {
+		t.Fatal("route is paused by the other user")
+	}
+	tc.MustSendTx(util.UserKeys[5], bridgeAddr, "PauseRoute", common.ZeroAddr, "ETHEREUM", true)
+	if err := sendFromGateway(tc, util.Users[9], amount.NewAmount(1, 0)); err == nil {
+		t.Fatal("transfer is sent through the paused route")
+	}
+	if is, err := tc.ReadTx(util.AdminKey, bridgeAddr, "IsRoutePaused", testToken, "ETHEREUM", false); err != nil {
+		t.Fatal(err)
+	} else if is[0].(bool) {
+		t.Fatal("outbound route is paused by the inbound pause")
+	}
+	if _, err := tc.SendTx(util.UserKeys[5], bridgeAddr, "UnpauseRoute", common.ZeroAddr, "ETHEREUM", true); err == nil {
+		t.Fatal("route is resumed by the guardian")
+	}
+	tc.MustSendTx(util.AdminKey, bridgeAddr, "UnpauseRoute", common.ZeroAddr, "ETHEREUM", true)
+	if err := sendFromGateway(tc, util.Users[9], amount.NewAmount(1, 0)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestQueuedTransfer(t *testing.T) {
+	chain.SetVersion(1, ratelimit.Version)
+	defer chain.SetVersion(1, 2)
+
+	tc := setupTest()
+	to := util.Users[9]
+	tc.MustSendTx(util.AdminKey, tc.MainToken, "Transfer", util.Users[6], amount.NewAmount(100, 0))
+	tc.MustSendTx(util.AdminKey, bridgeAddr, "SetLargeTransferDelay", testToken, "ETHEREUM", true, amount.NewAmount(100, 0), uint64(600))
+
+	if err := sendFromGateway(tc, to, amount.NewAmount(100, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if bal := getBal(tc, testToken, to, t, "TestQueuedTransfer"); !bal.IsZero() {
+		t.Fatalf("queued transfer is released %v", bal)
+	}
+	// the relayer sees the queued transfer as delivered
+	is, err := tc.ReadTx(util.AdminKey, bridgeAddr, "GetSequenceTo", to, "ETHEREUM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seq := is[0].(*big.Int); seq.Int64() != 1 {
+		t.Fatalf("sequence %v, want 1", seq)
+	}
+	if _, err := tc.SendTx(util.UserKeys[6], bridgeAddr, "ExecuteQueued", uint64(1)); err == nil {
+		t.Fatal("queued transfer is executed before the delay")
+	}
+	if err := tc.Sleep(600, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	tc.MustSendTx(util.UserKeys[6], bridgeAddr, "ExecuteQueued", uint64(1))
+	if bal := getBal(tc, testToken, to, t, "TestQueuedTransfer"); bal.Cmp(amount.NewAmount(100, 0).Int) != 0 {
+		t.Fatalf("balance %v, want 100", bal)
+	}
+	if _, err := tc.SendTx(util.UserKeys[6], bridgeAddr, "ExecuteQueued", uint64(1)); err == nil {
+		t.Fatal("queued transfer is executed twice")
+	}
+
+	if err := sendFromGateway(tc, to, amount.NewAmount(200, 0)); err != nil {
+		t.Fatal(err)
+	}
+	is, err = tc.ReadTx(util.AdminKey, bridgeAddr, "QueuedTransferIDs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := is[0].([]interface{}); len(ids) != 1 || ids[0].(uint64) != 2 {
+		t.Fatalf("queued ids %v, want [2]", ids)
+	}
+	if _, err := tc.SendTx(util.UserKeys[6], bridgeAddr, "CancelQueued", uint64(2)); err == nil {
+		t.Fatal("queued transfer is canceled by the other user")
+	}
+	tc.MustSendTx(util.AdminKey, bridgeAddr, "CancelQueued", uint64(2))
+	if err := tc.Sleep(600, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tc.SendTx(util.UserKeys[6], bridgeAddr, "ExecuteQueued", uint64(2)); err == nil {
+		t.Fatal("canceled transfer is executed")
+	}
+}
+
+func TestQueuedSendToGateway(t *testing.T) {
+	chain.SetVersion(1, ratelimit.Version)
+	defer chain.SetVersion(1, 2)
+
+	tc := setupTest()
+	user := testUsers[1]
+	userKey := testUserKeys[1]
+	queueToken := tc.MakeToken("Queue Token", "QTEST", "1000")
+	tc.MustSendTx(util.AdminKey, queueToken, "Transfer", user, amount.NewAmount(1000, 0))
+	tc.MustSendTx(userKey, queueToken, "Approve", bridgeAddr, amount.NewAmount(1000, 0))
+	tc.MustSendTx(userKey, tc.MainToken, "Approve", bridgeAddr, amount.NewAmount(10000000000, 0))
+	tc.MustSendTx(util.AdminKey, bridgeAddr, "SetLargeTransferDelay", queueToken, "KLAYTN", false, amount.NewAmount(100, 0), uint64(600))
+
+	sendToGateway := func(am *amount.Amount) {
+		tc.MustSendTx(userKey, bridgeAddr, "SendToGateway", queueToken, am, []common.Address{}, "KLAYTN", []byte("MEVERSE/KLAYTN/MEV"))
+	}
+	sequenceFrom := func() int64 {
+		is, err := tc.ReadTx(util.AdminKey, bridgeAddr, "GetSequenceFrom", user, "KLAYTN")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return is[0].(*big.Int).Int64()
+	}
+
+	sendToGateway(amount.NewAmount(500, 0))
+	if seq := sequenceFrom(); seq != 0 {
+		t.Fatalf("queued transfer is delivered, sequence %v", seq)
+	}
+	if err := tc.Sleep(600, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	// the route paused after the queue holds the queued transfer
+	tc.MustSendTx(util.AdminKey, bridgeAddr, "PauseRoute", queueToken, "KLAYTN", false)
+	if _, err := tc.SendTx(userKey, bridgeAddr, "ExecuteQueued", uint64(1)); err == nil {
+		t.Fatal("queued transfer is executed through the paused route")
+	}
+	tc.MustSendTx(util.AdminKey, bridgeAddr, "UnpauseRoute", queueToken, "KLAYTN", false)
+	tc.MustSendTx(userKey, bridgeAddr, "ExecuteQueued", uint64(1))
+	if seq := sequenceFrom(); seq != 1 {
+		t.Fatalf("sequence %v, want 1", seq)
+	}
+
+	// the canceled transfer is refunded to the sender
+	sendToGateway(amount.NewAmount(500, 0))
+	if bal := getBal(tc, queueToken, user, t, "TestQueuedSendToGateway"); bal.Cmp(amount.NewAmount(0, 0).Int) != 0 {
+		t.Fatalf("balance %v, want 0", bal)
+	}
+	tc.MustSendTx(util.AdminKey, bridgeAddr, "CancelQueued", uint64(2))
+	if bal := getBal(tc, queueToken, user, t, "TestQueuedSendToGateway"); bal.Cmp(amount.NewAmount(500, 0).Int) != 0 {
+		t.Fatalf("balance %v, want 500", bal)
+	}
+	if seq := sequenceFrom(); seq != 1 {
+		t.Fatalf("canceled transfer is delivered, sequence %v", seq)
+	}
+}
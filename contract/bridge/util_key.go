@@ -1,7 +1,10 @@
 package bridge
 
 import (
+	"strings"
+
 	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/hash"
 )
 
 var (
@@ -27,6 +30,11 @@ var (
 	tagTokenFeeOwnerAddress = byte(0x15)
 
 	tagDelegateTransferFeeInfoToChain = byte(0x16)
+
+	tagAttestation      = byte(0x17)
+	tagAttestedDeposits = byte(0x18)
+
+	tagRateLimit = byte(0x19)
 )
 
 func makeBridgeKey(key byte, body []byte) []byte {
@@ -53,6 +61,10 @@ func makeTransferTokenFeeInfoToChain(chain string) []byte {
 func makeTokenFeeInfoFromChain(chain string) []byte {
 	return makeBridgeKey(tagTokenFeeInfoFromChain, []byte(chain))
 }
+func makeAttestedDepositKey(fromChain string, ercHash string) []byte {
+	h := hash.HexToHash(ercHash)
+	return makeBridgeKey(tagAttestedDeposits, append(h[:], []byte(strings.ToLower(fromChain))...))
+}
 func makeSendMaintokenInfoKey(fromChain string) []byte {
 	return makeBridgeKey(tagSendMaintokenInfo, []byte(fromChain))
 }
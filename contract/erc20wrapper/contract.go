@@ -63,8 +63,11 @@ func (cont *Erc20WrapperContract) Approve(cc *types.ContractContext, spender com
 		return err
 	}
 	erc20 := cont.Erc20Token(cc)
-	_, _, err = cc.EvmCall(vm.AccountRef(cc.From()), erc20, data)
-	return err
+	if _, _, err = cc.EvmCall(vm.AccountRef(cc.From()), erc20, data); err != nil {
+		return err
+	}
+	cc.EmitApproval(cc.From(), spender, Amount.Int)
+	return nil
 }
 
 func (cont *Erc20WrapperContract) Transfer(cc *types.ContractContext, To common.Address, Amount *amount.Amount) error {
@@ -76,8 +79,11 @@ func (cont *Erc20WrapperContract) Transfer(cc *types.ContractContext, To common.
 		return err
 	}
 	erc20 := cont.Erc20Token(cc)
-	_, _, err = cc.EvmCall(vm.AccountRef(cc.From()), erc20, data)
-	return err
+	if _, _, err = cc.EvmCall(vm.AccountRef(cc.From()), erc20, data); err != nil {
+		return err
+	}
+	cc.EmitTransfer(cc.From(), To, Amount.Int)
+	return nil
 }
 
 func (cont *Erc20WrapperContract) TransferFrom(cc *types.ContractContext, From common.Address, To common.Address, Amount *amount.Amount) error {
@@ -89,8 +95,11 @@ func (cont *Erc20WrapperContract) TransferFrom(cc *types.ContractContext, From c
 		return err
 	}
 	erc20 := cont.Erc20Token(cc)
-	_, _, err = cc.EvmCall(vm.AccountRef(cc.From()), erc20, data)
-	return err
+	if _, _, err = cc.EvmCall(vm.AccountRef(cc.From()), erc20, data); err != nil {
+		return err
+	}
+	cc.EmitTransfer(From, To, Amount.Int)
+	return nil
 }
 
 func (cont *Erc20WrapperContract) IncreaseAllowance(cc *types.ContractContext, spender common.Address, addedValue *amount.Amount) error {
@@ -103,8 +112,10 @@ func (cont *Erc20WrapperContract) IncreaseAllowance(cc *types.ContractContext, s
 	}
 
 	erc20 := cont.Erc20Token(cc)
-	_, _, err = cc.EvmCall(vm.AccountRef(cc.From()), erc20, data)
-	return err
+	if _, _, err = cc.EvmCall(vm.AccountRef(cc.From()), erc20, data); err != nil {
+		return err
+	}
+	return cont.emitAllowance(cc, spender)
 }
 
 func (cont *Erc20WrapperContract) DecreaseAllowance(cc *types.ContractContext, spender common.Address, subtractedValue *amount.Amount) error {
@@ -116,8 +127,10 @@ func (cont *Erc20WrapperContract) DecreaseAllowance(cc *types.ContractContext, s
 		return err
 	}
 	erc20 := cont.Erc20Token(cc)
-	_, _, err = cc.EvmCall(vm.AccountRef(cc.From()), erc20, data)
-	return err
+	if _, _, err = cc.EvmCall(vm.AccountRef(cc.From()), erc20, data); err != nil {
+		return err
+	}
+	return cont.emitAllowance(cc, spender)
 }
 
 func (cont *Erc20WrapperContract) SetMinter(cc *types.ContractContext, To common.Address, Is bool) error {
@@ -146,8 +159,11 @@ func (cont *Erc20WrapperContract) Mint(cc *types.ContractContext, To common.Addr
 		return err
 	}
 	erc20 := cont.Erc20Token(cc)
-	_, _, err = cc.EvmCall(vm.AccountRef(cc.From()), erc20, data)
-	return err
+	if _, _, err = cc.EvmCall(vm.AccountRef(cc.From()), erc20, data); err != nil {
+		return err
+	}
+	cc.EmitTransfer(common.ZeroAddr, To, Amount.Int)
+	return nil
 }
 
 func (cont *Erc20WrapperContract) Burn(cc *types.ContractContext, Amount *amount.Amount) error {
@@ -159,8 +175,11 @@ func (cont *Erc20WrapperContract) Burn(cc *types.ContractContext, Amount *amount
 		return err
 	}
 	erc20 := cont.Erc20Token(cc)
-	_, _, err = cc.EvmCall(vm.AccountRef(cc.From()), erc20, data)
-	return err
+	if _, _, err = cc.EvmCall(vm.AccountRef(cc.From()), erc20, data); err != nil {
+		return err
+	}
+	cc.EmitTransfer(cc.From(), common.ZeroAddr, Amount.Int)
+	return nil
 }
 
 func (cont *Erc20WrapperContract) BurnFrom(cc *types.ContractContext, Addr common.Address, Amount *amount.Amount) error {
@@ -172,8 +191,25 @@ func (cont *Erc20WrapperContract) BurnFrom(cc *types.ContractContext, Addr commo
 		return err
 	}
 	erc20 := cont.Erc20Token(cc)
-	_, _, err = cc.EvmCall(vm.AccountRef(cc.From()), erc20, data)
-	return err
+	if _, _, err = cc.EvmCall(vm.AccountRef(cc.From()), erc20, data); err != nil {
+		return err
+	}
+	cc.EmitTransfer(Addr, common.ZeroAddr, Amount.Int)
+	return nil
+}
+
+// emitAllowance adds the Approval log of the changed allowance
+// the allowance is read only when the logs are written so that the gas of the old blocks is not changed
+func (cont *Erc20WrapperContract) emitAllowance(cc *types.ContractContext, spender common.Address) error {
+	if cc.Version(cc.TargetHeight()) < types.NativeLogVersion {
+		return nil
+	}
+	allowance, err := cont.Allowance(cc, cc.From(), spender)
+	if err != nil {
+		return err
+	}
+	cc.EmitApproval(cc.From(), spender, allowance.Int)
+	return nil
 }
 
 //////////////////////////////////////////////////
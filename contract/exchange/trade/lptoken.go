@@ -63,6 +63,7 @@ func (self *LPToken) _mint(cc *types.ContractContext, to common.Address, amount
 
 	cc.SetAccountData(to, []byte{tagTokenAmount}, balance.Bytes())
 	cc.SetContractData([]byte{tagTokenTotalSupply}, total.Bytes())
+	cc.EmitTransfer(ZeroAddress, to, amount)
 	return nil
 }
 func (self *LPToken) _burn(cc *types.ContractContext, from common.Address, amount *big.Int) error {
@@ -78,6 +79,7 @@ func (self *LPToken) _burn(cc *types.ContractContext, from common.Address, amoun
 
 	cc.SetAccountData(from, []byte{tagTokenAmount}, balance.Bytes())
 	cc.SetContractData([]byte{tagTokenTotalSupply}, total.Bytes())
+	cc.EmitTransfer(from, ZeroAddress, amount)
 	return nil
 }
 
@@ -99,6 +101,7 @@ func (self *LPToken) _approve(cc *types.ContractContext, owner, spender common.A
 		return errors.New("LPToken: APPROVE_NEGATIVE_AMOUNT")
 	}
 	cc.SetAccountData(owner, makeTokenKey(spender, tagTokenApprove), amount.Bytes())
+	cc.EmitApproval(owner, spender, amount)
 	return nil
 }
 func (self *LPToken) _transfer(cc *types.ContractContext, from, to common.Address, amount *big.Int) error {
@@ -118,6 +121,7 @@ func (self *LPToken) _transfer(cc *types.ContractContext, from, to common.Addres
 	fromBalance = Sub(fromBalance, amount)
 	cc.SetAccountData(from, []byte{tagTokenAmount}, fromBalance.Bytes())
 	cc.SetAccountData(to, []byte{tagTokenAmount}, Add(self.balanceOf(cc, to), amount).Bytes())
+	cc.EmitTransfer(from, to, amount)
 	return nil
 }
 
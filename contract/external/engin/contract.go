@@ -2,6 +2,7 @@ package engin
 
 import (
 	"bytes"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -16,10 +17,26 @@ import (
 	"github.com/meverselabs/meverse/common/bin"
 	"github.com/meverselabs/meverse/common/hash"
 	"github.com/meverselabs/meverse/contract/external/deployer"
+	"github.com/meverselabs/meverse/contract/external/engin/wasmengin"
 	"github.com/meverselabs/meverse/core/types"
+	"github.com/meverselabs/meverse/core/wasmvm"
 )
 
+// kinds of the engin binary
+const (
+	EnginKindPlugin = uint8(0)
+	EnginKindWasm   = uint8(1)
+)
+
+// MaxEnginSize is the maximum size of the engin binary to download
+const MaxEnginSize = 64 * 1024 * 1024
+
+// PinnedEnginVersion is the chain version from which the engins are added with the pinned hash
+// AddEngin without the hash keeps working before it so that the previous blocks are replayed as they were
+const PinnedEnginVersion = uint16(9)
+
 var enginCache = map[string]types.IEngin{}
+var enginCacheLock sync.Mutex
 
 type EnginContract struct {
 	addr   common.Address
@@ -83,7 +100,10 @@ func (cont *EnginContract) NextContractSeq(cc *types.ContractContext) uint32 {
 
 var fileLock sync.Mutex
 
-func (cont *EnginContract) saveEngin(Name string, Version uint32, EnginURL string) error {
+func (cont *EnginContract) saveEngin(Name string, Version uint32, EnginURL string, EnginHash hash.Hash256, Kind uint8) error {
+	enginCacheLock.Lock()
+	defer enginCacheLock.Unlock()
+
 	ID := cont.enginID(Name, Version)
 	if _, has := enginCache[ID]; has {
 		return nil
@@ -92,9 +112,32 @@ func (cont *EnginContract) saveEngin(Name string, Version uint32, EnginURL strin
 		return err
 	}
 
-	path, err := checkEnginFile(ID, EnginURL)
-	if err != nil {
-		return err
+	if Kind == EnginKindWasm {
+		code, err := loadEnginFile(wasmEnginPath(EnginHash), EnginURL, EnginHash)
+		if err != nil {
+			return err
+		}
+		eg, err := wasmengin.NewEngin(wasmvm.DefaultVM(), code)
+		if err != nil {
+			return err
+		}
+		enginCache[ID] = eg
+		return nil
+	}
+
+	var path string
+	if EnginHash == (hash.Hash256{}) {
+		// legacy engins which are added before the hash was required keep the previous loading rule until SetEnginHash pins them
+		_path, err := checkEnginFile(ID, EnginURL)
+		if err != nil {
+			return err
+		}
+		path = _path
+	} else {
+		path = fmt.Sprintf("./engin/%v.so", ID)
+		if _, err := loadEnginFile(path, EnginURL, EnginHash); err != nil {
+			return err
+		}
 	}
 
 	pg, err := plugin.Open(path)
@@ -116,6 +159,64 @@ func (cont *EnginContract) saveEngin(Name string, Version uint32, EnginURL strin
 	return nil
 }
 
+func wasmEnginPath(EnginHash hash.Hash256) string {
+	return fmt.Sprintf("./engin/%v.wasm", hex.EncodeToString(EnginHash[:]))
+}
+
+// loadEnginFile returns the engin binary of the path after verifying it with the pinned hash
+// the binary is downloaded again when the local file is not exist or not matched
+func loadEnginFile(path string, EnginURL string, EnginHash hash.Hash256) ([]byte, error) {
+	fileLock.Lock()
+	defer fileLock.Unlock()
+
+	if bs, err := os.ReadFile(path); err == nil {
+		if hash.Hash(bs) == EnginHash {
+			return bs, nil
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+
+	bs, err := fetchEngin(EnginURL)
+	if err != nil {
+		return nil, err
+	}
+	if hash.Hash(bs) != EnginHash {
+		return nil, ErrEnginHashMismatch
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, bs, 0644); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return nil, err
+	}
+	return bs, nil
+}
+
+func fetchEngin(EnginURL string) ([]byte, error) {
+	resp, err := http.Get(EnginURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("engin download failed: %v", resp.Status)
+	}
+	bs, err := io.ReadAll(io.LimitReader(resp.Body, MaxEnginSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(bs) == 0 {
+		return nil, errors.New("engin is empty")
+	}
+	if len(bs) > MaxEnginSize {
+		return nil, errors.New("engin is too large")
+	}
+	return bs, nil
+}
+
 func checkEnginFile(ID string, EnginURL string) (string, error) {
 	fileLock.Lock()
 	defer fileLock.Unlock()
@@ -201,17 +302,17 @@ func (cont *EnginContract) loadEngin(cc *types.ContractContext, Name string, Ver
 	ID := cont.enginID(Name, Version)
 	EnginURL := cc.ContractData(makeEnginURLKey(Name, Version))
 
-	eg, has := enginCache[ID]
+	eg, has := cachedEngin(ID)
 	if !has {
 		if len(EnginURL) == 0 {
 			return nil, errors.New("engin not exist")
 		}
 
-		err := cont.saveEngin(Name, Version, string(EnginURL))
+		err := cont.saveEngin(Name, Version, string(EnginURL), cont.EnginHash(cc, Name, Version), cont.enginKind(cc, Name, Version))
 		if err != nil {
 			return nil, err
 		}
-		eg, has = enginCache[ID]
+		eg, has = cachedEngin(ID)
 		if !has {
 			return nil, errors.New("engin not loaded")
 		}
@@ -220,6 +321,22 @@ func (cont *EnginContract) loadEngin(cc *types.ContractContext, Name string, Ver
 	return eg, nil
 }
 
+func cachedEngin(ID string) (types.IEngin, bool) {
+	enginCacheLock.Lock()
+	defer enginCacheLock.Unlock()
+
+	eg, has := enginCache[ID]
+	return eg, has
+}
+
+func (cont *EnginContract) enginKind(cc *types.ContractContext, Name string, Version uint32) uint8 {
+	bs := cc.ContractData(makeEnginKindKey(Name, Version))
+	if len(bs) == 0 {
+		return EnginKindPlugin
+	}
+	return bs[0]
+}
+
 func (cont *EnginContract) enginInfo(cc *types.ContractContext, Name string) (map[uint32]string, error) {
 	Version := cont.EnginVersion(cc, Name)
 
@@ -227,7 +344,11 @@ func (cont *EnginContract) enginInfo(cc *types.ContractContext, Name string) (ma
 	for i := uint32(1); i <= Version; i++ {
 		bsEnginURL := cc.ContractData(makeEnginURLKey(Name, i))
 		EnginURL := string(bsEnginURL)
-		res[i] = fmt.Sprintf("ID: %v, URL: %v", cont.enginID(Name, i), EnginURL)
+		kind := "plugin"
+		if cont.enginKind(cc, Name, i) == EnginKindWasm {
+			kind = "wasm"
+		}
+		res[i] = fmt.Sprintf("ID: %v, URL: %v, Kind: %v, Hash: %v", cont.enginID(Name, i), EnginURL, kind, cont.EnginHash(cc, Name, i).String())
 	}
 
 	return res, nil
@@ -237,24 +358,104 @@ func (cont *EnginContract) enginInfo(cc *types.ContractContext, Name string) (ma
 // Public admin only Writer Functions
 //////////////////////////////////////////////////
 
+// addEngin adds the plugin engin without the hash, it is not accepted from PinnedEnginVersion
 func (cont *EnginContract) addEngin(cc *types.ContractContext, Name string, Description string, EnginURL string) error {
 	if cc.From() != cont.master {
 		return errors.New("not owner")
 	}
+	if cc.Version(cc.TargetHeight()) >= PinnedEnginVersion {
+		return ErrEnginHashRequired
+	}
+	if len(EnginURL) == 0 {
+		return errors.New("engin not provided")
+	}
+	Version := cont.NextEnginVersion(cc, Name)
+	cc.SetContractData(makeDescriptionKey(Name, Version), []byte(Description))
+	err := cont.saveEngin(Name, Version, EnginURL, hash.Hash256{}, EnginKindPlugin)
+	if err != nil {
+		return err
+	}
+	cc.SetContractData(makeEnginURLKey(Name, Version), []byte(EnginURL))
+
+	return nil
+}
+
+// addPinnedEngin adds the plugin engin pinned by the hash
+func (cont *EnginContract) addPinnedEngin(cc *types.ContractContext, Name string, Description string, EnginURL string, EnginHash hash.Hash256) error {
+	if cc.From() != cont.master {
+		return errors.New("not owner")
+	}
+	if cc.Version(cc.TargetHeight()) < PinnedEnginVersion {
+		return ErrNotPinnedEnginYet
+	}
+	if len(EnginURL) == 0 {
+		return errors.New("engin not provided")
+	}
+	if EnginHash == (hash.Hash256{}) {
+		return errors.New("engin hash not provided")
+	}
+	Version := cont.NextEnginVersion(cc, Name)
+	cc.SetContractData(makeDescriptionKey(Name, Version), []byte(Description))
+	err := cont.saveEngin(Name, Version, EnginURL, EnginHash, EnginKindPlugin)
+	if err != nil {
+		return err
+	}
+	cc.SetContractData(makeEnginURLKey(Name, Version), []byte(EnginURL))
+	cc.SetContractData(makeEnginHashKey(Name, Version), EnginHash[:])
+
+	return nil
+}
+
+func (cont *EnginContract) addWasmEngin(cc *types.ContractContext, Name string, Description string, EnginURL string, EnginHash hash.Hash256) error {
+	if cc.From() != cont.master {
+		return errors.New("not owner")
+	}
+	if cc.Version(cc.TargetHeight()) < PinnedEnginVersion {
+		return ErrNotPinnedEnginYet
+	}
 	if len(EnginURL) == 0 {
 		return errors.New("engin not provided")
 	}
+	if EnginHash == (hash.Hash256{}) {
+		return errors.New("engin hash not provided")
+	}
 	Version := cont.NextEnginVersion(cc, Name)
 	cc.SetContractData(makeDescriptionKey(Name, Version), []byte(Description))
-	err := cont.saveEngin(Name, Version, EnginURL)
+	err := cont.saveEngin(Name, Version, EnginURL, EnginHash, EnginKindWasm)
 	if err != nil {
 		return err
 	}
 	cc.SetContractData(makeEnginURLKey(Name, Version), []byte(EnginURL))
+	cc.SetContractData(makeEnginHashKey(Name, Version), EnginHash[:])
+	cc.SetContractData(makeEnginKindKey(Name, Version), []byte{EnginKindWasm})
 
 	return nil
 }
 
+// setEnginHash pins the hash of the plugin engin which is added before the hash was required
+func (cont *EnginContract) setEnginHash(cc *types.ContractContext, Name string, Version uint32, EnginHash hash.Hash256) error {
+	if cc.From() != cont.master {
+		return errors.New("not owner")
+	}
+	if cc.Version(cc.TargetHeight()) < PinnedEnginVersion {
+		return ErrNotPinnedEnginYet
+	}
+	if Version == 0 || cont.EnginVersion(cc, Name) < Version {
+		return errors.New("not exist engin")
+	}
+	if EnginHash == (hash.Hash256{}) {
+		return errors.New("engin hash not provided")
+	}
+	if cont.EnginHash(cc, Name, Version) != (hash.Hash256{}) {
+		return errors.New("engin hash already pinned")
+	}
+	cc.SetContractData(makeEnginHashKey(Name, Version), EnginHash[:])
+	enginCacheLock.Lock()
+	delete(enginCache, cont.enginID(Name, Version))
+	enginCacheLock.Unlock()
+	return nil
+}
+
 func (cont *EnginContract) EnginDescription(cc *types.ContractContext, Name string, Version uint32) (string, error) {
 	DescriptionBs := cc.ContractData(makeDescriptionKey(Name, Version))
 	if len(DescriptionBs) == 0 {
@@ -314,6 +515,12 @@ func (cont *EnginContract) deploryContract(cc *types.ContractContext, EnginName
 // Public Reader Functions
 //////////////////////////////////////////////////
 
+func (cont *EnginContract) EnginHash(cc *types.ContractContext, Name string, Version uint32) hash.Hash256 {
+	var h hash.Hash256
+	copy(h[:], cc.ContractData(makeEnginHashKey(Name, Version)))
+	return h
+}
+
 func (cont *EnginContract) EnginVersion(cc *types.ContractContext, name string) uint32 {
 	seqbs := cc.ContractData(makeEnginVersionKey(name))
 	var seq uint32
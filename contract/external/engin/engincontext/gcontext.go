@@ -6,6 +6,7 @@ import (
 	"github.com/meverselabs/meverse/common"
 	"github.com/meverselabs/meverse/common/amount"
 	"github.com/meverselabs/meverse/core/types"
+	"github.com/meverselabs/meverse/core/wasmvm"
 )
 
 var (
@@ -67,6 +68,11 @@ func (pc *EnginContextContract) From() string {
 	return pc.cc.From().String()
 }
 
+// WasmMeter returns the meter of the wasm calls of the transaction
+func (pc *EnginContextContract) WasmMeter() *wasmvm.Meter {
+	return pc.cc.WasmMeter()
+}
+
 // IsGenerator returns the account is generator or not
 func (pc *EnginContextContract) IsGenerator(addr string) bool {
 	return pc.cc.IsGenerator(common.HexToAddress(addr))
@@ -0,0 +1,10 @@
+package engin
+
+import "errors"
+
+// engin errors
+var (
+	ErrEnginHashMismatch = errors.New("engin hash mismatch")
+	ErrEnginHashRequired = errors.New("engin hash required, use AddPinnedEngin")
+	ErrNotPinnedEnginYet = errors.New("pinned engin is not supported yet")
+)
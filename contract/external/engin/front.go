@@ -2,6 +2,7 @@ package engin
 
 import (
 	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/hash"
 	"github.com/meverselabs/meverse/core/types"
 )
 
@@ -18,6 +19,15 @@ type front struct {
 func (f *front) AddEngin(cc *types.ContractContext, Name string, Description string, EnginURL string) error {
 	return f.cont.addEngin(cc, Name, Description, EnginURL)
 }
+func (f *front) AddPinnedEngin(cc *types.ContractContext, Name string, Description string, EnginURL string, EnginHash hash.Hash256) error {
+	return f.cont.addPinnedEngin(cc, Name, Description, EnginURL, EnginHash)
+}
+func (f *front) AddWasmEngin(cc *types.ContractContext, Name string, Description string, EnginURL string, EnginHash hash.Hash256) error {
+	return f.cont.addWasmEngin(cc, Name, Description, EnginURL, EnginHash)
+}
+func (f *front) SetEnginHash(cc *types.ContractContext, Name string, Version uint32, EnginHash hash.Hash256) error {
+	return f.cont.setEnginHash(cc, Name, Version, EnginHash)
+}
 func (f *front) LoadEngin(cc *types.ContractContext, Name string, Version uint32) (types.IEngin, error) {
 	return f.cont.loadEngin(cc, Name, Version)
 }
@@ -30,6 +40,9 @@ func (f *front) DeploryContract(cc *types.ContractContext, EnginName string, Eng
 func (f *front) EnginDescription(cc *types.ContractContext, Name string, Version uint32) (string, error) {
 	return f.cont.EnginDescription(cc, Name, Version)
 }
+func (f *front) EnginHash(cc *types.ContractContext, Name string, Version uint32) hash.Hash256 {
+	return f.cont.EnginHash(cc, Name, Version)
+}
 func (f *front) EnginVersion(cc *types.ContractContext, name string) uint32 {
 	return f.cont.EnginVersion(cc, name)
 }
@@ -0,0 +1,103 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/contract/external/deployer"
+	"github.com/meverselabs/meverse/contract/external/engin"
+	"github.com/meverselabs/meverse/core/chain"
+	"github.com/meverselabs/meverse/extern/test/util"
+)
+
+func TestWasmEnginTx(t *testing.T) {
+	defer os.RemoveAll("./engin/")
+	chain.SetVersion(1, engin.PinnedEnginVersion)
+	defer chain.SetVersion(1, 2)
+
+	code := util.WasmStoreEngin()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(code)
+	}))
+	defer ts.Close()
+
+	tc := util.NewTestContext()
+	util.RegisterContractClass(&engin.EnginContract{}, "EnginContract")
+	util.RegisterContractClass(&deployer.DeployerContract{}, "DeployerContract")
+	egAddr := tc.DeployContract(&engin.EnginContract{}, &engin.EnginContractConstruction{})
+
+	if _, err := tc.SendTx(util.AdminKey, egAddr, "AddWasmEngin", "WasmEngin", "wasm engin", ts.URL+"/engin.wasm", hash.HexToHash("0x01")); err == nil {
+		t.Fatal("engin which is not matched with the hash should not be added")
+	}
+	if _, err := tc.SendTx(util.AdminKey, egAddr, "AddWasmEngin", "WasmEngin", "wasm engin", ts.URL+"/engin.wasm", hash.Hash(code)); err != nil {
+		t.Fatal(err)
+	}
+	inf, err := tc.ReadTx(util.AdminKey, egAddr, "EnginHash", "WasmEngin", uint32(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h, ok := inf[0].(hash.Hash256); !ok || h != hash.Hash(code) {
+		t.Fatalf("engin hash is not pinned %v", inf)
+	}
+
+	inf, err = tc.SendTx(util.AdminKey, egAddr, "DeploryContract", "WasmEngin", uint32(1), []byte("contract"), []interface{}{}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contAddr, ok := inf[0].(common.Address)
+	if !ok {
+		t.Fatalf("deplory contract not retruned address %v", inf)
+	}
+
+	if _, err := tc.SendTx(util.AdminKey, contAddr, "SetData", "hello"); err != nil {
+		t.Fatal(err)
+	}
+	res, err := readData(tc, contAddr, t, "1", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != "hello" {
+		t.Errorf("res is not matched SetData (%v, %v)", res, "hello")
+	}
+}
+
+func TestAddEnginWithoutHash(t *testing.T) {
+	defer os.RemoveAll("./engin/")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("plugin"))
+	}))
+	defer ts.Close()
+
+	tc := util.NewTestContext()
+	util.RegisterContractClass(&engin.EnginContract{}, "EnginContract")
+	egAddr := tc.DeployContract(&engin.EnginContract{}, &engin.EnginContractConstruction{})
+
+	// the pinned engin is not added before the version so that the previous blocks are replayed as they were
+	if _, err := tc.SendTx(util.AdminKey, egAddr, "AddPinnedEngin", "PluginEngin", "plugin engin", ts.URL+"/engin.so", hash.Hash([]byte("plugin"))); err == nil {
+		t.Fatal("pinned engin should not be added before the version")
+	}
+
+	chain.SetVersion(1, engin.PinnedEnginVersion)
+	defer chain.SetVersion(1, 2)
+	if _, err := tc.SendTx(util.AdminKey, egAddr, "AddEngin", "PluginEngin", "plugin engin", ts.URL+"/engin.so"); err == nil {
+		t.Fatal("engin without the hash should not be added")
+	}
+	if _, err := tc.SendTx(util.AdminKey, egAddr, "AddPinnedEngin", "PluginEngin", "plugin engin", ts.URL+"/engin.so", hash.Hash256{}); err == nil {
+		t.Fatal("engin without the hash should not be added")
+	}
+	if _, err := tc.SendTx(util.AdminKey, egAddr, "AddPinnedEngin", "PluginEngin", "plugin engin", ts.URL+"/engin.so", hash.HexToHash("0x01")); err == nil {
+		t.Fatal("engin which is not matched with the hash should not be added")
+	}
+	inf, err := tc.ReadTx(util.AdminKey, egAddr, "EnginVersion", "PluginEngin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := inf[0].(uint32); !ok || v != 0 {
+		t.Fatalf("engin version is changed %v", inf)
+	}
+}
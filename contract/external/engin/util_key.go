@@ -7,6 +7,8 @@ var (
 	tagContractSeq  = byte(0x02)
 	tagDescription  = byte(0x03)
 	tagEnginVersion = byte(0x04)
+	tagEnginHash    = byte(0x05)
+	tagEnginKind    = byte(0x06)
 )
 
 func makeEnginURLKey(name string, version uint32) []byte {
@@ -34,3 +36,21 @@ func makeDescriptionKey(name string, version uint32) []byte {
 	copy(bs[5:], n)
 	return bs
 }
+
+func makeEnginHashKey(name string, version uint32) []byte {
+	n := []byte(name)
+	bs := make([]byte, 1+4+len(n))
+	bs[0] = tagEnginHash
+	copy(bs[1:], bin.Uint32Bytes(version))
+	copy(bs[5:], n)
+	return bs
+}
+
+func makeEnginKindKey(name string, version uint32) []byte {
+	n := []byte(name)
+	bs := make([]byte, 1+4+len(n))
+	bs[0] = tagEnginKind
+	copy(bs[1:], bin.Uint32Bytes(version))
+	copy(bs[5:], n)
+	return bs
+}
@@ -0,0 +1,135 @@
+package wasmengin
+
+import (
+	"errors"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/contract/external/engin/engincontext"
+	"github.com/meverselabs/meverse/core/wasmvm"
+)
+
+// exported functions of the wasm engin module
+const (
+	FuncInitContract   = "init_contract"
+	FuncUpdateContract = "update_contract"
+	FuncInvoke         = "invoke"
+)
+
+// Engin runs the contract code on the engin module which is compiled to wasm
+// it is loaded instead of the go plugin so that the engin runs in the sandbox of the wasm vm
+type Engin struct {
+	vm   *wasmvm.VM
+	code []byte
+}
+
+// NewEngin returns a Engin after validating the engin module
+func NewEngin(vm *wasmvm.VM, code []byte) (*Engin, error) {
+	if _, err := vm.Compile(code); err != nil {
+		return nil, err
+	}
+	for _, name := range []string{FuncInitContract, FuncUpdateContract, FuncInvoke} {
+		if !vm.HasFunction(code, name) {
+			return nil, errors.New("invalid engin: not exist " + name)
+		}
+	}
+	return &Engin{
+		vm:   vm,
+		code: code,
+	}, nil
+}
+
+func enginContext(_cc interface{}) (*engincontext.EnginContextContract, error) {
+	ecc, ok := _cc.(*engincontext.EnginContextContract)
+	if !ok {
+		return nil, errors.New("invalid engin context")
+	}
+	return ecc, nil
+}
+
+func (eg *Engin) InitContract(_cc interface{}, contract []byte, InitArgs []interface{}) error {
+	ecc, err := enginContext(_cc)
+	if err != nil {
+		return err
+	}
+	args, err := wasmvm.MarshalValues(InitArgs)
+	if err != nil {
+		return err
+	}
+	_, err = eg.call(ecc, FuncInitContract, contract, args)
+	return err
+}
+
+func (eg *Engin) UpdateContract(_cc interface{}, contract []byte) error {
+	ecc, err := enginContext(_cc)
+	if err != nil {
+		return err
+	}
+	_, err = eg.call(ecc, FuncUpdateContract, contract)
+	return err
+}
+
+func (eg *Engin) ContractInvoke(_cc interface{}, method string, params []interface{}) (interface{}, error) {
+	ecc, err := enginContext(_cc)
+	if err != nil {
+		return nil, err
+	}
+	args, err := wasmvm.MarshalValues(params)
+	if err != nil {
+		return nil, err
+	}
+	bs, err := eg.call(ecc, FuncInvoke, []byte(method), args)
+	if err != nil {
+		return nil, err
+	}
+	if len(bs) == 0 {
+		return []interface{}{}, nil
+	}
+	return wasmvm.UnmarshalValue(bs)
+}
+
+// call runs the function of the engin on the wasm meter of the transaction
+func (eg *Engin) call(ecc *engincontext.EnginContextContract, name string, params ...[]byte) ([]byte, error) {
+	bs, _, err := eg.vm.Call(&enginHost{ecc}, eg.code, name, ecc.WasmMeter(), params...)
+	return bs, err
+}
+
+// enginHost connects the engin context to the host functions of the wasm vm
+type enginHost struct {
+	ecc *engincontext.EnginContextContract
+}
+
+func (h *enginHost) ContractAddress() common.Address {
+	return common.HexToAddress(h.ecc.ContractAddress())
+}
+
+func (h *enginHost) From() common.Address {
+	return common.HexToAddress(h.ecc.From())
+}
+
+func (h *enginHost) TargetHeight() uint32 {
+	return h.ecc.TargetHeight()
+}
+
+func (h *enginHost) LastTimestamp() uint64 {
+	return h.ecc.LastTimestamp()
+}
+
+func (h *enginHost) ContractData(name []byte) []byte {
+	return h.ecc.ContractData(name)
+}
+
+func (h *enginHost) SetContractData(name []byte, value []byte) {
+	h.ecc.SetContractData(name, value)
+}
+
+func (h *enginHost) AccountData(addr common.Address, name []byte) []byte {
+	return h.ecc.AccountData(addr.String(), name)
+}
+
+func (h *enginHost) SetAccountData(addr common.Address, name []byte, value []byte) {
+	h.ecc.SetAccountData(addr.String(), name, value)
+}
+
+func (h *enginHost) Exec(addr common.Address, method string, args []interface{}) ([]interface{}, error) {
+	return h.ecc.Exec(addr.String(), method, args)
+}
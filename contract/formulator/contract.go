@@ -323,7 +323,8 @@ func (cont *FormulatorContract) OnReward(cc *types.ContractContext, b *types.Blo
 		sum.Int.Add(sum.Int, am.Int)
 	}
 
-	if TotalForCmp.Cmp(sum.Int) < 0 {
+	// the total is not made when no formulator has the reward power at the interval
+	if TotalForCmp != nil && TotalForCmp.Cmp(sum.Int) < 0 {
 		panic(errors.Errorf("%v %v %v", TotalForCmp.String(), sum.String(), TotalForCmp.Cmp(sum.Int)))
 	}
 
@@ -0,0 +1,42 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/contract/formulator"
+	"github.com/meverselabs/meverse/extern/test/util"
+)
+
+func TestOnRewardWithoutRewardPower(t *testing.T) {
+	tc := util.NewTestContext()
+	frAddr := tc.DeployContract(&formulator.FormulatorContract{}, &formulator.FormulatorContractConstruction{
+		TokenAddress: tc.MainToken,
+		FormulatorPolicy: formulator.FormulatorPolicy{
+			AlphaAmount:    amount.NewAmount(0, 0),
+			SigmaCount:     1,
+			OmegaCount:     1,
+			HyperAmount:    amount.NewAmount(0, 0),
+			MinStakeAmount: amount.NewAmount(0, 0),
+		},
+		RewardPolicy: formulator.RewardPolicy{
+			RewardPerBlock: amount.NewAmount(1, 0),
+		},
+	})
+	v, err := tc.Ctx.Contract(frAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cont := v.(*formulator.FormulatorContract)
+	cc := tc.Ctx.ContractContext(cont, util.Admin)
+
+	// the block of the address which is neither a formulator nor a generator has no reward power
+	rewardEvent, err := cont.OnReward(cc, nil, map[common.Address]uint32{util.Users[0]: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rewardEvent) != 0 {
+		t.Fatalf("reward is sent without the reward power %v", rewardEvent)
+	}
+}
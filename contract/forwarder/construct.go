@@ -0,0 +1,16 @@
+package forwarder
+
+import (
+	"io"
+)
+
+type ForwarderContractConstruction struct {
+}
+
+func (s *ForwarderContractConstruction) WriteTo(w io.Writer) (int64, error) {
+	return 0, nil
+}
+
+func (s *ForwarderContractConstruction) ReadFrom(r io.Reader) (int64, error) {
+	return 0, nil
+}
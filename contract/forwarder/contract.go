@@ -0,0 +1,133 @@
+package forwarder
+
+import (
+	"bytes"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/core/types"
+)
+
+// ForwarderName and ForwarderVersion are the EIP-712 domain of the forwarder
+const (
+	ForwarderName    = "MeverseForwarder"
+	ForwarderVersion = "1"
+)
+
+// RequestTypeHash is the type hash of the forward request
+var RequestTypeHash = crypto.Keccak256Hash([]byte("ForwardRequest(address from,address token,string method,address to,uint256 value,uint256 nonce,uint256 deadline)"))
+
+// methods of the token which can be forwarded
+var forwardMethods = map[string]string{
+	"Transfer": "ForwardedTransfer",
+	"Approve":  "ForwardedApprove",
+}
+
+// ForwarderContract executes the Transfer and the Approve of the tokens by the EIP-712 signed request
+// so that a relayer pays the fee of the transaction instead of the signer
+// the token should register the forwarder by SetTrustedForwarder
+type ForwarderContract struct {
+	addr   common.Address
+	master common.Address
+}
+
+func (cont *ForwarderContract) Name() string {
+	return "Forwarder"
+}
+
+func (cont *ForwarderContract) Address() common.Address {
+	return cont.addr
+}
+
+func (cont *ForwarderContract) Master() common.Address {
+	return cont.master
+}
+
+func (cont *ForwarderContract) Init(addr common.Address, master common.Address) {
+	cont.addr = addr
+	cont.master = master
+}
+
+func (cont *ForwarderContract) OnCreate(cc *types.ContractContext, Args []byte) error {
+	data := &ForwarderContractConstruction{}
+	if _, err := data.ReadFrom(bytes.NewReader(Args)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cont *ForwarderContract) OnReward(cc *types.ContractContext, b *types.Block, CountMap map[common.Address]uint32) (map[common.Address]*amount.Amount, error) {
+	return nil, nil
+}
+
+//////////////////////////////////////////////////
+// Public Writer Functions
+//////////////////////////////////////////////////
+
+// Execute verifies the request signed by the From and calls the token on behalf of it
+func (cont *ForwarderContract) Execute(cc *types.ContractContext, From common.Address, Token common.Address, Method string, To common.Address, Amount *amount.Amount, Deadline *big.Int, Sig []byte) error {
+	fm, has := forwardMethods[Method]
+	if !has {
+		return errors.Errorf("not forwardable method %v", Method)
+	}
+	now := new(big.Int).SetUint64(cc.LastTimestamp() / uint64(time.Second))
+	if Deadline == nil || Deadline.Cmp(now) < 0 {
+		return errors.New("Forwarder: REQUEST_EXPIRED")
+	}
+
+	nonce := cont.Nonces(cc, From)
+	h := cont.RequestHash(cc, From, Token, Method, To, Amount, nonce, Deadline)
+	signer, err := common.RecoverTypedSigner(cc.ChainID(), h, common.Signature(Sig))
+	if err != nil {
+		return err
+	}
+	if signer != From {
+		return errors.New("Forwarder: INVALID_SIGNATURE")
+	}
+	cc.SetAccountData(From, []byte{tagNonce}, nonce.Add(nonce, big.NewInt(1)).Bytes())
+
+	if _, err := cc.Exec(cc, Token, fm, []interface{}{From, To, Amount}); err != nil {
+		return err
+	}
+	return nil
+}
+
+//////////////////////////////////////////////////
+// Public Reader Functions
+//////////////////////////////////////////////////
+
+// Nonces returns the request nonce of the signer
+func (cont *ForwarderContract) Nonces(cc types.ContractLoader, from common.Address) *big.Int {
+	bs := cc.AccountData(from, []byte{tagNonce})
+	return big.NewInt(0).SetBytes(bs)
+}
+
+// DomainSeparator returns the EIP-712 domain separator of the forwarder
+func (cont *ForwarderContract) DomainSeparator(cc types.ContractLoader) hash.Hash256 {
+	return common.EIP712DomainSeparator(ForwarderName, ForwarderVersion, cc.ChainID(), cont.addr)
+}
+
+// RequestHash returns the EIP-712 hash of the request which is signed by the From
+func (cont *ForwarderContract) RequestHash(cc types.ContractLoader, From common.Address, Token common.Address, Method string, To common.Address, Amount *amount.Amount, Nonce *big.Int, Deadline *big.Int) hash.Hash256 {
+	var value *big.Int
+	if Amount != nil {
+		value = Amount.Int
+	}
+	structHash := crypto.Keccak256Hash(
+		RequestTypeHash[:],
+		common.EIP712Address(From),
+		common.EIP712Address(Token),
+		crypto.Keccak256([]byte(Method)),
+		common.EIP712Address(To),
+		common.EIP712Uint256(value),
+		common.EIP712Uint256(Nonce),
+		common.EIP712Uint256(Deadline),
+	)
+	return common.EIP712Hash(cont.DomainSeparator(cc), structHash)
+}
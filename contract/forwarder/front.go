@@ -0,0 +1,41 @@
+package forwarder
+
+import (
+	"math/big"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/core/types"
+)
+
+func (cont *ForwarderContract) Front() interface{} {
+	return &front{
+		cont: cont,
+	}
+}
+
+type front struct {
+	cont *ForwarderContract
+}
+
+func (f *front) Execute(cc *types.ContractContext, From common.Address, Token common.Address, Method string, To common.Address, Amount *amount.Amount, Deadline *big.Int, Sig []byte) (bool, error) {
+	err := f.cont.Execute(cc, From, Token, Method, To, Amount, Deadline, Sig)
+	return err == nil, err
+}
+
+//////////////////////////////////////////////////
+// Public Reader Functions
+//////////////////////////////////////////////////
+
+func (f *front) Nonces(cc types.ContractLoader, from common.Address) *big.Int {
+	return f.cont.Nonces(cc, from)
+}
+
+func (f *front) DomainSeparator(cc types.ContractLoader) hash.Hash256 {
+	return f.cont.DomainSeparator(cc)
+}
+
+func (f *front) RequestHash(cc types.ContractLoader, From common.Address, Token common.Address, Method string, To common.Address, Amount *amount.Amount, Nonce *big.Int, Deadline *big.Int) hash.Hash256 {
+	return f.cont.RequestHash(cc, From, Token, Method, To, Amount, Nonce, Deadline)
+}
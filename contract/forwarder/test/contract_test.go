@@ -0,0 +1,81 @@
+package test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/common/key"
+	"github.com/meverselabs/meverse/contract/forwarder"
+	"github.com/meverselabs/meverse/extern/test/util"
+)
+
+func signRequest(t *testing.T, tc *util.TestContext, fwAddr common.Address, mkey key.Key, tokenAddr common.Address, method string, to common.Address, am *amount.Amount, deadline *big.Int) common.Signature {
+	from := mkey.PublicKey().Address()
+	is, err := tc.ReadTx(mkey, fwAddr, "Nonces", from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	is, err = tc.ReadTx(mkey, fwAddr, "RequestHash", from, tokenAddr, method, to, am, is[0].(*big.Int), deadline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the key signs with the chain id recovery like the ether type transactions
+	sig, err := mkey.Sign(is[0].(hash.Hash256))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sig
+}
+
+func TestExecute(t *testing.T) {
+	tc := util.NewTestContext()
+	tokenAddr := tc.MakeToken("TestToken", "TEST", "10000")
+	fwAddr := tc.DeployContract(&forwarder.ForwarderContract{}, &forwarder.ForwarderContractConstruction{})
+
+	user := util.UserKeys[0]
+	if _, err := tc.SendTx(util.AdminKey, tokenAddr, "Transfer", util.Users[0], amount.MustParseAmount("10")); err != nil {
+		t.Fatal(err)
+	}
+
+	am := amount.MustParseAmount("3")
+	deadline := big.NewInt(int64(tc.Ctx.LastTimestamp()/uint64(time.Second)) + 3600)
+	sig := signRequest(t, tc, fwAddr, user, tokenAddr, "Transfer", util.Users[1], am, deadline)
+	if _, err := tc.SendTx(util.AdminKey, fwAddr, "Execute", util.Users[0], tokenAddr, "Transfer", util.Users[1], am, deadline, []byte(sig)); err == nil {
+		t.Fatal("not trusted forwarder executes")
+	}
+	if _, err := tc.SendTx(util.AdminKey, tokenAddr, "SetTrustedForwarder", fwAddr, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tc.SendTx(util.AdminKey, fwAddr, "Execute", util.Users[0], tokenAddr, "Transfer", util.Users[1], am, deadline, []byte(sig)); err != nil {
+		t.Fatal(err)
+	}
+	is, err := tc.ReadTx(util.AdminKey, tokenAddr, "BalanceOf", util.Users[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if is[0].(*amount.Amount).Cmp(am.Int) != 0 {
+		t.Errorf("balance %v, want %v", is[0], am)
+	}
+	if _, err := tc.SendTx(util.AdminKey, fwAddr, "Execute", util.Users[0], tokenAddr, "Transfer", util.Users[1], am, deadline, []byte(sig)); err == nil {
+		t.Error("replayed request is executed")
+	}
+
+	sig = signRequest(t, tc, fwAddr, user, tokenAddr, "Approve", util.Users[2], am, deadline)
+	if _, err := tc.SendTx(util.AdminKey, fwAddr, "Execute", util.Users[0], tokenAddr, "Approve", util.Users[3], am, deadline, []byte(sig)); err == nil {
+		t.Error("request of the other spender is executed")
+	}
+	if _, err := tc.SendTx(util.AdminKey, fwAddr, "Execute", util.Users[0], tokenAddr, "Approve", util.Users[2], am, deadline, []byte(sig)); err != nil {
+		t.Fatal(err)
+	}
+	is, err = tc.ReadTx(util.AdminKey, tokenAddr, "Allowance", util.Users[0], util.Users[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if is[0].(*amount.Amount).Cmp(am.Int) != 0 {
+		t.Errorf("allowance %v, want %v", is[0], am)
+	}
+}
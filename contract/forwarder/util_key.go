@@ -0,0 +1,5 @@
+package forwarder
+
+var (
+	tagNonce = byte(0x01)
+)
@@ -9,6 +9,8 @@ import (
 	"github.com/meverselabs/meverse/common"
 	"github.com/meverselabs/meverse/common/amount"
 	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/contract/attestation"
+	"github.com/meverselabs/meverse/contract/ratelimit"
 	"github.com/meverselabs/meverse/core/types"
 )
 
@@ -94,6 +96,10 @@ func (cont *GatewayContract) Transfer(cc *types.ContractContext, to common.Addre
 	if cc.From() != cont.Master() && !isSender {
 		return errors.New("not token sender")
 	}
+	// the sender can not move the funds by itself when the relayers attest the deposits
+	if cc.From() != cont.Master() && cc.Version(cc.TargetHeight()) >= attestation.Version && len(cont.Relayers(cc)) > 0 {
+		return attestation.ErrAttestationRequired
+	}
 
 	taddr := cont.TokenAddress(cc)
 	if _, err := cc.Exec(cc, taddr, "Transfer", []interface{}{to, Amount}); err != nil {
@@ -115,6 +121,9 @@ func (cont *GatewayContract) TokenInRevert(cc *types.ContractContext, Platform,
 	if !(len(bs) == 1 && bs[0] == 1) {
 		return errors.New("not support platform: " + Platform)
 	}
+	if err := attestation.CheckSinglePath(cc, tagAttestation, pf); err != nil {
+		return err
+	}
 
 	_, _, err := types.ParseTransactionIDBytes(txid1)
 	if err != nil {
@@ -161,13 +170,20 @@ func (cont *GatewayContract) TokenIn(cc *types.ContractContext, Platform string,
 	if !(len(bs) == 1 && bs[0] == 1) {
 		return errors.New("not support platform: " + Platform)
 	}
+	if err := attestation.CheckSinglePath(cc, tagAttestation, pf); err != nil {
+		return err
+	}
+	return cont.tokenIn(cc, pf, ercHash, to, Amount)
+}
 
+func (cont *GatewayContract) tokenIn(cc *types.ContractContext, pf string, ercHash string, to common.Address, Amount *amount.Amount) error {
 	ErcHash := hash.HexToHash(ercHash)
-	bs = cc.ContractData(makeTokenInKey(ErcHash, pf))
+	bs := cc.ContractData(makeTokenInKey(ErcHash, pf))
 	if len(bs) == 1 && bs[0] == 1 {
 		return errors.New("exist hash: " + ercHash)
 	}
-	if cc.TargetHeight() > 1783888 {
+	// the past blocks didn't mark the hash until 1783888, the attested deposits are always marked
+	if cc.TargetHeight() > 1783888 || cc.Version(cc.TargetHeight()) >= attestation.Version {
 		cc.SetContractData(makeTokenInKey(ErcHash, pf), []byte{1})
 	}
 
@@ -178,6 +194,20 @@ func (cont *GatewayContract) TokenIn(cc *types.ContractContext, Platform string,
 	return nil
 }
 
+// AttestedTokenIn credits the deposit which is signed by the relayers as many as the threshold of the platform
+// anyone can submit it because the signatures authorize the deposit
+func (cont *GatewayContract) AttestedTokenIn(cc *types.ContractContext, Platform string, ercHash string, to common.Address, Amount *amount.Amount, Sigs [][]byte) error {
+	pf := strings.ToLower(Platform)
+	bs := cc.ContractData(makePlatformKey(pf))
+	if !(len(bs) == 1 && bs[0] == 1) {
+		return errors.New("not support platform: " + Platform)
+	}
+	if err := attestation.Verify(cc, tagAttestation, cont.addr, pf, ercHash, cont.TokenAddress(cc), to, Amount, Sigs); err != nil {
+		return err
+	}
+	return cont.tokenIn(cc, pf, ercHash, to, Amount)
+}
+
 func (cont *GatewayContract) TokenIndexIn(cc *types.ContractContext, Platform string, ercHash string, to common.Address, Amount *amount.Amount) error {
 	err := cont.TokenIn(cc, Platform, ercHash, to, Amount)
 	if err != nil {
@@ -193,6 +223,9 @@ func (cont *GatewayContract) TokenOut(cc *types.ContractContext, Platform string
 	taddr := cont.TokenAddress(cc)
 
 	pf := strings.ToLower(Platform)
+	if err := ratelimit.Consume(cc, tagRateLimit, ratelimit.Outbound, taddr, pf, Amount); err != nil {
+		return err
+	}
 	feebs := cc.ContractData(makePlatformFeeKey(pf))
 	fee := amount.NewAmountFromBytes(feebs)
 
@@ -206,9 +239,27 @@ func (cont *GatewayContract) TokenOut(cc *types.ContractContext, Platform string
 	if _, err := cc.Exec(cc, taddr, "TransferFrom", []interface{}{cc.From(), cont.Address(), Amount}); err != nil {
 		return err
 	}
+	// the large transfer is locked in the contract and the relayers withdraw it when the queued transfer is executed
+	if ratelimit.IsLarge(cc, tagRateLimit, ratelimit.Outbound, taddr, pf, Amount) {
+		ratelimit.Enqueue(cc, tagRateLimit, ratelimit.Outbound, taddr, cc.From(), withdrawAddress, Amount, pf)
+	}
 	return nil
 }
 
+// ExecuteQueued releases the queued TokenOut to the relayers after the delay, anyone can execute it
+// the pause of the platform is checked again at the execution so that the guardian can hold the queued transfers
+func (cont *GatewayContract) ExecuteQueued(cc *types.ContractContext, id uint64) error {
+	t, err := ratelimit.Queued(cc, tagRateLimit, id)
+	if err != nil {
+		return err
+	}
+	if ratelimit.IsPaused(cc, tagRateLimit, t.Dir, t.Token, t.Chain) {
+		return ratelimit.ErrPaused
+	}
+	_, err = ratelimit.Dequeue(cc, tagRateLimit, id, false)
+	return err
+}
+
 func (cont *GatewayContract) TokenLeave(cc *types.ContractContext, CoinTXID string, ERC20TXID string, Platform string) error {
 	isSender := cont.IsSender(cc, cc.From())
 
@@ -245,6 +296,79 @@ func (cont *GatewayContract) SetSender(cc *types.ContractContext, To common.Addr
 	return nil
 }
 
+// SetRelayers replaces the relayer set which attests the deposits
+func (cont *GatewayContract) SetRelayers(cc *types.ContractContext, Relayers []common.Address) error {
+	if cc.From() != cont.Master() {
+		return errors.New("not token master")
+	}
+	return attestation.SetRelayers(cc, tagAttestation, Relayers)
+}
+
+// SetAttestationThreshold sets the count of the relayer signatures of the platform
+// the single sender paths of the platform are refused while the threshold is not zero
+func (cont *GatewayContract) SetAttestationThreshold(cc *types.ContractContext, Platform string, Threshold uint16) error {
+	if cc.From() != cont.Master() {
+		return errors.New("not token master")
+	}
+	return attestation.SetThreshold(cc, tagAttestation, Platform, Threshold)
+}
+
+func (cont *GatewayContract) SetGuardian(cc *types.ContractContext, Guardian common.Address) error {
+	if cc.From() != cont.Master() {
+		return errors.New("not token master")
+	}
+	ratelimit.SetGuardian(cc, tagRateLimit, Guardian)
+	return nil
+}
+
+// PausePlatform stops the TokenOut of the platform instantly
+func (cont *GatewayContract) PausePlatform(cc *types.ContractContext, Platform string) error {
+	if cc.From() != cont.Master() && cc.From() != cont.Guardian(cc) {
+		return ratelimit.ErrNotGuardian
+	}
+	ratelimit.SetPaused(cc, tagRateLimit, ratelimit.Outbound, cont.TokenAddress(cc), Platform, true)
+	return nil
+}
+
+func (cont *GatewayContract) UnpausePlatform(cc *types.ContractContext, Platform string) error {
+	if cc.From() != cont.Master() {
+		return errors.New("not token master")
+	}
+	ratelimit.SetPaused(cc, tagRateLimit, ratelimit.Outbound, cont.TokenAddress(cc), Platform, false)
+	return nil
+}
+
+// SetRateLimit limits the TokenOut of the platform to the amount during the window in seconds
+func (cont *GatewayContract) SetRateLimit(cc *types.ContractContext, Platform string, Limit *amount.Amount, Window uint64) error {
+	if cc.From() != cont.Master() {
+		return errors.New("not token master")
+	}
+	return ratelimit.SetLimit(cc, tagRateLimit, ratelimit.Outbound, cont.TokenAddress(cc), Platform, Limit, Window)
+}
+
+// SetLargeTransferDelay queues the TokenOut of the platform for the delay in seconds when the amount is over the threshold
+func (cont *GatewayContract) SetLargeTransferDelay(cc *types.ContractContext, Platform string, Threshold *amount.Amount, Delay uint64) error {
+	if cc.From() != cont.Master() {
+		return errors.New("not token master")
+	}
+	return ratelimit.SetDelay(cc, tagRateLimit, ratelimit.Outbound, cont.TokenAddress(cc), Platform, Threshold, Delay)
+}
+
+// CancelQueued drops the queued TokenOut and refunds the amount to the sender, the fee is not refunded
+func (cont *GatewayContract) CancelQueued(cc *types.ContractContext, id uint64) error {
+	if cc.From() != cont.Master() {
+		return errors.New("not token master")
+	}
+	t, err := ratelimit.Dequeue(cc, tagRateLimit, id, true)
+	if err != nil {
+		return err
+	}
+	if _, err := cc.Exec(cc, t.Token, "Transfer", []interface{}{t.From, t.Amount}); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (cont *GatewayContract) SetFeeOwner(cc *types.ContractContext, feeOwner common.Address) error {
 	if cc.From() != cont.Master() {
 		return errors.New("not token master")
@@ -265,6 +389,57 @@ func (cont *GatewayContract) FeeOwner(cc *types.ContractContext) common.Address
 	return common.BytesToAddress(cc.ContractData([]byte{tagFeeOwner}))
 }
 
+func (cont *GatewayContract) Relayers(cc types.ContractLoader) []common.Address {
+	return attestation.Relayers(cc, tagAttestation)
+}
+
+func (cont *GatewayContract) RelayerEpoch(cc types.ContractLoader) uint64 {
+	return attestation.Epoch(cc, tagAttestation)
+}
+
+func (cont *GatewayContract) AttestationThreshold(cc types.ContractLoader, Platform string) uint16 {
+	return attestation.Threshold(cc, tagAttestation, Platform)
+}
+
+func (cont *GatewayContract) Guardian(cc types.ContractLoader) common.Address {
+	return ratelimit.Guardian(cc, tagRateLimit)
+}
+
+func (cont *GatewayContract) IsPlatformPaused(cc *types.ContractContext, Platform string) bool {
+	return ratelimit.IsPaused(cc, tagRateLimit, ratelimit.Outbound, cont.TokenAddress(cc), Platform)
+}
+
+func (cont *GatewayContract) RateLimit(cc *types.ContractContext, Platform string) (*amount.Amount, uint64) {
+	return ratelimit.Limit(cc, tagRateLimit, ratelimit.Outbound, cont.TokenAddress(cc), Platform)
+}
+
+// AvailableRateLimit returns the amount which can be sent by TokenOut now and false when the platform is not limited
+func (cont *GatewayContract) AvailableRateLimit(cc *types.ContractContext, Platform string) (*amount.Amount, bool) {
+	avail, limited := ratelimit.Available(cc, tagRateLimit, ratelimit.Outbound, cont.TokenAddress(cc), Platform, cc.LastTimestamp())
+	if !limited {
+		return amount.NewAmount(0, 0), false
+	}
+	return avail, true
+}
+
+// LargeTransferDelay returns the threshold and the delay in seconds of the TokenOut of the platform
+func (cont *GatewayContract) LargeTransferDelay(cc *types.ContractContext, Platform string) (*amount.Amount, uint64) {
+	return ratelimit.Delay(cc, tagRateLimit, ratelimit.Outbound, cont.TokenAddress(cc), Platform)
+}
+
+func (cont *GatewayContract) QueuedTransferIDs(cc types.ContractLoader) []uint64 {
+	return ratelimit.QueuedIDs(cc, tagRateLimit)
+}
+
+// QueuedTransfer returns the sender, the withdraw address, the amount, the platform and the ready timestamp of the queued TokenOut
+func (cont *GatewayContract) QueuedTransfer(cc types.ContractLoader, id uint64) (common.Address, common.Address, *amount.Amount, string, uint64, error) {
+	t, err := ratelimit.Queued(cc, tagRateLimit, id)
+	if err != nil {
+		return common.ZeroAddr, common.ZeroAddr, nil, "", 0, err
+	}
+	return t.From, t.To, t.Amount, t.Chain, t.ReadyAt, nil
+}
+
 func (cont *GatewayContract) IsSender(cc types.ContractLoader, addr common.Address) bool {
 	bs := cc.AccountData(addr, []byte{tagTokenSender})
 	if len(bs) == 1 && bs[0] == 1 {
@@ -31,21 +31,81 @@ func (f *front) TokenIn(cc *types.ContractContext, Platform string, ercHash stri
 func (f *front) TokenIndexIn(cc *types.ContractContext, Platform string, ercHash string, to common.Address, Amount *amount.Amount) error {
 	return f.cont.TokenIndexIn(cc, Platform, ercHash, to, Amount)
 }
+func (f *front) AttestedTokenIn(cc *types.ContractContext, Platform string, ercHash string, to common.Address, Amount *amount.Amount, Sigs [][]byte) error {
+	return f.cont.AttestedTokenIn(cc, Platform, ercHash, to, Amount, Sigs)
+}
 func (f *front) TokenInRevert(cc *types.ContractContext, Platform, ercHash string, txid1, txid2 []byte, to common.Address, Amount *amount.Amount) error {
 	return f.cont.TokenInRevert(cc, Platform, ercHash, txid1, txid2, to, Amount)
 }
 func (f *front) TokenOut(cc *types.ContractContext, Platform string, withdrawAddress common.Address, Amount *amount.Amount) error {
 	return f.cont.TokenOut(cc, Platform, withdrawAddress, Amount)
 }
+func (f *front) ExecuteQueued(cc *types.ContractContext, id uint64) error {
+	return f.cont.ExecuteQueued(cc, id)
+}
 func (f *front) TokenLeave(cc *types.ContractContext, CoinTXID string, ERC20TXID string, Platform string) error {
 	return f.cont.TokenLeave(cc, CoinTXID, ERC20TXID, Platform)
 }
 func (f *front) SetSender(cc *types.ContractContext, To common.Address, Is bool) error {
 	return f.cont.SetSender(cc, To, Is)
 }
+func (f *front) SetRelayers(cc *types.ContractContext, Relayers []common.Address) error {
+	return f.cont.SetRelayers(cc, Relayers)
+}
+func (f *front) SetAttestationThreshold(cc *types.ContractContext, Platform string, Threshold uint16) error {
+	return f.cont.SetAttestationThreshold(cc, Platform, Threshold)
+}
+func (f *front) SetGuardian(cc *types.ContractContext, Guardian common.Address) error {
+	return f.cont.SetGuardian(cc, Guardian)
+}
+func (f *front) PausePlatform(cc *types.ContractContext, Platform string) error {
+	return f.cont.PausePlatform(cc, Platform)
+}
+func (f *front) UnpausePlatform(cc *types.ContractContext, Platform string) error {
+	return f.cont.UnpausePlatform(cc, Platform)
+}
+func (f *front) SetRateLimit(cc *types.ContractContext, Platform string, Limit *amount.Amount, Window uint64) error {
+	return f.cont.SetRateLimit(cc, Platform, Limit, Window)
+}
+func (f *front) SetLargeTransferDelay(cc *types.ContractContext, Platform string, Threshold *amount.Amount, Delay uint64) error {
+	return f.cont.SetLargeTransferDelay(cc, Platform, Threshold, Delay)
+}
+func (f *front) CancelQueued(cc *types.ContractContext, id uint64) error {
+	return f.cont.CancelQueued(cc, id)
+}
 func (f *front) TokenAddress(cc *types.ContractContext) common.Address {
 	return f.cont.TokenAddress(cc)
 }
 func (f *front) IsSender(cc types.ContractLoader, addr common.Address) bool {
 	return f.cont.IsSender(cc, addr)
 }
+func (f *front) Relayers(cc types.ContractLoader) []common.Address {
+	return f.cont.Relayers(cc)
+}
+func (f *front) RelayerEpoch(cc types.ContractLoader) uint64 {
+	return f.cont.RelayerEpoch(cc)
+}
+func (f *front) AttestationThreshold(cc types.ContractLoader, Platform string) uint16 {
+	return f.cont.AttestationThreshold(cc, Platform)
+}
+func (f *front) Guardian(cc types.ContractLoader) common.Address {
+	return f.cont.Guardian(cc)
+}
+func (f *front) IsPlatformPaused(cc *types.ContractContext, Platform string) bool {
+	return f.cont.IsPlatformPaused(cc, Platform)
+}
+func (f *front) RateLimit(cc *types.ContractContext, Platform string) (*amount.Amount, uint64) {
+	return f.cont.RateLimit(cc, Platform)
+}
+func (f *front) AvailableRateLimit(cc *types.ContractContext, Platform string) (*amount.Amount, bool) {
+	return f.cont.AvailableRateLimit(cc, Platform)
+}
+func (f *front) LargeTransferDelay(cc *types.ContractContext, Platform string) (*amount.Amount, uint64) {
+	return f.cont.LargeTransferDelay(cc, Platform)
+}
+func (f *front) QueuedTransferIDs(cc types.ContractLoader) []uint64 {
+	return f.cont.QueuedTransferIDs(cc)
+}
+func (f *front) QueuedTransfer(cc types.ContractLoader, id uint64) (common.Address, common.Address, *amount.Amount, string, uint64, error) {
+	return f.cont.QueuedTransfer(cc, id)
+}
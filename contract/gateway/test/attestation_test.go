@@ -0,0 +1,105 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/common/key"
+	"github.com/meverselabs/meverse/contract/attestation"
+	"github.com/meverselabs/meverse/contract/gateway"
+	"github.com/meverselabs/meverse/core/chain"
+	"github.com/meverselabs/meverse/extern/test/util"
+)
+
+func attest(t *testing.T, gwAddr common.Address, epoch uint64, tokenAddr common.Address, ercHash string, to common.Address, am *amount.Amount, keys ...key.Key) [][]byte {
+	h := attestation.Hash(util.ChainID, gwAddr, epoch, "ETH", ercHash, tokenAddr, to, am)
+	sigs := [][]byte{}
+	for _, k := range keys {
+		sig, err := k.Sign(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigs = append(sigs, sig)
+	}
+	return sigs
+}
+
+func TestAttestedTokenIn(t *testing.T) {
+	chain.SetVersion(1, attestation.Version)
+	defer chain.SetVersion(1, 2)
+
+	tc := util.NewTestContext()
+	tokenAddr := tc.MakeToken("TestToken", "TEST", "10000")
+	gwAddr := tc.DeployContract(&gateway.GatewayContract{}, &gateway.GatewayContractConstruction{TokenAddress: tokenAddr})
+	tc.MustSendTx(util.AdminKey, tokenAddr, "Transfer", gwAddr, amount.MustParseAmount("1000"))
+	tc.MustSendTx(util.AdminKey, gwAddr, "AddPlatform", "ETH", amount.MustParseAmount("1"))
+	tc.MustSendTx(util.AdminKey, gwAddr, "SetSender", util.Users[9], true)
+	tc.MustSendTx(util.AdminKey, tc.MainToken, "Transfer", util.Users[9], amount.MustParseAmount("100"))
+
+	relayers := []common.Address{util.Users[0], util.Users[1], util.Users[2]}
+	if _, err := tc.SendTx(util.AdminKey, gwAddr, "SetAttestationThreshold", "ETH", uint16(2)); err == nil {
+		t.Fatal("threshold is set over the relayer count")
+	}
+	tc.MustSendTx(util.AdminKey, gwAddr, "SetRelayers", relayers)
+	if _, err := tc.SendTx(util.AdminKey, gwAddr, "SetAttestationThreshold", "ETH", uint16(1)); err == nil {
+		t.Fatal("threshold is set under the min threshold")
+	}
+	tc.MustSendTx(util.AdminKey, gwAddr, "SetAttestationThreshold", "ETH", uint16(2))
+	if _, err := tc.SendTx(util.AdminKey, gwAddr, "SetRelayers", []common.Address{util.Users[0]}); err == nil {
+		t.Fatal("relayer set is shrunk under the threshold")
+	}
+
+	to := util.Users[5]
+	am := amount.MustParseAmount("10")
+	ercHash := "0x01"
+	if _, err := tc.SendTx(util.UserKeys[9], gwAddr, "TokenIn", "ETH", ercHash, to, am); err == nil {
+		t.Fatal("single sender credits the deposit in the attestation mode")
+	}
+	if _, err := tc.SendTx(util.UserKeys[9], gwAddr, "Transfer", to, am); err == nil {
+		t.Fatal("single sender transfers the funds in the attestation mode")
+	}
+
+	sigs := attest(t, gwAddr, 1, tokenAddr, ercHash, to, am, util.UserKeys[0], util.UserKeys[0], util.UserKeys[3])
+	if _, err := tc.SendTx(util.UserKeys[9], gwAddr, "AttestedTokenIn", "ETH", ercHash, to, am, sigs); err == nil {
+		t.Fatal("deposit is credited by one relayer")
+	}
+	sigs = attest(t, gwAddr, 1, tokenAddr, ercHash, to, am, util.UserKeys[0], util.UserKeys[2])
+	if _, err := tc.SendTx(util.UserKeys[9], gwAddr, "AttestedTokenIn", "ETH", ercHash, to, amount.MustParseAmount("20"), sigs); err == nil {
+		t.Fatal("deposit of the other amount is credited")
+	}
+	if _, err := tc.SendTx(util.UserKeys[9], gwAddr, "AttestedTokenIn", "ETH", ercHash, to, am, sigs); err != nil {
+		t.Fatal(err)
+	}
+	is, err := tc.ReadTx(util.AdminKey, tokenAddr, "BalanceOf", to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if is[0].(*amount.Amount).Cmp(am.Int) != 0 {
+		t.Errorf("balance %v, want %v", is[0], am)
+	}
+	if _, err := tc.SendTx(util.UserKeys[9], gwAddr, "AttestedTokenIn", "ETH", ercHash, to, am, sigs); err == nil {
+		t.Fatal("deposit is credited twice")
+	}
+
+	// the signatures of the previous set are not accepted after the rotation
+	tc.MustSendTx(util.AdminKey, gwAddr, "SetRelayers", []common.Address{util.Users[2], util.Users[3], util.Users[4]})
+	ercHash = "0x02"
+	sigs = attest(t, gwAddr, 1, tokenAddr, ercHash, to, am, util.UserKeys[2], util.UserKeys[3])
+	if _, err := tc.SendTx(util.UserKeys[9], gwAddr, "AttestedTokenIn", "ETH", ercHash, to, am, sigs); err == nil {
+		t.Fatal("deposit is credited by the signatures of the previous epoch")
+	}
+	sigs = attest(t, gwAddr, 2, tokenAddr, ercHash, to, am, util.UserKeys[0], util.UserKeys[1])
+	if _, err := tc.SendTx(util.UserKeys[9], gwAddr, "AttestedTokenIn", "ETH", ercHash, to, am, sigs); err == nil {
+		t.Fatal("deposit is credited by the removed relayers")
+	}
+	sigs = attest(t, gwAddr, 2, tokenAddr, ercHash, to, am, util.UserKeys[2], util.UserKeys[3])
+	if _, err := tc.SendTx(util.UserKeys[9], gwAddr, "AttestedTokenIn", "ETH", ercHash, to, am, sigs); err != nil {
+		t.Fatal(err)
+	}
+
+	tc.MustSendTx(util.AdminKey, gwAddr, "SetAttestationThreshold", "ETH", uint16(0))
+	if _, err := tc.SendTx(util.UserKeys[9], gwAddr, "TokenIn", "ETH", "0x03", to, am); err != nil {
+		t.Fatal(err)
+	}
+}
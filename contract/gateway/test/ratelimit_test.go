@@ -0,0 +1,107 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/contract/gateway"
+	"github.com/meverselabs/meverse/contract/ratelimit"
+	"github.com/meverselabs/meverse/core/chain"
+	"github.com/meverselabs/meverse/extern/test/util"
+)
+
+func TestTokenOutRateLimit(t *testing.T) {
+	chain.SetVersion(1, ratelimit.Version)
+	defer chain.SetVersion(1, 2)
+
+	tc := util.NewTestContext()
+	tokenAddr := tc.MakeToken("TestToken", "TEST", "10000")
+	gwAddr := tc.DeployContract(&gateway.GatewayContract{}, &gateway.GatewayContractConstruction{TokenAddress: tokenAddr})
+	tc.MustSendTx(util.AdminKey, gwAddr, "AddPlatform", "ETH", amount.MustParseAmount("1"))
+
+	user := util.Users[1]
+	userKey := util.UserKeys[1]
+	tc.MustSendTx(util.AdminKey, tokenAddr, "Transfer", user, amount.MustParseAmount("1000"))
+	tc.MustSendTx(util.AdminKey, tc.MainToken, "Transfer", user, amount.MustParseAmount("100"))
+	tc.MustSendTx(util.AdminKey, tc.MainToken, "Transfer", util.Users[2], amount.MustParseAmount("100"))
+	tc.MustSendTx(userKey, tokenAddr, "Approve", gwAddr, amount.MustParseAmount("1000"))
+
+	tc.MustSendTx(util.AdminKey, gwAddr, "SetRateLimit", "ETH", amount.MustParseAmount("100"), uint64(86400))
+	if _, err := tc.SendTx(userKey, gwAddr, "TokenOut", "ETH", user, amount.MustParseAmount("80")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tc.SendTx(userKey, gwAddr, "TokenOut", "ETH", user, amount.MustParseAmount("30")); err == nil {
+		t.Fatal("token out over the limit is sent")
+	}
+
+	tc.MustSendTx(util.AdminKey, gwAddr, "SetGuardian", util.Users[2])
+	tc.MustSendTx(util.UserKeys[2], gwAddr, "PausePlatform", "eth")
+	if _, err := tc.SendTx(userKey, gwAddr, "TokenOut", "ETH", user, amount.MustParseAmount("1")); err == nil {
+		t.Fatal("token out is sent to the paused platform")
+	}
+	if _, err := tc.SendTx(util.UserKeys[2], gwAddr, "UnpausePlatform", "ETH"); err == nil {
+		t.Fatal("platform is resumed by the guardian")
+	}
+	tc.MustSendTx(util.AdminKey, gwAddr, "UnpausePlatform", "ETH")
+	if _, err := tc.SendTx(userKey, gwAddr, "TokenOut", "ETH", user, amount.MustParseAmount("1")); err != nil {
+		t.Fatal(err)
+	}
+
+	// the limit is removed by the zero limit
+	tc.MustSendTx(util.AdminKey, gwAddr, "SetRateLimit", "ETH", amount.NewAmount(0, 0), uint64(0))
+	if _, err := tc.SendTx(userKey, gwAddr, "TokenOut", "ETH", user, amount.MustParseAmount("300")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestQueuedTokenOut(t *testing.T) {
+	chain.SetVersion(1, ratelimit.Version)
+	defer chain.SetVersion(1, 2)
+
+	tc := util.NewTestContext()
+	tokenAddr := tc.MakeToken("TestToken", "TEST", "10000")
+	gwAddr := tc.DeployContract(&gateway.GatewayContract{}, &gateway.GatewayContractConstruction{TokenAddress: tokenAddr})
+	tc.MustSendTx(util.AdminKey, gwAddr, "AddPlatform", "ETH", amount.MustParseAmount("1"))
+
+	user := util.Users[1]
+	userKey := util.UserKeys[1]
+	tc.MustSendTx(util.AdminKey, tokenAddr, "Transfer", user, amount.MustParseAmount("1000"))
+	tc.MustSendTx(util.AdminKey, tc.MainToken, "Transfer", user, amount.MustParseAmount("100"))
+	tc.MustSendTx(userKey, tokenAddr, "Approve", gwAddr, amount.MustParseAmount("1000"))
+	tc.MustSendTx(util.AdminKey, gwAddr, "SetLargeTransferDelay", "ETH", amount.MustParseAmount("100"), uint64(600))
+
+	// the small transfer is not queued
+	tc.MustSendTx(userKey, gwAddr, "TokenOut", "ETH", user, amount.MustParseAmount("10"))
+	tc.MustSendTx(userKey, gwAddr, "TokenOut", "ETH", util.Users[3], amount.MustParseAmount("200"))
+	is, err := tc.ReadTx(util.AdminKey, gwAddr, "QueuedTransfer", uint64(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if is[0].(common.Address) != user || is[1].(common.Address) != util.Users[3] || is[3].(string) != "eth" {
+		t.Fatalf("queued transfer %v", is)
+	}
+	if _, err := tc.SendTx(userKey, gwAddr, "ExecuteQueued", uint64(1)); err == nil {
+		t.Fatal("queued transfer is executed before the delay")
+	}
+	if err := tc.Sleep(600, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	tc.MustSendTx(util.AdminKey, gwAddr, "PausePlatform", "ETH")
+	if _, err := tc.SendTx(userKey, gwAddr, "ExecuteQueued", uint64(1)); err == nil {
+		t.Fatal("queued transfer is executed to the paused platform")
+	}
+	tc.MustSendTx(util.AdminKey, gwAddr, "UnpausePlatform", "ETH")
+	tc.MustSendTx(userKey, gwAddr, "ExecuteQueued", uint64(1))
+
+	// the canceled transfer is refunded to the sender except the fee
+	tc.MustSendTx(userKey, gwAddr, "TokenOut", "ETH", user, amount.MustParseAmount("300"))
+	tc.MustSendTx(util.AdminKey, gwAddr, "CancelQueued", uint64(2))
+	is, err = tc.ReadTx(util.AdminKey, tokenAddr, "BalanceOf", user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bal := is[0].(*amount.Amount); bal.Cmp(amount.MustParseAmount("787").Int) != 0 {
+		t.Fatalf("balance %v, want 787", bal)
+	}
+}
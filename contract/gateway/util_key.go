@@ -13,6 +13,8 @@ var (
 	tagPlatformFee          = byte(0x06)
 	tagTokenInRevert        = byte(0x07)
 	tagFeeOwner             = byte(0x08)
+	tagAttestation          = byte(0x09)
+	tagRateLimit            = byte(0x0A)
 )
 
 func makeGatewayKey(key byte, body []byte) []byte {
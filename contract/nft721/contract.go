@@ -219,6 +219,7 @@ func (cont *NFT721Contract) mint(cc *types.ContractContext, count *big.Int) ([]h
 		if err != nil {
 			return []hash.Hash256{}, err
 		}
+		emitTransfer(cc, common.ZeroAddr, cc.From(), nftID)
 
 		nc.Add(nc, one)
 		hs = append(hs, nftID)
@@ -327,7 +328,12 @@ func burn(cc *types.ContractContext, nftID hash.Hash256) error {
 	burnIndex := big.NewInt(0).SetBytes(indexbs)
 
 	removeNFT(cc, nftID, burnIndex)
-	return removeNFTAccount(cc, nftID)
+	owner, err := removeNFTAccount(cc, nftID)
+	if err != nil {
+		return err
+	}
+	emitTransfer(cc, owner, common.ZeroAddr, nftID)
+	return nil
 }
 
 func removeNFT(cc *types.ContractContext, nftID hash.Hash256, burnIndex *big.Int) {
@@ -344,12 +350,12 @@ func removeNFT(cc *types.ContractContext, nftID hash.Hash256, burnIndex *big.Int
 	cc.SetContractData([]byte{tagNFTCount}, lastIndex.Bytes())
 }
 
-func removeNFTAccount(cc *types.ContractContext, nftID hash.Hash256) error {
+func removeNFTAccount(cc *types.ContractContext, nftID hash.Hash256) (common.Address, error) {
 	addr := ownerOf(cc, nftID)
 
 	indexbs := cc.AccountData(addr, makeNFTIndexKey(nftID))
 	if len(indexbs) == 0 {
-		return errors.New("not exist nft")
+		return common.Address{}, errors.New("not exist nft")
 	}
 	burnIndex := big.NewInt(0).SetBytes(indexbs)
 
@@ -364,7 +370,7 @@ func removeNFTAccount(cc *types.ContractContext, nftID hash.Hash256) error {
 	}
 	cc.SetAccountData(addr, makeIndexNFTKey(lastIndex), nil)
 	cc.SetAccountData(addr, []byte{tagNFTCount}, lastIndex.Bytes())
-	return nil
+	return addr, nil
 }
 
 func (cont *NFT721Contract) setBaseURI(cc *types.ContractContext, uri string) error {
@@ -432,13 +438,14 @@ func transferFrom(cc *types.ContractContext, _from common.Address, _to common.Ad
 		return err
 	}
 
-	if err := removeNFTAccount(cc, _tokenId); err != nil {
+	if _, err := removeNFTAccount(cc, _tokenId); err != nil {
 		return err
 	}
 
 	if err := addNFTAccount(cc, _to, _tokenId); err != nil {
 		return err
 	}
+	emitTransfer(cc, _from, _to, _tokenId)
 
 	return nil
 }
@@ -478,6 +485,7 @@ func approve(cc *types.ContractContext, _approved common.Address, _tokenId hash.
 		return errors.New("not token owner")
 	}
 	cc.SetContractData(makeTokenApproveKey(_tokenId), _approved[:])
+	cc.AddLog([]hash.Hash256{types.ApprovalEventTopic, types.AddressTopic(cc.From()), types.AddressTopic(_approved), _tokenId}, nil)
 	return nil
 }
 
@@ -493,6 +501,16 @@ func setApprovalForAll(cc *types.ContractContext, _operator common.Address, _app
 	} else {
 		cc.SetContractData(makeTokenApproveForAllKey(cc.From(), _operator), nil)
 	}
+	data := make([]byte, 32)
+	if _approved {
+		data[31] = 1
+	}
+	cc.AddLog([]hash.Hash256{types.ApprovalForAllEventTopic, types.AddressTopic(cc.From()), types.AddressTopic(_operator)}, data)
+}
+
+// emitTransfer adds the erc721 Transfer log which indexes the token id
+func emitTransfer(cc *types.ContractContext, _from common.Address, _to common.Address, _tokenId hash.Hash256) {
+	cc.AddLog([]hash.Hash256{types.TransferEventTopic, types.AddressTopic(_from), types.AddressTopic(_to), _tokenId}, nil)
 }
 
 func printContractData(cc *types.ContractContext, addr common.Address) {
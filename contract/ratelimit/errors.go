@@ -0,0 +1,15 @@
+package ratelimit
+
+import "errors"
+
+// ratelimit errors
+var (
+	ErrPaused        = errors.New("route is paused")
+	ErrRateLimited   = errors.New("rate limit exceeded")
+	ErrNotGuardian   = errors.New("not guardian")
+	ErrInvalidWindow = errors.New("invalid window")
+	ErrInvalidLimit  = errors.New("invalid limit")
+	ErrNotQueued     = errors.New("not queued transfer")
+	ErrQueueNotReady = errors.New("queued transfer is not ready")
+	ErrInvalidQueued = errors.New("invalid queued transfer")
+)
@@ -0,0 +1,347 @@
+package ratelimit
+
+import (
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/core/types"
+)
+
+// Version is the chain version from which the outflows of the contract are limited
+const Version = uint16(6)
+
+// directions of the route
+const (
+	Outbound = byte(0x01)
+	Inbound  = byte(0x02)
+)
+
+// sub keys under the tag of the contract
+var (
+	subGuardian   = byte(0x01)
+	subPaused     = byte(0x02)
+	subLimit      = byte(0x03)
+	subBucket     = byte(0x04)
+	subDelay      = byte(0x05)
+	subQueue      = byte(0x06)
+	subQueueCount = byte(0x07)
+	subQueueList  = byte(0x08)
+)
+
+// topics of the queue events, the relayers follow the outbound transfer when it is executed
+var (
+	TransferQueuedEventTopic   = crypto.Keccak256Hash([]byte("TransferQueued(uint64,uint256)"))
+	TransferExecutedEventTopic = crypto.Keccak256Hash([]byte("TransferExecuted(uint64,uint256)"))
+	TransferCanceledEventTopic = crypto.Keccak256Hash([]byte("TransferCanceled(uint64,uint256)"))
+)
+
+func makeKey(tag byte, sub byte, body []byte) []byte {
+	bs := make([]byte, 2+len(body))
+	bs[0] = tag
+	bs[1] = sub
+	copy(bs[2:], body)
+	return bs
+}
+
+// routeBody makes the key body of the route, the zero token means all tokens of the chain
+func routeBody(dir byte, token common.Address, chain string) []byte {
+	bs := make([]byte, 0, 1+common.AddressLength+len(chain))
+	bs = append(bs, dir)
+	bs = append(bs, token[:]...)
+	bs = append(bs, []byte(strings.ToLower(chain))...)
+	return bs
+}
+
+// SetGuardian sets the guardian which can pause the routes of the contract
+func SetGuardian(cc *types.ContractContext, tag byte, guardian common.Address) {
+	if guardian == common.ZeroAddr {
+		cc.SetContractData(makeKey(tag, subGuardian, nil), nil)
+	} else {
+		cc.SetContractData(makeKey(tag, subGuardian, nil), guardian[:])
+	}
+}
+
+// Guardian returns the guardian of the contract
+func Guardian(cc types.ContractLoader, tag byte) common.Address {
+	return common.BytesToAddress(cc.ContractData(makeKey(tag, subGuardian, nil)))
+}
+
+// SetPaused pauses or resumes the route
+func SetPaused(cc *types.ContractContext, tag byte, dir byte, token common.Address, chain string, paused bool) {
+	if paused {
+		cc.SetContractData(makeKey(tag, subPaused, routeBody(dir, token, chain)), []byte{1})
+	} else {
+		cc.SetContractData(makeKey(tag, subPaused, routeBody(dir, token, chain)), nil)
+	}
+}
+
+// IsPaused returns true when the route or all tokens of the chain are paused
+func IsPaused(cc types.ContractLoader, tag byte, dir byte, token common.Address, chain string) bool {
+	if bs := cc.ContractData(makeKey(tag, subPaused, routeBody(dir, common.ZeroAddr, chain))); len(bs) == 1 && bs[0] == 1 {
+		return true
+	}
+	if token == common.ZeroAddr {
+		return false
+	}
+	bs := cc.ContractData(makeKey(tag, subPaused, routeBody(dir, token, chain)))
+	return len(bs) == 1 && bs[0] == 1
+}
+
+// SetLimit sets the amount which can flow through the route during the window in seconds
+// zero limit removes the limit of the route
+func SetLimit(cc *types.ContractContext, tag byte, dir byte, token common.Address, chain string, limit *amount.Amount, window uint64) error {
+	key := makeKey(tag, subLimit, routeBody(dir, token, chain))
+	if limit == nil || limit.IsZero() {
+		cc.SetContractData(key, nil)
+		cc.SetContractData(makeKey(tag, subBucket, routeBody(dir, token, chain)), nil)
+		return nil
+	}
+	if limit.IsMinus() {
+		return ErrInvalidLimit
+	}
+	if window == 0 {
+		return ErrInvalidWindow
+	}
+	cc.SetContractData(key, bin.TypeWriteAll(limit, window))
+	// the bucket is filled again with the new limit
+	cc.SetContractData(makeKey(tag, subBucket, routeBody(dir, token, chain)), nil)
+	return nil
+}
+
+// Limit returns the limit and the window in seconds of the route, the zero window means unlimited
+func Limit(cc types.ContractLoader, tag byte, dir byte, token common.Address, chain string) (*amount.Amount, uint64) {
+	bs := cc.ContractData(makeKey(tag, subLimit, routeBody(dir, token, chain)))
+	if len(bs) == 0 {
+		return amount.NewAmount(0, 0), 0
+	}
+	is, err := bin.TypeReadAll(bs, 2)
+	if err != nil {
+		return amount.NewAmount(0, 0), 0
+	}
+	limit, ok := is[0].(*amount.Amount)
+	if !ok {
+		return amount.NewAmount(0, 0), 0
+	}
+	window, ok := is[1].(uint64)
+	if !ok {
+		return amount.NewAmount(0, 0), 0
+	}
+	return limit, window
+}
+
+// Available returns the amount which can flow through the route now
+// the used amount is recovered linearly over the window so that the limit works as a rolling window
+func Available(cc types.ContractLoader, tag byte, dir byte, token common.Address, chain string, now uint64) (*amount.Amount, bool) {
+	limit, window := Limit(cc, tag, dir, token, chain)
+	if window == 0 {
+		return nil, false
+	}
+	bs := cc.ContractData(makeKey(tag, subBucket, routeBody(dir, token, chain)))
+	if len(bs) < 8 {
+		return limit, true
+	}
+	last := bin.Uint64(bs[:8])
+	remain := new(big.Int).SetBytes(bs[8:])
+	if now > last {
+		refill := new(big.Int).Mul(limit.Int, new(big.Int).SetUint64(now-last))
+		refill.Div(refill, new(big.Int).Mul(new(big.Int).SetUint64(window), big.NewInt(int64(time.Second))))
+		remain.Add(remain, refill)
+	}
+	if remain.Cmp(limit.Int) > 0 {
+		remain.Set(limit.Int)
+	}
+	return amount.NewAmountFromBytes(remain.Bytes()), true
+}
+
+// Consume checks the pause of the route and spends the amount from the limit of the route
+// it doesn't read anything before the Version to keep the gas of the past blocks
+func Consume(cc *types.ContractContext, tag byte, dir byte, token common.Address, chain string, amt *amount.Amount) error {
+	if cc.Version(cc.TargetHeight()) < Version {
+		return nil
+	}
+	if IsPaused(cc, tag, dir, token, chain) {
+		return ErrPaused
+	}
+	now := cc.LastTimestamp()
+	avail, limited := Available(cc, tag, dir, token, chain, now)
+	if !limited {
+		return nil
+	}
+	if avail.Cmp(amt.Int) < 0 {
+		return ErrRateLimited
+	}
+	remain := avail.Sub(amt)
+	bs := make([]byte, 8, 8+len(remain.Bytes()))
+	bin.PutUint64(bs, now)
+	bs = append(bs, remain.Int.Bytes()...)
+	cc.SetContractData(makeKey(tag, subBucket, routeBody(dir, token, chain)), bs)
+	return nil
+}
+
+// SetDelay sets the threshold from which the transfer of the route is queued for the delay in seconds
+// zero threshold disables the queue of the route
+func SetDelay(cc *types.ContractContext, tag byte, dir byte, token common.Address, chain string, threshold *amount.Amount, delay uint64) error {
+	key := makeKey(tag, subDelay, routeBody(dir, token, chain))
+	if threshold == nil || threshold.IsZero() {
+		cc.SetContractData(key, nil)
+		return nil
+	}
+	if threshold.IsMinus() {
+		return ErrInvalidLimit
+	}
+	cc.SetContractData(key, bin.TypeWriteAll(threshold, delay))
+	return nil
+}
+
+// Delay returns the threshold and the delay in seconds of the route
+func Delay(cc types.ContractLoader, tag byte, dir byte, token common.Address, chain string) (*amount.Amount, uint64) {
+	bs := cc.ContractData(makeKey(tag, subDelay, routeBody(dir, token, chain)))
+	if len(bs) == 0 {
+		return amount.NewAmount(0, 0), 0
+	}
+	is, err := bin.TypeReadAll(bs, 2)
+	if err != nil {
+		return amount.NewAmount(0, 0), 0
+	}
+	threshold, ok := is[0].(*amount.Amount)
+	if !ok {
+		return amount.NewAmount(0, 0), 0
+	}
+	delay, ok := is[1].(uint64)
+	if !ok {
+		return amount.NewAmount(0, 0), 0
+	}
+	return threshold, delay
+}
+
+// IsLarge returns true when the transfer of the route should be queued
+// it doesn't read anything before the Version to keep the gas of the past blocks
+func IsLarge(cc *types.ContractContext, tag byte, dir byte, token common.Address, chain string, amt *amount.Amount) bool {
+	if cc.Version(cc.TargetHeight()) < Version {
+		return false
+	}
+	threshold, _ := Delay(cc, tag, dir, token, chain)
+	return threshold.IsPlus() && amt.Cmp(threshold.Int) >= 0
+}
+
+// Transfer is the transfer which waits the delay of the route
+type Transfer struct {
+	ID      uint64
+	Dir     byte
+	Token   common.Address
+	From    common.Address // the outbound transfer is refunded to it when it is canceled
+	To      common.Address
+	Amount  *amount.Amount
+	Chain   string
+	ReadyAt uint64 // timestamp in nanoseconds from which the transfer can be executed
+}
+
+// Enqueue stores the transfer which can be executed after the delay of the route and returns the id of it
+func Enqueue(cc *types.ContractContext, tag byte, dir byte, token common.Address, from common.Address, to common.Address, amt *amount.Amount, chain string) uint64 {
+	_, delay := Delay(cc, tag, dir, token, chain)
+	var id uint64
+	if bs := cc.ContractData(makeKey(tag, subQueueCount, nil)); len(bs) == 8 {
+		id = bin.Uint64(bs)
+	}
+	id++
+	cc.SetContractData(makeKey(tag, subQueueCount, nil), bin.Uint64Bytes(id))
+
+	readyAt := cc.LastTimestamp() + delay*uint64(time.Second)
+	cc.SetContractData(makeKey(tag, subQueue, bin.Uint64Bytes(id)), bin.TypeWriteAll([]byte{dir}, token, from, to, amt, chain, readyAt))
+
+	list := cc.ContractData(makeKey(tag, subQueueList, nil))
+	list = append(list[:len(list):len(list)], bin.Uint64Bytes(id)...)
+	cc.SetContractData(makeKey(tag, subQueueList, nil), list)
+
+	cc.AddLog([]hash.Hash256{TransferQueuedEventTopic, types.Uint256Topic(new(big.Int).SetUint64(id))}, types.Uint256Data(amt.Int))
+	return id
+}
+
+// Queued returns the queued transfer of the id
+func Queued(cc types.ContractLoader, tag byte, id uint64) (*Transfer, error) {
+	bs := cc.ContractData(makeKey(tag, subQueue, bin.Uint64Bytes(id)))
+	if len(bs) == 0 {
+		return nil, ErrNotQueued
+	}
+	is, err := bin.TypeReadAll(bs, 7)
+	if err != nil {
+		return nil, err
+	}
+	t := &Transfer{ID: id}
+	dir, ok := is[0].([]byte)
+	if !ok || len(dir) != 1 {
+		return nil, ErrInvalidQueued
+	}
+	t.Dir = dir[0]
+	if t.Token, ok = is[1].(common.Address); !ok {
+		return nil, ErrInvalidQueued
+	}
+	if t.From, ok = is[2].(common.Address); !ok {
+		return nil, ErrInvalidQueued
+	}
+	if t.To, ok = is[3].(common.Address); !ok {
+		return nil, ErrInvalidQueued
+	}
+	if t.Amount, ok = is[4].(*amount.Amount); !ok {
+		return nil, ErrInvalidQueued
+	}
+	if t.Chain, ok = is[5].(string); !ok {
+		return nil, ErrInvalidQueued
+	}
+	if t.ReadyAt, ok = is[6].(uint64); !ok {
+		return nil, ErrInvalidQueued
+	}
+	return t, nil
+}
+
+// QueuedIDs returns the ids of the queued transfers in the queued order
+func QueuedIDs(cc types.ContractLoader, tag byte) []uint64 {
+	bs := cc.ContractData(makeKey(tag, subQueueList, nil))
+	ids := make([]uint64, 0, len(bs)/8)
+	for i := 0; i+8 <= len(bs); i += 8 {
+		ids = append(ids, bin.Uint64(bs[i:i+8]))
+	}
+	return ids
+}
+
+// Dequeue removes the queued transfer and returns it
+// the transfer can be removed before the delay when it is canceled
+// the executor should check the pause of the route at the execution
+func Dequeue(cc *types.ContractContext, tag byte, id uint64, cancel bool) (*Transfer, error) {
+	t, err := Queued(cc, tag, id)
+	if err != nil {
+		return nil, err
+	}
+	if !cancel {
+		if cc.LastTimestamp() < t.ReadyAt {
+			return nil, ErrQueueNotReady
+		}
+	}
+	cc.SetContractData(makeKey(tag, subQueue, bin.Uint64Bytes(id)), nil)
+
+	ids := QueuedIDs(cc, tag)
+	list := make([]byte, 0, len(ids)*8)
+	for _, v := range ids {
+		if v != id {
+			list = append(list, bin.Uint64Bytes(v)...)
+		}
+	}
+	if len(list) == 0 {
+		list = nil
+	}
+	cc.SetContractData(makeKey(tag, subQueueList, nil), list)
+
+	topic := TransferExecutedEventTopic
+	if cancel {
+		topic = TransferCanceledEventTopic
+	}
+	cc.AddLog([]hash.Hash256{topic, types.Uint256Topic(new(big.Int).SetUint64(id))}, types.Uint256Data(t.Amount.Int))
+	return t, nil
+}
@@ -43,6 +43,7 @@ func (cont *TokenContract) OnCreate(cc *types.ContractContext, Args []byte) erro
 			if err := cont.addBalance(cc, k, v); err != nil {
 				return err
 			}
+			cc.EmitTransfer(common.ZeroAddr, k, v.Int)
 		}
 	}
 
@@ -153,7 +154,11 @@ func (cont *TokenContract) SubCollectedFee(cc *types.ContractContext, am *amount
 }
 
 func (cont *TokenContract) Transfer(cc *types.ContractContext, To common.Address, Amount *amount.Amount) error {
-	if cc.From() == common.ZeroAddr {
+	return cont._transfer(cc, cc.From(), To, Amount)
+}
+
+func (cont *TokenContract) _transfer(cc *types.ContractContext, From common.Address, To common.Address, Amount *amount.Amount) error {
+	if From == common.ZeroAddr {
 		return errors.New("Token: TRANSFER_FROM_ZEROADDRESS")
 	}
 
@@ -165,18 +170,21 @@ func (cont *TokenContract) Transfer(cc *types.ContractContext, To common.Address
 		return errors.New("minus amount")
 	}
 
-	fromBalance := cont.BalanceOf(cc, cc.From())
+	fromBalance := cont.BalanceOf(cc, From)
 	if fromBalance.Cmp(Amount.Int) < 0 {
-		return fmt.Errorf("Token: TRANSFER_EXCEED_BALANCE %v %v %v %v", cc.From().String(), To.String(), fromBalance.String(), Amount.String())
+		return fmt.Errorf("Token: TRANSFER_EXCEED_BALANCE %v %v %v %v", From.String(), To.String(), fromBalance.String(), Amount.String())
 	}
 
-	if Amount.IsZero() {
-		return nil
-	}
-	if err := cont.subBalance(cc, cc.From(), Amount); err != nil {
-		return err
+	if !Amount.IsZero() {
+		if err := cont.subBalance(cc, From, Amount); err != nil {
+			return err
+		}
+		if err := cont.addBalance(cc, To, Amount); err != nil {
+			return err
+		}
 	}
-	return cont.addBalance(cc, To, Amount)
+	cc.EmitTransfer(From, To, Amount.Int)
+	return nil
 }
 func (cont *TokenContract) DelegateFeeTransfer(cc *types.ContractContext, To common.Address, Amount *amount.Amount) error {
 	di, err := getDelegateInfo(cc)
@@ -194,7 +202,11 @@ func (cont *TokenContract) Burn(cc *types.ContractContext, am *amount.Amount) er
 	if am.IsMinus() {
 		return errors.New("minus amount")
 	}
-	return cont.subBalance(cc, cc.From(), am)
+	if err := cont.subBalance(cc, cc.From(), am); err != nil {
+		return err
+	}
+	cc.EmitTransfer(cc.From(), common.ZeroAddr, am.Int)
+	return nil
 }
 
 func (cont *TokenContract) Mint(cc *types.ContractContext, To common.Address, Amount *amount.Amount) error {
@@ -202,7 +214,11 @@ func (cont *TokenContract) Mint(cc *types.ContractContext, To common.Address, Am
 	if cc.From() != cont.Master() && !isMinter {
 		return errors.New(cc.From().String() + ": not token minter")
 	}
-	return cont.addBalance(cc, To, Amount)
+	if err := cont.addBalance(cc, To, Amount); err != nil {
+		return err
+	}
+	cc.EmitTransfer(common.ZeroAddr, To, Amount.Int)
+	return nil
 }
 
 func (cont *TokenContract) MintBatch(cc *types.ContractContext, Tos []common.Address, Amounts []*amount.Amount) error {
@@ -217,6 +233,7 @@ func (cont *TokenContract) MintBatch(cc *types.ContractContext, Tos []common.Add
 		if err := cont.addBalance(cc, To, Amounts[i]); err != nil {
 			return err
 		}
+		cc.EmitTransfer(common.ZeroAddr, To, Amounts[i].Int)
 	}
 	return nil
 }
@@ -263,7 +280,11 @@ func (cont *TokenContract) SetTokenManager(cc *types.ContractContext, To common.
 }
 
 func (cont *TokenContract) Approve(cc *types.ContractContext, spender common.Address, Amount *amount.Amount) error {
-	if cc.From() == common.ZeroAddr {
+	return cont._checkedApprove(cc, cc.From(), spender, Amount)
+}
+
+func (cont *TokenContract) _checkedApprove(cc *types.ContractContext, owner common.Address, spender common.Address, Amount *amount.Amount) error {
+	if owner == common.ZeroAddr {
 		return errors.New("Token: APPROVE_FROM_ZEROADDRESS")
 	}
 
@@ -275,7 +296,7 @@ func (cont *TokenContract) Approve(cc *types.ContractContext, spender common.Add
 		return errors.New("Token: APPROVE_NEGATIVE_AMOUNT")
 	}
 
-	cont._approve(cc, cc.From(), spender, Amount)
+	cont._approve(cc, owner, spender, Amount)
 	return nil
 }
 
@@ -310,10 +331,12 @@ func (cont *TokenContract) DelegateFeeApprove(cc *types.ContractContext, spender
 
 func (cont *TokenContract) _approve(cc *types.ContractContext, owner common.Address, spender common.Address, Amount *amount.Amount) {
 	cc.SetAccountData(owner, MakeAllowanceTokenKey(spender), Amount.Bytes())
+	cc.EmitApproval(owner, spender, Amount.Int)
 }
 
 func (cont *TokenContract) TransferFrom(cc *types.ContractContext, From common.Address, To common.Address, Amount *amount.Amount) error {
 	if Amount.IsZero() {
+		cc.EmitTransfer(From, To, Amount.Int)
 		return nil
 	}
 	balance := cont.BalanceOf(cc, From)
@@ -339,6 +362,7 @@ func (cont *TokenContract) TransferFrom(cc *types.ContractContext, From common.A
 	if err := cont.addBalance(cc, To, Amount); err != nil {
 		return err
 	}
+	cc.EmitTransfer(From, To, Amount.Int)
 	return nil
 }
 
@@ -354,6 +378,7 @@ func (cont *TokenContract) TokenInRevert(cc *types.ContractContext, Platform str
 	if err := cont.addBalance(cc, cc.From(), Amount); err != nil {
 		return err
 	}
+	cc.EmitTransfer(to, cc.From(), Amount.Int)
 
 	return nil
 }
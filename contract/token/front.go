@@ -5,6 +5,7 @@ import (
 
 	"github.com/meverselabs/meverse/common"
 	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/common/hash"
 	"github.com/meverselabs/meverse/core/types"
 )
 
@@ -98,6 +99,20 @@ func (f *front) Unpause(cc *types.ContractContext) error {
 func (f *front) SetVersion(cc *types.ContractContext, version uint8) error {
 	return f.cont.SetVersion(cc, version)
 }
+func (f *front) Permit(cc *types.ContractContext, owner common.Address, spender common.Address, value *amount.Amount, deadline *big.Int, v uint8, r hash.Hash256, s hash.Hash256) error {
+	return f.cont.Permit(cc, owner, spender, value, deadline, v, r, s)
+}
+func (f *front) SetTrustedForwarder(cc *types.ContractContext, Forwarder common.Address, Is bool) error {
+	return f.cont.SetTrustedForwarder(cc, Forwarder, Is)
+}
+func (f *front) ForwardedTransfer(cc *types.ContractContext, From common.Address, To common.Address, Amount *amount.Amount) (bool, error) {
+	err := f.cont.ForwardedTransfer(cc, From, To, Amount)
+	return err == nil, err
+}
+func (f *front) ForwardedApprove(cc *types.ContractContext, From common.Address, spender common.Address, Amount *amount.Amount) (bool, error) {
+	err := f.cont.ForwardedApprove(cc, From, spender, Amount)
+	return err == nil, err
+}
 
 //////////////////////////////////////////////////
 // Public Reader Functions
@@ -134,6 +149,18 @@ func (f *front) CollectedFee(cc types.ContractLoader) *amount.Amount {
 func (f *front) Allowance(cc types.ContractLoader, _owner common.Address, _spender common.Address) *amount.Amount {
 	return f.cont.Allowance(cc, _owner, _spender)
 }
+
+func (f *front) Nonces(cc types.ContractLoader, owner common.Address) *big.Int {
+	return f.cont.Nonces(cc, owner)
+}
+
+func (f *front) DomainSeparator(cc types.ContractLoader) hash.Hash256 {
+	return f.cont.DomainSeparator(cc)
+}
+
+func (f *front) IsTrustedForwarder(cc types.ContractLoader, addr common.Address) bool {
+	return f.cont.IsTrustedForwarder(cc, addr)
+}
 func (f *front) Version(cc *types.ContractContext) uint8 {
 	return f.cont.Version(cc)
 }
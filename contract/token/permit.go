@@ -0,0 +1,125 @@
+package token
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/core/types"
+)
+
+// PermitVersion is the version of the EIP-712 domain of the token
+const PermitVersion = "1"
+
+// PermitTypeHash is the type hash of the EIP-2612 permit
+var PermitTypeHash = crypto.Keccak256Hash([]byte("Permit(address owner,address spender,uint256 value,uint256 nonce,uint256 deadline)"))
+
+//////////////////////////////////////////////////
+// Permit Writer Functions
+//////////////////////////////////////////////////
+
+// Permit approves the spender by the EIP-712 signature of the owner so that the owner doesn't need to send the transaction
+// the deadline is the unix time in seconds
+func (cont *TokenContract) Permit(cc *types.ContractContext, owner common.Address, spender common.Address, value *amount.Amount, deadline *big.Int, v uint8, r hash.Hash256, s hash.Hash256) error {
+	now := new(big.Int).SetUint64(cc.LastTimestamp() / uint64(time.Second))
+	if deadline == nil || deadline.Cmp(now) < 0 {
+		return errors.New("Token: PERMIT_EXPIRED")
+	}
+
+	nonce := cont.Nonces(cc, owner)
+	structHash := crypto.Keccak256Hash(
+		PermitTypeHash[:],
+		common.EIP712Address(owner),
+		common.EIP712Address(spender),
+		common.EIP712Uint256(value.Int),
+		common.EIP712Uint256(nonce),
+		common.EIP712Uint256(deadline),
+	)
+	digest := common.EIP712Hash(cont.DomainSeparator(cc), structHash)
+
+	sig := make(common.Signature, 0, 65)
+	sig = append(sig, r[:]...)
+	sig = append(sig, s[:]...)
+	sig = append(sig, v)
+	signer, err := common.RecoverTypedSigner(cc.ChainID(), digest, sig)
+	if err != nil {
+		return err
+	}
+	if signer != owner {
+		return errors.New("Token: INVALID_PERMIT_SIGNATURE")
+	}
+	cc.SetAccountData(owner, []byte{tagPermitNonce}, nonce.Add(nonce, big.NewInt(1)).Bytes())
+
+	return cont._checkedApprove(cc, owner, spender, value)
+}
+
+//////////////////////////////////////////////////
+// Forwarder Writer Functions
+//////////////////////////////////////////////////
+
+// SetTrustedForwarder sets the forwarder which can transfer and approve on behalf of the signer of the request
+func (cont *TokenContract) SetTrustedForwarder(cc *types.ContractContext, Forwarder common.Address, Is bool) error {
+	if cc.From() != cont.Master() {
+		return errors.New("not token master")
+	}
+
+	isForwarder := cont.IsTrustedForwarder(cc, Forwarder)
+
+	if Is {
+		if isForwarder {
+			return errors.New("already trusted forwarder")
+		}
+		cc.SetAccountData(Forwarder, []byte{tagTrustedForwarder}, []byte{1})
+	} else {
+		if !isForwarder {
+			return errors.New("not trusted forwarder")
+		}
+		cc.SetAccountData(Forwarder, []byte{tagTrustedForwarder}, nil)
+	}
+	return nil
+}
+
+// ForwardedTransfer transfers the token of the From which is verified by the trusted forwarder
+// it is the native form of EIP-2771 which appends the sender to the call data
+func (cont *TokenContract) ForwardedTransfer(cc *types.ContractContext, From common.Address, To common.Address, Amount *amount.Amount) error {
+	if !cont.IsTrustedForwarder(cc, cc.From()) {
+		return errors.New("not trusted forwarder")
+	}
+	return cont._transfer(cc, From, To, Amount)
+}
+
+// ForwardedApprove approves the spender of the From which is verified by the trusted forwarder
+func (cont *TokenContract) ForwardedApprove(cc *types.ContractContext, From common.Address, spender common.Address, Amount *amount.Amount) error {
+	if !cont.IsTrustedForwarder(cc, cc.From()) {
+		return errors.New("not trusted forwarder")
+	}
+	return cont._checkedApprove(cc, From, spender, Amount)
+}
+
+//////////////////////////////////////////////////
+// Permit Reader Functions
+//////////////////////////////////////////////////
+
+// Nonces returns the permit nonce of the owner
+func (cont *TokenContract) Nonces(cc types.ContractLoader, owner common.Address) *big.Int {
+	bs := cc.AccountData(owner, []byte{tagPermitNonce})
+	return big.NewInt(0).SetBytes(bs)
+}
+
+// DomainSeparator returns the EIP-712 domain separator of the token
+func (cont *TokenContract) DomainSeparator(cc types.ContractLoader) hash.Hash256 {
+	return common.EIP712DomainSeparator(cont.Name(cc), PermitVersion, cc.ChainID(), cont.addr)
+}
+
+func (cont *TokenContract) IsTrustedForwarder(cc types.ContractLoader, addr common.Address) bool {
+	bs := cc.AccountData(addr, []byte{tagTrustedForwarder})
+	if len(bs) == 1 && bs[0] == 1 {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/common/key"
+	"github.com/meverselabs/meverse/contract/token"
+	"github.com/meverselabs/meverse/extern/test/util"
+)
+
+func signPermit(t *testing.T, tc *util.TestContext, tokenAddr common.Address, mkey key.Key, spender common.Address, value *amount.Amount, deadline *big.Int) (uint8, hash.Hash256, hash.Hash256) {
+	owner := mkey.PublicKey().Address()
+	is, err := tc.ReadTx(mkey, tokenAddr, "Nonces", owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := is[0].(*big.Int)
+	is, err = tc.ReadTx(mkey, tokenAddr, "DomainSeparator")
+	if err != nil {
+		t.Fatal(err)
+	}
+	domain := is[0].(hash.Hash256)
+
+	structHash := crypto.Keccak256Hash(
+		token.PermitTypeHash[:],
+		common.EIP712Address(owner),
+		common.EIP712Address(spender),
+		common.EIP712Uint256(value.Int),
+		common.EIP712Uint256(nonce),
+		common.EIP712Uint256(deadline),
+	)
+	digest := common.EIP712Hash(domain, structHash)
+	sig, err := crypto.Sign(digest[:], mkey.(*key.MemoryKey).PrivKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r, s hash.Hash256
+	copy(r[:], sig[:32])
+	copy(s[:], sig[32:64])
+	// eth_signTypedData returns 27 or 28
+	return sig[64] + 27, r, s
+}
+
+func TestPermit(t *testing.T) {
+	tc := util.NewTestContext()
+	tokenAddr := tc.MakeToken("TestToken", "TEST", "10000")
+
+	owner := util.UserKeys[0]
+	spender := util.Users[1]
+	value := amount.MustParseAmount("5")
+	deadline := big.NewInt(int64(tc.Ctx.LastTimestamp()/uint64(time.Second)) + 3600)
+
+	v, r, s := signPermit(t, tc, tokenAddr, owner, spender, value, deadline)
+	// the relayer sends the permit so that the owner doesn't need the fee
+	if _, err := tc.SendTx(util.AdminKey, tokenAddr, "Permit", util.Users[0], spender, value, deadline, v, r, s); err != nil {
+		t.Fatal(err)
+	}
+	is, err := tc.ReadTx(util.AdminKey, tokenAddr, "Allowance", util.Users[0], spender)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if is[0].(*amount.Amount).Cmp(value.Int) != 0 {
+		t.Errorf("allowance %v, want %v", is[0], value)
+	}
+	is, err = tc.ReadTx(util.AdminKey, tokenAddr, "Nonces", util.Users[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if is[0].(*big.Int).Int64() != 1 {
+		t.Errorf("nonce %v, want 1", is[0])
+	}
+
+	if _, err := tc.SendTx(util.AdminKey, tokenAddr, "Permit", util.Users[0], spender, value, deadline, v, r, s); err == nil {
+		t.Error("replayed permit is accepted")
+	}
+
+	v, r, s = signPermit(t, tc, tokenAddr, owner, spender, value, deadline)
+	if _, err := tc.SendTx(util.AdminKey, tokenAddr, "Permit", util.Users[0], spender, amount.MustParseAmount("6"), deadline, v, r, s); err == nil {
+		t.Error("permit of the other value is accepted")
+	}
+
+	expired := big.NewInt(int64(tc.Ctx.LastTimestamp()/uint64(time.Second)) - 1)
+	v, r, s = signPermit(t, tc, tokenAddr, owner, spender, value, expired)
+	if _, err := tc.SendTx(util.AdminKey, tokenAddr, "Permit", util.Users[0], spender, value, expired, v, r, s); err == nil {
+		t.Error("expired permit is accepted")
+	}
+}
+
+func TestForwardedTransfer(t *testing.T) {
+	tc := util.NewTestContext()
+	tokenAddr := tc.MakeToken("TestToken", "TEST", "10000")
+
+	am := amount.MustParseAmount("1")
+	if _, err := tc.SendTx(util.AdminKey, tokenAddr, "ForwardedTransfer", util.Users[0], util.Users[1], am); err == nil {
+		t.Fatal("not trusted forwarder transfers")
+	}
+	if _, err := tc.SendTx(util.UserKeys[0], tokenAddr, "SetTrustedForwarder", util.Users[0], true); err == nil {
+		t.Fatal("not master sets the forwarder")
+	}
+	if _, err := tc.SendTx(util.AdminKey, tokenAddr, "SetTrustedForwarder", util.Admin, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tc.SendTx(util.AdminKey, tokenAddr, "ForwardedTransfer", util.Admin, util.Users[1], am); err != nil {
+		t.Fatal(err)
+	}
+	is, err := tc.ReadTx(util.AdminKey, tokenAddr, "BalanceOf", util.Users[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if is[0].(*amount.Amount).Cmp(am.Int) != 0 {
+		t.Errorf("balance %v, want %v", is[0], am)
+	}
+}
@@ -0,0 +1,114 @@
+package test
+
+import (
+	"testing"
+
+	etypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/core/chain"
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/meverselabs/meverse/extern/test/util"
+	"github.com/meverselabs/meverse/service/bloomservice"
+)
+
+func TestTokenTransferLogs(t *testing.T) {
+	chain.SetVersion(1, types.NativeLogVersion)
+	defer chain.SetVersion(1, 2)
+
+	tc := util.NewTestContext()
+	tokenAddr := tc.MakeToken("TestToken", "TEST", "10000")
+
+	provider := tc.Cn.Provider()
+	am := amount.MustParseAmount("1")
+	if _, err := tc.SendTx(util.AdminKey, tokenAddr, "Transfer", util.Users[0], am); err != nil {
+		t.Fatal(err)
+	}
+	b, err := provider.Block(provider.Height())
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt, err := bloomservice.NativeReceipt(tc.Cn, b, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt == nil {
+		t.Fatal("receipt is not exist")
+	}
+
+	var transfer *etypes.Log
+	for _, l := range receipt.Logs {
+		if l.Address == tokenAddr {
+			transfer = l
+		}
+	}
+	if transfer == nil {
+		t.Fatalf("transfer log is not exist %v", receipt.Logs)
+	}
+	if len(transfer.Topics) != 3 || transfer.Topics[0] != types.TransferEventTopic ||
+		transfer.Topics[1] != types.AddressTopic(util.Admin) || transfer.Topics[2] != types.AddressTopic(util.Users[0]) {
+		t.Errorf("invalid transfer topics %v", transfer.Topics)
+	}
+	if string(transfer.Data) != string(types.Uint256Data(am.Int)) {
+		t.Errorf("invalid transfer data %x", transfer.Data)
+	}
+	if transfer.TxHash != b.Body.Transactions[0].Hash(b.Header.Height) {
+		t.Errorf("invalid transfer tx hash %v", transfer.TxHash)
+	}
+
+	bloom, logs, err := bloomservice.TxLogsBloom(tc.Cn, b, 0, receipt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	for _, l := range logs {
+		if l.Address == tokenAddr && l.Topics[0] == types.TransferEventTopic {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("transfer logs count %v, want 1", count)
+	}
+	if !bloom.Test(tokenAddr[:]) || !bloom.Test(types.TransferEventTopic[:]) || !bloom.Test(types.AddressTopic(util.Users[0]).Bytes()) {
+		t.Error("transfer log is not in the bloom")
+	}
+
+	if _, err := tc.SendTx(util.AdminKey, tokenAddr, "Approve", util.Users[1], am); err != nil {
+		t.Fatal(err)
+	}
+	b, err = provider.Block(provider.Height())
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt, err = bloomservice.NativeReceipt(tc.Cn, b, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	has := false
+	for _, l := range receipt.Logs {
+		if l.Address == tokenAddr && l.Topics[0] == types.ApprovalEventTopic {
+			has = l.Topics[1] == types.AddressTopic(util.Admin) && l.Topics[2] == types.AddressTopic(util.Users[1])
+		}
+	}
+	if !has {
+		t.Errorf("approval log is not exist %v", receipt.Logs)
+	}
+}
+
+func TestTokenLogsBeforeVersion(t *testing.T) {
+	tc := util.NewTestContext()
+	tokenAddr := tc.MakeToken("TestToken", "TEST", "10000")
+
+	if _, err := tc.SendTx(util.AdminKey, tokenAddr, "Transfer", util.Users[0], amount.MustParseAmount("1")); err != nil {
+		t.Fatal(err)
+	}
+	provider := tc.Cn.Provider()
+	receipts, err := provider.Receipts(provider.Height())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range receipts {
+		if len(r.Logs) > 0 {
+			t.Errorf("logs are written before the version %v", r.Logs)
+		}
+	}
+}
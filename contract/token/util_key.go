@@ -24,8 +24,15 @@ var (
 	tagVersion          = byte(0x16)
 	tagDelegateInfo     = byte(0x17)
 	tagTokenManager     = byte(0x18)
+	tagPermitNonce      = byte(0x19)
+	tagTrustedForwarder = byte(0x1A)
 )
 
+// AdditiveData returns the contract data which are changed only by adding amounts
+func AdditiveData() [][]byte {
+	return [][]byte{{tagTokenTotalSupply}}
+}
+
 func MakeAllowanceTokenKey(sender common.Address) []byte {
 	return makeTokenKey(sender, tagTokenApprove)
 }
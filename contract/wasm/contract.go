@@ -0,0 +1,131 @@
+package wasm
+
+import (
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/meverselabs/meverse/core/wasmvm"
+)
+
+// exported functions of the wasm contract module
+// init is optional and called once with the deploy arguments
+const (
+	FuncInit   = "init"
+	FuncInvoke = "invoke"
+)
+
+// WasmContract runs the code which is deployed by the wasm deploy transaction on the wasm vm
+type WasmContract struct {
+	addr   common.Address
+	master common.Address
+}
+
+func (cont *WasmContract) Name() string {
+	return "WasmContract"
+}
+
+func (cont *WasmContract) Address() common.Address {
+	return cont.addr
+}
+
+func (cont *WasmContract) Master() common.Address {
+	return cont.master
+}
+
+func (cont *WasmContract) Init(addr common.Address, master common.Address) {
+	cont.addr = addr
+	cont.master = master
+}
+
+// OnCreate validates and stores the code which is given as the Args
+func (cont *WasmContract) OnCreate(cc *types.ContractContext, Args []byte) error {
+	vm := wasmvm.DefaultVM()
+	h, err := vm.Compile(Args)
+	if err != nil {
+		return err
+	}
+	if !vm.HasFunction(Args, FuncInvoke) {
+		return wasmvm.ErrNotExistFunction
+	}
+	cc.SetContractData([]byte{tagCode}, Args)
+	cc.SetContractData([]byte{tagCodeHash}, h[:])
+	return nil
+}
+
+func (cont *WasmContract) OnReward(cc *types.ContractContext, b *types.Block, CountMap map[common.Address]uint32) (map[common.Address]*amount.Amount, error) {
+	return nil, nil
+}
+
+//////////////////////////////////////////////////
+// Public Writer Functions
+//////////////////////////////////////////////////
+
+// InitContract calls the init function of the code once by the deployer
+func (cont *WasmContract) InitContract(cc *types.ContractContext, params []interface{}) error {
+	if cc.From() != cont.master {
+		return ErrNotMaster
+	}
+	if len(cc.ContractData([]byte{tagInitialized})) > 0 {
+		return ErrAlreadyInitialized
+	}
+	cc.SetContractData([]byte{tagInitialized}, []byte{1})
+
+	code := cont.Code(cc)
+	if !wasmvm.DefaultVM().HasFunction(code, FuncInit) {
+		return nil
+	}
+	args, err := wasmvm.MarshalValues(params)
+	if err != nil {
+		return err
+	}
+	_, err = cont.call(cc, code, FuncInit, args)
+	return err
+}
+
+func (cont *WasmContract) ContractInvoke(cc *types.ContractContext, method string, params []interface{}) (interface{}, error) {
+	args, err := wasmvm.MarshalValues(params)
+	if err != nil {
+		return nil, err
+	}
+	bs, err := cont.call(cc, cont.Code(cc), FuncInvoke, []byte(method), args)
+	if err != nil {
+		return nil, err
+	}
+	if len(bs) == 0 {
+		return []interface{}{}, nil
+	}
+	return wasmvm.UnmarshalValue(bs)
+}
+
+func (cont *WasmContract) call(cc *types.ContractContext, code []byte, name string, params ...[]byte) ([]byte, error) {
+	h := &contractHost{
+		addr: cont.addr,
+		cc:   cc,
+	}
+	// the calls of the transaction use the left gas of it
+	meter := cc.WasmMeter()
+	bs, used, err := wasmvm.DefaultVM().Call(h, code, name, meter, params...)
+	// the used gas includes the nested calls, so only the outer call charges it even when the call fails
+	if meter.Depth() == 0 {
+		cc.UseGas(used)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return bs, nil
+}
+
+//////////////////////////////////////////////////
+// Public Reader Functions
+//////////////////////////////////////////////////
+
+func (cont *WasmContract) Code(cc types.ContractLoader) []byte {
+	return cc.ContractData([]byte{tagCode})
+}
+
+func (cont *WasmContract) CodeHash(cc types.ContractLoader) hash.Hash256 {
+	var h hash.Hash256
+	copy(h[:], cc.ContractData([]byte{tagCodeHash}))
+	return h
+}
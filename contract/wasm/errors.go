@@ -0,0 +1,9 @@
+package wasm
+
+import "errors"
+
+// wasm contract errors
+var (
+	ErrAlreadyInitialized = errors.New("already initialized")
+	ErrNotMaster          = errors.New("not master")
+)
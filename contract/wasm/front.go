@@ -0,0 +1,27 @@
+package wasm
+
+import (
+	"github.com/meverselabs/meverse/core/types"
+)
+
+func (cont *WasmContract) Front() interface{} {
+	return &front{
+		cont: cont,
+	}
+}
+
+type front struct {
+	cont *WasmContract
+}
+
+//////////////////////////////////////////////////
+// Public Writer Functions
+//////////////////////////////////////////////////
+
+func (f *front) InitContract(cc *types.ContractContext, params []interface{}) error {
+	return f.cont.InitContract(cc, params)
+}
+
+func (f *front) ContractInvoke(cc *types.ContractContext, method string, params []interface{}) (interface{}, error) {
+	return f.cont.ContractInvoke(cc, method, params)
+}
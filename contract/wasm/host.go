@@ -0,0 +1,52 @@
+package wasm
+
+import (
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/core/types"
+)
+
+// contractHost connects the contract context to the host functions of the wasm vm
+// the keys of the guest module are prefixed so that they can not overwrite the code of the contract
+type contractHost struct {
+	addr common.Address
+	cc   *types.ContractContext
+}
+
+func (h *contractHost) ContractAddress() common.Address {
+	return h.addr
+}
+
+func (h *contractHost) From() common.Address {
+	return h.cc.From()
+}
+
+func (h *contractHost) TargetHeight() uint32 {
+	return h.cc.TargetHeight()
+}
+
+func (h *contractHost) LastTimestamp() uint64 {
+	return h.cc.LastTimestamp()
+}
+
+func (h *contractHost) ContractData(name []byte) []byte {
+	return h.cc.ContractData(makeDataKey(name))
+}
+
+func (h *contractHost) SetContractData(name []byte, value []byte) {
+	h.cc.SetContractData(makeDataKey(name), value)
+}
+
+func (h *contractHost) AccountData(addr common.Address, name []byte) []byte {
+	return h.cc.AccountData(addr, makeDataKey(name))
+}
+
+func (h *contractHost) SetAccountData(addr common.Address, name []byte, value []byte) {
+	h.cc.SetAccountData(addr, makeDataKey(name), value)
+}
+
+func (h *contractHost) Exec(addr common.Address, method string, args []interface{}) ([]interface{}, error) {
+	if h.cc.Exec == nil {
+		return nil, types.ErrNotExistContract
+	}
+	return h.cc.Exec(h.cc, addr, method, args)
+}
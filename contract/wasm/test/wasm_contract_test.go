@@ -0,0 +1,154 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/contract/wasm"
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/meverselabs/meverse/core/wasmvm"
+	"github.com/meverselabs/meverse/extern/test/util"
+)
+
+func TestWasmContractData(t *testing.T) {
+	tc := util.NewTestContext()
+
+	contAddr, err := tc.DeployWasmContract(util.AdminKey, util.WasmStoreEngin())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tc.SendTx(util.AdminKey, contAddr, "set", "hello"); err != nil {
+		t.Fatal(err)
+	}
+	inf, err := tc.ReadTx(util.UserKeys[0], contAddr, "get")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ls, ok := inf[0].([]interface{})
+	if !ok || len(ls) != 1 || ls[0] != "hello" {
+		t.Errorf("stored data is not matched %v", inf)
+	}
+	if _, err := tc.SendTx(util.AdminKey, contAddr, "InitContract", []interface{}{}); err == nil {
+		t.Error("contract should not be initialized twice")
+	}
+}
+
+func TestWasmContractExec(t *testing.T) {
+	tc := util.NewTestContext()
+	mainToken := *tc.Ctx.MainToken()
+
+	contAddr, err := tc.DeployWasmContract(util.AdminKey, util.WasmExecModule())
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := map[string]interface{}{
+		"to":     mainToken.String(),
+		"method": "balanceOf",
+		"args":   []interface{}{util.Admin.String()},
+	}
+	inf, err := tc.ReadTx(util.AdminKey, contAddr, "balance", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ls, ok := inf[0].([]interface{})
+	if !ok || len(ls) != 1 {
+		t.Fatalf("exec result is invalid %v", inf)
+	}
+	bal, err := tc.ReadTx(util.AdminKey, mainToken, "BalanceOf", util.Admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ls[0] != bal[0].(*amount.Amount).Int.String() {
+		t.Errorf("balance is not matched %v, %v", ls[0], bal[0])
+	}
+}
+
+func TestWasmDeployInvalidCode(t *testing.T) {
+	tc := util.NewTestContext()
+
+	if _, err := tc.DeployWasmContract(util.AdminKey, []byte("not wasm")); err == nil {
+		t.Error("invalid code should not be deployed")
+	}
+}
+
+// spinModule returns the contract module which loops forever
+func spinModule() []byte {
+	body := []byte{0x03, 0x40, 0x0c, 0x00, 0x0b} // loop, br 0, end
+	body = append(body, util.WasmI64Const(0)...)
+	m := &util.WasmModule{
+		Globals: []int32{1024},
+		Funcs: []util.WasmFunc{
+			util.WasmBumpAlloc(),
+			{Export: "invoke", Params: []byte{util.WasmI32, util.WasmI32, util.WasmI32, util.WasmI32}, Results: []byte{util.WasmI64}, Body: body},
+		},
+	}
+	return m.Bytes()
+}
+
+func TestWasmContractInfiniteLoop(t *testing.T) {
+	tc := util.NewTestContext()
+
+	contAddr, err := tc.DeployWasmContract(util.AdminKey, spinModule())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the loop is stopped by the gas limit, not by the time, so every node fails it in the same way
+	for i := 0; i < 3; i++ {
+		_, err := tc.SendTx(util.AdminKey, contAddr, "spin")
+		if err == nil || !strings.Contains(err.Error(), wasmvm.ErrOutOfGas.Error()) {
+			t.Fatalf("out of gas expected got %v", err)
+		}
+	}
+	if _, err := tc.ReadTx(util.AdminKey, contAddr, "spin"); err == nil || !strings.Contains(err.Error(), wasmvm.ErrOutOfGas.Error()) {
+		t.Fatalf("out of gas expected got %v", err)
+	}
+}
+
+func TestWasmCallGas(t *testing.T) {
+	tc := util.NewTestContext()
+
+	spinAddr, err := tc.DeployWasmContract(util.AdminKey, spinModule())
+	if err != nil {
+		t.Fatal(err)
+	}
+	storeAddr, err := tc.DeployWasmContract(util.AdminKey, util.WasmStoreEngin())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := types.NewContext(tc.Cn.Store())
+	invoke := func(addr common.Address, method string) error {
+		cont, err := ctx.Contract(addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = cont.(*wasm.WasmContract).ContractInvoke(ctx.ContractContext(cont, util.Admin), method, []interface{}{"hello"})
+		return err
+	}
+
+	// the failed call is charged by the used gas
+	sn := ctx.Snapshot()
+	size := ctx.GetPCSize()
+	if err := invoke(spinAddr, "spin"); err == nil || !strings.Contains(err.Error(), wasmvm.ErrOutOfGas.Error()) {
+		t.Fatalf("out of gas expected got %v", err)
+	}
+	if used := ctx.GetPCSize() - size; used < wasmvm.DefaultGasLimit {
+		t.Fatalf("failed call is charged %v", used)
+	}
+
+	// the next call of the transaction has no gas left
+	if err := invoke(storeAddr, "set"); err == nil || !strings.Contains(err.Error(), wasmvm.ErrOutOfGas.Error()) {
+		t.Fatalf("out of gas expected got %v", err)
+	}
+	ctx.Commit(sn)
+
+	// the gas limit is given again to the next transaction
+	sn = ctx.Snapshot()
+	if err := invoke(storeAddr, "set"); err != nil {
+		t.Fatal(err)
+	}
+	ctx.Commit(sn)
+}
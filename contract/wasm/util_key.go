@@ -0,0 +1,12 @@
+package wasm
+
+var (
+	tagCode        = byte(0x01)
+	tagCodeHash    = byte(0x02)
+	tagInitialized = byte(0x03)
+	tagData        = byte(0x10)
+)
+
+func makeDataKey(name []byte) []byte {
+	return append([]byte{tagData}, name...)
+}
@@ -5,6 +5,8 @@ const (
 	AdminRemove            = "Admin.Remove"
 	GeneratorAdd           = "Generator.Add"
 	GeneratorRemove        = "Generator.Remove"
+	ObserverAdd            = "Observer.Add"
+	ObserverRemove         = "Observer.Remove"
 	ContractDeploy         = "Contract.Deploy"
 	TransactionSetBasicFee = "Transaction.SetBasicFee"
 )
@@ -14,6 +16,8 @@ func IsAdminMethod(method string) bool {
 		method == AdminRemove ||
 		method == GeneratorAdd ||
 		method == GeneratorRemove ||
+		method == ObserverAdd ||
+		method == ObserverRemove ||
 		method == ContractDeploy ||
 		method == TransactionSetBasicFee {
 		return true
@@ -0,0 +1,158 @@
+package chain
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/common/vrf"
+	"github.com/meverselabs/meverse/core/ctypes"
+	"github.com/meverselabs/meverse/core/types"
+)
+
+// BeaconObserverKeys returns the observers which make the beacon of the height in the order of the public key
+//
+// they are the majority of the observers which comes first in the order, so the value of the beacon is decided by the height
+// and the generator cannot choose it by the subset of the proofs
+func (cn *Chain) BeaconObserverKeys(Height uint32) ([]common.PublicKey, error) {
+	KeyMap, err := cn.ObserverKeyMap(Height)
+	if err != nil {
+		return nil, err
+	}
+	if len(KeyMap) == 0 {
+		return nil, errors.WithStack(ErrInvalidBeacon)
+	}
+	keys := make([]common.PublicKey, 0, len(KeyMap))
+	for pubkey := range KeyMap {
+		keys = append(keys, pubkey)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i][:], keys[j][:]) < 0
+	})
+	return keys[:len(keys)/2+1], nil
+}
+
+// VerifyBeacon checks the vrf outputs of the beacon by the beacon observer keys of its height and returns the beacon value
+//
+// the outputs of all beacon observers are required in the order of the public key
+func (cn *Chain) VerifyBeacon(bn *types.Beacon) (hash.Hash256, error) {
+	keys, err := cn.BeaconObserverKeys(bn.Height)
+	if err != nil {
+		return hash.Hash256{}, err
+	}
+	if len(bn.Proofs) < len(keys) {
+		return hash.Hash256{}, errors.WithStack(ErrInsufficientBeaconProofs)
+	}
+	if len(bn.Proofs) != len(keys) {
+		return hash.Hash256{}, errors.WithStack(ErrInvalidBeacon)
+	}
+	ChainID := cn.store.ChainID()
+	seed := types.BeaconSeed(ChainID, bn.Height)
+	outputs := make([]hash.Hash256, 0, len(bn.Proofs))
+	for i, bp := range bn.Proofs {
+		if bp.PublicKey != keys[i] {
+			return hash.Hash256{}, errors.WithStack(ErrInvalidBeacon)
+		}
+		output, err := vrf.Verify(bp.PublicKey, seed, bp.Proof)
+		if err != nil {
+			return hash.Hash256{}, err
+		}
+		outputs = append(outputs, output)
+	}
+	return bn.Value(ChainID, outputs), nil
+}
+
+// BlockBeacon returns the beacon which is included in the block, it is nil when the block has no beacon
+func BlockBeacon(b *types.Block) (*types.Beacon, error) {
+	var bn *types.Beacon
+	for _, e := range b.Body.Events {
+		if e.Type != ctypes.EventTagBeacon {
+			continue
+		}
+		if bn != nil {
+			return nil, errors.WithStack(ErrInvalidBeacon)
+		}
+		bn = &types.Beacon{}
+		if _, err := bn.ReadFrom(bytes.NewReader(e.Result)); err != nil {
+			return nil, err
+		}
+	}
+	return bn, nil
+}
+
+// applyBeacon validates the beacon of the block by the header and updates the latest beacon of the context
+func (cn *Chain) applyBeacon(b *types.Block, ctx *types.Context) error {
+	bn, err := BlockBeacon(b)
+	if err != nil {
+		return err
+	}
+	if bn == nil {
+		if isBeaconRequired(ctx) {
+			return errors.WithStack(ErrMissingBeacon)
+		}
+		if b.Header.Beacon != (hash.Hash256{}) {
+			return errors.WithStack(ErrInvalidBeacon)
+		}
+		return nil
+	}
+	if b.Header.Version < types.BeaconVersion || !isNextBeacon(ctx, bn) {
+		return errors.WithStack(ErrInvalidBeacon)
+	}
+	Value, err := cn.VerifyBeacon(bn)
+	if err != nil {
+		return err
+	}
+	if Value != b.Header.Beacon {
+		return errors.WithStack(ErrInvalidBeacon)
+	}
+	ctx.SetBeacon(bn.Height, Value)
+	return nil
+}
+
+// isBeaconRequired returns the block of the target height should include the beacon of the previous height
+//
+// the observers make the vrf outputs from the block of the BeaconVersion, so the beacon is required from the next block of it
+func isBeaconRequired(ctx *types.Context) bool {
+	TargetHeight := ctx.TargetHeight()
+	return TargetHeight > 1 && ctx.Version(TargetHeight-1) >= types.BeaconVersion
+}
+
+// isNextBeacon returns the beacon is of the previous height
+//
+// the beacon of every height is included in the next block, so the generator cannot choose the beacon by skipping one
+func isNextBeacon(ctx *types.Context, bn *types.Beacon) bool {
+	return isBeaconRequired(ctx) && bn.Height == ctx.TargetHeight()-1
+}
+
+// SetBeacon includes the beacon of the observers to the block, it should be called before the transactions are added
+func (bc *BlockCreator) SetBeacon(bn *types.Beacon) error {
+	if bc.b.Header.Version < types.BeaconVersion || bc.beacon != nil || len(bc.b.Body.Transactions) > 0 {
+		return errors.WithStack(ErrInvalidBeacon)
+	}
+	if !isNextBeacon(bc.ctx, bn) {
+		return errors.WithStack(ErrInvalidBeacon)
+	}
+	Value, err := bc.cn.VerifyBeacon(bn)
+	if err != nil {
+		return err
+	}
+	bc.b.Header.Beacon = Value
+	bc.beacon = bn
+	bc.ctx.SetBeacon(bn.Height, Value)
+	return nil
+}
+
+func (bc *BlockCreator) beaconEvent() (*ctypes.Event, error) {
+	bs, _, err := bin.WriterToBytes(bc.beacon)
+	if err != nil {
+		return nil, err
+	}
+	return &ctypes.Event{
+		Type:   ctypes.EventTagBeacon,
+		Result: bs,
+	}, nil
+}
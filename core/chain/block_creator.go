@@ -22,6 +22,7 @@ type BlockCreator struct {
 	ctx      *types.Context
 	txHashes []hash.Hash256
 	b        *types.Block
+	beacon   *types.Beacon
 }
 
 // NewBlockCreator returns a BlockCreator
@@ -53,17 +54,38 @@ func NewBlockCreator(cn *Chain, ctx *types.Context, Generator common.Address, Ti
 
 // AddTx validates, executes and adds transactions
 func (bc *BlockCreator) AddTx(tx *types.Transaction, sig common.Signature) (*etypes.Receipt, error) {
-	pubkey, err := common.RecoverPubkey(tx.ChainID, tx.Message(), sig)
+	signer, err := bc.cn.verifier.Recover(tx, sig)
 	if err != nil {
 		return nil, err
 	}
-	tx.From = pubkey.Address()
+	tx.From = signer
 	TxHash := tx.HashSig()
 	return bc.UnsafeAddTx(TxHash, tx, sig, tx.From)
 }
 
 // UnsafeAddTx adds transactions without signer validation if signers is not empty
 func (bc *BlockCreator) UnsafeAddTx(TxHash hash.Hash256, tx *types.Transaction, sig common.Signature, signer common.Address) (receipt *etypes.Receipt, err error) {
+	return bc.unsafeAddTx(nil, 0, TxHash, tx, sig, signer)
+}
+
+// UnsafeAddTxs adds the transactions in the order like UnsafeAddTx and returns the receipt or the error of each transaction
+//
+// the contract calls are executed speculatively by the execute workers of the chain and applied when the state read by them is not changed,
+// the context of the creator should read the store directly to be speculated, otherwise the transactions are executed sequentially
+func (bc *BlockCreator) UnsafeAddTxs(TxHashes []hash.Hash256, txs []*types.Transaction, sigs []common.Signature, signers []common.Address) ([]*etypes.Receipt, []error) {
+	TXID := types.TransactionID(bc.b.Header.Height, uint16(len(bc.b.Body.Transactions)))
+	sp := newSpeculator(bc.cn.executeWorkers, bc.ctx, txs, signers, TXID)
+	defer sp.stop()
+
+	receipts := make([]*etypes.Receipt, len(txs))
+	errs := make([]error, len(txs))
+	for i, tx := range txs {
+		receipts[i], errs[i] = bc.unsafeAddTx(sp, i, TxHashes[i], tx, sigs[i], signers[i])
+	}
+	return receipts, errs
+}
+
+func (bc *BlockCreator) unsafeAddTx(sp *speculator, idx int, TxHash hash.Hash256, tx *types.Transaction, sig common.Signature, signer common.Address) (receipt *etypes.Receipt, err error) {
 	currentSlot := types.ToTimeSlot(bc.b.Header.Timestamp)
 	slot := types.ToTimeSlot(tx.Timestamp)
 
@@ -74,6 +96,7 @@ func (bc *BlockCreator) UnsafeAddTx(TxHash hash.Hash256, tx *types.Transaction,
 	}
 
 	sn := bc.ctx.Snapshot()
+	ls := bc.ctx.LogSize()
 	var ens []*ctypes.Event
 	defer func() {
 		if err != nil {
@@ -97,7 +120,12 @@ func (bc *BlockCreator) UnsafeAddTx(TxHash hash.Hash256, tx *types.Transaction,
 
 	types.CheckABI(bc.b, bc.cn.NewContext())
 
-	if tx.VmType != types.Evm {
+	if tx.VmType == types.Wasm && tx.To == common.ZeroAddr {
+		receipt = new(etypes.Receipt)
+		if ens, err = ExecuteWasmDeployTxWithEvent(bc.ctx, tx, signer, TXID); err != nil {
+			return nil, err
+		}
+	} else if tx.VmType != types.Evm {
 		receipt = new(etypes.Receipt)
 		if tx.To == common.ZeroAddr {
 			if !bc.ctx.IsAdmin(signer) {
@@ -107,7 +135,7 @@ func (bc *BlockCreator) UnsafeAddTx(TxHash hash.Hash256, tx *types.Transaction,
 				return nil, err
 			}
 		} else {
-			if ens, err = ExecuteContractTxWithEvent(bc.ctx, tx, signer, TXID); err != nil {
+			if ens, err = sp.executeContractTxWithEvent(idx, bc.ctx, tx, signer, TXID); err != nil {
 				return nil, err
 			}
 		}
@@ -116,6 +144,7 @@ func (bc *BlockCreator) UnsafeAddTx(TxHash hash.Hash256, tx *types.Transaction,
 			return nil, err
 		}
 	}
+	receipt.Logs = append(receipt.Logs, bc.ctx.TakeLogs(ls)...)
 
 	return receipt, nil
 }
@@ -234,41 +263,12 @@ func _execContractWithOutSeq(ctx *types.Context, tx *types.Transaction, signer c
 	var result []interface{}
 	var intr types.IInteractor
 	if ctx.IsContract(to) {
-		cont, err := ctx.Contract(to)
-		if err != nil {
-			return nil, nil, err
-		}
-		if _, ok := cont.(types.InvokeableContract); !ok {
-			tx.Method = strings.ToUpper(string(method[0])) + method[1:]
-		}
-		cc := ctx.ContractContext(cont, signer)
-		intr = types.NewInteractor(ctx, cont, cc, TXID, true)
-		cc.Exec = intr.Exec
-
-		/** Correction due to a mainnet bug  */
-		if ctx.ChainID().Int64() == 7518 && ctx.TargetHeight() == 68260278 {
-			intr.Exec(cc, to, "abis", []interface{}{})
-		}
-
-		is, err := intr.Exec(cc, to, method, data)
-		intr.Distroy()
-
+		var txMethod string
+		intr, result, txMethod, err = execContractCall(ctx, tx, signer, TXID, to, method, data)
 		if err != nil {
 			return nil, nil, err
 		}
-
-		for _, i := range is {
-			if i != nil {
-				if reflect.TypeOf(i).Kind() == reflect.Slice {
-					s := reflect.ValueOf(i)
-					if s.Len() > 0 {
-						result = append(result, i)
-					}
-				} else {
-					result = append(result, i)
-				}
-			}
-		}
+		tx.Method = txMethod
 
 		gh := intr.GasHistory()
 		if fee, err := ChargeFee(ctx, gh[0], signer); err != nil {
@@ -302,8 +302,62 @@ func _execContractWithOutSeq(ctx *types.Context, tx *types.Transaction, signer c
 	return intr, result, nil
 }
 
+// execContractCall calls the method of the native contract without the fee
+//
+// it returns the method name which should be recorded to the transaction by the caller
+func execContractCall(ctx *types.Context, tx *types.Transaction, signer common.Address, TXID string, to common.Address, method string, data []interface{}) (types.IInteractor, []interface{}, string, error) {
+	cont, err := ctx.Contract(to)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	txMethod := tx.Method
+	if _, ok := cont.(types.InvokeableContract); !ok {
+		txMethod = strings.ToUpper(string(method[0])) + method[1:]
+	}
+	cc := ctx.ContractContext(cont, signer)
+	intr := types.NewInteractor(ctx, cont, cc, TXID, true)
+	cc.Exec = intr.Exec
+
+	/** Correction due to a mainnet bug  */
+	if ctx.ChainID().Int64() == 7518 && ctx.TargetHeight() == 68260278 {
+		intr.Exec(cc, to, "abis", []interface{}{})
+	}
+
+	is, err := intr.Exec(cc, to, method, data)
+	intr.Distroy()
+
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	var result []interface{}
+	for _, i := range is {
+		if i != nil {
+			if reflect.TypeOf(i).Kind() == reflect.Slice {
+				s := reflect.ValueOf(i)
+				if s.Len() > 0 {
+					result = append(result, i)
+				}
+			} else {
+				result = append(result, i)
+			}
+		}
+	}
+	return intr, result, txMethod, nil
+}
+
 // Finalize generates block that has transactions adds by AddTx
 func (bc *BlockCreator) Finalize(gasLv uint16, receipts types.Receipts) (*types.Block, error) {
+	if bc.beacon == nil && isBeaconRequired(bc.ctx) {
+		return nil, errors.WithStack(ErrMissingBeacon)
+	}
+	if bc.beacon != nil {
+		e, err := bc.beaconEvent()
+		if err != nil {
+			return nil, err
+		}
+		bc.b.Body.Events = append(bc.b.Body.Events, e)
+	}
 	if bc.b.Header.Height%prefix.RewardIntervalBlocks == 0 {
 		if rewardMap, err := bc.ctx.ProcessReward(bc.ctx, bc.b); err != nil {
 			return nil, err
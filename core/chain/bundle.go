@@ -0,0 +1,241 @@
+package chain
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"time"
+
+	etypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/core/ctypes"
+	"github.com/meverselabs/meverse/core/types"
+)
+
+// MaxBundleTransactions is the limit of the transactions of a bundle
+const MaxBundleTransactions = 64
+
+// Bundle is an ordered set of transactions which is included atomically and in order, or not at all
+//
+// the zero bounds are not checked
+type Bundle struct {
+	Transactions          []*types.Transaction
+	TransactionSignatures []common.Signature
+	MinHeight             uint32
+	MaxHeight             uint32
+	MinTimestamp          uint64
+	MaxTimestamp          uint64
+}
+
+// Hash returns the hash of the bundle
+func (bd *Bundle) Hash() hash.Hash256 {
+	var buffer bytes.Buffer
+	for i, tx := range bd.Transactions {
+		h := tx.HashSig()
+		buffer.Write(h[:])
+		buffer.Write(bd.TransactionSignatures[i])
+	}
+	buffer.Write(bin.Uint32Bytes(bd.MinHeight))
+	buffer.Write(bin.Uint32Bytes(bd.MaxHeight))
+	buffer.Write(bin.Uint64Bytes(bd.MinTimestamp))
+	buffer.Write(bin.Uint64Bytes(bd.MaxTimestamp))
+	return hash.Hash(buffer.Bytes())
+}
+
+// Validate checks the form of the bundle
+func (bd *Bundle) Validate() error {
+	if len(bd.Transactions) == 0 || len(bd.Transactions) > MaxBundleTransactions {
+		return errors.WithStack(ErrInvalidBundleSize)
+	}
+	if len(bd.Transactions) != len(bd.TransactionSignatures) {
+		return errors.WithStack(ErrInvalidSignatureCount)
+	}
+	if bd.MaxHeight > 0 && bd.MinHeight > bd.MaxHeight {
+		return errors.WithStack(ErrInvalidBundleBound)
+	}
+	if bd.MaxTimestamp > 0 && bd.MinTimestamp > bd.MaxTimestamp {
+		return errors.WithStack(ErrInvalidBundleBound)
+	}
+	return nil
+}
+
+// IsTarget returns the bundle can be included in the block of the height and the timestamp
+func (bd *Bundle) IsTarget(height uint32, timestamp uint64) bool {
+	if height < bd.MinHeight || (bd.MaxHeight > 0 && height > bd.MaxHeight) {
+		return false
+	}
+	if timestamp < bd.MinTimestamp || (bd.MaxTimestamp > 0 && timestamp > bd.MaxTimestamp) {
+		return false
+	}
+	return true
+}
+
+// IsExpired returns the bundle cannot be included after the block of the height
+func (bd *Bundle) IsExpired(height uint32, timestamp uint64) bool {
+	return (bd.MaxHeight > 0 && height >= bd.MaxHeight) || (bd.MaxTimestamp > 0 && timestamp >= bd.MaxTimestamp)
+}
+
+// WriteTo is a serialization function
+func (bd *Bundle) WriteTo(w io.Writer) (int64, error) {
+	sw := bin.NewSumWriter()
+	if sum, err := sw.Uint16(w, uint16(len(bd.Transactions))); err != nil {
+		return sum, err
+	}
+	for i, tx := range bd.Transactions {
+		if sum, err := sw.WriterTo(w, tx); err != nil {
+			return sum, err
+		}
+		if sum, err := sw.Signature(w, bd.TransactionSignatures[i]); err != nil {
+			return sum, err
+		}
+	}
+	if sum, err := sw.Uint32(w, bd.MinHeight); err != nil {
+		return sum, err
+	}
+	if sum, err := sw.Uint32(w, bd.MaxHeight); err != nil {
+		return sum, err
+	}
+	if sum, err := sw.Uint64(w, bd.MinTimestamp); err != nil {
+		return sum, err
+	}
+	if sum, err := sw.Uint64(w, bd.MaxTimestamp); err != nil {
+		return sum, err
+	}
+	return sw.Sum(), nil
+}
+
+// ReadFrom is a deserialization function
+func (bd *Bundle) ReadFrom(r io.Reader) (int64, error) {
+	sr := bin.NewSumReader()
+	var Len uint16
+	if sum, err := sr.Uint16(r, &Len); err != nil {
+		return sum, err
+	}
+	if Len > MaxBundleTransactions {
+		return sr.Sum(), errors.WithStack(ErrInvalidBundleSize)
+	}
+	bd.Transactions = make([]*types.Transaction, 0, Len)
+	bd.TransactionSignatures = make([]common.Signature, 0, Len)
+	for i := 0; i < int(Len); i++ {
+		tx := &types.Transaction{}
+		if sum, err := sr.ReaderFrom(r, tx); err != nil {
+			return sum, err
+		}
+		var sig common.Signature
+		if sum, err := sr.Signature(r, &sig); err != nil {
+			return sum, err
+		}
+		bd.Transactions = append(bd.Transactions, tx)
+		bd.TransactionSignatures = append(bd.TransactionSignatures, sig)
+	}
+	if sum, err := sr.Uint32(r, &bd.MinHeight); err != nil {
+		return sum, err
+	}
+	if sum, err := sr.Uint32(r, &bd.MaxHeight); err != nil {
+		return sum, err
+	}
+	if sum, err := sr.Uint64(r, &bd.MinTimestamp); err != nil {
+		return sum, err
+	}
+	if sum, err := sr.Uint64(r, &bd.MaxTimestamp); err != nil {
+		return sum, err
+	}
+	return sr.Sum(), nil
+}
+
+// BundleError is the error of the transaction which fails the bundle
+type BundleError struct {
+	Index int
+	Err   error
+}
+
+func (e *BundleError) Error() string {
+	return "bundle transaction " + strconv.Itoa(e.Index) + ": " + e.Err.Error()
+}
+
+// Cause returns the error of the transaction
+func (e *BundleError) Cause() error {
+	return e.Err
+}
+
+// AddBundle executes and adds the transactions of the bundle in order, the block is not changed when any transaction fails
+//
+// the signers of the transactions should be validated before
+func (bc *BlockCreator) AddBundle(bd *Bundle, signers []common.Address) (types.Receipts, error) {
+	if !bd.IsTarget(bc.b.Header.Height, bc.b.Header.Timestamp) {
+		return nil, errors.WithStack(ErrInvalidBundleBound)
+	}
+	txLen := len(bc.b.Body.Transactions)
+	eventLen := len(bc.b.Body.Events)
+	hashLen := len(bc.txHashes)
+
+	sn := bc.ctx.Snapshot()
+	receipts := types.Receipts{}
+	for i, tx := range bd.Transactions {
+		receipt, err := bc.UnsafeAddTx(tx.HashSig(), tx, bd.TransactionSignatures[i], signers[i])
+		if err != nil {
+			bc.ctx.Revert(sn)
+			bc.b.Body.Transactions = bc.b.Body.Transactions[:txLen]
+			bc.b.Body.TransactionSignatures = bc.b.Body.TransactionSignatures[:txLen]
+			bc.b.Body.Events = bc.b.Body.Events[:eventLen]
+			bc.txHashes = bc.txHashes[:hashLen]
+			return nil, &BundleError{Index: i, Err: err}
+		}
+		receipts = append(receipts, receipt)
+	}
+	bc.ctx.Commit(sn)
+	return receipts, nil
+}
+
+// BundleResult is the result of the bundle simulation
+type BundleResult struct {
+	Height   uint32
+	TxHashes []hash.Hash256
+	Receipts []*etypes.Receipt
+	Events   []*ctypes.Event
+}
+
+// CallBundle simulates the bundle on the next block of the chain without the storing, 0 timestamp means the current time
+func (cn *Chain) CallBundle(bd *Bundle, Timestamp uint64) (*BundleResult, error) {
+	if err := bd.Validate(); err != nil {
+		return nil, err
+	}
+	ctx := cn.NewContext()
+	signers := make([]common.Address, len(bd.Transactions))
+	for i, tx := range bd.Transactions {
+		tx.VmType, tx.Method = types.GetTxType(ctx, tx)
+		signer, err := cn.verifier.Recover(tx, bd.TransactionSignatures[i])
+		if err != nil {
+			return nil, &BundleError{Index: i, Err: err}
+		}
+		tx.From = signer
+		signers[i] = signer
+	}
+
+	if Timestamp == 0 {
+		Timestamp = uint64(time.Now().UnixNano())
+	}
+	if Timestamp <= ctx.LastTimestamp() {
+		Timestamp = ctx.LastTimestamp() + 1
+	}
+	Generator, err := cn.TopGenerator(0)
+	if err != nil {
+		return nil, err
+	}
+	bc := NewBlockCreator(cn, ctx, Generator, 0, Timestamp, 0)
+	receipts, err := bc.AddBundle(bd, signers)
+	if err != nil {
+		return nil, err
+	}
+	result := &BundleResult{
+		Height:   bc.b.Header.Height,
+		TxHashes: bc.txHashes[1:],
+		Receipts: receipts,
+		Events:   bc.b.Body.Events,
+	}
+	return result, nil
+}
@@ -3,6 +3,7 @@ package chain
 import (
 	"bytes"
 	"log"
+	"math/big"
 	"runtime"
 	"sync"
 	"time"
@@ -28,43 +29,52 @@ import (
 // Chain manages the chain data
 type Chain struct {
 	sync.Mutex
-	isInit         bool
-	store          *Store
-	services       []types.Service
-	serviceMap     map[string]types.Service
-	observerKeyMap map[common.PublicKey]bool
-	closeLock      sync.RWMutex
-	waitChan       map[uuid.UUID]*common.SyncChan
-	waitLock       sync.Mutex
-	isClose        bool
-	tag            string
-	dumpStorage    string
-	CallRootDump   func()
+	isInit       bool
+	store        *Store
+	services     []types.Service
+	serviceMap   map[string]types.Service
+	observerKeys []common.PublicKey
+	closeLock    sync.RWMutex
+	waitChan     map[uuid.UUID]*common.SyncChan
+	waitLock     sync.Mutex
+	isClose      bool
+	tag          string
+	dumpStorage  string
+	CallRootDump func()
+
+	executeWorkers int
+	verifier       *TxVerifier
+
+	observerLock        sync.Mutex
+	observerCache       []*ObserverSet
+	observerCacheHeight uint32
 }
 
 // NewChain returns a Chain
 func NewChain(ObserverKeys []common.PublicKey, store *Store, tag string) *Chain {
-	ObserverKeyMap := map[common.PublicKey]bool{}
-	for _, v := range ObserverKeys {
-		ObserverKeyMap[v] = true
-	}
 	cn := &Chain{
-		store:          store,
-		observerKeyMap: ObserverKeyMap,
-		services:       []types.Service{},
-		serviceMap:     map[string]types.Service{},
-		waitChan:       map[uuid.UUID]*common.SyncChan{},
-		tag:            tag,
+		store:        store,
+		observerKeys: ObserverKeys,
+		services:     []types.Service{},
+		serviceMap:   map[string]types.Service{},
+		waitChan:     map[uuid.UUID]*common.SyncChan{},
+		tag:          tag,
+		verifier:     NewTxVerifier(store.ChainID(), 0, 0),
 	}
 	return cn
 }
 
+// GenesisHash returns the hash of the genesis of the chain
+func GenesisHash(ChainID *big.Int, genesisContextData *types.ContextData) hash.Hash256 {
+	return hash.Hashes(hash.Hash(ChainID.Bytes()), genesisContextData.Hash())
+}
+
 // Init initializes the chain
 func (cn *Chain) Init(genesisContextData *types.ContextData) error {
 	cn.Lock()
 	defer cn.Unlock()
 
-	GenesisHash := hash.Hashes(hash.Hash(cn.store.ChainID().Bytes()), genesisContextData.Hash())
+	GenesisHash := GenesisHash(cn.store.ChainID(), genesisContextData)
 	Height := cn.store.Height()
 	if Height > 0 {
 		if h, err := cn.store.Hash(0); err != nil {
@@ -155,6 +165,7 @@ func (cn *Chain) Close() {
 
 	if !cn.isClose {
 		cn.store.Close()
+		cn.verifier.Close()
 		cn.isClose = true
 	}
 }
@@ -207,6 +218,11 @@ func (cn *Chain) Provider() types.Provider {
 	return cn.store
 }
 
+// TxVerifier returns the verifier which validates the transactions before the pool and the block execution
+func (cn *Chain) TxVerifier() *TxVerifier {
+	return cn.verifier
+}
+
 // Store returns the store of the chain
 func (cn *Chain) Store() *Store {
 	return cn.store
@@ -287,7 +303,11 @@ func (cn *Chain) ValidateSignature(bh *types.Header, sigs []common.Signature) er
 		return errors.WithStack(ErrInvalidTopAddress)
 	}
 
-	if len(sigs) != len(cn.observerKeyMap)/2+2 {
+	KeyMap, err := cn.ObserverKeyMap(bh.Height)
+	if err != nil {
+		return err
+	}
+	if len(sigs) != len(KeyMap)/2+2 {
 		return errors.WithStack(ErrInvalidSignatureCount)
 	}
 
@@ -304,10 +324,6 @@ func (cn *Chain) ValidateSignature(bh *types.Header, sigs []common.Signature) er
 		return errors.WithStack(ErrInvalidTopSignature)
 	}
 
-	KeyMap := map[common.PublicKey]bool{}
-	for pubkey := range cn.observerKeyMap {
-		KeyMap[pubkey] = true
-	}
 	bs := types.BlockSign{
 		HeaderHash:         h,
 		GeneratorSignature: sigs[0],
@@ -379,6 +395,13 @@ func (cn *Chain) executeBlockOnContext(b *types.Block, ctx *types.Context, sm ma
 
 	types.CheckABI(b, cn.NewContext())
 
+	if err := cn.applyBeacon(b, ctx); err != nil {
+		return nil, err
+	}
+
+	sp := newSpeculator(cn.executeWorkers, ctx, b.Body.Transactions, TxSigners, types.TransactionID(b.Header.Height, uint16(len(b.Body.Transactions))))
+	defer sp.stop()
+
 	// Execute Transctions
 	currentSlot := types.ToTimeSlot(b.Header.Timestamp)
 	receipts := types.Receipts{}
@@ -391,12 +414,21 @@ func (cn *Chain) executeBlockOnContext(b *types.Block, ctx *types.Context, sm ma
 		}
 
 		sn := ctx.Snapshot()
+		ls := ctx.LogSize()
 		if err := ctx.UseTimeSlot(slot, string(TxHashes[i][:])); err != nil {
 			ctx.Revert(sn)
 			return nil, err
 		}
 		TXID := types.TransactionID(b.Header.Height, uint16(len(b.Body.Transactions)))
-		if tx.VmType != types.Evm {
+		if tx.VmType == types.Wasm && tx.To == common.ZeroAddr {
+			if err := ExecuteWasmDeployTx(ctx, tx, TxSigners[i], TXID); err != nil {
+				ctx.Revert(sn)
+				return nil, err
+			}
+			receipt := new(etypes.Receipt)
+			receipt.Logs = ctx.TakeLogs(ls)
+			receipts = append(receipts, receipt)
+		} else if tx.VmType != types.Evm {
 			if tx.To == common.ZeroAddr {
 				if !ctx.IsAdmin(TxSigners[i]) {
 					ctx.Revert(sn)
@@ -407,18 +439,20 @@ func (cn *Chain) executeBlockOnContext(b *types.Block, ctx *types.Context, sm ma
 					return nil, err
 				}
 			} else {
-				if err := ExecuteContractTx(ctx, tx, TxSigners[i], TXID); err != nil {
+				if err := sp.executeContractTx(i, ctx, tx, TxSigners[i], TXID); err != nil {
 					ctx.Revert(sn)
 					return nil, err
 				}
 			}
 			receipt := new(etypes.Receipt)
+			receipt.Logs = ctx.TakeLogs(ls)
 			receipts = append(receipts, receipt)
 		} else {
 			if _, receipt, err := cn.ApplyEvmTransaction(ctx, tx, uint16(i), TxSigners[i]); err != nil {
 				ctx.Revert(sn)
 				return nil, err
 			} else {
+				receipt.Logs = append(receipt.Logs, ctx.TakeLogs(ls)...)
 				receipts = append(receipts, receipt)
 			}
 		}
@@ -518,12 +552,12 @@ func (cn *Chain) validateTransactionSignatures(b *types.Block, SigMap map[hash.H
 					}
 					if !hasSigner {
 						sig := b.Body.TransactionSignatures[sidx+q]
-						pubkey, err := common.RecoverPubkey(tx.ChainID, tx.Message(), sig)
+						signer, err := cn.verifier.Recover(tx, sig)
 						if err != nil {
 							errs <- err
 							return
 						}
-						TxSigners[sidx+q] = pubkey.Address()
+						TxSigners[sidx+q] = signer
 					}
 				}
 			}(i*txUnit, b.Body.Transactions[i*txUnit:lastCnt])
@@ -555,6 +589,10 @@ func (cn *Chain) ExecuteTransaction(ctx *types.Context, tx *types.Transaction, T
 		return nil, ctx.SetGenerator(common.BytesToAddress(tx.Args), true)
 	case admin.GeneratorRemove:
 		return nil, ctx.SetGenerator(common.BytesToAddress(tx.Args), false)
+	case admin.ObserverAdd:
+		return nil, cn.changeObserver(ctx, tx.Args, true)
+	case admin.ObserverRemove:
+		return nil, cn.changeObserver(ctx, tx.Args, false)
 	case admin.ContractDeploy:
 		data := &DeployContractData{}
 		if _, err := data.ReadFrom(bytes.NewReader(tx.Args)); err != nil {
@@ -119,6 +119,10 @@ func (cn *Chain) UpdateExecuteBlockOnContext(b *types.Block, ctx *types.Context,
 
 	types.CheckABI(b, cn.NewContext())
 
+	if err := cn.applyBeacon(b, ctx); err != nil {
+		return nil, err
+	}
+
 	// Execute Transctions
 	currentSlot := types.ToTimeSlot(b.Header.Timestamp)
 	receipts := types.Receipts{}
@@ -131,12 +135,21 @@ func (cn *Chain) UpdateExecuteBlockOnContext(b *types.Block, ctx *types.Context,
 		}
 
 		sn := ctx.Snapshot()
+		ls := ctx.LogSize()
 		if err := ctx.UseTimeSlot(slot, string(TxHashes[i][:])); err != nil {
 			ctx.Revert(sn)
 			return nil, err
 		}
 		TXID := types.TransactionID(b.Header.Height, uint16(len(b.Body.Transactions)))
-		if tx.VmType != types.Evm {
+		if tx.VmType == types.Wasm && tx.To == common.ZeroAddr {
+			if err := ExecuteWasmDeployTx(ctx, tx, TxSigners[i], TXID); err != nil {
+				ctx.Revert(sn)
+				return nil, err
+			}
+			receipt := new(etypes.Receipt)
+			receipt.Logs = ctx.TakeLogs(ls)
+			receipts = append(receipts, receipt)
+		} else if tx.VmType != types.Evm {
 			if tx.To == common.ZeroAddr {
 				if !ctx.IsAdmin(TxSigners[i]) {
 					ctx.Revert(sn)
@@ -153,12 +166,14 @@ func (cn *Chain) UpdateExecuteBlockOnContext(b *types.Block, ctx *types.Context,
 				}
 			}
 			receipt := new(etypes.Receipt)
+			receipt.Logs = ctx.TakeLogs(ls)
 			receipts = append(receipts, receipt)
 		} else {
 			if _, receipt, err := cn.ApplyEvmTransaction(ctx, tx, uint16(i), TxSigners[i]); err != nil {
 				ctx.Revert(sn)
 				return nil, err
 			} else {
+				receipt.Logs = append(receipt.Logs, ctx.TakeLogs(ls)...)
 				receipts = append(receipts, receipt)
 			}
 		}
@@ -38,4 +38,16 @@ var (
 	ErrFoundForkedBlock           = errors.New("found forked block")
 	ErrInvalidBasicFee            = errors.New("invalid basic fee")
 	ErrNotExistContract           = errors.New("not exist contract")
+	ErrInvalidWasmDeploy          = errors.New("invalid wasm deploy")
+	ErrInvalidObserverArgs        = errors.New("invalid observer args")
+	ErrInvalidObserverHeight      = errors.New("invalid observer height")
+	ErrExistObserver              = errors.New("exist observer")
+	ErrNotExistObserver           = errors.New("not exist observer")
+	ErrEmptyObserverSet           = errors.New("empty observer set")
+	ErrTooLargeTransaction        = errors.New("too large transaction")
+	ErrInvalidBundleSize          = errors.New("invalid bundle size")
+	ErrInvalidBundleBound         = errors.New("invalid bundle bound")
+	ErrInvalidBeacon              = errors.New("invalid beacon")
+	ErrInsufficientBeaconProofs   = errors.New("insufficient beacon proofs")
+	ErrMissingBeacon              = errors.New("missing beacon")
 )
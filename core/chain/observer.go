@@ -0,0 +1,252 @@
+package chain
+
+import (
+	"bytes"
+	"io"
+	"sort"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/pkg/errors"
+)
+
+// observerDataAddress is the reserved address which keeps the observer schedule in the context data
+var observerDataAddress = common.BytesToAddress([]byte("meverse.observer"))
+
+var tagObserverSchedule = []byte("schedule")
+
+// ObserverChangeDelay is the min count of the blocks between the change transaction and the height of the change
+// so the nodes can reconfigure the observers before the new set signs the blocks
+const ObserverChangeDelay = uint32(30)
+
+type observerLoader interface {
+	Data(cont common.Address, addr common.Address, name []byte) []byte
+}
+
+// ObserverSet is the observer keys which are effective from the height
+type ObserverSet struct {
+	Height     uint32
+	PublicKeys []common.PublicKey
+}
+
+func (s *ObserverSet) WriteTo(w io.Writer) (int64, error) {
+	sw := bin.NewSumWriter()
+	if sum, err := sw.Uint32(w, s.Height); err != nil {
+		return sum, err
+	}
+	if sum, err := sw.Uint16(w, uint16(len(s.PublicKeys))); err != nil {
+		return sum, err
+	}
+	for _, pubkey := range s.PublicKeys {
+		if sum, err := sw.PublicKey(w, pubkey); err != nil {
+			return sum, err
+		}
+	}
+	return sw.Sum(), nil
+}
+
+func (s *ObserverSet) ReadFrom(r io.Reader) (int64, error) {
+	sr := bin.NewSumReader()
+	if sum, err := sr.Uint32(r, &s.Height); err != nil {
+		return sum, err
+	}
+	Len, sum, err := sr.GetUint16(r)
+	if err != nil {
+		return sum, err
+	}
+	s.PublicKeys = make([]common.PublicKey, Len)
+	for i := range s.PublicKeys {
+		if sum, err := sr.PublicKey(r, &s.PublicKeys[i]); err != nil {
+			return sum, err
+		}
+	}
+	return sr.Sum(), nil
+}
+
+// KeyMap returns the observer keys as the map
+func (s *ObserverSet) KeyMap() map[common.PublicKey]bool {
+	KeyMap := map[common.PublicKey]bool{}
+	for _, pubkey := range s.PublicKeys {
+		KeyMap[pubkey] = true
+	}
+	return KeyMap
+}
+
+// ObserverSchedule returns the observer sets changed by the admin, ordered by the height
+func ObserverSchedule(loader observerLoader) ([]*ObserverSet, error) {
+	bs := loader.Data(observerDataAddress, common.ZeroAddr, tagObserverSchedule)
+	if len(bs) == 0 {
+		return nil, nil
+	}
+	r := bytes.NewReader(bs)
+	Len, _, err := bin.ReadUint16(r)
+	if err != nil {
+		return nil, err
+	}
+	sets := make([]*ObserverSet, Len)
+	for i := range sets {
+		s := &ObserverSet{}
+		if _, err := s.ReadFrom(r); err != nil {
+			return nil, err
+		}
+		sets[i] = s
+	}
+	return sets, nil
+}
+
+func setObserverSchedule(ctx *types.Context, sets []*ObserverSet) error {
+	var buffer bytes.Buffer
+	sw := bin.NewSumWriter()
+	if _, err := sw.Uint16(&buffer, uint16(len(sets))); err != nil {
+		return err
+	}
+	for _, s := range sets {
+		if _, err := s.WriteTo(&buffer); err != nil {
+			return err
+		}
+	}
+	ctx.SetData(observerDataAddress, common.ZeroAddr, tagObserverSchedule, buffer.Bytes())
+	return nil
+}
+
+// observerKeysAt returns the observer keys of the height, the base keys are used before the first change
+func observerKeysAt(sets []*ObserverSet, base []common.PublicKey, height uint32) []common.PublicKey {
+	keys := base
+	for _, s := range sets {
+		if s.Height > height {
+			break
+		}
+		keys = s.PublicKeys
+	}
+	return keys
+}
+
+// ObserverKeys returns the observer keys of the height
+// the broken schedule is returned as the error, it doesn't fall back to the base keys which can be already replaced
+func (cn *Chain) ObserverKeys(height uint32) ([]common.PublicKey, error) {
+	sets, err := cn.observerSchedule()
+	if err != nil {
+		return nil, err
+	}
+	return observerKeysAt(sets, cn.observerKeys, height), nil
+}
+
+// ObserverKeyMap returns the observer keys of the height as the map
+func (cn *Chain) ObserverKeyMap(height uint32) (map[common.PublicKey]bool, error) {
+	keys, err := cn.ObserverKeys(height)
+	if err != nil {
+		return nil, err
+	}
+	s := &ObserverSet{PublicKeys: keys}
+	return s.KeyMap(), nil
+}
+
+// IsObserverKey returns the key is the observer at the height or is scheduled to be the observer
+func (cn *Chain) IsObserverKey(pubkey common.PublicKey, height uint32) bool {
+	sets, err := cn.observerSchedule()
+	if err != nil {
+		return false
+	}
+	for _, pk := range observerKeysAt(sets, cn.observerKeys, height) {
+		if pk == pubkey {
+			return true
+		}
+	}
+	for _, s := range sets {
+		if s.Height > height && s.KeyMap()[pubkey] {
+			return true
+		}
+	}
+	return false
+}
+
+func (cn *Chain) observerSchedule() ([]*ObserverSet, error) {
+	cn.observerLock.Lock()
+	defer cn.observerLock.Unlock()
+
+	height := cn.store.Height()
+	if cn.observerCache != nil && cn.observerCacheHeight == height {
+		return cn.observerCache, nil
+	}
+	sets, err := ObserverSchedule(cn.store)
+	if err != nil {
+		return nil, err
+	}
+	if sets == nil {
+		sets = []*ObserverSet{}
+	}
+	cn.observerCache = sets
+	cn.observerCacheHeight = height
+	return sets, nil
+}
+
+// ObserverChangeArgs returns the arguments of the Observer.Add and Observer.Remove transaction
+// the height should be ObserverChangeDelay blocks after the block of the transaction at least
+func ObserverChangeArgs(pubkey common.PublicKey, height uint32) []byte {
+	return bin.TypeWriteAll(pubkey, height)
+}
+
+func (cn *Chain) changeObserver(ctx *types.Context, Args []byte, add bool) error {
+	iss, err := bin.TypeReadAll(Args, 2)
+	if err != nil {
+		return err
+	}
+	pubkey, ok := iss[0].(common.PublicKey)
+	if !ok {
+		return errors.WithStack(ErrInvalidObserverArgs)
+	}
+	height, ok := iss[1].(uint32)
+	if !ok {
+		return errors.WithStack(ErrInvalidObserverArgs)
+	}
+	if height < ctx.TargetHeight()+ObserverChangeDelay {
+		return errors.WithStack(ErrInvalidObserverHeight)
+	}
+
+	sets, err := ObserverSchedule(ctx)
+	if err != nil {
+		return err
+	}
+	target := &ObserverSet{
+		Height:     height,
+		PublicKeys: observerKeysAt(sets, cn.observerKeys, height),
+	}
+	idx := sort.Search(len(sets), func(i int) bool { return sets[i].Height >= height })
+	if idx < len(sets) && sets[idx].Height == height {
+		sets = append(sets[:idx], sets[idx+1:]...)
+	}
+	sets = append(sets[:idx], append([]*ObserverSet{target}, sets[idx:]...)...)
+
+	// the change is applied to the sets which are scheduled after the height too
+	for _, s := range sets[idx:] {
+		KeyMap := s.KeyMap()
+		if add {
+			if KeyMap[pubkey] {
+				if s == target {
+					return errors.WithStack(ErrExistObserver)
+				}
+				continue
+			}
+			s.PublicKeys = append(append([]common.PublicKey{}, s.PublicKeys...), pubkey)
+		} else {
+			if !KeyMap[pubkey] {
+				if s == target {
+					return errors.WithStack(ErrNotExistObserver)
+				}
+				continue
+			}
+			keys := []common.PublicKey{}
+			for _, k := range s.PublicKeys {
+				if k != pubkey {
+					keys = append(keys, k)
+				}
+			}
+			if len(keys) == 0 {
+				return errors.WithStack(ErrEmptyObserverSet)
+			}
+			s.PublicKeys = keys
+		}
+	}
+	return setObserverSchedule(ctx, sets)
+}
@@ -0,0 +1,210 @@
+package chain
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/core/ctypes"
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/meverselabs/meverse/extern/txparser"
+	"github.com/pkg/errors"
+)
+
+// speculation is a contract transaction executed on a speculative context before its turn
+type speculation struct {
+	ctx    *types.Context
+	intr   types.IInteractor
+	result []interface{}
+	method string
+	err    error
+	done   chan struct{}
+}
+
+// speculator executes the contract transactions of the block speculatively by the workers
+//
+// the speculations are applied in the order of the block by ApplySpeculation and the fee is charged on the block context,
+// a transaction is executed again on the block context when the state read by the speculation is changed
+type speculator struct {
+	specs   []*speculation
+	stopped int32
+	wg      sync.WaitGroup
+}
+
+// SetExecuteWorkers sets the count of the workers which execute the transactions of a block speculatively, 1 or less means the sequential execution
+func (cn *Chain) SetExecuteWorkers(n int) {
+	cn.executeWorkers = n
+}
+
+// ExecuteWorkers returns the count of the workers which execute the transactions of a block speculatively
+func (cn *Chain) ExecuteWorkers() int {
+	return cn.executeWorkers
+}
+
+// isSpeculatable returns the transaction is a native contract call which can be speculated
+func isSpeculatable(tx *types.Transaction) bool {
+	if tx.VmType == types.Evm || tx.To == common.ZeroAddr {
+		return false
+	}
+	if tx.IsEtherType {
+		// the call data is parsed by the abis which are added while the execution
+		etx, _, err := txparser.EthTxFromRLP(tx.Args)
+		if err != nil || len(etx.Data()) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// newSpeculator starts the speculations of the transactions on the ctx, it returns nil when they should be executed sequentially
+func newSpeculator(workers int, ctx *types.Context, txs []*types.Transaction, TxSigners []common.Address, TXID string) *speculator {
+	if workers < 2 || !ctx.Speculatable() {
+		return nil
+	}
+	sp := &speculator{
+		specs: make([]*speculation, len(txs)),
+	}
+	idxCh := make(chan int, len(txs))
+	for i, tx := range txs {
+		if isSpeculatable(tx) {
+			sp.specs[i] = &speculation{done: make(chan struct{})}
+			idxCh <- i
+		}
+	}
+	close(idxCh)
+	if len(idxCh) < 2 {
+		return nil
+	}
+	for i := 0; i < workers; i++ {
+		sp.wg.Add(1)
+		go func() {
+			defer sp.wg.Done()
+			for idx := range idxCh {
+				s := sp.specs[idx]
+				if atomic.LoadInt32(&sp.stopped) == 0 {
+					s.err = speculateContractTx(s, ctx, txs[idx], TxSigners[idx], TXID)
+				} else {
+					s.err = errors.WithStack(types.ErrSpeculationAborted)
+				}
+				close(s.done)
+			}
+		}()
+	}
+	return sp
+}
+
+// wait returns the finished speculation of the transaction or nil
+func (sp *speculator) wait(i int) *speculation {
+	if sp == nil || sp.specs[i] == nil {
+		return nil
+	}
+	s := sp.specs[i]
+	<-s.done
+	if s.err != nil {
+		return nil
+	}
+	return s
+}
+
+// stop skips the remained speculations and waits the workers
+func (sp *speculator) stop() {
+	if sp == nil {
+		return
+	}
+	atomic.StoreInt32(&sp.stopped, 1)
+	sp.wg.Wait()
+}
+
+// speculateContractTx executes the contract call of the transaction without the fee on a speculative context
+//
+// the transaction is not changed by the worker, the method name is recorded to it when the speculation is applied
+func speculateContractTx(s *speculation, ctx *types.Context, tx *types.Transaction, signer common.Address, TXID string) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			s.ctx, err = nil, errors.Errorf("speculation panic: %v", v)
+		}
+	}()
+
+	sctx := ctx.SpeculativeContext()
+	sctx.Snapshot()
+	if tx.UseSeq {
+		if seq := sctx.AddrSeq(signer); seq != tx.Seq {
+			return errors.Errorf("invalid signer sequence siger %v seq %v, got %v", signer, seq, tx.Seq)
+		}
+		sctx.AddAddrSeq(signer)
+	}
+	to, method, data, err := types.TxArg(sctx, tx)
+	if err != nil {
+		return err
+	}
+	if !sctx.IsContract(to) {
+		return errors.WithStack(types.ErrSpeculationAborted)
+	}
+	intr, result, txMethod, err := execContractCall(sctx, tx, signer, TXID, to, method, data)
+	if err != nil {
+		return err
+	}
+	s.ctx, s.intr, s.result, s.method = sctx, intr, result, txMethod
+	return nil
+}
+
+// apply applies the speculation of the transaction to the ctx and charges the fee, it returns false when the transaction should be executed again
+func (sp *speculator) apply(i int, ctx *types.Context, tx *types.Transaction, signer common.Address) (*speculation, *amount.Amount, bool, error) {
+	s := sp.wait(i)
+	if s == nil {
+		return nil, nil, false, nil
+	}
+	types.ExecLock.Lock()
+	defer types.ExecLock.Unlock()
+
+	if !ctx.ApplySpeculation(s.ctx) {
+		return nil, nil, false, nil
+	}
+	fee, err := ChargeFee(ctx, s.intr.GasHistory()[0], signer)
+	if err != nil {
+		return nil, nil, true, err
+	}
+	tx.Method = s.method
+	return s, fee, true, nil
+}
+
+// executeContractTx executes the contract transaction by applying the speculation or on the ctx
+func (sp *speculator) executeContractTx(i int, ctx *types.Context, tx *types.Transaction, signer common.Address, TXID string) error {
+	if _, _, applied, err := sp.apply(i, ctx, tx, signer); applied {
+		return err
+	}
+	return ExecuteContractTx(ctx, tx, signer, TXID)
+}
+
+// executeContractTxWithEvent executes the contract transaction like ExecuteContractTxWithEvent by applying the speculation or on the ctx
+//
+// the events of the speculation are moved to the index of the TXID because it is not known while the speculation
+func (sp *speculator) executeContractTxWithEvent(i int, ctx *types.Context, tx *types.Transaction, signer common.Address, TXID string) ([]*ctypes.Event, error) {
+	s, fee, applied, err := sp.apply(i, ctx, tx, signer)
+	if !applied {
+		return ExecuteContractTxWithEvent(ctx, tx, signer, TXID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	_, idx, err := types.ParseTransactionID(TXID)
+	if err != nil {
+		return nil, err
+	}
+	s.intr.AddEvent(&ctypes.Event{
+		Index:  idx,
+		Type:   ctypes.EventTagTxFee,
+		Result: bin.TypeWriteAll(fee),
+	})
+	var ens []*ctypes.Event
+	if len(s.result) > 0 {
+		ens = append(ens, ctypes.NewEvent(idx, ctypes.EventTagTxMsg, bin.TypeWriteAll(s.result...)))
+	}
+	for _, en := range s.intr.EventList() {
+		en.Index = idx
+		ens = append(ens, en)
+	}
+	return ens, nil
+}
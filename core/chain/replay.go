@@ -0,0 +1,330 @@
+package chain
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/core/keydb"
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/pkg/errors"
+)
+
+// KeyDiff is a state key which has different values, a nil value means that the key is not exist
+type KeyDiff struct {
+	Key    string
+	Before []byte
+	After  []byte
+}
+
+func (d *KeyDiff) String() string {
+	return fmt.Sprintf("%v before %v after %v", d.Key, diffValueString(d.Before), diffValueString(d.After))
+}
+
+func diffValueString(v []byte) string {
+	if v == nil {
+		return "<none>"
+	}
+	return "0x" + hex.EncodeToString(v)
+}
+
+// DivergenceError is returned when the replayed block does not derive the hash of the header
+//
+// Keys are the state keys changed by the block, the values before the block and the values derived by the replay
+type DivergenceError struct {
+	Height  uint32
+	Field   string
+	Header  hash.Hash256
+	Derived hash.Hash256
+	Keys    []*KeyDiff
+}
+
+func (e *DivergenceError) Error() string {
+	return fmt.Sprintf("%v diverged at %v: header %v, derived %v", e.Field, e.Height, e.Header.String(), e.Derived.String())
+}
+
+// Unwrap returns ErrInvalidContextHash or ErrInvalidReceiptHash
+func (e *DivergenceError) Unwrap() error {
+	if e.Field == "ReceiptHash" {
+		return ErrInvalidReceiptHash
+	}
+	return ErrInvalidContextHash
+}
+
+// ReplayBlock executes the stored block again and connects it like ConnectBlock
+//
+// it returns a DivergenceError instead of the panic when the derived hashes are not matched with the header,
+// services are not called because the replayed chain is used to audit the data only
+func (cn *Chain) ReplayBlock(b *types.Block) error {
+	cn.closeLock.RLock()
+	defer cn.closeLock.RUnlock()
+	if cn.isClose {
+		return errors.WithStack(ErrChainClosed)
+	}
+
+	cn.Lock()
+	defer cn.Unlock()
+
+	if err := cn.validateHeader(&b.Header); err != nil {
+		return err
+	}
+	if err := cn.ValidateSignature(&b.Header, b.Body.BlockSignatures); err != nil {
+		return err
+	}
+	// the signers are recovered before the execution lock, so the replays of other chains can recover in parallel
+	TxSigners, TxHashes, err := cn.validateTransactionSignatures(b, nil)
+	if err != nil {
+		return err
+	}
+	SigMap := map[hash.Hash256]common.Address{}
+	for i, h := range TxHashes {
+		SigMap[h] = TxSigners[i]
+	}
+
+	ctx := types.NewContext(cn.store)
+	receipts, err := cn.executeBlockOnContext(b, ctx, SigMap)
+	if err != nil {
+		return err
+	}
+	if h := ctx.Hash(); h != b.Header.ContextHash {
+		Keys, err := cn.store.diffContextData(ctx.Top())
+		if err != nil {
+			return err
+		}
+		return &DivergenceError{
+			Height:  b.Header.Height,
+			Field:   "ContextHash",
+			Header:  b.Header.ContextHash,
+			Derived: h,
+			Keys:    Keys,
+		}
+	}
+	if cn.store.Version(b.Header.Height) > 1 {
+		if h := bin.MustWriterToHash(&receipts); h != b.Header.ReceiptHash {
+			return &DivergenceError{
+				Height:  b.Header.Height,
+				Field:   "ReceiptHash",
+				Header:  b.Header.ReceiptHash,
+				Derived: h,
+			}
+		}
+	}
+	if ctx.StackSize() > 1 {
+		return errors.WithStack(types.ErrDirtyContext)
+	}
+	return cn.store.StoreBlock(b, ctx, receipts)
+}
+
+// diffContextData returns the keys changed by the context data with the stored values
+func (st *Store) diffContextData(ctd *types.ContextData) ([]*KeyDiff, error) {
+	after := map[string][]byte{}
+	for addr, is := range ctd.AdminMap {
+		if is {
+			after[string(toAdminKey(addr))] = []byte{1}
+		}
+	}
+	for addr := range ctd.DeletedAdminMap {
+		after[string(toAdminKey(addr))] = nil
+	}
+	for addr, seq := range ctd.AddrSeqMap {
+		bs := make([]byte, 8)
+		binary.LittleEndian.PutUint64(bs, seq)
+		after[string(toAddressSeqKey(addr))] = bs
+	}
+	for addr, is := range ctd.GeneratorMap {
+		if is {
+			after[string(toGeneratorKey(addr))] = []byte{1}
+		}
+	}
+	for addr := range ctd.DeletedGeneratorMap {
+		after[string(toGeneratorKey(addr))] = nil
+	}
+	for addr, cd := range ctd.ContractDefineMap {
+		bs, _, err := bin.WriterToBytes(cd)
+		if err != nil {
+			return nil, err
+		}
+		after[string(toContractKey(addr))] = bs
+	}
+	for key, value := range ctd.DataMap {
+		after[string(toDataKey(key))] = value
+	}
+	for key := range ctd.DeletedDataMap {
+		after[string(toDataKey(key))] = nil
+	}
+	if addr := ctd.UnsafeGetMainToken(); addr != nil {
+		after[string([]byte{tagMainToken})] = (*addr)[:]
+	}
+	if fee := ctd.UnsafeBasicFee(); fee != nil {
+		after[string([]byte{tagBasicFee})] = fee.Bytes()
+	}
+
+	diffs := []*KeyDiff{}
+	if err := st.db.View(func(txn *keydb.Tx) error {
+		for k, v := range after {
+			var before []byte
+			value, err := txn.Get([]byte(k))
+			if k == string([]byte{tagBasicFee}) {
+				// the default fee is not stored
+				before, err = _basicFee(txn).Bytes(), nil
+			} else if err != nil {
+				if errors.Cause(err) != keydb.ErrNotFound {
+					return err
+				}
+			} else if before, err = storeValueBytes(value); err != nil {
+				return err
+			}
+			if (before == nil) == (v == nil) && bytes.Equal(before, v) {
+				continue
+			}
+			diffs = append(diffs, &KeyDiff{Key: k, Before: before, After: v})
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return sortKeyDiffs(diffs), nil
+}
+
+// DiffStore compares the states of the stores and returns the different keys up to the limit (0 means no limit)
+//
+// Before is the value of a and After is the value of b, the height informations are not compared
+func DiffStore(a *Store, b *Store, limit int) ([]*KeyDiff, error) {
+	am, err := a.stateBytes()
+	if err != nil {
+		return nil, err
+	}
+	bm, err := b.stateBytes()
+	if err != nil {
+		return nil, err
+	}
+	diffs := []*KeyDiff{}
+	for k, av := range am {
+		if bv, has := bm[k]; !has || !bytes.Equal(av, bv) {
+			diffs = append(diffs, &KeyDiff{Key: k, Before: av, After: bv})
+		}
+	}
+	for k, bv := range bm {
+		if _, has := am[k]; !has {
+			diffs = append(diffs, &KeyDiff{Key: k, After: bv})
+		}
+	}
+	diffs = sortKeyDiffs(diffs)
+	if limit > 0 && len(diffs) > limit {
+		diffs = diffs[:limit]
+	}
+	return diffs, nil
+}
+
+func (st *Store) stateBytes() (map[string][]byte, error) {
+	st.closeLock.RLock()
+	defer st.closeLock.RUnlock()
+	if st.isClose {
+		return nil, errors.WithStack(ErrStoreClosed)
+	}
+
+	m := map[string][]byte{}
+	if err := st.db.View(func(txn *keydb.Tx) error {
+		return txn.Iterate(nil, func(key []byte, value interface{}) error {
+			switch key[0] {
+			case tagHeight, tagHeightHash:
+				return nil
+			}
+			bs, err := storeValueBytes(value)
+			if err != nil {
+				return err
+			}
+			m[string(key)] = bs
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// storeValueBytes returns the stored form of the unmarshaled value of the keydb
+func storeValueBytes(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	case bool:
+		if v {
+			return []byte{1}, nil
+		}
+		return []byte{0}, nil
+	case uint32:
+		return bin.Uint32Bytes(v), nil
+	case uint64:
+		bs := make([]byte, 8)
+		binary.LittleEndian.PutUint64(bs, v)
+		return bs, nil
+	case common.Address:
+		return v[:], nil
+	case hash.Hash256:
+		return v[:], nil
+	case io.WriterTo:
+		bs, _, err := bin.WriterToBytes(v)
+		return bs, err
+	default:
+		return nil, errors.Errorf("unknown store value %T", value)
+	}
+}
+
+func sortKeyDiffs(diffs []*KeyDiff) []*KeyDiff {
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Key < diffs[j].Key
+	})
+	for _, d := range diffs {
+		d.Key = describeKey([]byte(d.Key))
+	}
+	return diffs
+}
+
+// describeKey returns the readable form of the keydb key
+func describeKey(key []byte) string {
+	if len(key) == 0 {
+		return ""
+	}
+	var addr common.Address
+	switch key[0] {
+	case tagPoFRankTable:
+		return "ranktable"
+	case tagMainToken:
+		return "maintoken"
+	case tagBasicFee:
+		return "basicfee"
+	case tagAdmin, tagAddressSeq, tagGenerator, tagContract, tagBlockGen:
+		if len(key) != 1+common.AddressLength {
+			break
+		}
+		copy(addr[:], key[1:])
+		switch key[0] {
+		case tagAdmin:
+			return "admin/" + addr.String()
+		case tagAddressSeq:
+			return "seq/" + addr.String()
+		case tagGenerator:
+			return "generator/" + addr.String()
+		case tagContract:
+			return "contract/" + addr.String()
+		default:
+			return "blockgen/" + addr.String()
+		}
+	case tagData:
+		if len(key) < 1+common.AddressLength*2 {
+			break
+		}
+		var user common.Address
+		copy(addr[:], key[1:])
+		copy(user[:], key[1+common.AddressLength:])
+		return "data/" + addr.String() + "/" + user.String() + "/0x" + hex.EncodeToString(key[1+common.AddressLength*2:])
+	}
+	return "0x" + hex.EncodeToString(key)
+}
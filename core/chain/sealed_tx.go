@@ -0,0 +1,38 @@
+package chain
+
+import (
+	"bytes"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/common/threshold"
+	"github.com/meverselabs/meverse/core/types"
+)
+
+// SealTransaction encrypts the signed transaction by the fair ordering key of the observers
+func SealTransaction(pk *threshold.PublicKey, tx *types.Transaction, sig common.Signature) (*threshold.Ciphertext, error) {
+	var buffer bytes.Buffer
+	sw := bin.NewSumWriter()
+	if _, err := sw.WriterTo(&buffer, tx); err != nil {
+		return nil, err
+	}
+	if _, err := sw.Signature(&buffer, sig); err != nil {
+		return nil, err
+	}
+	return pk.Encrypt(buffer.Bytes())
+}
+
+// OpenTransaction decodes the decrypted message of SealTransaction
+func OpenTransaction(bs []byte) (*types.Transaction, common.Signature, error) {
+	r := bytes.NewReader(bs)
+	sr := bin.NewSumReader()
+	tx := &types.Transaction{}
+	if _, err := sr.ReaderFrom(r, tx); err != nil {
+		return nil, nil, err
+	}
+	var sig common.Signature
+	if _, err := sr.Signature(r, &sig); err != nil {
+		return nil, nil, err
+	}
+	return tx, sig, nil
+}
@@ -0,0 +1,156 @@
+package test
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/core/chain"
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/meverselabs/meverse/extern/test/util"
+)
+
+func TestBeacon(t *testing.T) {
+	chain.SetVersion(1, types.BeaconVersion)
+	defer chain.SetVersion(1, 2)
+
+	tc := util.NewTestContext()
+	tc.MustSkipBlock(3)
+
+	ctx := tc.Cn.NewContext()
+	Height, Value := ctx.Beacon()
+	last := tc.Cn.Provider().Height()
+	if Height != last-1 {
+		t.Fatalf("invalid beacon height %v, last %v", Height, last)
+	}
+	b, err := tc.Cn.Provider().Block(last)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Header.Beacon != Value {
+		t.Fatal("beacon is not the value of the header")
+	}
+	if h, has := ctx.BeaconBlock(Height); !has || h != last {
+		t.Fatalf("invalid beacon block %v", h)
+	}
+	bn, err := chain.BlockBeacon(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := tc.Cn.VerifyBeacon(bn); err != nil {
+		t.Fatalf("%+v", err)
+	} else if v != Value {
+		t.Fatal("verified value is not the beacon")
+	}
+
+	cc, err := util.GetCC(ctx, tc.MainToken, util.Admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r1, err := cc.Random([]byte("draw 1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, _ := cc.Random([]byte("draw 1")); r != r1 {
+		t.Fatal("random is not deterministic")
+	}
+	if r, _ := cc.Random([]byte("draw 2")); r == r1 {
+		t.Fatal("random of the other seed is same")
+	}
+	tc.MustSkipBlock(1)
+	cc, err = util.GetCC(tc.Cn.NewContext(), tc.MainToken, util.Admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, _ := cc.Random([]byte("draw 1")); r == r1 {
+		t.Fatal("random is not changed by the next beacon")
+	}
+
+	// the outputs of the majority are required in the order of the public key
+	last = tc.Cn.Provider().Height()
+	bn, err = tc.MakeBeacon(last)
+	if err != nil {
+		t.Fatal(err)
+	}
+	short := &types.Beacon{Height: bn.Height, Proofs: bn.Proofs[:len(bn.Proofs)-1]}
+	if _, err := tc.Cn.VerifyBeacon(short); errors.Cause(err) != chain.ErrInsufficientBeaconProofs {
+		t.Fatalf("insufficient proofs are verified %v", err)
+	}
+	swapped := &types.Beacon{Height: bn.Height, Proofs: append([]*types.BeaconProof{bn.Proofs[1], bn.Proofs[0]}, bn.Proofs[2:]...)}
+	if _, err := tc.Cn.VerifyBeacon(swapped); errors.Cause(err) != chain.ErrInvalidBeacon {
+		t.Fatalf("unordered proofs are verified %v", err)
+	}
+	moved := &types.Beacon{Height: bn.Height + 1, Proofs: bn.Proofs}
+	if _, err := tc.Cn.VerifyBeacon(moved); err == nil {
+		t.Fatal("proofs are verified at the other height")
+	}
+	foreign := &types.Beacon{Height: bn.Height, Proofs: append([]*types.BeaconProof{}, bn.Proofs...)}
+	foreign.Proofs[0] = &types.BeaconProof{
+		PublicKey: util.UserKeys[0].PublicKey(),
+		Proof:     bn.Proofs[0].Proof,
+	}
+	if _, err := tc.Cn.VerifyBeacon(foreign); err == nil {
+		t.Fatal("proof of the non observer is verified")
+	}
+
+	// the majority of the other observers cannot make the beacon
+	keys, err := tc.Cn.BeaconObserverKeys(bn.Height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, err := tc.Cn.ObserverKeys(bn.Height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pubkey := range all {
+		if pubkey == keys[len(keys)-1] {
+			continue
+		}
+		canonical := false
+		for _, k := range keys {
+			canonical = canonical || k == pubkey
+		}
+		if canonical {
+			continue
+		}
+		others := append(append([]common.PublicKey{}, keys[:len(keys)-1]...), pubkey)
+		sort.Slice(others, func(i, j int) bool {
+			return bytes.Compare(others[i][:], others[j][:]) < 0
+		})
+		other, err := tc.MakeBeaconBy(bn.Height, others)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tc.Cn.VerifyBeacon(other); errors.Cause(err) != chain.ErrInvalidBeacon {
+			t.Fatalf("beacon of the other majority is verified %v", err)
+		}
+		extra, err := tc.MakeBeaconBy(bn.Height, append(append([]common.PublicKey{}, keys...), pubkey))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tc.Cn.VerifyBeacon(extra); errors.Cause(err) != chain.ErrInvalidBeacon {
+			t.Fatalf("beacon of the extra proof is verified %v", err)
+		}
+	}
+
+	// the block should include the beacon of the previous height
+	ctx = tc.Cn.NewContext()
+	bc := chain.NewBlockCreator(tc.Cn, ctx, common.ZeroAddr, 0, ctx.LastTimestamp()+1, 0)
+	if _, err := bc.Finalize(0, nil); errors.Cause(err) != chain.ErrMissingBeacon {
+		t.Fatalf("block without the beacon is finalized %v", err)
+	}
+
+	// the included beacon cannot be included again
+	ctx = tc.Cn.NewContext()
+	old, err := tc.MakeBeacon(last - 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bc = chain.NewBlockCreator(tc.Cn, ctx, common.ZeroAddr, 0, ctx.LastTimestamp()+1, 0)
+	if err := bc.SetBeacon(old); errors.Cause(err) != chain.ErrInvalidBeacon {
+		t.Fatalf("old beacon is included %v", err)
+	}
+}
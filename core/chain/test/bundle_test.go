@@ -0,0 +1,93 @@
+package test
+
+import (
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/contract/token"
+	"github.com/meverselabs/meverse/core/chain"
+	"github.com/meverselabs/meverse/core/types"
+
+	. "github.com/meverselabs/meverse/tests/lib"
+)
+
+func TestBundle(t *testing.T) {
+	userKeys, err := GetSingers(ChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin := userKeys[0].PublicKey().Address()
+	supply := map[common.Address]*amount.Amount{}
+	for _, k := range userKeys[:2] {
+		supply[k.PublicKey().Address()] = amount.NewAmount(1000000, 0)
+	}
+
+	var mev *common.Address
+	initialize := func(ctx *types.Context, classMap map[string]uint64) error {
+		mev, err = MevInitialize(ctx, classMap, admin, supply)
+		return err
+	}
+	tb := NewTestBlockChain(filepath.Join(t.TempDir(), "chain"), true, ChainID, Version, admin, initialize, DefaultInitContextInfo)
+	defer tb.Close()
+
+	to := common.BigToAddress(big.NewInt(0x1000))
+	newBundle := func(ams ...uint64) *chain.Bundle {
+		bd := &chain.Bundle{}
+		for i, am := range ams {
+			k := userKeys[i%2]
+			tx := MakeGoTx(k, tb.Provider, mev, "Transfer", to, amount.NewAmount(am, 0)).Tx
+			tx.Timestamp += uint64(i)
+			sig, err := k.Sign(tx.Message())
+			if err != nil {
+				t.Fatal(err)
+			}
+			bd.Transactions = append(bd.Transactions, tx)
+			bd.TransactionSignatures = append(bd.TransactionSignatures, sig)
+		}
+		return bd
+	}
+	balanceOf := func(addr common.Address) *amount.Amount {
+		ctx := tb.Chain.NewContext()
+		cont, err := ctx.Contract(*mev)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cont.(*token.TokenContract).BalanceOf(ctx.ContractContext(cont, addr), addr)
+	}
+
+	result, err := tb.Chain.CallBundle(newBundle(1, 2), 0)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if len(result.TxHashes) != 2 || result.Height != tb.Provider.Height()+1 {
+		t.Fatalf("call result %v %v", len(result.TxHashes), result.Height)
+	}
+	if !balanceOf(to).IsZero() {
+		t.Fatal("simulation is stored")
+	}
+	if _, err := tb.Chain.CallBundle(newBundle(1, 2000000), 0); err == nil {
+		t.Fatal("exceeded transfer is simulated")
+	} else if be, ok := err.(*chain.BundleError); !ok || be.Index != 1 {
+		t.Fatalf("bundle error %+v", err)
+	}
+
+	failed := newBundle(1, 2000000)
+	early := newBundle(4)
+	early.MinHeight = tb.Provider.Height() + 2
+	b, errs, err := tb.AddBundleBlock([]*chain.Bundle{newBundle(1, 2), failed, early, newBundle(3)})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if errs[0] != nil || errs[1] == nil || errs[2] == nil || errs[3] != nil {
+		t.Fatalf("bundle errors %v", errs)
+	}
+	if len(b.Body.Transactions) != 3 {
+		t.Fatalf("block transactions %v", len(b.Body.Transactions))
+	}
+	if bal := balanceOf(to); bal.Cmp(amount.NewAmount(6, 0).Int) != 0 {
+		t.Fatalf("balance %v", bal)
+	}
+}
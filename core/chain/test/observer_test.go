@@ -0,0 +1,79 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/core/chain"
+	"github.com/meverselabs/meverse/core/chain/admin"
+	"github.com/meverselabs/meverse/extern/test/util"
+)
+
+func TestObserverChange(t *testing.T) {
+	tc := util.NewTestContext()
+	newKey := util.UserKeys[0]
+	oldKey := util.ObserverKeys[0]
+	util.RegisterObserverKey(newKey)
+
+	height := tc.Ctx.TargetHeight() + chain.ObserverChangeDelay + 5
+	if _, err := tc.SendTx(util.UserKeys[1], common.ZeroAddr, admin.ObserverAdd, newKey.PublicKey(), height); err == nil {
+		t.Fatal("observer is added by the other user")
+	}
+	if _, err := tc.SendTx(util.AdminKey, common.ZeroAddr, admin.ObserverAdd, newKey.PublicKey(), tc.Ctx.TargetHeight()); err == nil {
+		t.Fatal("observer is added at the current height")
+	}
+	if _, err := tc.SendTx(util.AdminKey, common.ZeroAddr, admin.ObserverRemove, util.UserKeys[2].PublicKey(), height); err == nil {
+		t.Fatal("not exist observer is removed")
+	}
+	tc.MustSendTx(util.AdminKey, common.ZeroAddr, admin.ObserverAdd, newKey.PublicKey(), height)
+	tc.MustSendTx(util.AdminKey, common.ZeroAddr, admin.ObserverRemove, oldKey, height)
+	if _, err := tc.SendTx(util.AdminKey, common.ZeroAddr, admin.ObserverAdd, newKey.PublicKey(), height); err == nil {
+		t.Fatal("observer is added twice")
+	}
+
+	before, err := tc.Cn.ObserverKeyMap(height - 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !before[oldKey] || before[newKey.PublicKey()] {
+		t.Fatal("observer set is changed before the height")
+	}
+	after, err := tc.Cn.ObserverKeyMap(height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after[oldKey] || !after[newKey.PublicKey()] || len(after) != len(util.ObserverKeys) {
+		t.Fatalf("invalid observer set after the height %v", after)
+	}
+	if !tc.Cn.IsObserverKey(newKey.PublicKey(), tc.Ctx.TargetHeight()) {
+		t.Fatal("scheduled observer is not accepted")
+	}
+
+	// blocks after the height are signed by the new observer set
+	for tc.Ctx.TargetHeight() <= height+2 {
+		if err := tc.Sleep(10, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestObserverChangeDelay(t *testing.T) {
+	tc := util.NewTestContext()
+	newKey := util.UserKeys[0]
+
+	if _, err := tc.SendTx(util.AdminKey, common.ZeroAddr, admin.ObserverAdd, newKey.PublicKey(), tc.Ctx.TargetHeight()+chain.ObserverChangeDelay-1); err == nil {
+		t.Fatal("observer is added before the delay")
+	}
+	height := tc.Ctx.TargetHeight() + chain.ObserverChangeDelay
+	tc.MustSendTx(util.AdminKey, common.ZeroAddr, admin.ObserverAdd, newKey.PublicKey(), height)
+	if before, err := tc.Cn.ObserverKeyMap(height - 1); err != nil {
+		t.Fatal(err)
+	} else if before[newKey.PublicKey()] {
+		t.Fatal("observer set is changed before the height")
+	}
+	if after, err := tc.Cn.ObserverKeyMap(height); err != nil {
+		t.Fatal(err)
+	} else if !after[newKey.PublicKey()] {
+		t.Fatal("observer is not added at the height of the delay")
+	}
+}
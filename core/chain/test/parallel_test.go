@@ -0,0 +1,157 @@
+package test
+
+import (
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/contract/token"
+	"github.com/meverselabs/meverse/core/ctypes"
+	"github.com/meverselabs/meverse/core/types"
+
+	. "github.com/meverselabs/meverse/tests/lib"
+)
+
+// TestParallelExecution connects the blocks created by the sequential execution with the parallel execution,
+// so the context hash and the receipt hash of the header are derived again by the speculations
+func TestParallelExecution(t *testing.T) {
+	userKeys, err := GetSingers(ChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin := userKeys[0].PublicKey().Address()
+	supply := map[common.Address]*amount.Amount{}
+	for _, k := range userKeys[:8] {
+		supply[k.PublicKey().Address()] = amount.NewAmount(1000000, 0)
+	}
+
+	var mev *common.Address
+	initialize := func(ctx *types.Context, classMap map[string]uint64) error {
+		mev, err = MevInitialize(ctx, classMap, admin, supply)
+		return err
+	}
+	tb := NewTestBlockChain(filepath.Join(t.TempDir(), "chain"), true, ChainID, Version, admin, initialize, DefaultInitContextInfo)
+	defer tb.Close()
+	tb.Chain.SetExecuteWorkers(4)
+
+	to := func(i int) common.Address {
+		return common.BigToAddress(big.NewInt(int64(0x1000 + i)))
+	}
+	cases := map[string]func() []*TxWithSigner{
+		"independent": func() []*TxWithSigner {
+			txs := []*TxWithSigner{}
+			for i, k := range userKeys[:8] {
+				txs = append(txs, MakeGoTx(k, tb.Provider, mev, "Transfer", to(i), amount.NewAmount(1, 0)))
+			}
+			return txs
+		},
+		"same sender": func() []*TxWithSigner {
+			txs := []*TxWithSigner{}
+			for i := 0; i < 6; i++ {
+				txs = append(txs, MakeGoTx(userKeys[0], tb.Provider, mev, "Transfer", to(i), amount.NewAmount(1, 0)))
+			}
+			return txs
+		},
+		"chained": func() []*TxWithSigner {
+			// each transfer writes the balance of the sender of the next transfer
+			txs := []*TxWithSigner{}
+			for i := 0; i < 7; i++ {
+				txs = append(txs, MakeGoTx(userKeys[i], tb.Provider, mev, "Transfer", userKeys[i+1].PublicKey().Address(), amount.NewAmount(10, 0)))
+			}
+			return txs
+		},
+		"mixed": func() []*TxWithSigner {
+			txs := []*TxWithSigner{}
+			for i := 0; i < 4; i++ {
+				txs = append(txs, MakeGoTx(userKeys[i], tb.Provider, mev, "Transfer", to(i), amount.NewAmount(1, 0)))
+				txs = append(txs, MakeGoTx(userKeys[4], tb.Provider, mev, "Approve", to(i), amount.NewAmount(uint64(i+1), 0)))
+				txs = append(txs, MakeGoTx(userKeys[i+1], tb.Provider, mev, "Transfer", userKeys[i].PublicKey().Address(), amount.NewAmount(1, 0)))
+			}
+			return txs
+		},
+	}
+	for _, name := range []string{"independent", "same sender", "chained", "mixed"} {
+		if _, err := tb.AddBlock(cases[name]()); err != nil {
+			t.Fatalf("%v: %+v", name, err)
+		}
+	}
+
+	ctx := types.NewContext(tb.Chain.Store())
+	cont, err := ctx.Contract(*mev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[common.Address]*amount.Amount{
+		to(0): amount.NewAmount(3, 0),
+		to(5): amount.NewAmount(2, 0),
+	}
+	for addr, am := range want {
+		if bal := cont.(*token.TokenContract).BalanceOf(ctx.ContractContext(cont, addr), addr); bal.Cmp(am.Int) != 0 {
+			t.Fatalf("balance of %v %v, want %v", addr, bal, am)
+		}
+	}
+}
+
+// TestSpeculatedBlockCreation creates the blocks by adding the transactions together with the speculations,
+// the events and the method names are recorded as the sequential creation
+func TestSpeculatedBlockCreation(t *testing.T) {
+	userKeys, err := GetSingers(ChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin := userKeys[0].PublicKey().Address()
+	supply := map[common.Address]*amount.Amount{}
+	for _, k := range userKeys[:8] {
+		supply[k.PublicKey().Address()] = amount.NewAmount(1000000, 0)
+	}
+
+	var mev *common.Address
+	initialize := func(ctx *types.Context, classMap map[string]uint64) error {
+		mev, err = MevInitialize(ctx, classMap, admin, supply)
+		return err
+	}
+	tb := NewTestBlockChain(filepath.Join(t.TempDir(), "chain"), true, ChainID, Version, admin, initialize, DefaultInitContextInfo)
+	defer tb.Close()
+	tb.Chain.SetExecuteWorkers(4)
+
+	to := func(i int) common.Address {
+		return common.BigToAddress(big.NewInt(int64(0x1000 + i)))
+	}
+	txs := []*TxWithSigner{}
+	for i, k := range userKeys[:8] {
+		txs = append(txs, MakeGoTx(k, tb.Provider, mev, "Transfer", to(i), amount.NewAmount(1, 0)))
+		// each transfer writes the balance of the sender of the next transfer
+		txs = append(txs, MakeGoTx(k, tb.Provider, mev, "Transfer", userKeys[(i+1)%8].PublicKey().Address(), amount.NewAmount(10, 0)))
+	}
+	b, err := tb.AddSpeculatedBlock(txs)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if len(b.Body.Transactions) != len(txs) {
+		t.Fatalf("transactions %v, want %v", len(b.Body.Transactions), len(txs))
+	}
+	fees := map[uint16]bool{}
+	for _, en := range b.Body.Events {
+		if en.Type == ctypes.EventTagTxFee {
+			fees[en.Index] = true
+		}
+	}
+	for i := range txs {
+		if !fees[uint16(i)] {
+			t.Fatalf("no fee event of the transaction %v", i)
+		}
+	}
+
+	ctx := types.NewContext(tb.Chain.Store())
+	cont, err := ctx.Contract(*mev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 8; i++ {
+		if bal := cont.(*token.TokenContract).BalanceOf(ctx.ContractContext(cont, to(i)), to(i)); bal.Cmp(amount.NewAmount(1, 0).Int) != 0 {
+			t.Fatalf("balance of %v %v, want 1", to(i), bal)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/core/chain"
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/pkg/errors"
+
+	. "github.com/meverselabs/meverse/tests/lib"
+)
+
+func TestTxVerifier(t *testing.T) {
+	userKeys, err := GetSingers(ChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tv := chain.NewTxVerifier(ChainID, 4, 2)
+	defer tv.Close()
+
+	now := uint64(time.Now().UnixNano())
+	currentSlot := types.ToTimeSlot(now)
+	makeTx := func(i int, chainID *big.Int, timestamp uint64) (*types.Transaction, common.Signature) {
+		tx := &types.Transaction{
+			ChainID:   chainID,
+			Timestamp: timestamp,
+			To:        common.BigToAddress(big.NewInt(0x1000)),
+			Method:    "Transfer",
+			Args:      bin.TypeWriteAll(common.BigToAddress(big.NewInt(int64(i))), amount.NewAmount(1, 0)),
+		}
+		sig, err := userKeys[i].Sign(tx.HashSig())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx, sig
+	}
+
+	txs := []*types.Transaction{}
+	sigs := []common.Signature{}
+	for i := 0; i < 3; i++ {
+		tx, sig := makeTx(i, ChainID, now)
+		txs = append(txs, tx)
+		sigs = append(sigs, sig)
+	}
+	signers, errs := tv.VerifyAll(txs, sigs, currentSlot)
+	for i := range txs {
+		if errs[i] != nil {
+			t.Fatalf("%v: %+v", i, errs[i])
+		}
+		if signers[i] != userKeys[i].PublicKey().Address() {
+			t.Fatalf("%v: signer %v, want %v", i, signers[i], userKeys[i].PublicKey().Address())
+		}
+	}
+
+	// the oldest one is evicted by the cache size 2
+	tv = chain.NewTxVerifier(ChainID, 4, 2)
+	defer tv.Close()
+	for i := range txs {
+		if _, err := tv.Verify(txs[i], sigs[i], currentSlot); err != nil {
+			t.Fatalf("%v: %+v", i, err)
+		}
+	}
+	if _, has := tv.CachedSigner(txs[0], sigs[0]); has {
+		t.Fatal("evicted signer is cached")
+	}
+	if signer, has := tv.CachedSigner(txs[2], sigs[2]); !has || signer != userKeys[2].PublicKey().Address() {
+		t.Fatalf("cached signer %v %v", signer, has)
+	}
+	if _, has := tv.CachedSigner(txs[2], sigs[1]); has {
+		t.Fatal("signer is cached by the other signature")
+	}
+
+	tx, sig := makeTx(0, big.NewInt(1), now)
+	if _, err := tv.Verify(tx, sig, currentSlot); errors.Cause(err) != chain.ErrInvalidChainID {
+		t.Fatalf("chain id %+v", err)
+	}
+	tx, sig = makeTx(0, ChainID, now+uint64(20*time.Minute))
+	if _, err := tv.Verify(tx, sig, currentSlot); errors.Cause(err) != types.ErrInvalidTransactionTimeSlot {
+		t.Fatalf("time slot %+v", err)
+	}
+	if _, err := tv.Verify(tx, sig, 0); err != nil {
+		t.Fatalf("skipped time slot %+v", err)
+	}
+	tx.Args = make([]byte, chain.MaxTransactionArgsSize+1)
+	if _, err := tv.Verify(tx, sig, 0); errors.Cause(err) != chain.ErrTooLargeTransaction {
+		t.Fatalf("size %+v", err)
+	}
+}
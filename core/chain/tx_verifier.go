@@ -0,0 +1,207 @@
+package chain
+
+import (
+	"bytes"
+	"math/big"
+	"runtime"
+	"sync"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/meverselabs/meverse/extern/txparser"
+	"github.com/pkg/errors"
+)
+
+// MaxTransactionArgsSize is the limit of the arguments of a transaction, it covers the wasm code of a deployment
+const MaxTransactionArgsSize = 5 * 1024 * 1024
+
+// DefaultSignerCacheSize is the count of the signers cached by the TxVerifier
+const DefaultSignerCacheSize = 200000
+
+type verifyJob struct {
+	tx          *types.Transaction
+	sig         common.Signature
+	currentSlot uint32
+	signer      common.Address
+	err         error
+	wg          *sync.WaitGroup
+}
+
+type signerItem struct {
+	sig    common.Signature
+	signer common.Address
+}
+
+// TxVerifier validates the transactions without the state by the bounded workers
+//
+// the recovered signers are cached by HashSig, so the transactions of the blocks from peers skip the repeated recovery
+type TxVerifier struct {
+	sync.Mutex
+	ChainID   *big.Int
+	workers   int
+	jobCh     chan *verifyJob
+	startOnce sync.Once
+	closeLock sync.RWMutex
+	isClose   bool
+	cacheSize int
+	cache     map[hash.Hash256]*signerItem
+	cacheKeys []hash.Hash256
+	cacheHead int
+}
+
+// NewTxVerifier returns a TxVerifier, 0 workers means the count of cpus
+func NewTxVerifier(ChainID *big.Int, workers int, cacheSize int) *TxVerifier {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if cacheSize <= 0 {
+		cacheSize = DefaultSignerCacheSize
+	}
+	return &TxVerifier{
+		ChainID:   ChainID,
+		workers:   workers,
+		jobCh:     make(chan *verifyJob, workers*64),
+		cacheSize: cacheSize,
+		cache:     map[hash.Hash256]*signerItem{},
+		cacheKeys: []hash.Hash256{},
+	}
+}
+
+func (tv *TxVerifier) start() {
+	tv.startOnce.Do(func() {
+		for i := 0; i < tv.workers; i++ {
+			go func() {
+				for job := range tv.jobCh {
+					job.signer, job.err = tv.verify(job.tx, job.sig, job.currentSlot)
+					job.wg.Done()
+				}
+			}()
+		}
+	})
+}
+
+// Close stops the workers
+func (tv *TxVerifier) Close() {
+	tv.closeLock.Lock()
+	defer tv.closeLock.Unlock()
+
+	if !tv.isClose {
+		tv.start()
+		close(tv.jobCh)
+		tv.isClose = true
+	}
+}
+
+// Verify validates the transaction by a worker and returns the signer
+//
+// it checks the chain id, the size, the time slot window, the ether type encoding and the signature,
+// currentSlot 0 skips the time slot check
+func (tv *TxVerifier) Verify(tx *types.Transaction, sig common.Signature, currentSlot uint32) (common.Address, error) {
+	signers, errs := tv.VerifyAll([]*types.Transaction{tx}, []common.Signature{sig}, currentSlot)
+	return signers[0], errs[0]
+}
+
+// VerifyAll validates the transactions in parallel like Verify, the error of each transaction is returned at the same index
+func (tv *TxVerifier) VerifyAll(txs []*types.Transaction, sigs []common.Signature, currentSlot uint32) ([]common.Address, []error) {
+	tv.closeLock.RLock()
+	defer tv.closeLock.RUnlock()
+
+	signers := make([]common.Address, len(txs))
+	errs := make([]error, len(txs))
+	if tv.isClose {
+		for i := range errs {
+			errs[i] = errors.WithStack(ErrChainClosed)
+		}
+		return signers, errs
+	}
+
+	tv.start()
+	var wg sync.WaitGroup
+	jobs := make([]*verifyJob, len(txs))
+	for i, tx := range txs {
+		jobs[i] = &verifyJob{
+			tx:          tx,
+			sig:         sigs[i],
+			currentSlot: currentSlot,
+			wg:          &wg,
+		}
+		wg.Add(1)
+		tv.jobCh <- jobs[i]
+	}
+	wg.Wait()
+
+	for i, job := range jobs {
+		signers[i] = job.signer
+		errs[i] = job.err
+	}
+	return signers, errs
+}
+
+func (tv *TxVerifier) verify(tx *types.Transaction, sig common.Signature, currentSlot uint32) (common.Address, error) {
+	if tx.ChainID == nil || tx.ChainID.Cmp(tv.ChainID) != 0 {
+		return common.Address{}, errors.WithStack(ErrInvalidChainID)
+	}
+	if len(tx.Args) > MaxTransactionArgsSize {
+		return common.Address{}, errors.WithStack(ErrTooLargeTransaction)
+	}
+	if currentSlot > 0 {
+		slot := types.ToTimeSlot(tx.Timestamp)
+		if slot < currentSlot-1 || slot > currentSlot+10 {
+			return common.Address{}, errors.WithStack(types.ErrInvalidTransactionTimeSlot)
+		}
+	}
+	if tx.IsEtherType {
+		if _, _, err := txparser.EthTxFromRLP(tx.Args); err != nil {
+			return common.Address{}, err
+		}
+	}
+	return tv.Recover(tx, sig)
+}
+
+// Recover returns the signer of the transaction on the caller, it is used by the block validation which has its own workers
+func (tv *TxVerifier) Recover(tx *types.Transaction, sig common.Signature) (common.Address, error) {
+	TxHash := tx.HashSig()
+	if signer, has := tv.cachedSigner(TxHash, sig); has {
+		return signer, nil
+	}
+	pubkey, err := common.RecoverPubkey(tx.ChainID, tx.Message(), sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	signer := pubkey.Address()
+	tv.cacheSigner(TxHash, sig, signer)
+	return signer, nil
+}
+
+// CachedSigner returns the signer recovered before, the signature is compared because the hash of a native transaction does not include it
+func (tv *TxVerifier) CachedSigner(tx *types.Transaction, sig common.Signature) (common.Address, bool) {
+	return tv.cachedSigner(tx.HashSig(), sig)
+}
+
+func (tv *TxVerifier) cachedSigner(TxHash hash.Hash256, sig common.Signature) (common.Address, bool) {
+	tv.Lock()
+	defer tv.Unlock()
+
+	if item, has := tv.cache[TxHash]; has && bytes.Equal(item.sig, sig) {
+		return item.signer, true
+	}
+	return common.Address{}, false
+}
+
+func (tv *TxVerifier) cacheSigner(TxHash hash.Hash256, sig common.Signature, signer common.Address) {
+	tv.Lock()
+	defer tv.Unlock()
+
+	if _, has := tv.cache[TxHash]; !has {
+		// the oldest one is evicted when the cache is full
+		if len(tv.cacheKeys) < tv.cacheSize {
+			tv.cacheKeys = append(tv.cacheKeys, TxHash)
+		} else {
+			delete(tv.cache, tv.cacheKeys[tv.cacheHead])
+			tv.cacheKeys[tv.cacheHead] = TxHash
+			tv.cacheHead = (tv.cacheHead + 1) % tv.cacheSize
+		}
+	}
+	tv.cache[TxHash] = &signerItem{sig: sig, signer: signer}
+}
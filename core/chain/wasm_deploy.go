@@ -0,0 +1,108 @@
+package chain
+
+import (
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/contract/wasm"
+	"github.com/meverselabs/meverse/core/ctypes"
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/meverselabs/meverse/core/wasmvm"
+	"github.com/pkg/errors"
+)
+
+// WasmDeployArgs returns the args of the wasm deploy transaction
+// the init args are the json array which is passed to the init function of the code
+func WasmDeployArgs(code []byte, initArgs []interface{}) ([]byte, error) {
+	bs, err := wasmvm.MarshalValues(initArgs)
+	if err != nil {
+		return nil, err
+	}
+	return bin.TypeWriteAll(code, string(bs)), nil
+}
+
+func ExecuteWasmDeployTxWithEvent(ctx *types.Context, tx *types.Transaction, signer common.Address, TXID string) ([]*ctypes.Event, error) {
+	intr, addr, resultErr := _executeWasmDeployTx(ctx, tx, signer, TXID)
+	if resultErr != nil {
+		return nil, resultErr
+	}
+
+	_, i, err := types.ParseTransactionID(TXID)
+	if err != nil {
+		return nil, err
+	}
+	ens := []*ctypes.Event{
+		ctypes.NewEvent(i, ctypes.EventTagTxMsg, bin.TypeWriteAll(addr)),
+	}
+	if len(intr.EventList()) > 0 {
+		ens = append(ens, intr.EventList()...)
+	}
+	return ens, nil
+}
+
+// ExecuteWasmDeployTx deploys the wasm code of the transaction as a WasmContract of the signer
+func ExecuteWasmDeployTx(ctx *types.Context, tx *types.Transaction, signer common.Address, TXID string) error {
+	_, _, err := _executeWasmDeployTx(ctx, tx, signer, TXID)
+	return err
+}
+
+func _executeWasmDeployTx(ctx *types.Context, tx *types.Transaction, signer common.Address, TXID string) (types.IInteractor, common.Address, error) {
+	types.ExecLock.Lock()
+	defer types.ExecLock.Unlock()
+
+	_, i, err := types.ParseTransactionID(TXID)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+
+	if tx.UseSeq {
+		seq := ctx.AddrSeq(signer)
+		if seq != tx.Seq {
+			return nil, common.Address{}, errors.Errorf("invalid signer sequence siger %v seq %v, got %v", signer, seq, tx.Seq)
+		}
+		ctx.AddAddrSeq(signer)
+	}
+
+	data, err := bin.TypeReadAll(tx.Args, 2)
+	if err != nil {
+		return nil, common.Address{}, errors.Wrap(ErrInvalidWasmDeploy, err.Error())
+	}
+	code, ok := data[0].([]byte)
+	if !ok {
+		return nil, common.Address{}, errors.Wrap(ErrInvalidWasmDeploy, "code")
+	}
+	sArgs, ok := data[1].(string)
+	if !ok {
+		return nil, common.Address{}, errors.Wrap(ErrInvalidWasmDeploy, "init args")
+	}
+	initArgs, err := wasmvm.UnmarshalValues([]byte(sArgs))
+	if err != nil {
+		return nil, common.Address{}, errors.Wrap(ErrInvalidWasmDeploy, err.Error())
+	}
+
+	_, ClassID := types.GetContractClassID(&wasm.WasmContract{})
+	cont, err := ctx.DeployContract(signer, ClassID, code)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	cc := ctx.ContractContext(cont, signer)
+	intr := types.NewInteractor(ctx, cont, cc, TXID, true)
+	cc.Exec = intr.Exec
+	_, err = intr.Exec(cc, cont.Address(), "InitContract", []interface{}{initArgs})
+	intr.Distroy()
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+
+	gh := intr.GasHistory()
+	useGas := gh[0] + uint64(len(code))*wasmvm.GasPerByte
+	if fee, err := ChargeFee(ctx, useGas, signer); err != nil {
+		return nil, common.Address{}, err
+	} else {
+		intr.AddEvent(&ctypes.Event{
+			Index:  i,
+			Type:   ctypes.EventTagTxFee,
+			Result: bin.TypeWriteAll(fee),
+		})
+	}
+	return intr, cont.Address(), nil
+}
@@ -0,0 +1,156 @@
+package types
+
+import (
+	"io"
+	"math/big"
+
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/common/vrf"
+)
+
+// BeaconVersion is the chain version from which the header has the randomness beacon of the observers
+const BeaconVersion = uint16(8)
+
+// beaconDataAddress is the reserved address which keeps the latest beacon in the context data
+var beaconDataAddress = common.BytesToAddress([]byte("meverse.beacon"))
+
+var (
+	tagLatestBeacon = []byte("latest")
+	tagBeaconBlock  = []byte("block")
+)
+
+// BeaconSeed returns the message which is evaluated by the observers for the beacon of the height
+func BeaconSeed(ChainID *big.Int, Height uint32) []byte {
+	return bin.TypeWriteAll(ChainID, Height)
+}
+
+// BeaconProof is the vrf output of an observer over the beacon seed
+type BeaconProof struct {
+	PublicKey common.PublicKey
+	Proof     *vrf.Proof
+}
+
+func (bp *BeaconProof) WriteTo(w io.Writer) (int64, error) {
+	sw := bin.NewSumWriter()
+	if sum, err := sw.PublicKey(w, bp.PublicKey); err != nil {
+		return sum, err
+	}
+	if sum, err := sw.WriterTo(w, bp.Proof); err != nil {
+		return sum, err
+	}
+	return sw.Sum(), nil
+}
+
+func (bp *BeaconProof) ReadFrom(r io.Reader) (int64, error) {
+	sr := bin.NewSumReader()
+	if sum, err := sr.PublicKey(r, &bp.PublicKey); err != nil {
+		return sum, err
+	}
+	bp.Proof = &vrf.Proof{}
+	if sum, err := sr.ReaderFrom(r, bp.Proof); err != nil {
+		return sum, err
+	}
+	return sr.Sum(), nil
+}
+
+// Beacon is the vrf outputs of the beacon observers of the height, it is included in the next block of the height
+//
+// the beacon observers are the majority of the observers which comes first in the order of the public key,
+// so the value is decided by the height and does not depend on the arrival of the votes
+type Beacon struct {
+	Height uint32
+	Proofs []*BeaconProof
+}
+
+// Value returns the beacon value of the verified outputs of the beacon observers
+func (bn *Beacon) Value(ChainID *big.Int, outputs []hash.Hash256) hash.Hash256 {
+	data := make([][]byte, 0, len(outputs)+1)
+	data = append(data, BeaconSeed(ChainID, bn.Height))
+	for i := range outputs {
+		data = append(data, outputs[i][:])
+	}
+	return hash.Hash(data...)
+}
+
+func (bn *Beacon) WriteTo(w io.Writer) (int64, error) {
+	sw := bin.NewSumWriter()
+	if sum, err := sw.Uint32(w, bn.Height); err != nil {
+		return sum, err
+	}
+	if sum, err := sw.Uint16(w, uint16(len(bn.Proofs))); err != nil {
+		return sum, err
+	}
+	for _, bp := range bn.Proofs {
+		if sum, err := sw.WriterTo(w, bp); err != nil {
+			return sum, err
+		}
+	}
+	return sw.Sum(), nil
+}
+
+func (bn *Beacon) ReadFrom(r io.Reader) (int64, error) {
+	sr := bin.NewSumReader()
+	if sum, err := sr.Uint32(r, &bn.Height); err != nil {
+		return sum, err
+	}
+	Len, sum, err := sr.GetUint16(r)
+	if err != nil {
+		return sum, err
+	}
+	bn.Proofs = make([]*BeaconProof, 0, Len)
+	for i := uint16(0); i < Len; i++ {
+		bp := &BeaconProof{}
+		if sum, err := sr.ReaderFrom(r, bp); err != nil {
+			return sum, err
+		}
+		bn.Proofs = append(bn.Proofs, bp)
+	}
+	return sr.Sum(), nil
+}
+
+// Beacon returns the latest beacon value and its height, the height is zero when there is no beacon
+func (ctx *Context) Beacon() (uint32, hash.Hash256) {
+	bs := ctx.Data(beaconDataAddress, common.ZeroAddr, tagLatestBeacon)
+	if len(bs) != 36 {
+		return 0, hash.Hash256{}
+	}
+	var h hash.Hash256
+	copy(h[:], bs[4:])
+	return bin.Uint32(bs[:4]), h
+}
+
+// BeaconBlock returns the height of the block which includes the beacon of the height
+func (ctx *Context) BeaconBlock(Height uint32) (uint32, bool) {
+	bs := ctx.Data(beaconDataAddress, common.ZeroAddr, append(tagBeaconBlock, bin.Uint32Bytes(Height)...))
+	if len(bs) != 4 {
+		return 0, false
+	}
+	return bin.Uint32(bs), true
+}
+
+// SetBeacon updates the latest beacon value which is included in the target height
+func (ctx *Context) SetBeacon(Height uint32, Value hash.Hash256) {
+	ctx.SetData(beaconDataAddress, common.ZeroAddr, tagLatestBeacon, append(bin.Uint32Bytes(Height), Value[:]...))
+	ctx.SetData(beaconDataAddress, common.ZeroAddr, append(tagBeaconBlock, bin.Uint32Bytes(Height)...), bin.Uint32Bytes(ctx.TargetHeight()))
+}
+
+// Beacon returns the latest beacon value and its height
+func (cc *ContractContext) Beacon() (uint32, hash.Hash256) {
+	return cc.ctx.Beacon()
+}
+
+// Random returns the random value of the seed from the latest beacon
+//
+// the generator knows the beacon of the block before the transactions are included,
+// so the contract should fix the request first and use the beacon of a later height
+func (cc *ContractContext) Random(seed []byte) (hash.Hash256, error) {
+	Height, Value := cc.ctx.Beacon()
+	if Height == 0 {
+		return hash.Hash256{}, errors.WithStack(ErrBeaconNotAvailable)
+	}
+	return hash.Hash(Value[:], bin.Uint32Bytes(Height), cc.cont[:], seed), nil
+}
@@ -21,6 +21,7 @@ type Header struct {
 	Generator     common.Address // 20byte
 	Gas           uint16         // 2byte
 	ReceiptHash   hash.Hash256   // 32byte
+	Beacon        hash.Hash256   // 32byte
 }
 
 func (s *Header) Clone() Header {
@@ -36,6 +37,7 @@ func (s *Header) Clone() Header {
 		Generator:     s.Generator,
 		Gas:           s.Gas,
 		ReceiptHash:   s.ReceiptHash,
+		Beacon:        s.Beacon,
 	}
 }
 
@@ -76,6 +78,11 @@ func (s *Header) WriteTo(w io.Writer) (int64, error) {
 			return sum, err
 		}
 	}
+	if s.Version >= BeaconVersion {
+		if sum, err := sw.Hash256(w, s.Beacon); err != nil {
+			return sum, err
+		}
+	}
 	return sw.Sum(), nil
 }
 
@@ -116,5 +123,10 @@ func (s *Header) ReadFrom(r io.Reader) (int64, error) {
 			return sum, err
 		}
 	}
+	if s.Version >= BeaconVersion {
+		if sum, err := sr.Hash256(r, &s.Beacon); err != nil {
+			return sum, err
+		}
+	}
 	return sr.Sum(), nil
 }
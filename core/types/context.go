@@ -3,9 +3,11 @@ package types
 import (
 	"math/big"
 
+	etypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/meverselabs/meverse/common"
 	"github.com/meverselabs/meverse/common/amount"
 	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/core/wasmvm"
 	"github.com/pkg/errors"
 )
 
@@ -20,6 +22,10 @@ type Context struct {
 	isLatestHash    bool
 	dataHash        hash.Hash256
 	isProcessReward bool
+	logs            []*etypes.Log
+	transient       *transientStorage
+	access          *accessSet
+	wasmMeter       *wasmvm.Meter
 }
 
 // NewContext returns a Context
@@ -245,6 +251,8 @@ func (ctx *Context) Snapshot() int {
 		ctd.AddrSeqMap[k] = v
 	}
 	ctd.seq = prevCtd.seq
+	ctd.logSize = len(ctx.logs)
+	ctd.transientSize = ctx.transient.size()
 	return len(ctx.stack)
 }
 
@@ -257,9 +265,14 @@ func (ctx *Context) GetPCSize() uint64 {
 func (ctx *Context) Revert(sn int) {
 	ctx.isLatestHash = false
 	if len(ctx.stack) >= sn {
+		if ls := ctx.stack[sn-1].logSize; ls < len(ctx.logs) {
+			ctx.logs = ctx.logs[:ls]
+		}
+		ctx.transient.revert(ctx.stack[sn-1].transientSize)
 		ctx.stack = ctx.stack[:sn-1]
 	}
 	ctx.stack[len(ctx.stack)-1].isTop = true
+	ctx.clearTransientAtTxEnd()
 }
 
 // Commit apply snapshots to the top after the snapshot number
@@ -306,6 +319,7 @@ func (ctx *Context) Commit(sn int) {
 		top.size = top.size + ctd.size
 	}
 	ctx.stack[len(ctx.stack)-1].isTop = true
+	ctx.clearTransientAtTxEnd()
 }
 
 // Hash returns the hash value of it
@@ -5,8 +5,10 @@ import (
 	"math/big"
 	"reflect"
 
+	etypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/meverselabs/meverse/common"
 	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/core/wasmvm"
 	"github.com/meverselabs/meverse/ethereum/core/defaultevm"
 	"github.com/meverselabs/meverse/ethereum/core/vm"
 )
@@ -49,6 +51,20 @@ func (cc *ContractContext) LastTimestamp() uint64 {
 	return cc.ctx.LastTimestamp()
 }
 
+// UseGas charges the gas which is used outside of the context data like the wasm vm
+func (cc *ContractContext) UseGas(gas uint64) {
+	cc.ctx.Top().UseGas(gas)
+}
+
+// WasmMeter returns the meter of the wasm calls of the transaction
+// the calls of a transaction share the gas limit and the meter is dropped at the end of the transaction
+func (cc *ContractContext) WasmMeter() *wasmvm.Meter {
+	if cc.ctx.wasmMeter == nil {
+		cc.ctx.wasmMeter = wasmvm.NewMeter(wasmvm.DefaultGasLimit)
+	}
+	return cc.ctx.wasmMeter
+}
+
 // From returns current signer address
 func (cc *ContractContext) From() common.Address {
 	return cc.from
@@ -131,10 +147,22 @@ func (cc *ContractContext) IsContract(addr common.Address) bool {
 
 // EvmCall execute evm.Call function and returns result, usedGas, error
 func (cc *ContractContext) EvmCall(caller vm.ContractRef, to common.Address, input []byte) ([]byte, uint64, error) {
+	if err := cc.ctx.access.abort(); err != nil {
+		return nil, 0, err
+	}
 	statedb := NewStateDB(cc.ctx)
 	evm := defaultevm.DefaultEVM(statedb, nil)
 
 	inputGas := uint64(math.MaxUint64)
 	ret, leftOverGas, err := evm.Call(caller, to, input, inputGas, big.NewInt(0))
+	if err == nil && cc.ctx.Version(cc.ctx.TargetHeight()) >= NativeLogVersion {
+		for _, l := range statedb.Logs() {
+			cc.ctx.AddLog(&etypes.Log{
+				Address: l.Address,
+				Topics:  l.Topics,
+				Data:    l.Data,
+			})
+		}
+	}
 	return ret, inputGas - leftOverGas, err
 }
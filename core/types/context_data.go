@@ -31,6 +31,8 @@ type ContextData struct {
 	isTop               bool
 	seq                 uint32
 	size                uint64
+	logSize             int
+	transientSize       int
 }
 
 // NewContextData returns a ContextData
@@ -52,12 +54,25 @@ func NewContextData(cache *contextCache, Parent *ContextData) *ContextData {
 	return ctd
 }
 
+// access returns the access set of the speculative context or nil
+func (ctd *ContextData) access() *accessSet {
+	return ctd.cache.ctx.access
+}
+
 func (ctd *ContextData) GetPCSize() uint64 {
 	return ctd.size * 22
 }
 
+// UseGas adds the gas which is used outside of the context data like the wasm vm
+func (ctd *ContextData) UseGas(gas uint64) {
+	ctd.size += (gas + 21) / 22
+}
+
 // IsAdmin returns the account is admin or not
 func (ctd *ContextData) IsAdmin(addr common.Address) bool {
+	if as := ctd.access(); as != nil {
+		as.admin[addr] = true
+	}
 	if _, has := ctd.DeletedAdminMap[addr]; has {
 		return false
 	}
@@ -96,6 +111,9 @@ func (ctd *ContextData) SetAdmin(addr common.Address, is bool) error {
 
 // IsGenerator returns the account is generator or not
 func (ctd *ContextData) IsGenerator(addr common.Address) bool {
+	if as := ctd.access(); as != nil {
+		as.generator[addr] = true
+	}
 	if _, has := ctd.DeletedGeneratorMap[addr]; has {
 		return false
 	}
@@ -137,6 +155,9 @@ func (ctd *ContextData) UnsafeGetMainToken() *common.Address {
 
 // MainToken returns the MainToken
 func (ctd *ContextData) MainToken() *common.Address {
+	if as := ctd.access(); as != nil {
+		as.mainToken = true
+	}
 	if ctd.mainToken != nil {
 		return ctd.mainToken
 	}
@@ -155,12 +176,18 @@ func (ctd *ContextData) MainToken() *common.Address {
 
 // SetMainToken is set the maintoken
 func (ctd *ContextData) SetMainToken(addr common.Address) {
+	if as := ctd.access(); as != nil {
+		as.mainToken = true
+	}
 	ctd.mainToken = &addr
 	ctd.size += 20 // uint32(common.Sizeof(reflect.TypeOf(addr)))
 }
 
 // IsContract returns is the contract
 func (ctd *ContextData) IsContract(addr common.Address) bool {
+	if as := ctd.access(); as != nil {
+		as.contract[addr] = true
+	}
 	if _, has := ctd.ContractDefineMap[addr]; has {
 		ctd.size += 20 // uint32(common.Sizeof(reflect.TypeOf(addr)))
 		return true
@@ -175,19 +202,34 @@ func (ctd *ContextData) IsContract(addr common.Address) bool {
 
 // Contract returns the contract
 func (ctd *ContextData) Contract(addr common.Address) (Contract, error) {
+	if as := ctd.access(); as != nil {
+		as.contract[addr] = true
+	}
+	var cont Contract
+	var err error
 	if cd, has := ctd.ContractDefineMap[addr]; has {
 		ctd.size += 20 // uint32(common.Sizeof(reflect.TypeOf(addr)))
-		return CreateContract(cd)
+		cont, err = CreateContract(cd)
 	} else if ctd.Parent != nil {
 		return ctd.Parent.Contract(addr)
 	} else {
 		ctd.size += 20 // uint32(common.Sizeof(reflect.TypeOf(addr)))
-		return ctd.cache.Contract(addr)
+		cont, err = ctd.cache.Contract(addr)
 	}
+	if err != nil {
+		return nil, err
+	}
+	if err := ctd.access().checkContract(cont); err != nil {
+		return nil, err
+	}
+	return cont, nil
 }
 
 // NextSeq returns the next squence number
 func (ctd *ContextData) NextSeq() uint32 {
+	if as := ctd.access(); as != nil {
+		as.seq = true
+	}
 	ctd.seq++
 	return ctd.seq
 }
@@ -219,6 +261,9 @@ func (ctd *ContextData) DeployContract(sender common.Address, ClassID uint64, Ar
 	if err != nil {
 		return nil, err
 	}
+	if err := ctd.access().checkContract(cont); err != nil {
+		return nil, err
+	}
 	ctd.ContractDefineMap[addr] = cd
 	if err := cont.OnCreate(ctd.cache.ctx.ContractContext(cont, addr), Args); err != nil {
 		return nil, err
@@ -238,6 +283,9 @@ func (ctd *ContextData) DeployContractWithAddress(sender common.Address, ClassID
 	if err != nil {
 		return nil, err
 	}
+	if err := ctd.access().checkContract(cont); err != nil {
+		return nil, err
+	}
 	ctd.ContractDefineMap[addr] = cd
 	if err := cont.OnCreate(ctd.cache.ctx.ContractContext(cont, addr), Args); err != nil {
 		return nil, err
@@ -249,6 +297,9 @@ func (ctd *ContextData) DeployContractWithAddress(sender common.Address, ClassID
 // Data returns the data
 func (ctd *ContextData) Data(cont common.Address, addr common.Address, name []byte) []byte {
 	key := string(cont[:]) + string(addr[:]) + string(name)
+	if as := ctd.access(); as != nil {
+		as.data[key] = true
+	}
 	if _, has := ctd.DeletedDataMap[key]; has {
 		return nil
 	}
@@ -288,6 +339,9 @@ func (ctd *ContextData) Data(cont common.Address, addr common.Address, name []by
 // SetData inserts the data
 func (ctd *ContextData) SetData(cont common.Address, addr common.Address, name []byte, value []byte) {
 	key := string(cont[:]) + string(addr[:]) + string(name)
+	if as := ctd.access(); as != nil && addr == common.ZeroAddr {
+		as.write(key, value)
+	}
 	ctd.size += uint64(len(key))
 	if len(value) == 0 {
 		delete(ctd.DataMap, key)
@@ -342,6 +396,9 @@ func (ctd *ContextData) UseTimeSlot(slot uint32, key string) error {
 
 // Seq returns the number of txs using the UseSeq flag of the address.
 func (ctd *ContextData) AddrSeq(addr common.Address) uint64 {
+	if as := ctd.access(); as != nil {
+		as.addrSeq[addr] = true
+	}
 	var seq uint64
 	var has bool
 	if seq, has = ctd.AddrSeqMap[addr]; has {
@@ -361,6 +418,9 @@ func (ctd *ContextData) AddrSeq(addr common.Address) uint64 {
 
 // AddSeq update the sequence of the target address
 func (ctd *ContextData) AddAddrSeq(addr common.Address) {
+	if as := ctd.access(); as != nil {
+		as.addrSeq[addr] = true
+	}
 	if val, has := ctd.AddrSeqMap[addr]; !has {
 		ctd.size += 28 // addr + uint64
 		ctd.AddrSeqMap[addr] = val + 1
@@ -371,6 +431,9 @@ func (ctd *ContextData) AddAddrSeq(addr common.Address) {
 
 // SetNonce update the sequence(nonce) of the target address
 func (ctd *ContextData) SetNonce(addr common.Address, nonce uint64) {
+	if as := ctd.access(); as != nil {
+		as.addrSeq[addr] = true
+	}
 	if _, has := ctd.AddrSeqMap[addr]; !has {
 		ctd.size += 28 // addr + uint64
 		ctd.AddrSeqMap[addr] = nonce
@@ -386,6 +449,9 @@ func (ctd *ContextData) UnsafeBasicFee() *amount.Amount {
 
 // BasicFee returns the basic fee
 func (ctd *ContextData) BasicFee() *amount.Amount {
+	if as := ctd.access(); as != nil {
+		as.basicFee = true
+	}
 	if ctd.basicFee != nil {
 		return ctd.basicFee
 	}
@@ -0,0 +1,87 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	etypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/hash"
+)
+
+// NativeLogVersion is the chain version which writes the logs of the native contracts to the receipt of the transaction
+const NativeLogVersion = uint16(4)
+
+// topics of the standard token events
+var (
+	TransferEventTopic       = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+	ApprovalEventTopic       = crypto.Keccak256Hash([]byte("Approval(address,address,uint256)"))
+	ApprovalForAllEventTopic = crypto.Keccak256Hash([]byte("ApprovalForAll(address,address,bool)"))
+)
+
+// AddressTopic returns the indexed topic of the address
+func AddressTopic(addr common.Address) hash.Hash256 {
+	var h hash.Hash256
+	copy(h[hash.HashLength-common.AddressLength:], addr[:])
+	return h
+}
+
+// Uint256Topic returns the indexed topic of the uint256 value
+func Uint256Topic(v *big.Int) hash.Hash256 {
+	var h hash.Hash256
+	copy(h[:], Uint256Data(v))
+	return h
+}
+
+// Uint256Data returns the abi encoded data of the uint256 value
+func Uint256Data(v *big.Int) []byte {
+	if v == nil {
+		return make([]byte, 32)
+	}
+	return math.U256Bytes(new(big.Int).Set(v))
+}
+
+// AddLog appends the log which is moved to the receipt of the transaction
+func (ctx *Context) AddLog(l *etypes.Log) {
+	ctx.logs = append(ctx.logs, l)
+}
+
+// LogSize returns the count of the logs which are not moved to the receipt yet
+func (ctx *Context) LogSize() int {
+	return len(ctx.logs)
+}
+
+// TakeLogs removes the logs from the index and returns them
+func (ctx *Context) TakeLogs(from int) []*etypes.Log {
+	if from >= len(ctx.logs) {
+		return nil
+	}
+	logs := make([]*etypes.Log, len(ctx.logs)-from)
+	copy(logs, ctx.logs[from:])
+	ctx.logs = ctx.logs[:from]
+	return logs
+}
+
+// AddLog adds the log of the contract to the receipt of the transaction
+// it is ignored before the NativeLogVersion
+func (cc *ContractContext) AddLog(topics []hash.Hash256, data []byte) {
+	if cc.ctx.Version(cc.ctx.TargetHeight()) < NativeLogVersion {
+		return
+	}
+	cc.ctx.AddLog(&etypes.Log{
+		Address: cc.cont,
+		Topics:  topics,
+		Data:    data,
+	})
+}
+
+// EmitTransfer adds the erc20 Transfer log of the contract
+func (cc *ContractContext) EmitTransfer(from common.Address, to common.Address, value *big.Int) {
+	cc.AddLog([]hash.Hash256{TransferEventTopic, AddressTopic(from), AddressTopic(to)}, Uint256Data(value))
+}
+
+// EmitApproval adds the erc20 Approval log of the contract
+func (cc *ContractContext) EmitApproval(owner common.Address, spender common.Address, value *big.Int) {
+	cc.AddLog([]hash.Hash256{ApprovalEventTopic, AddressTopic(owner), AddressTopic(spender)}, Uint256Data(value))
+}
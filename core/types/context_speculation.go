@@ -0,0 +1,336 @@
+package types
+
+import (
+	"bytes"
+	"reflect"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/pkg/errors"
+)
+
+var gConcurrentTypeMap = map[reflect.Type]map[string]bool{}
+
+// SetConcurrentContract marks the contract type which does not share a state out of the context
+//
+// only the transactions calling the concurrent contracts are executed speculatively,
+// additive names are the contract data which are changed only by adding amounts like a total supply.
+// it must be called only at initialization time like RegisterContractType
+func SetConcurrentContract(cont Contract, additive ...[]byte) {
+	names := map[string]bool{}
+	for _, name := range additive {
+		names[string(name)] = true
+	}
+	gConcurrentTypeMap[contractType(cont)] = names
+}
+
+func contractType(cont Contract) reflect.Type {
+	rt := reflect.TypeOf(cont)
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	return rt
+}
+
+func isConcurrentContract(cont Contract) bool {
+	_, has := gConcurrentTypeMap[contractType(cont)]
+	return has
+}
+
+// accessSet is the state which is read by a speculative context
+type accessSet struct {
+	data      map[string]bool
+	written   map[string][2]*amount.Amount
+	admin     map[common.Address]bool
+	generator map[common.Address]bool
+	contract  map[common.Address]bool
+	addrSeq   map[common.Address]bool
+	mainToken bool
+	seq       bool
+	basicFee  bool
+	aborted   bool
+}
+
+func newAccessSet() *accessSet {
+	return &accessSet{
+		data:      map[string]bool{},
+		written:   map[string][2]*amount.Amount{},
+		admin:     map[common.Address]bool{},
+		generator: map[common.Address]bool{},
+		contract:  map[common.Address]bool{},
+		addrSeq:   map[common.Address]bool{},
+	}
+}
+
+// checkContract aborts the speculation when the contract is not a concurrent class
+func (as *accessSet) checkContract(cont Contract) error {
+	if as == nil || isConcurrentContract(cont) {
+		return nil
+	}
+	as.aborted = true
+	return errors.WithStack(ErrSpeculationAborted)
+}
+
+// abort aborts the speculation, it is used before the evm execution
+func (as *accessSet) abort() error {
+	if as == nil {
+		return nil
+	}
+	as.aborted = true
+	return errors.WithStack(ErrSpeculationAborted)
+}
+
+// write keeps the range of the values written to the contract data to rebase the additive data
+func (as *accessSet) write(key string, value []byte) {
+	v := amount.NewAmountFromBytes(value)
+	if r, has := as.written[key]; !has {
+		as.written[key] = [2]*amount.Amount{v, v}
+	} else if v.Less(r[0]) {
+		as.written[key] = [2]*amount.Amount{v, r[1]}
+	} else if r[1].Less(v) {
+		as.written[key] = [2]*amount.Amount{r[0], v}
+	}
+}
+
+// validate returns the state read by the speculation through sbase is same with base
+//
+// the changed additive data are rebased on base, it returns the rebased values which replace the values written by ctd
+func (as *accessSet) validate(base *ContextData, sbase *ContextData, ctd *ContextData) (map[string][]byte, bool) {
+	if as.mainToken {
+		a, b := base.mainToken, sbase.mainToken
+		if (a == nil) != (b == nil) || (a != nil && *a != *b) {
+			return nil, false
+		}
+	}
+	if as.seq && base.seq != sbase.seq {
+		return nil, false
+	}
+	if as.basicFee && base.peekBasicFee().Cmp(sbase.peekBasicFee().Int) != 0 {
+		return nil, false
+	}
+	for addr := range as.addrSeq {
+		a, ha := base.AddrSeqMap[addr]
+		b, hb := sbase.AddrSeqMap[addr]
+		if ha != hb || a != b {
+			return nil, false
+		}
+	}
+	for addr := range as.contract {
+		_, ha := base.ContractDefineMap[addr]
+		_, hb := sbase.ContractDefineMap[addr]
+		if ha || hb {
+			return nil, false
+		}
+	}
+	for addr := range as.admin {
+		if base.peekAdmin(addr) != sbase.peekAdmin(addr) {
+			return nil, false
+		}
+	}
+	for addr := range as.generator {
+		if base.peekGenerator(addr) != sbase.peekGenerator(addr) {
+			return nil, false
+		}
+	}
+	rebased := map[string][]byte{}
+	for key := range as.data {
+		a, b := base.peekData(key), sbase.peekData(key)
+		if bytes.Equal(a, b) {
+			continue
+		}
+		value, ok := as.rebaseAdditive(key, a, b, ctd)
+		if !ok {
+			return nil, false
+		}
+		rebased[key] = value
+	}
+	return rebased, true
+}
+
+// rebaseAdditive returns the additive data written by ctd with the changed value of the base
+//
+// the sizes of all written values should not be changed by the rebasing to keep the gas of the speculation
+func (as *accessSet) rebaseAdditive(key string, base []byte, read []byte, ctd *ContextData) ([]byte, bool) {
+	var cont common.Address
+	copy(cont[:], key)
+	c, has := ctd.cache.ContractMap[cont]
+	if !has || key[common.AddressLength:common.AddressLength*2] != string(common.ZeroAddr[:]) {
+		return nil, false
+	}
+	if !gConcurrentTypeMap[contractType(c)][key[common.AddressLength*2:]] {
+		return nil, false
+	}
+	written, has := ctd.DataMap[key]
+	r, hasRange := as.written[key]
+	if !has || !hasRange {
+		return nil, false
+	}
+	// the byte length is monotonic, so the values between the range keep the size when the bounds keep it
+	shift := amount.NewAmountFromBytes(base).Sub(amount.NewAmountFromBytes(read))
+	if len(r[0].Bytes()) != len(r[1].Bytes()) {
+		return nil, false
+	}
+	for _, v := range r {
+		sv := v.Add(shift)
+		if !sv.IsPlus() || len(sv.Bytes()) != len(v.Bytes()) {
+			return nil, false
+		}
+	}
+	value := amount.NewAmountFromBytes(written).Add(shift)
+	return value.Bytes(), true
+}
+
+// Speculatable returns the transactions of the ctx can be executed on speculative contexts
+//
+// the ctx should be a context of a loader which can be used concurrently,
+// the changes of the ctx are validated when the speculations are applied
+func (ctx *Context) Speculatable() bool {
+	if _, is := ctx.loader.(*Context); is {
+		return false
+	}
+	return len(ctx.stack) == 1
+}
+
+// SpeculativeContext returns a context to execute a transaction concurrently with the ctx
+//
+// it reads the loader of the ctx only, so the state read by it is validated when it is applied by ApplySpeculation
+func (ctx *Context) SpeculativeContext() *Context {
+	sctx := NewContext(ctx.loader)
+	sctx.access = newAccessSet()
+	// the main token is cached after the first transaction of the block
+	sctx.Top().mainToken = sctx.cache.MainToken()
+	return sctx
+}
+
+// ApplySpeculation validates the state read by the speculative context with the context data under the top
+// and applies the changes and the logs of the speculation to the top
+//
+// it returns false without changes when the speculation is aborted or the state is changed by the previous transactions
+func (ctx *Context) ApplySpeculation(sctx *Context) bool {
+	as := sctx.access
+	if as == nil || as.aborted || len(sctx.stack) != 2 || len(ctx.stack) < 2 {
+		return false
+	}
+	ctd := sctx.stack[1]
+	rebased, ok := as.validate(ctx.stack[len(ctx.stack)-2], sctx.stack[0], ctd)
+	if !ok {
+		return false
+	}
+	ctx.isLatestHash = false
+	top := ctx.Top()
+	for key, value := range ctd.AdminMap {
+		top.AdminMap[key] = value
+		delete(top.DeletedAdminMap, key)
+	}
+	for key, value := range ctd.DeletedAdminMap {
+		delete(top.AdminMap, key)
+		top.DeletedAdminMap[key] = value
+	}
+	for key, value := range ctd.AddrSeqMap {
+		top.AddrSeqMap[key] = value
+	}
+	for key, value := range ctd.GeneratorMap {
+		top.GeneratorMap[key] = value
+		delete(top.DeletedGeneratorMap, key)
+	}
+	for key, value := range ctd.DeletedGeneratorMap {
+		delete(top.GeneratorMap, key)
+		top.DeletedGeneratorMap[key] = value
+	}
+	for key, value := range ctd.ContractDefineMap {
+		top.ContractDefineMap[key] = value
+	}
+	for key, value := range ctd.DataMap {
+		delete(top.DeletedDataMap, key)
+		top.DataMap[key] = value
+	}
+	for key, value := range ctd.DeletedDataMap {
+		delete(top.DataMap, key)
+		top.DeletedDataMap[key] = value
+	}
+	for key, value := range rebased {
+		top.DataMap[key] = value
+	}
+	for key, value := range ctd.TimeSlotMap {
+		if tp, has := top.TimeSlotMap[key]; has {
+			for k, v := range value {
+				tp[k] = v
+			}
+		} else {
+			top.TimeSlotMap[key] = value
+		}
+	}
+	// the inherited values are same with the top when they are read by the speculation
+	if as.mainToken {
+		top.mainToken = ctd.mainToken
+	}
+	if as.seq {
+		top.seq = ctd.seq
+	}
+	if ctd.basicFee != nil {
+		top.basicFee = ctd.basicFee
+	}
+	top.size += ctd.size
+	ctx.logs = append(ctx.logs, sctx.logs...)
+	return true
+}
+
+// peekData returns the data like Data without the size and the caching
+func (ctd *ContextData) peekData(key string) []byte {
+	for c := ctd; ; c = c.Parent {
+		if _, has := c.DeletedDataMap[key]; has {
+			return nil
+		}
+		if value, has := c.DataMap[key]; has {
+			return value
+		}
+		if c.Parent == nil {
+			var cont, addr common.Address
+			copy(cont[:], key)
+			copy(addr[:], key[common.AddressLength:])
+			return c.cache.Data(cont, addr, []byte(key[common.AddressLength*2:]))
+		}
+	}
+}
+
+// peekAdmin returns the account is admin or not like IsAdmin without the size
+func (ctd *ContextData) peekAdmin(addr common.Address) bool {
+	for c := ctd; ; c = c.Parent {
+		if _, has := c.DeletedAdminMap[addr]; has {
+			return false
+		}
+		if is, has := c.AdminMap[addr]; has {
+			return is
+		}
+		if c.Parent == nil {
+			return c.cache.IsAdmin(addr)
+		}
+	}
+}
+
+// peekGenerator returns the account is generator or not like IsGenerator without the size
+func (ctd *ContextData) peekGenerator(addr common.Address) bool {
+	for c := ctd; ; c = c.Parent {
+		if _, has := c.DeletedGeneratorMap[addr]; has {
+			return false
+		}
+		if is, has := c.GeneratorMap[addr]; has {
+			return is
+		}
+		if c.Parent == nil {
+			return c.cache.IsGenerator(addr)
+		}
+	}
+}
+
+// peekBasicFee returns the basic fee like BasicFee without the caching
+func (ctd *ContextData) peekBasicFee() *amount.Amount {
+	for c := ctd; ; c = c.Parent {
+		if c.basicFee != nil {
+			return c.basicFee
+		}
+		if c.Parent == nil {
+			return c.cache.BasicFee()
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package types
+
+import (
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/hash"
+)
+
+type transientChange struct {
+	addr common.Address
+	key  hash.Hash256
+	prev hash.Hash256
+}
+
+// transientStorage is the storage of EIP-1153 which is discarded at the end of the transaction
+// the changes are journaled so that the snapshot of the context reverts them
+type transientStorage struct {
+	state   map[common.Address]map[hash.Hash256]hash.Hash256
+	journal []transientChange
+}
+
+func (ts *transientStorage) size() int {
+	if ts == nil {
+		return 0
+	}
+	return len(ts.journal)
+}
+
+func (ts *transientStorage) revert(size int) {
+	if ts == nil {
+		return
+	}
+	for i := len(ts.journal) - 1; i >= size; i-- {
+		c := ts.journal[i]
+		ts.state[c.addr][c.key] = c.prev
+	}
+	if size < len(ts.journal) {
+		ts.journal = ts.journal[:size]
+	}
+}
+
+// TransientState returns the transient value of the key of the contract
+func (ctx *Context) TransientState(addr common.Address, key hash.Hash256) hash.Hash256 {
+	if ctx.transient == nil {
+		return hash.Hash256{}
+	}
+	return ctx.transient.state[addr][key]
+}
+
+// SetTransientState sets the transient value of the key of the contract
+func (ctx *Context) SetTransientState(addr common.Address, key hash.Hash256, value hash.Hash256) {
+	if ctx.transient == nil {
+		ctx.transient = &transientStorage{
+			state: map[common.Address]map[hash.Hash256]hash.Hash256{},
+		}
+	}
+	m, has := ctx.transient.state[addr]
+	if !has {
+		m = map[hash.Hash256]hash.Hash256{}
+		ctx.transient.state[addr] = m
+	}
+	prev := m[key]
+	if prev == value {
+		return
+	}
+	ctx.transient.journal = append(ctx.transient.journal, transientChange{addr: addr, key: key, prev: prev})
+	m[key] = value
+}
+
+// clearTransientAtTxEnd drops the transient storage and the wasm meter when the snapshot of the transaction is finished
+func (ctx *Context) clearTransientAtTxEnd() {
+	if len(ctx.stack) == 1 {
+		ctx.transient = nil
+		ctx.wasmMeter = nil
+	}
+}
@@ -0,0 +1,571 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/core/ctypes"
+	"github.com/pkg/errors"
+)
+
+// NativeABIVersion is the chain version which dispatches the calls from the evm to the native contracts by the contract abi
+const NativeABIVersion = uint16(3)
+
+var (
+	contractABILock sync.Mutex
+	contractABIMap  = map[reflect.Type]*contractABI{}
+
+	typeBigInt   = reflect.TypeOf(&big.Int{})
+	typeAmount   = reflect.TypeOf(&amount.Amount{})
+	typeAddress  = reflect.TypeOf(common.Address{})
+	typeLoader   = reflect.TypeOf((*ContractLoader)(nil)).Elem()
+	typeCC       = reflect.TypeOf(&ContractContext{})
+	typeErrorOut = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+type contractABI struct {
+	json    []byte
+	abi     abi.ABI
+	methods map[string]reflect.Method
+}
+
+// abiArgument is the argument of the solidity abi json
+type abiArgument struct {
+	Name         string        `json:"name"`
+	Type         string        `json:"type"`
+	InternalType string        `json:"internalType,omitempty"`
+	Components   []abiArgument `json:"components,omitempty"`
+}
+
+// abiFunction is the function of the solidity abi json
+type abiFunction struct {
+	Type            string        `json:"type"`
+	Name            string        `json:"name"`
+	Inputs          []abiArgument `json:"inputs"`
+	Outputs         []abiArgument `json:"outputs"`
+	StateMutability string        `json:"stateMutability"`
+}
+
+// ContractABI returns the abi of the native contract which is generated from the front of it
+// methods which have the types that can not be expressed in the abi are omitted
+func ContractABI(cont Contract) (abi.ABI, error) {
+	ca, err := loadContractABI(cont)
+	if err != nil {
+		return abi.ABI{}, err
+	}
+	return ca.abi, nil
+}
+
+// ContractABIJSON returns the solidity abi json of the native contract
+func ContractABIJSON(cont Contract) ([]byte, error) {
+	ca, err := loadContractABI(cont)
+	if err != nil {
+		return nil, err
+	}
+	return ca.json, nil
+}
+
+func loadContractABI(cont Contract) (*contractABI, error) {
+	rt := reflect.TypeOf(cont)
+
+	contractABILock.Lock()
+	defer contractABILock.Unlock()
+
+	if ca, has := contractABIMap[rt]; has {
+		return ca, nil
+	}
+	ca, err := generateContractABI(cont.Front())
+	if err != nil {
+		return nil, err
+	}
+	contractABIMap[rt] = ca
+	return ca, nil
+}
+
+func generateContractABI(front interface{}) (*contractABI, error) {
+	ft := reflect.TypeOf(front)
+	if ft == nil {
+		return nil, errors.WithStack(ErrInvalidContractMethod)
+	}
+	fns := []*abiFunction{}
+	methods := map[string]reflect.Method{}
+	for i := 0; i < ft.NumMethod(); i++ {
+		m := ft.Method(i)
+		fn, err := abiFunctionOf(m)
+		if err != nil {
+			continue
+		}
+		fns = append(fns, fn)
+		methods[fn.Name] = m
+	}
+	bs, err := json.Marshal(fns)
+	if err != nil {
+		return nil, err
+	}
+	a, err := abi.JSON(bytes.NewReader(bs))
+	if err != nil {
+		return nil, err
+	}
+	return &contractABI{
+		json:    bs,
+		abi:     a,
+		methods: methods,
+	}, nil
+}
+
+func abiFunctionOf(m reflect.Method) (*abiFunction, error) {
+	mt := m.Type
+	// receiver and the contract context
+	if mt.NumIn() < 2 {
+		return nil, errors.WithStack(ErrInvalidContractMethod)
+	}
+	fn := &abiFunction{
+		Type:    "function",
+		Name:    strings.ToLower(m.Name[:1]) + m.Name[1:],
+		Inputs:  []abiArgument{},
+		Outputs: []abiArgument{},
+	}
+	switch mt.In(1) {
+	case typeLoader:
+		fn.StateMutability = "view"
+	case typeCC:
+		fn.StateMutability = "nonpayable"
+	default:
+		return nil, errors.WithStack(ErrInvalidContractMethod)
+	}
+	for i := 2; i < mt.NumIn(); i++ {
+		arg, err := abiArgumentOf(fmt.Sprintf("arg%v", i-2), mt.In(i))
+		if err != nil {
+			return nil, err
+		}
+		fn.Inputs = append(fn.Inputs, arg)
+	}
+	for i := 0; i < mt.NumOut(); i++ {
+		if mt.Out(i) == typeErrorOut {
+			continue
+		}
+		arg, err := abiArgumentOf("", mt.Out(i))
+		if err != nil {
+			return nil, err
+		}
+		fn.Outputs = append(fn.Outputs, arg)
+	}
+	return fn, nil
+}
+
+func abiArgumentOf(name string, rt reflect.Type) (abiArgument, error) {
+	arg := abiArgument{Name: name}
+	switch rt {
+	case typeBigInt, typeAmount:
+		arg.Type = "uint256"
+		return arg, nil
+	case typeAddress:
+		arg.Type = "address"
+		return arg, nil
+	}
+	switch rt.Kind() {
+	case reflect.Bool:
+		arg.Type = "bool"
+	case reflect.String:
+		arg.Type = "string"
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		arg.Type = fmt.Sprintf("uint%v", rt.Bits())
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		arg.Type = fmt.Sprintf("int%v", rt.Bits())
+	case reflect.Uint:
+		arg.Type = "uint256"
+	case reflect.Int:
+		arg.Type = "int256"
+	case reflect.Array:
+		if rt.Elem().Kind() == reflect.Uint8 && rt.Len() <= 32 {
+			arg.Type = fmt.Sprintf("bytes%v", rt.Len())
+			return arg, nil
+		}
+		elem, err := abiArgumentOf(name, rt.Elem())
+		if err != nil {
+			return arg, err
+		}
+		elem.Type = fmt.Sprintf("%v[%v]", elem.Type, rt.Len())
+		return elem, nil
+	case reflect.Slice:
+		if rt.Elem().Kind() == reflect.Uint8 {
+			arg.Type = "bytes"
+			return arg, nil
+		}
+		elem, err := abiArgumentOf(name, rt.Elem())
+		if err != nil {
+			return arg, err
+		}
+		elem.Type = elem.Type + "[]"
+		return elem, nil
+	case reflect.Map:
+		key, err := abiArgumentOf("key", rt.Key())
+		if err != nil {
+			return arg, err
+		}
+		value, err := abiArgumentOf("value", rt.Elem())
+		if err != nil {
+			return arg, err
+		}
+		arg.Type = "tuple[]"
+		arg.Components = []abiArgument{key, value}
+	case reflect.Ptr:
+		if rt.Elem().Kind() != reflect.Struct {
+			return arg, errors.Wrap(ErrInvalidArguments, rt.String())
+		}
+		return abiArgumentOf(name, rt.Elem())
+	case reflect.Struct:
+		arg.Type = "tuple"
+		arg.InternalType = "struct " + rt.Name()
+		for i := 0; i < rt.NumField(); i++ {
+			f := rt.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			c, err := abiArgumentOf(strings.ToLower(f.Name[:1])+f.Name[1:], f.Type)
+			if err != nil {
+				return arg, err
+			}
+			arg.Components = append(arg.Components, c)
+		}
+		if len(arg.Components) == 0 {
+			return arg, errors.Wrap(ErrInvalidArguments, rt.String())
+		}
+	default:
+		return arg, errors.Wrap(ErrInvalidArguments, rt.String())
+	}
+	return arg, nil
+}
+
+// toABIValue converts the value of the front method to the go type of the abi type
+func toABIValue(v reflect.Value, t abi.Type) (reflect.Value, error) {
+	target := t.GetType()
+	if !v.IsValid() {
+		return reflect.Value{}, errors.Wrap(ErrInvalidArguments, t.String())
+	}
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			if target == typeBigInt {
+				return reflect.ValueOf(big.NewInt(0)), nil
+			}
+			return reflect.Zero(target), nil
+		}
+		if v.Type() == typeBigInt || v.Type() == typeAmount {
+			break
+		}
+		v = v.Elem()
+	}
+
+	switch t.T {
+	case abi.UintTy, abi.IntTy:
+		bi, err := bigIntOf(v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if target == typeBigInt {
+			return reflect.ValueOf(bi), nil
+		}
+		rv := reflect.New(target).Elem()
+		if err := setIntValue(rv, bi); err != nil {
+			return reflect.Value{}, err
+		}
+		return rv, nil
+	case abi.BoolTy, abi.StringTy, abi.AddressTy, abi.BytesTy, abi.FixedBytesTy:
+		if !v.Type().ConvertibleTo(target) {
+			return reflect.Value{}, errors.Wrapf(ErrInvalidArguments, "%v to %v", v.Type(), t.String())
+		}
+		return v.Convert(target), nil
+	case abi.SliceTy, abi.ArrayTy:
+		var elems []reflect.Value
+		if v.Kind() == reflect.Map {
+			elems = sortedMapEntries(v)
+		} else {
+			for i := 0; i < v.Len(); i++ {
+				elems = append(elems, v.Index(i))
+			}
+		}
+		var rv reflect.Value
+		if t.T == abi.SliceTy {
+			rv = reflect.MakeSlice(target, len(elems), len(elems))
+		} else {
+			if len(elems) != t.Size {
+				return reflect.Value{}, errors.Wrapf(ErrInvalidArguments, "array size %v want %v", len(elems), t.Size)
+			}
+			rv = reflect.New(target).Elem()
+		}
+		for i, e := range elems {
+			ev, err := toABIValue(e, *t.Elem)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			rv.Index(i).Set(ev)
+		}
+		return rv, nil
+	case abi.TupleTy:
+		rv := reflect.New(target).Elem()
+		fields := []reflect.Value{}
+		if entry, ok := v.Interface().(mapEntry); ok {
+			fields = append(fields, entry.key, entry.value)
+		} else if v.Kind() == reflect.Struct {
+			for i := 0; i < v.NumField(); i++ {
+				if v.Type().Field(i).PkgPath == "" {
+					fields = append(fields, v.Field(i))
+				}
+			}
+		}
+		if len(fields) != len(t.TupleElems) {
+			return reflect.Value{}, errors.Wrapf(ErrInvalidArguments, "%v to %v", v.Type(), t.String())
+		}
+		for i, f := range fields {
+			ev, err := toABIValue(f, *t.TupleElems[i])
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			rv.Field(i).Set(ev)
+		}
+		return rv, nil
+	}
+	return reflect.Value{}, errors.Wrap(ErrInvalidArguments, t.String())
+}
+
+// setIntValue sets the big int to the int or uint value and returns an error when it does not fit the kind of the value
+func setIntValue(rv reflect.Value, bi *big.Int) error {
+	switch rv.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if !bi.IsUint64() || rv.OverflowUint(bi.Uint64()) {
+			return errors.Wrapf(ErrInvalidArguments, "%v overflows %v", bi, rv.Type())
+		}
+		rv.SetUint(bi.Uint64())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !bi.IsInt64() || rv.OverflowInt(bi.Int64()) {
+			return errors.Wrapf(ErrInvalidArguments, "%v overflows %v", bi, rv.Type())
+		}
+		rv.SetInt(bi.Int64())
+	default:
+		return errors.Wrapf(ErrInvalidArguments, "%v to %v", bi, rv.Type())
+	}
+	return nil
+}
+
+// fromABIValue converts the unpacked abi value to the param type of the front method
+func fromABIValue(v reflect.Value, rt reflect.Type) (reflect.Value, error) {
+	switch rt {
+	case typeBigInt, typeAmount:
+		bi, err := bigIntOf(v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if rt == typeAmount {
+			if bi.Sign() < 0 {
+				return reflect.Value{}, errors.Wrapf(ErrInvalidArguments, "negative amount %v", bi)
+			}
+			return reflect.ValueOf(amount.NewAmountFromBytes(bi.Bytes())), nil
+		}
+		return reflect.ValueOf(bi), nil
+	}
+
+	switch rt.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		bi, err := bigIntOf(v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		rv := reflect.New(rt).Elem()
+		if err := setIntValue(rv, bi); err != nil {
+			return reflect.Value{}, err
+		}
+		return rv, nil
+	case reflect.Slice, reflect.Array:
+		if v.Type().ConvertibleTo(rt) {
+			return v.Convert(rt), nil
+		}
+		var rv reflect.Value
+		if rt.Kind() == reflect.Slice {
+			rv = reflect.MakeSlice(rt, v.Len(), v.Len())
+		} else {
+			rv = reflect.New(rt).Elem()
+		}
+		for i := 0; i < v.Len() && i < rv.Len(); i++ {
+			ev, err := fromABIValue(v.Index(i), rt.Elem())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			rv.Index(i).Set(ev)
+		}
+		return rv, nil
+	case reflect.Map:
+		rv := reflect.MakeMap(rt)
+		for i := 0; i < v.Len(); i++ {
+			entry := v.Index(i)
+			k, err := fromABIValue(entry.Field(0), rt.Key())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			ev, err := fromABIValue(entry.Field(1), rt.Elem())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			rv.SetMapIndex(k, ev)
+		}
+		return rv, nil
+	case reflect.Ptr:
+		ev, err := fromABIValue(v, rt.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		rv := reflect.New(rt.Elem())
+		rv.Elem().Set(ev)
+		return rv, nil
+	case reflect.Struct:
+		rv := reflect.New(rt).Elem()
+		idx := 0
+		for i := 0; i < rt.NumField(); i++ {
+			if rt.Field(i).PkgPath != "" {
+				continue
+			}
+			ev, err := fromABIValue(v.Field(idx), rt.Field(i).Type)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			rv.Field(i).Set(ev)
+			idx++
+		}
+		return rv, nil
+	}
+	if !v.Type().ConvertibleTo(rt) {
+		return reflect.Value{}, errors.Wrapf(ErrInvalidArguments, "%v to %v", v.Type(), rt)
+	}
+	return v.Convert(rt), nil
+}
+
+func bigIntOf(v reflect.Value) (*big.Int, error) {
+	switch pv := v.Interface().(type) {
+	case *big.Int:
+		return pv, nil
+	case *amount.Amount:
+		return pv.Int, nil
+	}
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(v.Uint()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(v.Int()), nil
+	}
+	return nil, errors.Wrapf(ErrInvalidArguments, "%v to integer", v.Type())
+}
+
+// mapEntry is the key and value of the map which is encoded as a tuple
+type mapEntry struct {
+	key   reflect.Value
+	value reflect.Value
+}
+
+// sortedMapEntries returns the entries of the map ordered by the key so that the encoding is deterministic
+func sortedMapEntries(v reflect.Value) []reflect.Value {
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return mapKeyLess(keys[i], keys[j])
+	})
+	entries := make([]reflect.Value, 0, len(keys))
+	for _, k := range keys {
+		entries = append(entries, reflect.ValueOf(mapEntry{key: k, value: v.MapIndex(k)}))
+	}
+	return entries
+}
+
+func mapKeyLess(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.String:
+		return a.String() < b.String()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return a.Uint() < b.Uint()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	case reflect.Array:
+		for i := 0; i < a.Len(); i++ {
+			if x, y := a.Index(i).Uint(), b.Index(i).Uint(); x != y {
+				return x < y
+			}
+		}
+		return false
+	}
+	return fmt.Sprint(a.Interface()) < fmt.Sprint(b.Interface())
+}
+
+// execNativeABI calls the method of the native contract which is matched with the selector of the input by the contract abi
+// it returns false when the contract abi has not the method so that the caller can fall back to the legacy abi table
+func (s *StateDB) execNativeABI(user common.Address, contAddr common.Address, input []byte, gas uint64) ([]byte, uint64, []*ctypes.Event, bool, error) {
+	cont, err := s.ctx.Contract(contAddr)
+	if err != nil {
+		return nil, 0, nil, false, err
+	}
+	if _, ok := cont.(InvokeableContract); ok {
+		return nil, 0, nil, false, nil
+	}
+	ca, err := loadContractABI(cont)
+	if err != nil {
+		return nil, 0, nil, false, nil
+	}
+	m, err := ca.abi.MethodById(input[:4])
+	if err != nil {
+		return nil, 0, nil, false, nil
+	}
+	fm := ca.methods[m.Name]
+
+	values, err := m.Inputs.UnpackValues(input[4:])
+	if err != nil {
+		return nil, 0, nil, true, err
+	}
+	args := make([]interface{}, len(values))
+	for i, v := range values {
+		rv, err := fromABIValue(reflect.ValueOf(v), fm.Type.In(i+2))
+		if err != nil {
+			return nil, 0, nil, true, err
+		}
+		args[i] = rv.Interface()
+	}
+
+	intr, cc, err := s.getCC(contAddr, user)
+	if err != nil {
+		return nil, 0, nil, true, err
+	}
+	is, err := cc.Exec(cc, contAddr, fm.Name, args)
+	if err != nil {
+		return nil, 0, nil, true, err
+	}
+	if len(is) != fm.Type.NumOut() {
+		return nil, 0, nil, true, errors.Wrapf(ErrInvalidArguments, "output count %v want %v", len(is), fm.Type.NumOut())
+	}
+	outs := make([]interface{}, 0, len(m.Outputs))
+	for i, v := range is {
+		if fm.Type.Out(i) == typeErrorOut {
+			continue
+		}
+		rv, err := toABIValue(reflect.ValueOf(v), m.Outputs[len(outs)].Type)
+		if err != nil {
+			return nil, 0, nil, true, err
+		}
+		outs = append(outs, rv.Interface())
+	}
+	bs, err := m.Outputs.Pack(outs...)
+	if err != nil {
+		return nil, 0, nil, true, err
+	}
+
+	gh := intr.GasHistory()
+	usedGas := gh[0] - 21000
+	if usedGas > gas {
+		return nil, 0, nil, true, errors.New("out of gas")
+	}
+	return bs, gas - usedGas, intr.EventList(), true, nil
+}
@@ -24,4 +24,6 @@ var (
 	ErrInvalidArguments             = errors.New("invalid contract method arguments")
 	ErrConstructorNotAllowd         = errors.New("constructor not allowd")
 	ErrOnlyFormulatorAllowed        = errors.New("only formulator allowed")
+	ErrSpeculationAborted           = errors.New("speculation aborted")
+	ErrBeaconNotAvailable           = errors.New("beacon not available")
 )
@@ -12,6 +12,7 @@ const (
 	EventTagReward      = EventType(0x01)
 	EventTagCallHistory = EventType(0x02)
 	EventTagTxFee       = EventType(0x03)
+	EventTagBeacon      = EventType(0x04)
 )
 
 type EventType uint8
@@ -24,6 +25,8 @@ func (e EventType) String() string {
 		return "EventReward"
 	case EventTagCallHistory:
 		return "EventCallHistory"
+	case EventTagBeacon:
+		return "EventBeacon"
 	}
 	return "Unknow"
 }
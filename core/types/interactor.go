@@ -270,6 +270,9 @@ func (i *interactor) _execEvm(Cc *ContractContext, ContAddr common.Address, Meth
 	// constructor not allowd
 	// contract에서 호출할 경우
 	// 존재하는 method가 아닌 경우 []byte 직접 리턴
+	if err = Cc.ctx.access.abort(); err != nil {
+		return
+	}
 	statedb := NewStateDB(Cc.ctx)
 	if !statedb.IsEvmContract(ContAddr) {
 		err = ErrNotExistContract
@@ -594,6 +597,17 @@ func ContractInputsConv(Args []interface{}, rMethod reflect.Value) ([]reflect.Va
 							// }
 						}
 						param = reflect.ValueOf(as)
+					case "[][]uint8":
+						as := [][]byte{}
+						for _, t := range pv {
+							bs, ok := t.([]byte)
+							if !ok {
+								trfv := reflect.ValueOf(t)
+								return nil, errors.Errorf("invalid input bytes type get %v(%v, %v) want []byte", t, trfv.Type().String(), trfv.Kind().String())
+							}
+							as = append(as, bs)
+						}
+						param = reflect.ValueOf(as)
 					}
 				case []*big.Int:
 					switch mType.String() {
@@ -281,6 +281,21 @@ func (s *StateDB) SetState(addr common.Address, key, value common.Hash) {
 	s.ctx.SetData(addr, common.Address{}, append(tagState, key[:]...), value[:])
 }
 
+// GetTransientState gets transient storage for a given account.
+func (s *StateDB) GetTransientState(addr common.Address, key common.Hash) common.Hash {
+	return s.ctx.TransientState(addr, key)
+}
+
+// SetTransientState sets transient storage for a given account.
+func (s *StateDB) SetTransientState(addr common.Address, key, value common.Hash) {
+	s.ctx.SetTransientState(addr, key, value)
+}
+
+// Version returns the chain version of the height
+func (s *StateDB) Version(h uint32) uint16 {
+	return s.ctx.Version(h)
+}
+
 // SetStorage replaces the entire storage for the specified account with given
 // storage. This function should only be used for debugging.
 func (s *StateDB) SetStorage(addr common.Address, storage map[common.Hash]common.Hash) {
@@ -397,10 +412,31 @@ func (s *StateDB) IsExtContract(addr common.Address) bool {
 
 // Exec executes the method of compiled contract in evm
 func (s *StateDB) Exec(user common.Address, contAddr common.Address, input []byte, gas uint64) ([]byte, uint64, []*ctypes.Event, error) {
+	ls := s.ctx.LogSize()
+	bs, leftGas, evs, err := s.exec(user, contAddr, input, gas)
+	if err != nil {
+		return bs, leftGas, evs, err
+	}
+	for _, l := range s.ctx.TakeLogs(ls) {
+		s.AddLog(l)
+	}
+	return bs, leftGas, evs, nil
+}
+
+func (s *StateDB) exec(user common.Address, contAddr common.Address, input []byte, gas uint64) ([]byte, uint64, []*ctypes.Event, error) {
 	intr, cc, err := s.getCC(contAddr, user)
 	if err != nil {
 		return nil, 0, nil, err
 	}
+	if len(input) < 4 {
+		return nil, 0, nil, errors.New("not exist abi")
+	}
+	if s.ctx.Version(s.ctx.TargetHeight()) >= NativeABIVersion {
+		if bs, leftGas, evs, has, err := s.execNativeABI(user, contAddr, input, gas); has || err != nil {
+			return bs, leftGas, evs, err
+		}
+	}
+
 	method := hex.EncodeToString(input[:4])
 	m := txparser.Abi(method)
 	if m.Name == "" {
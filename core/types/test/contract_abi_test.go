@@ -0,0 +1,174 @@
+package test
+
+import (
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/contract/exchange/trade"
+	"github.com/meverselabs/meverse/contract/formulator"
+	"github.com/meverselabs/meverse/core/chain"
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/meverselabs/meverse/extern/test/util"
+)
+
+type abiItem struct {
+	Owner  common.Address
+	Amount *amount.Amount
+	Tags   []string
+}
+
+type abiTestContract struct {
+	addr   common.Address
+	master common.Address
+}
+
+func (cont *abiTestContract) Name() string            { return "AbiTestContract" }
+func (cont *abiTestContract) Address() common.Address { return cont.addr }
+func (cont *abiTestContract) Master() common.Address  { return cont.master }
+func (cont *abiTestContract) Init(addr common.Address, master common.Address) {
+	cont.addr, cont.master = addr, master
+}
+func (cont *abiTestContract) OnCreate(cc *types.ContractContext, Args []byte) error { return nil }
+func (cont *abiTestContract) OnReward(cc *types.ContractContext, b *types.Block, CountMap map[common.Address]uint32) (map[common.Address]*amount.Amount, error) {
+	return nil, nil
+}
+func (cont *abiTestContract) Front() interface{} { return &abiTestFront{} }
+
+type abiTestFront struct{}
+
+func (f *abiTestFront) Item(cc types.ContractLoader, owner common.Address) (*abiItem, error) {
+	return &abiItem{Owner: owner, Amount: amount.NewAmount(1, 0), Tags: []string{"a", "b"}}, nil
+}
+
+func (f *abiTestFront) Items(cc types.ContractLoader) (map[common.Address]*abiItem, error) {
+	return map[common.Address]*abiItem{
+		common.HexToAddress("0x02"): {Owner: common.HexToAddress("0x02"), Amount: amount.NewAmount(2, 0)},
+		common.HexToAddress("0x01"): {Owner: common.HexToAddress("0x01"), Amount: amount.NewAmount(1, 0)},
+	}, nil
+}
+
+func (f *abiTestFront) Pair(cc types.ContractLoader) ([]*amount.Amount, uint64) {
+	return []*amount.Amount{amount.NewAmount(3, 0), amount.NewAmount(4, 0)}, 77
+}
+
+func (f *abiTestFront) SetValue(cc *types.ContractContext, v *amount.Amount) error {
+	cc.SetContractData([]byte{0x01}, v.Bytes())
+	return nil
+}
+
+func (f *abiTestFront) Value(cc types.ContractLoader) *amount.Amount {
+	return amount.NewAmountFromBytes(cc.ContractData([]byte{0x01}))
+}
+
+func (f *abiTestFront) SetCount(cc *types.ContractContext, count uint) error {
+	cc.SetContractData([]byte{0x02}, big.NewInt(int64(count)).Bytes())
+	return nil
+}
+
+type emptyConstruction struct{}
+
+func (s *emptyConstruction) WriteTo(w io.Writer) (int64, error) { return 0, nil }
+
+func TestContractABIJSON(t *testing.T) {
+	a, err := types.ContractABI(&formulator.FormulatorContract{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, has := a.Methods["formulatorMap"]
+	if !has {
+		t.Fatal("formulatorMap is not exist in the abi")
+	}
+	if m.Outputs[0].Type.String() != "(address,(uint8,uint32,uint256,address,address))[]" {
+		t.Errorf("invalid formulatorMap output %v", m.Outputs[0].Type.String())
+	}
+
+	a, err = types.ContractABI(&trade.UniSwap{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, has = a.Methods["reserves"]
+	if !has {
+		t.Fatal("reserves is not exist in the abi")
+	}
+	if len(m.Outputs) != 2 || m.Outputs[0].Type.String() != "uint256[]" || m.Outputs[1].Type.String() != "uint64" {
+		t.Errorf("invalid reserves outputs %v", m.Outputs)
+	}
+}
+
+func TestEvmCallNativeABI(t *testing.T) {
+	chain.SetVersion(1, types.NativeABIVersion)
+	defer chain.SetVersion(1, 2)
+
+	util.RegisterContractClass(&abiTestContract{}, "AbiTest")
+	tc := util.NewTestContext()
+	contAddr := tc.DeployContract(&abiTestContract{}, &emptyConstruction{})
+
+	a, err := types.ContractABI(&abiTestContract{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exec := func(name string, args ...interface{}) ([]byte, error) {
+		m := a.Methods[name]
+		in, err := m.Inputs.Pack(args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		statedb := types.NewStateDB(tc.Cn.NewContext())
+		bs, _, _, err := statedb.Exec(util.Admin, contAddr, append(m.ID, in...), 10000000)
+		return bs, err
+	}
+	call := func(name string, args ...interface{}) []interface{} {
+		m := a.Methods[name]
+		bs, err := exec(name, args...)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		out, err := m.Outputs.Unpack(bs)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		return out
+	}
+
+	out := call("item", util.Users[0])
+	item := out[0].(struct {
+		Owner  common.Address `json:"owner"`
+		Amount *big.Int       `json:"amount"`
+		Tags   []string       `json:"tags"`
+	})
+	if item.Owner != util.Users[0] || item.Amount.Cmp(amount.NewAmount(1, 0).Int) != 0 || len(item.Tags) != 2 {
+		t.Errorf("invalid item %v", item)
+	}
+
+	out = call("items")
+	if rv := a.Methods["items"].Outputs[0].Type.String(); rv != "(address,(address,uint256,string[]))[]" {
+		t.Errorf("invalid items type %v", rv)
+	}
+	items := out[0].([]struct {
+		Key   common.Address `json:"key"`
+		Value struct {
+			Owner  common.Address `json:"owner"`
+			Amount *big.Int       `json:"amount"`
+			Tags   []string       `json:"tags"`
+		} `json:"value"`
+	})
+	if len(items) != 2 || items[0].Key != common.HexToAddress("0x01") || items[1].Value.Amount.Cmp(amount.NewAmount(2, 0).Int) != 0 {
+		t.Errorf("invalid items %v", items)
+	}
+
+	out = call("pair")
+	if len(out) != 2 || len(out[0].([]*big.Int)) != 2 || out[1].(uint64) != 77 {
+		t.Errorf("invalid pair %v", out)
+	}
+
+	call("setValue", big.NewInt(12345))
+
+	// the uint256 value which does not fit the param is not truncated
+	call("setCount", big.NewInt(7))
+	if _, err := exec("setCount", new(big.Int).Lsh(big.NewInt(1), 70)); err == nil {
+		t.Error("overflowed value is accepted")
+	}
+}
@@ -0,0 +1,100 @@
+package test
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/core/chain"
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/meverselabs/meverse/ethereum/core/defaultevm"
+	"github.com/meverselabs/meverse/ethereum/core/vm"
+	"github.com/meverselabs/meverse/ethereum/params"
+	"github.com/meverselabs/meverse/extern/test/util"
+)
+
+// tstore(0, 42), mstore(0, tload(0)), mcopy(0x20, 0, 0x20), mstore(0x40, blobbasefee()), return(0, 0x60)
+var cancunCode = []byte{
+	0x60, 0x2a, 0x5f, 0x5d,
+	0x5f, 0x5c, 0x5f, 0x52,
+	0x60, 0x20, 0x5f, 0x60, 0x20, 0x5e,
+	0x4a, 0x60, 0x40, 0x52,
+	0x60, 0x60, 0x5f, 0xf3,
+}
+
+// mstore(0, tload(0)), return(0, 0x20)
+var tloadCode = []byte{
+	0x5f, 0x5c, 0x5f, 0x52,
+	0x60, 0x20, 0x5f, 0xf3,
+}
+
+func evmCall(ctx *types.Context, to common.Address) ([]byte, error) {
+	evm := defaultevm.DefaultEVM(types.NewStateDB(ctx), nil)
+	ret, _, err := evm.Call(vm.AccountRef(util.Users[0]), to, nil, 1000000, big.NewInt(0))
+	return ret, err
+}
+
+func TestCancunOpcodes(t *testing.T) {
+	tc := util.NewTestContext()
+	cancunAddr := common.HexToAddress("0xca0c")
+	tloadAddr := common.HexToAddress("0x7100ad")
+	sdb := types.NewStateDB(tc.Ctx)
+	sdb.SetCode(cancunAddr, cancunCode)
+	sdb.SetCode(tloadAddr, tloadCode)
+
+	if _, err := evmCall(tc.Ctx, cancunAddr); err == nil {
+		t.Fatal("cancun opcodes are executed before the fork")
+	}
+
+	chain.SetVersion(1, params.CancunVersion)
+	defer chain.SetVersion(1, 2)
+
+	sn := tc.Ctx.Snapshot()
+	ret, err := evmCall(tc.Ctx, cancunAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := make([]byte, 96)
+	want[31], want[63], want[95] = 42, 42, 1
+	if !bytes.Equal(ret, want) {
+		t.Fatalf("result %x, want %x", ret, want)
+	}
+	// the transient storage is kept in the same transaction
+	if ret, err := evmCall(tc.Ctx, tloadAddr); err != nil {
+		t.Fatal(err)
+	} else if ret[31] != 0 {
+		t.Fatalf("transient storage of the other contract is %x", ret)
+	}
+	if v := tc.Ctx.TransientState(cancunAddr, hash.Hash256{}); v[31] != 42 {
+		t.Fatalf("transient value %v, want 42", v)
+	}
+	tc.Ctx.Commit(sn)
+
+	if v := tc.Ctx.TransientState(cancunAddr, hash.Hash256{}); v != (hash.Hash256{}) {
+		t.Fatalf("transient storage is kept after the transaction %v", v)
+	}
+}
+
+func TestTransientStorageRevert(t *testing.T) {
+	ctx := types.NewEmptyContext()
+	addr := common.HexToAddress("0x01")
+	key := hash.Hash256{1}
+
+	sn := ctx.Snapshot()
+	ctx.SetTransientState(addr, key, hash.Hash256{1})
+	inner := ctx.Snapshot()
+	ctx.SetTransientState(addr, key, hash.Hash256{2})
+	if v := ctx.TransientState(addr, key); v != (hash.Hash256{2}) {
+		t.Fatalf("transient value %v, want 2", v)
+	}
+	ctx.Revert(inner)
+	if v := ctx.TransientState(addr, key); v != (hash.Hash256{1}) {
+		t.Fatalf("transient value %v after the revert, want 1", v)
+	}
+	ctx.Revert(sn)
+	if v := ctx.TransientState(addr, key); v != (hash.Hash256{}) {
+		t.Fatalf("transient value %v after the transaction, want empty", v)
+	}
+}
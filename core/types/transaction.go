@@ -23,6 +23,7 @@ const (
 	Go uint8 = iota
 	Js
 	Evm
+	Wasm
 )
 
 type Transaction struct {
@@ -268,7 +269,9 @@ func GetTxType(ctx *Context, tx *Transaction) (tp uint8, method string) {
 			}
 		}
 	}()
-	if tx.To != common.ZeroAddr {
+	if tx.To == common.ZeroAddr && tx.VmType == Wasm {
+		tp, method = Wasm, tx.Method
+	} else if tx.To != common.ZeroAddr {
 		if tx.IsEtherType {
 			etx := new(etypes.Transaction)
 			if err := etx.UnmarshalBinary(tx.Args); err != nil {
@@ -0,0 +1,26 @@
+package wasmvm
+
+import "errors"
+
+// wasm vm errors
+var (
+	ErrOutOfGas              = errors.New("out of gas")
+	ErrInvalidModule         = errors.New("invalid wasm module")
+	ErrNotAllowedImport      = errors.New("not allowed import")
+	ErrNotExistMemory        = errors.New("not exist memory export")
+	ErrNotExistAlloc         = errors.New("not exist alloc export")
+	ErrNotExistFunction      = errors.New("not exist function")
+	ErrInvalidMemoryAccess   = errors.New("invalid memory access")
+	ErrInvalidExecRequest    = errors.New("invalid exec request")
+	ErrExecDepthExceeded     = errors.New("exec depth exceeded")
+	ErrInvalidFunctionResult = errors.New("invalid function result")
+)
+
+// RevertError is returned when a guest module calls mev.revert
+type RevertError struct {
+	Message string
+}
+
+func (e *RevertError) Error() string {
+	return e.Message
+}
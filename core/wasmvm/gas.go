@@ -0,0 +1,75 @@
+package wasmvm
+
+// gas schedule of the wasm vm
+const (
+	DefaultGasLimit = uint64(50000000)
+
+	GasInstruction  = uint64(1)
+	GasBlock        = uint64(10)
+	GasFunctionCall = uint64(10)
+	GasMemoryPage   = uint64(10000)
+	GasHostCall     = uint64(100)
+	GasPerByte      = uint64(3)
+	GasDataRead     = uint64(200)
+	GasDataWrite    = uint64(5000)
+	GasExec         = uint64(2000)
+)
+
+// Meter counts the gas used by a call chain which is nested by mev.exec
+// it also counts the depth of the running calls of the chain
+type Meter struct {
+	limit     uint64
+	used      uint64
+	depth     int32
+	exhausted bool
+}
+
+// NewMeter returns a Meter
+// the gas limit is capped under the out of gas mark of the gas global
+func NewMeter(limit uint64) *Meter {
+	if limit >= outOfGas {
+		limit = outOfGas - 1
+	}
+	return &Meter{
+		limit: limit,
+	}
+}
+
+// Use charges the gas and panics with ErrOutOfGas when it exceeds the limit
+// the panic is recovered by the runtime and returned from the guest call
+func (m *Meter) Use(gas uint64) {
+	if m.limit-m.used < gas {
+		m.used = m.limit
+		m.exhausted = true
+		panic(ErrOutOfGas)
+	}
+	m.used += gas
+}
+
+// sync loads the left gas from the gas global of the guest module
+func (m *Meter) sync(left uint64) {
+	if m.exhausted {
+		return
+	}
+	if left == outOfGas || left > m.limit {
+		m.used = m.limit
+		m.exhausted = true
+		return
+	}
+	m.used = m.limit - left
+}
+
+// Used returns the used gas
+func (m *Meter) Used() uint64 {
+	return m.used
+}
+
+// Depth returns the count of the running calls of the chain
+func (m *Meter) Depth() int32 {
+	return m.depth
+}
+
+// Left returns the remained gas
+func (m *Meter) Left() uint64 {
+	return m.limit - m.used
+}
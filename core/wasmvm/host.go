@@ -0,0 +1,212 @@
+package wasmvm
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/pkg/errors"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// HostModuleName is the only import module that guest modules can use
+const HostModuleName = "mev"
+
+// Host is the chain surface which guest modules can access through the host module
+type Host interface {
+	ContractAddress() common.Address
+	From() common.Address
+	TargetHeight() uint32
+	LastTimestamp() uint64
+	ContractData(name []byte) []byte
+	SetContractData(name []byte, value []byte)
+	AccountData(addr common.Address, name []byte) []byte
+	SetAccountData(addr common.Address, name []byte, value []byte)
+	Exec(addr common.Address, method string, args []interface{}) ([]interface{}, error)
+}
+
+// ExecRequest is the json request of the mev.exec host function
+type ExecRequest struct {
+	To     common.Address  `json:"to"`
+	Method string          `json:"method"`
+	Args   json.RawMessage `json:"args"`
+}
+
+type stateKey struct{}
+
+type callState struct {
+	host  Host
+	meter *Meter
+	mod   api.Module
+	gas   api.MutableGlobal
+}
+
+// use charges the gas on the meter and the gas global together
+// the guest instructions charge the gas global, so the meter is synced from it before the charge
+func (st *callState) use(gas uint64) {
+	st.meter.sync(st.gas.Get())
+	st.meter.Use(gas)
+	st.gas.Set(st.meter.Left())
+}
+
+func stateFrom(ctx context.Context) *callState {
+	st, _ := ctx.Value(stateKey{}).(*callState)
+	if st == nil {
+		panic(errors.New("wasm call state not found"))
+	}
+	return st
+}
+
+func (st *callState) read(ptr uint32, size uint32) []byte {
+	bs, ok := st.mod.Memory().Read(ptr, size)
+	if !ok {
+		panic(ErrInvalidMemoryAccess)
+	}
+	st.use(uint64(size) * GasPerByte)
+	return append([]byte{}, bs...)
+}
+
+func (st *callState) write(ctx context.Context, bs []byte) uint64 {
+	if len(bs) == 0 {
+		return 0
+	}
+	st.use(uint64(len(bs)) * GasPerByte)
+	alloc := st.mod.ExportedFunction("alloc")
+	if alloc == nil {
+		panic(ErrNotExistAlloc)
+	}
+	res, err := alloc.Call(ctx, uint64(len(bs)))
+	if err != nil {
+		panic(err)
+	}
+	if len(res) == 0 {
+		panic(ErrInvalidFunctionResult)
+	}
+	ptr := uint32(res[0])
+	if !st.mod.Memory().Write(ptr, bs) {
+		panic(ErrInvalidMemoryAccess)
+	}
+	return packPtr(ptr, uint32(len(bs)))
+}
+
+func (st *callState) address(ptr uint32) common.Address {
+	return common.BytesToAddress(st.read(ptr, common.AddressLength))
+}
+
+func packPtr(ptr uint32, size uint32) uint64 {
+	return uint64(ptr)<<32 | uint64(size)
+}
+
+func unpackPtr(v uint64) (uint32, uint32) {
+	return uint32(v >> 32), uint32(v)
+}
+
+func instantiateHostModule(ctx context.Context, rt wazero.Runtime) error {
+	_, err := rt.NewHostModuleBuilder(HostModuleName).
+		NewFunctionBuilder().WithFunc(hostContractAddress).Export("contract_address").
+		NewFunctionBuilder().WithFunc(hostFrom).Export("from").
+		NewFunctionBuilder().WithFunc(hostTargetHeight).Export("target_height").
+		NewFunctionBuilder().WithFunc(hostLastTimestamp).Export("last_timestamp").
+		NewFunctionBuilder().WithFunc(hostContractData).Export("contract_data").
+		NewFunctionBuilder().WithFunc(hostSetContractData).Export("set_contract_data").
+		NewFunctionBuilder().WithFunc(hostAccountData).Export("account_data").
+		NewFunctionBuilder().WithFunc(hostSetAccountData).Export("set_account_data").
+		NewFunctionBuilder().WithFunc(hostExec).Export("exec").
+		NewFunctionBuilder().WithFunc(hostRevert).Export("revert").
+		Instantiate(ctx)
+	return err
+}
+
+func hostContractAddress(ctx context.Context) uint64 {
+	st := stateFrom(ctx)
+	st.use(GasHostCall)
+	addr := st.host.ContractAddress()
+	return st.write(ctx, addr[:])
+}
+
+func hostFrom(ctx context.Context) uint64 {
+	st := stateFrom(ctx)
+	st.use(GasHostCall)
+	addr := st.host.From()
+	return st.write(ctx, addr[:])
+}
+
+func hostTargetHeight(ctx context.Context) uint32 {
+	st := stateFrom(ctx)
+	st.use(GasHostCall)
+	return st.host.TargetHeight()
+}
+
+func hostLastTimestamp(ctx context.Context) uint64 {
+	st := stateFrom(ctx)
+	st.use(GasHostCall)
+	return st.host.LastTimestamp()
+}
+
+func hostContractData(ctx context.Context, namePtr, nameLen uint32) uint64 {
+	st := stateFrom(ctx)
+	st.use(GasDataRead)
+	name := st.read(namePtr, nameLen)
+	return st.write(ctx, st.host.ContractData(name))
+}
+
+func hostSetContractData(ctx context.Context, namePtr, nameLen, valuePtr, valueLen uint32) {
+	st := stateFrom(ctx)
+	st.use(GasDataWrite)
+	name := st.read(namePtr, nameLen)
+	value := st.read(valuePtr, valueLen)
+	st.host.SetContractData(name, value)
+}
+
+func hostAccountData(ctx context.Context, addrPtr, namePtr, nameLen uint32) uint64 {
+	st := stateFrom(ctx)
+	st.use(GasDataRead)
+	addr := st.address(addrPtr)
+	name := st.read(namePtr, nameLen)
+	return st.write(ctx, st.host.AccountData(addr, name))
+}
+
+func hostSetAccountData(ctx context.Context, addrPtr, namePtr, nameLen, valuePtr, valueLen uint32) {
+	st := stateFrom(ctx)
+	st.use(GasDataWrite)
+	addr := st.address(addrPtr)
+	name := st.read(namePtr, nameLen)
+	value := st.read(valuePtr, valueLen)
+	st.host.SetAccountData(addr, name, value)
+}
+
+func hostExec(ctx context.Context, reqPtr, reqLen uint32) uint64 {
+	st := stateFrom(ctx)
+	st.use(GasExec)
+	req := &ExecRequest{}
+	if err := json.Unmarshal(st.read(reqPtr, reqLen), req); err != nil {
+		panic(errors.Wrap(ErrInvalidExecRequest, err.Error()))
+	}
+	if req.Method == "" {
+		panic(ErrInvalidExecRequest)
+	}
+	args, err := UnmarshalValues(req.Args)
+	if err != nil {
+		panic(errors.Wrap(ErrInvalidExecRequest, err.Error()))
+	}
+	res, err := st.host.Exec(req.To, req.Method, args)
+	// the nested call has used the gas of the shared meter
+	st.gas.Set(st.meter.Left())
+	if st.meter.exhausted {
+		panic(ErrOutOfGas)
+	}
+	if err != nil {
+		panic(&RevertError{Message: err.Error()})
+	}
+	bs, err := MarshalValues(res)
+	if err != nil {
+		panic(err)
+	}
+	return st.write(ctx, bs)
+}
+
+func hostRevert(ctx context.Context, msgPtr, msgLen uint32) {
+	st := stateFrom(ctx)
+	panic(&RevertError{Message: string(st.read(msgPtr, msgLen))})
+}
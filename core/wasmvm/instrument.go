@@ -0,0 +1,530 @@
+package wasmvm
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+)
+
+// GasGlobalName is the export name of the gas global which is injected by the instrument
+const GasGlobalName = "__mev_gas"
+
+// outOfGas is stored to the gas global by the injected code when the left gas is not enough
+// the gas limit of a call is lower than it so it can not be the left gas
+const outOfGas = ^uint64(0)
+
+// sections of the wasm binary
+const (
+	sectionCustom    = byte(0)
+	sectionType      = byte(1)
+	sectionImport    = byte(2)
+	sectionFunction  = byte(3)
+	sectionGlobal    = byte(6)
+	sectionExport    = byte(7)
+	sectionCode      = byte(10)
+	sectionDataCount = byte(12)
+)
+
+// instrument injects the gas metering to the module
+// it adds the mutable i64 global of the left gas and charges the instructions of each basic block at the start of the block
+// so every loop iteration and every function call consumes the gas by the instruction count and the execution is bounded only by the gas
+// the float instructions are rejected because the nan bits of them are not deterministic between the platforms
+func instrument(code []byte) ([]byte, error) {
+	r := &wasmReader{bs: code}
+	if !bytes.Equal(r.bytes(8), []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}) {
+		return nil, errors.WithStack(ErrInvalidModule)
+	}
+
+	type section struct {
+		id   byte
+		body []byte
+	}
+	sections := []*section{}
+	for r.err == nil && r.len() > 0 {
+		id := r.byte()
+		size := r.u32()
+		sections = append(sections, &section{id: id, body: r.bytes(int(size))})
+	}
+	if r.err != nil {
+		return nil, errors.Wrap(ErrInvalidModule, r.err.Error())
+	}
+
+	globalCount := uint32(0)
+	var typeParams, funcTypes []uint32
+	for _, s := range sections {
+		var err error
+		switch s.id {
+		case sectionType:
+			if typeParams, err = paramCounts(s.body); err != nil {
+				return nil, err
+			}
+		case sectionFunction:
+			sr := &wasmReader{bs: s.body}
+			for i, n := uint32(0), sr.u32(); i < n && sr.err == nil; i++ {
+				funcTypes = append(funcTypes, sr.u32())
+			}
+			if sr.err != nil {
+				return nil, errors.Wrap(ErrInvalidModule, sr.err.Error())
+			}
+		case sectionImport:
+			n, err := importedGlobals(s.body)
+			if err != nil {
+				return nil, err
+			}
+			globalCount += n
+		case sectionGlobal:
+			sr := &wasmReader{bs: s.body}
+			globalCount += sr.u32()
+			if sr.err != nil {
+				return nil, errors.Wrap(ErrInvalidModule, sr.err.Error())
+			}
+		}
+	}
+	funcParams := make([]uint32, len(funcTypes))
+	for i, t := range funcTypes {
+		if int(t) >= len(typeParams) {
+			return nil, errors.Wrap(ErrInvalidModule, "function type")
+		}
+		funcParams[i] = typeParams[t]
+	}
+
+	var err error
+	hasGlobal, hasExport := false, false
+	for _, s := range sections {
+		switch s.id {
+		case sectionGlobal:
+			s.body = appendVec(s.body, gasGlobal())
+			hasGlobal = true
+		case sectionExport:
+			if s.body, err = appendGasExport(s.body, globalCount); err != nil {
+				return nil, err
+			}
+			hasExport = true
+		case sectionCode:
+			if s.body, err = instrumentCode(s.body, globalCount, funcParams); err != nil {
+				return nil, err
+			}
+		}
+	}
+	insert := func(id byte, body []byte) {
+		at := len(sections)
+		for i, s := range sections {
+			if s.id != sectionCustom && sectionOrder(s.id) > sectionOrder(id) {
+				at = i
+				break
+			}
+		}
+		sections = append(sections[:at], append([]*section{{id: id, body: body}}, sections[at:]...)...)
+	}
+	if !hasGlobal {
+		insert(sectionGlobal, appendVec([]byte{0x00}, gasGlobal()))
+	}
+	if !hasExport {
+		body, err := appendGasExport([]byte{0x00}, globalCount)
+		if err != nil {
+			return nil, err
+		}
+		insert(sectionExport, body)
+	}
+
+	bf := &bytes.Buffer{}
+	bf.Write(code[:8])
+	for _, s := range sections {
+		bf.WriteByte(s.id)
+		bf.Write(encodeU32(uint32(len(s.body))))
+		bf.Write(s.body)
+	}
+	return bf.Bytes(), nil
+}
+
+// sectionOrder returns the order of the section, the data count section is placed before the code section
+func sectionOrder(id byte) int {
+	if id == sectionDataCount {
+		return int(sectionCode)*2 - 1
+	}
+	return int(id) * 2
+}
+
+// paramCounts returns the count of the params of each function type
+func paramCounts(body []byte) ([]uint32, error) {
+	r := &wasmReader{bs: body}
+	counts := []uint32{}
+	for i, n := uint32(0), r.u32(); i < n && r.err == nil; i++ {
+		if r.byte() != 0x60 {
+			return nil, errors.Wrap(ErrInvalidModule, "function type")
+		}
+		params := r.u32()
+		r.bytes(int(params))
+		r.bytes(int(r.u32()))
+		counts = append(counts, params)
+	}
+	if r.err != nil {
+		return nil, errors.Wrap(ErrInvalidModule, r.err.Error())
+	}
+	return counts, nil
+}
+
+func importedGlobals(body []byte) (uint32, error) {
+	r := &wasmReader{bs: body}
+	count := uint32(0)
+	for i, n := uint32(0), r.u32(); i < n && r.err == nil; i++ {
+		r.name()
+		r.name()
+		switch r.byte() {
+		case 0x00: // func
+			r.u32()
+		case 0x01: // table
+			r.byte()
+			r.limits()
+		case 0x02: // memory
+			r.limits()
+		case 0x03: // global
+			r.byte()
+			r.byte()
+			count++
+		default:
+			return 0, errors.Wrap(ErrInvalidModule, "import kind")
+		}
+	}
+	if r.err != nil {
+		return 0, errors.Wrap(ErrInvalidModule, r.err.Error())
+	}
+	return count, nil
+}
+
+// gasGlobal returns the global entry of the left gas, (mut i64) initialized by zero
+func gasGlobal() []byte {
+	return []byte{0x7e, 0x01, 0x42, 0x00, 0x0b}
+}
+
+func appendGasExport(body []byte, index uint32) ([]byte, error) {
+	r := &wasmReader{bs: body}
+	for i, n := uint32(0), r.u32(); i < n && r.err == nil; i++ {
+		if r.name() == GasGlobalName {
+			return nil, errors.Wrap(ErrInvalidModule, "reserved export "+GasGlobalName)
+		}
+		r.byte()
+		r.u32()
+	}
+	if r.err != nil {
+		return nil, errors.Wrap(ErrInvalidModule, r.err.Error())
+	}
+	entry := append(encodeU32(uint32(len(GasGlobalName))), GasGlobalName...)
+	entry = append(entry, 0x03)
+	entry = append(entry, encodeU32(index)...)
+	return appendVec(body, entry), nil
+}
+
+// appendVec appends the entry to the vector and increases the count of it
+func appendVec(body []byte, entry []byte) []byte {
+	r := &wasmReader{bs: body}
+	n := r.u32()
+	bs := append(encodeU32(n+1), r.bs[r.pos:]...)
+	return append(bs, entry...)
+}
+
+func instrumentCode(body []byte, gasIndex uint32, funcParams []uint32) ([]byte, error) {
+	r := &wasmReader{bs: body}
+	n := r.u32()
+	if int(n) != len(funcParams) {
+		return nil, errors.Wrap(ErrInvalidModule, "code count")
+	}
+	bf := &bytes.Buffer{}
+	bf.Write(encodeU32(n))
+	for i := uint32(0); i < n && r.err == nil; i++ {
+		size := r.u32()
+		fn, err := instrumentFunc(r.bytes(int(size)), gasIndex, funcParams[i])
+		if err != nil {
+			return nil, err
+		}
+		bf.Write(encodeU32(uint32(len(fn))))
+		bf.Write(fn)
+	}
+	if r.err != nil {
+		return nil, errors.Wrap(ErrInvalidModule, r.err.Error())
+	}
+	return bf.Bytes(), nil
+}
+
+// instrumentFunc splits the function body by the control instructions and charges each block at the start of it
+// a block ends at block, loop, if, else, end, br, br_if, br_table, return and unreachable, so the start of a loop body is always charged
+// each block is charged GasBlock more for the injected code itself
+// memory.grow is charged by the page operand of it, so the function which uses it gets two locals for the injected code
+func instrumentFunc(fn []byte, gasIndex uint32, params uint32) ([]byte, error) {
+	r := &wasmReader{bs: fn}
+	entries := r.u32()
+	decls := r.pos
+	locals := uint64(params)
+	for i := uint32(0); i < entries && r.err == nil; i++ {
+		locals += uint64(r.u32())
+		r.byte()
+	}
+	if r.err != nil {
+		return nil, errors.Wrap(ErrInvalidModule, r.err.Error())
+	}
+	if locals+2 > uint64(^uint32(0)) {
+		return nil, errors.Wrap(ErrInvalidModule, "too many locals")
+	}
+	pageLocal, costLocal := uint32(locals), uint32(locals+1)
+	header := fn[:r.pos]
+
+	bf := &bytes.Buffer{}
+	grows := []int{}
+	hasGrow := false
+	gas := GasFunctionCall
+	start := r.pos
+	for r.len() > 0 {
+		if r.bs[r.pos] == 0x40 { // memory.grow
+			grows = append(grows, r.pos)
+			hasGrow = true
+		}
+		cost, isEnd, err := r.instruction()
+		if err != nil {
+			return nil, err
+		}
+		gas += cost
+		if isEnd {
+			bf.Write(chargeCode(gasIndex, gas+GasBlock))
+			for _, at := range grows {
+				bf.Write(fn[start:at])
+				bf.Write(growCode(gasIndex, pageLocal, costLocal))
+				start = at
+			}
+			bf.Write(fn[start:r.pos])
+			grows = grows[:0]
+			gas = 0
+			start = r.pos
+		}
+	}
+	if start != len(fn) {
+		return nil, errors.Wrap(ErrInvalidModule, "function body is not terminated")
+	}
+	if !hasGrow {
+		return append(append([]byte{}, header...), bf.Bytes()...), nil
+	}
+	locs := append(encodeU32(entries+2), header[decls:]...)
+	locs = append(locs, 0x01, 0x7f, 0x01, 0x7e) // 1 i32, 1 i64
+	return append(locs, bf.Bytes()...), nil
+}
+
+// chargeCode returns the code which subtracts the gas from the gas global or traps after storing outOfGas to it
+func chargeCode(gasIndex uint32, gas uint64) []byte {
+	g := encodeU32(gasIndex)
+	c := encodeS64(int64(gas))
+	bs := []byte{}
+	bs = append(append(append(bs, 0x23), g...), 0x42) // global.get g, i64.const
+	bs = append(append(bs, c...), 0x54, 0x04, 0x40)   // gas, i64.lt_u, if
+	bs = append(bs, 0x42, 0x7f, 0x24)                 // i64.const -1, global.set
+	bs = append(append(bs, g...), 0x00, 0x0b)         // g, unreachable, end
+	bs = append(append(append(bs, 0x23), g...), 0x42) // global.get g, i64.const
+	bs = append(append(bs, c...), 0x7d, 0x24)         // gas, i64.sub, global.set
+	return append(bs, g...)                           // g
+}
+
+// growCode returns the code which charges GasMemoryPage by the page operand of memory.grow
+// the operand is kept in the page local and pushed back for memory.grow
+func growCode(gasIndex uint32, pageLocal uint32, costLocal uint32) []byte {
+	g := encodeU32(gasIndex)
+	p := encodeU32(pageLocal)
+	c := encodeU32(costLocal)
+	bs := append([]byte{0x22}, p...)                                        // local.tee p
+	bs = append(append(bs, 0xad, 0x42), encodeS64(int64(GasMemoryPage))...) // i64.extend_i32_u, i64.const gas
+	bs = append(append(bs, 0x7e, 0x21), c...)                               // i64.mul, local.set c
+	bs = append(append(append(bs, 0x23), g...), 0x20)                       // global.get g, local.get
+	bs = append(append(bs, c...), 0x54, 0x04, 0x40)                         // c, i64.lt_u, if
+	bs = append(bs, 0x42, 0x7f, 0x24)                                       // i64.const -1, global.set
+	bs = append(append(bs, g...), 0x00, 0x0b)                               // g, unreachable, end
+	bs = append(append(append(bs, 0x23), g...), 0x20)                       // global.get g, local.get
+	bs = append(append(bs, c...), 0x7d, 0x24)                               // c, i64.sub, global.set
+	bs = append(append(bs, g...), 0x20)                                     // g, local.get
+	return append(bs, p...)                                                 // p
+}
+
+// wasmReader reads the wasm binary, the first error is kept and the next reads return zero values
+type wasmReader struct {
+	bs  []byte
+	pos int
+	err error
+}
+
+func (r *wasmReader) len() int {
+	return len(r.bs) - r.pos
+}
+
+func (r *wasmReader) byte() byte {
+	if r.err != nil {
+		return 0
+	}
+	if r.pos >= len(r.bs) {
+		r.err = errors.New("unexpected end")
+		return 0
+	}
+	b := r.bs[r.pos]
+	r.pos++
+	return b
+}
+
+func (r *wasmReader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || r.len() < n {
+		r.err = errors.New("unexpected end")
+		return nil
+	}
+	bs := r.bs[r.pos : r.pos+n]
+	r.pos += n
+	return bs
+}
+
+func (r *wasmReader) u32() uint32 {
+	v := uint64(0)
+	for shift := uint(0); shift < 35; shift += 7 {
+		b := r.byte()
+		v |= uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			if v > uint64(^uint32(0)) {
+				r.err = errors.New("u32 overflow")
+			}
+			return uint32(v)
+		}
+	}
+	r.err = errors.New("u32 too long")
+	return 0
+}
+
+// leb skips the signed leb128 of the max bytes
+func (r *wasmReader) leb(max int) {
+	for i := 0; i < max; i++ {
+		if r.byte()&0x80 == 0 {
+			return
+		}
+	}
+	r.err = errors.New("leb too long")
+}
+
+func (r *wasmReader) name() string {
+	return string(r.bytes(int(r.u32())))
+}
+
+func (r *wasmReader) limits() {
+	if r.byte()&0x01 != 0 {
+		r.u32()
+	}
+	r.u32()
+}
+
+func (r *wasmReader) blockType() {
+	switch r.bs[r.pos] {
+	case 0x40, 0x7f, 0x7e, 0x7d, 0x7c, 0x7b, 0x70, 0x6f:
+		r.pos++
+	default:
+		r.leb(5)
+	}
+}
+
+// instruction reads an instruction and returns the gas of it and whether it ends the block
+func (r *wasmReader) instruction() (uint64, bool, error) {
+	op := r.byte()
+	gas := GasInstruction
+	isEnd := false
+	switch {
+	case op == 0x00, op == 0x05, op == 0x0b, op == 0x0f: // unreachable, else, end, return
+		isEnd = true
+	case op == 0x01: // nop
+	case op >= 0x02 && op <= 0x04: // block, loop, if
+		if r.len() == 0 {
+			r.err = errors.New("unexpected end")
+			break
+		}
+		r.blockType()
+		isEnd = true
+	case op == 0x0c, op == 0x0d: // br, br_if
+		r.u32()
+		isEnd = true
+	case op == 0x0e: // br_table
+		for i, n := uint32(0), r.u32(); i <= n && r.err == nil; i++ {
+			r.u32()
+		}
+		isEnd = true
+	case op == 0x10: // call
+		r.u32()
+	case op == 0x11: // call_indirect
+		r.u32()
+		r.u32()
+	case op == 0x1a, op == 0x1b: // drop, select
+	case op == 0x1c: // select t
+		r.bytes(int(r.u32()))
+	case op >= 0x20 && op <= 0x26: // local, global, table get and set
+		r.u32()
+	case isFloatOp(op):
+		return 0, false, errors.Wrapf(ErrInvalidModule, "not allowed float instruction 0x%x", op)
+	case op >= 0x28 && op <= 0x3e: // load, store
+		r.u32()
+		r.u32()
+	case op == 0x3f: // memory.size
+		r.byte()
+	case op == 0x40: // memory.grow, the pages are charged by the injected code
+		r.byte()
+	case op == 0x41: // i32.const
+		r.leb(5)
+	case op == 0x42: // i64.const
+		r.leb(10)
+	case op >= 0x45 && op <= 0xc4: // integer numeric
+	case op == 0xd0: // ref.null
+		r.byte()
+	case op == 0xd1: // ref.is_null
+	case op == 0xd2: // ref.func
+		r.u32()
+	case op == 0xfc:
+		// the saturating truncations are float and the bulk memory and table operations are not metered by the size
+		return 0, false, errors.Wrapf(ErrInvalidModule, "not allowed instruction 0xfc %v", r.u32())
+	default:
+		return 0, false, errors.Wrapf(ErrInvalidModule, "not allowed instruction 0x%x", op)
+	}
+	if r.err != nil {
+		return 0, false, errors.Wrap(ErrInvalidModule, r.err.Error())
+	}
+	return gas, isEnd, nil
+}
+
+// isFloatOp returns the instruction uses the float values or not
+func isFloatOp(op byte) bool {
+	switch {
+	case op == 0x2a, op == 0x2b, op == 0x38, op == 0x39: // f32, f64 load and store
+		return true
+	case op == 0x43, op == 0x44: // f32.const, f64.const
+		return true
+	case op >= 0x5b && op <= 0x66: // f32, f64 comparisons
+		return true
+	case op >= 0x8b && op <= 0xa6: // f32, f64 arithmetics
+		return true
+	case op >= 0xa8 && op <= 0xab, op >= 0xae && op <= 0xbf: // truncations, conversions and reinterpretations
+		return true
+	}
+	return false
+}
+
+func encodeU32(v uint32) []byte {
+	bs := []byte{}
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if v == 0 {
+			return append(bs, b)
+		}
+		bs = append(bs, b|0x80)
+	}
+}
+
+func encodeS64(v int64) []byte {
+	bs := []byte{}
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && b&0x40 == 0) || (v == -1 && b&0x40 != 0) {
+			return append(bs, b)
+		}
+		bs = append(bs, b|0x80)
+	}
+}
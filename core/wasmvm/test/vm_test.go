@@ -0,0 +1,314 @@
+package test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/core/wasmvm"
+	"github.com/meverselabs/meverse/extern/test/util"
+)
+
+type testHost struct {
+	data map[string][]byte
+}
+
+func newTestHost() *testHost {
+	return &testHost{data: map[string][]byte{}}
+}
+
+func (h *testHost) ContractAddress() common.Address { return common.HexToAddress("0x01") }
+func (h *testHost) From() common.Address            { return common.HexToAddress("0x02") }
+func (h *testHost) TargetHeight() uint32            { return 1 }
+func (h *testHost) LastTimestamp() uint64           { return 1 }
+func (h *testHost) ContractData(name []byte) []byte { return h.data[string(name)] }
+func (h *testHost) SetContractData(name []byte, value []byte) {
+	h.data[string(name)] = value
+}
+func (h *testHost) AccountData(addr common.Address, name []byte) []byte {
+	return h.data[addr.String()+string(name)]
+}
+func (h *testHost) SetAccountData(addr common.Address, name []byte, value []byte) {
+	h.data[addr.String()+string(name)] = value
+}
+func (h *testHost) Exec(addr common.Address, method string, args []interface{}) ([]interface{}, error) {
+	return nil, errors.New("exec not supported")
+}
+
+// execHost runs the exec of the guest by the function
+type execHost struct {
+	*testHost
+	exec func() error
+}
+
+func (h *execHost) Exec(addr common.Address, method string, args []interface{}) ([]interface{}, error) {
+	return nil, h.exec()
+}
+
+func invokeFunc(body []byte, imports ...util.WasmImport) []byte {
+	m := &util.WasmModule{
+		Imports: imports,
+		Globals: []int32{1024},
+		Datas:   []util.WasmData{{Offset: 16, Data: []byte("reverted")}},
+	}
+	m.Funcs = []util.WasmFunc{
+		util.WasmBumpAlloc(),
+		{Export: "invoke", Params: []byte{util.WasmI32, util.WasmI32, util.WasmI32, util.WasmI32}, Results: []byte{util.WasmI64}, Body: body},
+		{},
+	}
+	return m.Bytes()
+}
+
+func TestStoreData(t *testing.T) {
+	vm, err := wasmvm.NewVM()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer vm.Close()
+
+	code := util.WasmStoreEngin()
+	host := newTestHost()
+	if _, _, err := vm.Call(host, code, "invoke", wasmvm.NewMeter(wasmvm.DefaultGasLimit), []byte("SetData"), []byte(`["hello"]`)); err != nil {
+		t.Fatal(err)
+	}
+	bs, gas, err := vm.Call(host, code, "invoke", wasmvm.NewMeter(wasmvm.DefaultGasLimit), []byte("GetData"), []byte(`[]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bs) != `["hello"]` {
+		t.Errorf("stored data not matched got %v", string(bs))
+	}
+	if gas == 0 {
+		t.Errorf("gas is not charged")
+	}
+}
+
+func TestOutOfGas(t *testing.T) {
+	vm, err := wasmvm.NewVM()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer vm.Close()
+
+	body := []byte{0x03, 0x40} // loop
+	body = append(body, util.WasmCall(2)...)
+	body = append(body, 0x0c, 0x00, 0x0b) // br 0, end
+	body = append(body, util.WasmI64Const(0)...)
+	code := invokeFunc(body)
+
+	_, gas, err := vm.Call(newTestHost(), code, "invoke", wasmvm.NewMeter(100000), []byte("Spin"), []byte(`[]`))
+	if !errors.Is(err, wasmvm.ErrOutOfGas) {
+		t.Fatalf("out of gas expected got %v", err)
+	}
+	if gas != 100000 {
+		t.Errorf("all gas should be used got %v", gas)
+	}
+}
+
+func TestRevert(t *testing.T) {
+	vm, err := wasmvm.NewVM()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer vm.Close()
+
+	body := append(util.WasmI32Const(16), util.WasmI32Const(8)...)
+	body = append(body, util.WasmCall(0)...)
+	body = append(body, util.WasmI64Const(0)...)
+	code := invokeFunc(body, util.WasmImport{Module: "mev", Name: "revert", Params: []byte{util.WasmI32, util.WasmI32}})
+
+	_, _, err = vm.Call(newTestHost(), code, "invoke", wasmvm.NewMeter(wasmvm.DefaultGasLimit), []byte("Fail"), []byte(`[]`))
+	var rerr *wasmvm.RevertError
+	if !errors.As(err, &rerr) || rerr.Message != "reverted" {
+		t.Fatalf("revert expected got %v", err)
+	}
+}
+
+func TestNotAllowedImport(t *testing.T) {
+	vm, err := wasmvm.NewVM()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer vm.Close()
+
+	code := invokeFunc(util.WasmI64Const(0), util.WasmImport{Module: "wasi_snapshot_preview1", Name: "fd_write", Params: []byte{util.WasmI32, util.WasmI32, util.WasmI32, util.WasmI32}, Results: []byte{util.WasmI32}})
+	if _, err := vm.Compile(code); !errors.Is(err, wasmvm.ErrNotAllowedImport) {
+		t.Fatalf("not allowed import expected got %v", err)
+	}
+}
+
+func TestLoopOutOfGas(t *testing.T) {
+	vm, err := wasmvm.NewVM()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer vm.Close()
+
+	// the loop has no call, so it is stopped only by the gas of the instructions
+	body := []byte{0x03, 0x40, 0x0c, 0x00, 0x0b} // loop, br 0, end
+	body = append(body, util.WasmI64Const(0)...)
+	code := invokeFunc(body)
+
+	for i := 0; i < 3; i++ {
+		_, gas, err := vm.Call(newTestHost(), code, "invoke", wasmvm.NewMeter(1000000), []byte("Spin"), []byte(`[]`))
+		if !errors.Is(err, wasmvm.ErrOutOfGas) {
+			t.Fatalf("out of gas expected got %v", err)
+		}
+		if gas != 1000000 {
+			t.Fatalf("all gas should be used got %v", gas)
+		}
+	}
+}
+
+func TestGasDeterministic(t *testing.T) {
+	vm, err := wasmvm.NewVM()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer vm.Close()
+
+	// counts down the local from 1000
+	body := append(util.WasmI32Const(1000), 0x21, 0x04) // local.set 4
+	body = append(body, 0x03, 0x40, 0x20, 0x04)         // loop, local.get 4
+	body = append(body, util.WasmI32Const(1)...)
+	body = append(body, 0x6b, 0x22, 0x04, 0x0d, 0x00, 0x0b) // i32.sub, local.tee 4, br_if 0, end
+	body = append(body, util.WasmI64Const(0)...)
+	m := &util.WasmModule{Globals: []int32{1024}}
+	m.Funcs = []util.WasmFunc{
+		util.WasmBumpAlloc(),
+		{Export: "invoke", Params: []byte{util.WasmI32, util.WasmI32, util.WasmI32, util.WasmI32}, Results: []byte{util.WasmI64}, Locals: []byte{util.WasmI32}, Body: body},
+	}
+	code := m.Bytes()
+
+	_, gas, err := vm.Call(newTestHost(), code, "invoke", wasmvm.NewMeter(wasmvm.DefaultGasLimit), []byte("Count"), []byte(`[]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gas < 1000*5 {
+		t.Fatalf("loop iterations are not charged got %v", gas)
+	}
+	for i := 0; i < 3; i++ {
+		_, g, err := vm.Call(newTestHost(), code, "invoke", wasmvm.NewMeter(wasmvm.DefaultGasLimit), []byte("Count"), []byte(`[]`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g != gas {
+			t.Fatalf("gas %v is not %v", g, gas)
+		}
+	}
+	if _, _, err := vm.Call(newTestHost(), code, "invoke", wasmvm.NewMeter(gas-1), []byte("Count"), []byte(`[]`)); !errors.Is(err, wasmvm.ErrOutOfGas) {
+		t.Fatalf("out of gas expected got %v", err)
+	}
+}
+
+func TestNotMeteredInstruction(t *testing.T) {
+	vm, err := wasmvm.NewVM()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer vm.Close()
+
+	// memory.fill is not allowed because its cost depends on the size operand
+	body := append(util.WasmI32Const(0), util.WasmI32Const(0)...)
+	body = append(body, util.WasmI32Const(1024)...)
+	body = append(body, 0xfc, 0x0b, 0x00)
+	body = append(body, util.WasmI64Const(0)...)
+	if _, err := vm.Compile(invokeFunc(body)); !errors.Is(err, wasmvm.ErrInvalidModule) {
+		t.Fatalf("invalid module expected got %v", err)
+	}
+}
+
+func TestExecDepth(t *testing.T) {
+	vm, err := wasmvm.NewVM()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer vm.Close()
+
+	code := util.WasmExecModule()
+	req := []byte(`[{"to":"0x0000000000000000000000000000000000000001","method":"Run","args":[]}]`)
+	meter := wasmvm.NewMeter(wasmvm.DefaultGasLimit)
+	deepest := int32(0)
+	var other error
+	host := &execHost{testHost: newTestHost()}
+	host.exec = func() error {
+		if meter.Depth() > deepest {
+			deepest = meter.Depth()
+		}
+		if meter.Depth() == wasmvm.MaxExecDepth {
+			// the call of the other chain is not counted in the depth of this chain
+			_, _, other = vm.Call(newTestHost(), util.WasmStoreEngin(), "invoke", wasmvm.NewMeter(wasmvm.DefaultGasLimit), []byte("GetData"), []byte(`[]`))
+		}
+		_, _, err := vm.Call(host, code, "invoke", meter, []byte("Exec"), req)
+		return err
+	}
+
+	// the chain calls itself until the depth is exceeded
+	_, gas, err := vm.Call(host, code, "invoke", meter, []byte("Exec"), req)
+	if err == nil || !strings.Contains(err.Error(), wasmvm.ErrExecDepthExceeded.Error()) {
+		t.Fatalf("exec depth exceeded expected got %v", err)
+	}
+	if deepest != wasmvm.MaxExecDepth {
+		t.Fatalf("deepest depth %v", deepest)
+	}
+	if other != nil {
+		t.Fatalf("call of the other chain is failed %v", other)
+	}
+	if meter.Depth() != 0 {
+		t.Fatalf("depth %v after the chain", meter.Depth())
+	}
+	if gas != meter.Used() {
+		t.Fatalf("gas %v is not the gas %v of the chain", gas, meter.Used())
+	}
+}
+
+func TestFloatInstruction(t *testing.T) {
+	vm, err := wasmvm.NewVM()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer vm.Close()
+
+	// f32.const 0, drop
+	body := []byte{0x43, 0x00, 0x00, 0x00, 0x00, 0x1a}
+	body = append(body, util.WasmI64Const(0)...)
+	if _, err := vm.Compile(invokeFunc(body)); !errors.Is(err, wasmvm.ErrInvalidModule) {
+		t.Fatalf("invalid module expected got %v", err)
+	}
+
+	// i32.const 0, f64.convert_i32_s, drop
+	body = append(util.WasmI32Const(0), 0xb7, 0x1a)
+	body = append(body, util.WasmI64Const(0)...)
+	if _, err := vm.Compile(invokeFunc(body)); !errors.Is(err, wasmvm.ErrInvalidModule) {
+		t.Fatalf("invalid module expected got %v", err)
+	}
+}
+
+func TestMemoryGrowGas(t *testing.T) {
+	vm, err := wasmvm.NewVM()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer vm.Close()
+
+	grow := func(pages int32) []byte {
+		body := append(util.WasmI32Const(pages), 0x40, 0x00, 0x1a) // memory.grow 0, drop
+		return invokeFunc(append(body, util.WasmI64Const(0)...))
+	}
+	gas := func(pages int32) uint64 {
+		_, gas, err := vm.Call(newTestHost(), grow(pages), "invoke", wasmvm.NewMeter(wasmvm.DefaultGasLimit), []byte("Grow"), []byte(`[]`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return gas
+	}
+	if g1, g3 := gas(1), gas(3); g3-g1 != 2*wasmvm.GasMemoryPage {
+		t.Fatalf("gas of 3 pages %v is not the gas of 1 page %v and 2 pages", g3, g1)
+	}
+
+	// the pages are charged before the grow fails by the limit
+	if _, _, err := vm.Call(newTestHost(), grow(-1), "invoke", wasmvm.NewMeter(wasmvm.DefaultGasLimit), []byte("Grow"), []byte(`[]`)); !errors.Is(err, wasmvm.ErrOutOfGas) {
+		t.Fatalf("out of gas expected got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package wasmvm
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"reflect"
+
+	"github.com/meverselabs/meverse/common/amount"
+)
+
+// MarshalValues encodes contract values to the json array which is passed to guest modules
+// integers and amounts are encoded as decimal strings and bytes as 0x prefixed hex strings
+func MarshalValues(vs []interface{}) ([]byte, error) {
+	if vs == nil {
+		vs = []interface{}{}
+	}
+	return json.Marshal(normalizeValue(vs))
+}
+
+// UnmarshalValues decodes the json array from guest modules to contract values
+// numbers are kept as decimal strings so that the contract method conversion decides the type
+func UnmarshalValues(bs []byte) ([]interface{}, error) {
+	if len(bs) == 0 {
+		return []interface{}{}, nil
+	}
+	v, err := UnmarshalValue(bs)
+	if err != nil {
+		return nil, err
+	}
+	if vs, ok := v.([]interface{}); ok {
+		return vs, nil
+	}
+	return []interface{}{v}, nil
+}
+
+// UnmarshalValue decodes the json value from guest modules
+func UnmarshalValue(bs []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(bs))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return denormalizeValue(v), nil
+}
+
+func normalizeValue(v interface{}) interface{} {
+	switch pv := v.(type) {
+	case nil:
+		return nil
+	case *amount.Amount:
+		if pv == nil || pv.Int == nil {
+			return "0"
+		}
+		return pv.Int.String()
+	case *big.Int:
+		if pv == nil {
+			return "0"
+		}
+		return pv.String()
+	case []byte:
+		return "0x" + hex.EncodeToString(pv)
+	case error:
+		return pv.Error()
+	case []interface{}:
+		rv := make([]interface{}, len(pv))
+		for i, e := range pv {
+			rv[i] = normalizeValue(e)
+		}
+		return rv
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return v
+		}
+		vs := make([]interface{}, rv.Len())
+		for i := range vs {
+			vs[i] = normalizeValue(rv.Index(i).Interface())
+		}
+		return vs
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return v
+		}
+		m := map[string]interface{}{}
+		iter := rv.MapRange()
+		for iter.Next() {
+			m[iter.Key().String()] = normalizeValue(iter.Value().Interface())
+		}
+		return m
+	}
+	return v
+}
+
+func denormalizeValue(v interface{}) interface{} {
+	switch pv := v.(type) {
+	case json.Number:
+		return pv.String()
+	case []interface{}:
+		for i, e := range pv {
+			pv[i] = denormalizeValue(e)
+		}
+		return pv
+	case map[string]interface{}:
+		for k, e := range pv {
+			pv[k] = denormalizeValue(e)
+		}
+		return pv
+	}
+	return v
+}
@@ -0,0 +1,232 @@
+package wasmvm
+
+import (
+	"context"
+	"sync"
+
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/pkg/errors"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// limits of the wasm vm
+const (
+	MaxMemoryPages = uint32(256)
+	MaxCodeSize    = 4 * 1024 * 1024
+	MaxExecDepth   = int32(16)
+)
+
+// VM runs wasm modules in the sandboxed interpreter
+// modules can import only the mev host module so that they can not reach the file system, network or clock of the node
+// the execution is bounded only by the gas which is charged by the instrumented code, so the result does not depend on the speed of the node
+type VM struct {
+	sync.Mutex
+	rt       wazero.Runtime
+	compiled map[hash.Hash256]wazero.CompiledModule
+}
+
+var defaultVM *VM
+var defaultVMOnce sync.Once
+
+// DefaultVM returns the shared VM of the process
+func DefaultVM() *VM {
+	defaultVMOnce.Do(func() {
+		vm, err := NewVM()
+		if err != nil {
+			panic(err)
+		}
+		defaultVM = vm
+	})
+	return defaultVM
+}
+
+// NewVM returns a VM
+func NewVM() (*VM, error) {
+	ctx := context.Background()
+	features := api.CoreFeaturesV1 |
+		api.CoreFeatureSignExtensionOps |
+		api.CoreFeatureMultiValue
+	cfg := wazero.NewRuntimeConfigInterpreter().
+		WithCoreFeatures(features).
+		WithMemoryLimitPages(MaxMemoryPages)
+	rt := wazero.NewRuntimeWithConfig(ctx, cfg)
+	if err := instantiateHostModule(ctx, rt); err != nil {
+		rt.Close(ctx)
+		return nil, err
+	}
+	vm := &VM{
+		rt:       rt,
+		compiled: map[hash.Hash256]wazero.CompiledModule{},
+	}
+	return vm, nil
+}
+
+// Close releases the runtime
+func (vm *VM) Close() error {
+	vm.Lock()
+	defer vm.Unlock()
+
+	vm.compiled = map[hash.Hash256]wazero.CompiledModule{}
+	return vm.rt.Close(context.Background())
+}
+
+// Compile validates the code and caches the compiled module by the code hash
+func (vm *VM) Compile(code []byte) (hash.Hash256, error) {
+	h := hash.Hash(code)
+	if _, err := vm.compile(h, code); err != nil {
+		return hash.Hash256{}, err
+	}
+	return h, nil
+}
+
+func (vm *VM) compile(h hash.Hash256, code []byte) (wazero.CompiledModule, error) {
+	vm.Lock()
+	defer vm.Unlock()
+
+	if cm, has := vm.compiled[h]; has {
+		return cm, nil
+	}
+	if len(code) == 0 || len(code) > MaxCodeSize {
+		return nil, errors.WithStack(ErrInvalidModule)
+	}
+	metered, err := instrument(code)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	cm, err := vm.rt.CompileModule(ctx, metered)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidModule, err.Error())
+	}
+	if err := validateModule(cm); err != nil {
+		cm.Close(ctx)
+		return nil, err
+	}
+	vm.compiled[h] = cm
+	return cm, nil
+}
+
+func validateModule(cm wazero.CompiledModule) error {
+	for _, f := range cm.ImportedFunctions() {
+		if mod, name, _ := f.Import(); mod != HostModuleName {
+			return errors.Wrapf(ErrNotAllowedImport, "%v.%v", mod, name)
+		}
+	}
+	if len(cm.ImportedMemories()) > 0 {
+		return errors.Wrap(ErrNotAllowedImport, "memory")
+	}
+	if _, has := cm.ExportedMemories()["memory"]; !has {
+		return errors.WithStack(ErrNotExistMemory)
+	}
+	if _, has := cm.ExportedFunctions()["alloc"]; !has {
+		return errors.WithStack(ErrNotExistAlloc)
+	}
+	return nil
+}
+
+// HasFunction returns the code exports the function or not
+func (vm *VM) HasFunction(code []byte, name string) bool {
+	cm, err := vm.compile(hash.Hash(code), code)
+	if err != nil {
+		return false
+	}
+	_, has := cm.ExportedFunctions()[name]
+	return has
+}
+
+// Call instantiates the code on a clean memory and calls the exported function
+// each param is written to the guest memory and passed as a (ptr, len) pair
+// the function returns the packed (ptr << 32 | len) of the result bytes or zero
+// the calls can be nested by mev.exec up to MaxExecDepth
+// the nested calls share the meter of the call chain, so the depth and the gas are counted by the chain not by the process
+// it returns the gas used by the call including the nested calls of it
+func (vm *VM) Call(host Host, code []byte, name string, meter *Meter, params ...[]byte) ([]byte, uint64, error) {
+	if meter.depth >= MaxExecDepth {
+		return nil, 0, errors.WithStack(ErrExecDepthExceeded)
+	}
+	meter.depth++
+	defer func() {
+		meter.depth--
+	}()
+
+	cm, err := vm.compile(hash.Hash(code), code)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	start := meter.Used()
+	st := &callState{
+		host:  host,
+		meter: meter,
+	}
+	ctx := context.WithValue(context.Background(), stateKey{}, st)
+
+	res, err := func() (res []byte, err error) {
+		defer func() {
+			if v := recover(); v != nil {
+				if e, ok := v.(error); ok {
+					err = e
+				} else {
+					err = errors.Errorf("%v", v)
+				}
+			}
+		}()
+
+		mod, err := vm.rt.InstantiateModule(ctx, cm, wazero.NewModuleConfig().WithName("").WithStartFunctions())
+		if err != nil {
+			return nil, err
+		}
+		defer mod.Close(context.Background())
+		gas, ok := mod.ExportedGlobal(GasGlobalName).(api.MutableGlobal)
+		if !ok {
+			return nil, errors.Wrap(ErrInvalidModule, "gas global")
+		}
+		gas.Set(st.meter.Left())
+		st.mod = mod
+		st.gas = gas
+		defer func() {
+			st.meter.sync(gas.Get())
+		}()
+
+		fn := mod.ExportedFunction(name)
+		if fn == nil {
+			return nil, errors.Wrap(ErrNotExistFunction, name)
+		}
+		if len(fn.Definition().ParamTypes()) != len(params)*2 {
+			return nil, errors.Wrapf(ErrNotExistFunction, "%v: invalid params count", name)
+		}
+		stack := make([]uint64, 0, len(params)*2)
+		for _, p := range params {
+			ptr, size := unpackPtr(st.write(ctx, p))
+			stack = append(stack, uint64(ptr), uint64(size))
+		}
+		rv, err := fn.Call(ctx, stack...)
+		if err != nil {
+			return nil, err
+		}
+		if len(rv) == 0 || rv[0] == 0 {
+			return nil, nil
+		}
+		if fn.Definition().ResultTypes()[0] != api.ValueTypeI64 {
+			return nil, errors.WithStack(ErrInvalidFunctionResult)
+		}
+		ptr, size := unpackPtr(rv[0])
+		return st.read(ptr, size), nil
+	}()
+	if err != nil {
+		return nil, meter.Used() - start, callError(st, err)
+	}
+	return res, meter.Used() - start, nil
+}
+
+func callError(st *callState, err error) error {
+	var rerr *RevertError
+	if errors.As(err, &rerr) {
+		return rerr
+	}
+	if errors.Is(err, ErrOutOfGas) || st.meter.exhausted {
+		return errors.WithStack(ErrOutOfGas)
+	}
+	return err
+}
@@ -24,10 +24,20 @@ func Transfer(db vm.StateDB, sender, recipient common.Address, amount *big.Int)
 
 // Default EVM with statedb
 func DefaultEVM(statedb istate.IStateDB, tracer vm.EVMLogger) *vm.EVM {
+	return DefaultEVMWithConfig(statedb, ChainConfig(statedb), tracer)
+}
+
+// ChainConfig returns the config of the chain version at the target height of the statedb
+// the shanghai and the cancun forks are enabled from the params.CancunVersion
+func ChainConfig(statedb istate.IStateDB) *params.ChainConfig {
 	config := &params.ChainConfig{
 		ChainID: statedb.ChainID(),
 	}
-	return DefaultEVMWithConfig(statedb, config, tracer)
+	if statedb.Version(statedb.TargetHeight()) >= params.CancunVersion {
+		config.ShanghaiBlock = big.NewInt(0)
+		config.CancunBlock = big.NewInt(0)
+	}
+	return config
 }
 
 // Default EVM with statedb and config
@@ -39,6 +49,7 @@ func DefaultEVMWithConfig(statedb istate.IStateDB, config *params.ChainConfig, t
 		BlockNumber: big.NewInt(int64(statedb.Height())),
 		BaseFee:     new(big.Int),
 		Time:        new(big.Int).SetUint64(statedb.LastTimestamp()),
+		BlobBaseFee: big.NewInt(1), // the chain doesn't have the blob transactions so it is the minimum blob base fee
 	}
 	cfg := vm.Config{
 		EnablePreimageRecording: false,
@@ -36,9 +36,7 @@ func ApplyTransaction(statedb istate.IStateDB, tx *etypes.Transaction) (*etypes.
 	if err != nil {
 		return nil, nil, err
 	}
-	config := &params.ChainConfig{
-		ChainID: statedb.ChainID(),
-	}
+	config := defaultevm.ChainConfig(statedb)
 
 	vmenv := defaultevm.DefaultEVMWithConfig(statedb, config, nil)
 	receipt, err := applyTransaction(msg, config, bc, &author, gp, statedb, blockNumber, blockHash, tx, usedGas, vmenv)
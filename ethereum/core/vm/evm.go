@@ -79,6 +79,7 @@ type BlockContext struct {
 	Difficulty  *big.Int       // Provides information for DIFFICULTY
 	BaseFee     *big.Int       // Provides information for BASEFEE
 	Random      *common.Hash   // Provides information for RANDOM
+	BlobBaseFee *big.Int       // Provides information for BLOBBASEFEE
 }
 
 // TxContext provides the EVM with information about a transaction.
@@ -11,6 +11,7 @@ import (
 type IStateDB interface {
 	ChainID() *big.Int
 	TargetHeight() uint32
+	Version(h uint32) uint16
 	Height() uint32
 	Hash() common.Hash
 	LastTimestamp() uint64
@@ -37,6 +38,8 @@ type IStateDB interface {
 	GetProofByHash(addrHash common.Hash) ([][]byte, error)
 	GetStorageProof(a common.Address, key common.Hash) ([][]byte, error)
 	GetCommittedState(addr common.Address, hash common.Hash) common.Hash
+	GetTransientState(addr common.Address, key common.Hash) common.Hash
+	SetTransientState(addr common.Address, key, value common.Hash)
 	HasSuicided(addr common.Address) bool
 	AddBalance(addr common.Address, amount *big.Int)
 	SubBalance(addr common.Address, amount *big.Int)
@@ -5,3 +5,6 @@ package params
 const (
 	BlockGasLimit uint64 = 5000000 // estimate gas maximum value
 )
+
+// CancunVersion is the chain version from which the shanghai and the cancun opcodes are enabled in the evm
+const CancunVersion = uint16(7)
@@ -14,6 +14,7 @@ import (
 	"github.com/meverselabs/meverse/common/bin"
 	"github.com/meverselabs/meverse/common/hash"
 	"github.com/meverselabs/meverse/common/key"
+	"github.com/meverselabs/meverse/common/vrf"
 
 	"github.com/meverselabs/meverse/contract/token"
 	"github.com/meverselabs/meverse/core/chain"
@@ -118,6 +119,15 @@ func (tc *TestContext) AddBlock(seconds uint64, txs []*types.Transaction, signer
 
 	nextTimestamp := tc.Ctx.LastTimestamp() + (seconds * uint64(time.Second))
 	bc := chain.NewBlockCreator(tc.Cn, tc.Ctx, Generator, TimeoutCount, nextTimestamp, 0)
+	if TargetHeight := tc.Ctx.TargetHeight(); TargetHeight > 1 && tc.Ctx.Version(TargetHeight-1) >= types.BeaconVersion {
+		bn, err := tc.MakeBeacon(TargetHeight - 1)
+		if err != nil {
+			return hash.HexToHash(""), err
+		}
+		if err := bc.SetBeacon(bn); err != nil {
+			return hash.HexToHash(""), err
+		}
+	}
 	var receipts = types.Receipts{}
 	for i, tx := range txs {
 		if tx != nil {
@@ -158,9 +168,22 @@ func (tc *TestContext) AddBlock(seconds uint64, txs []*types.Transaction, signer
 
 	BlockSignHash := bin.MustWriterToHash(blockSign)
 
-	idxes := rand.Perm(len(obkeys))
-	for i := 0; i < len(obkeys)/2+1; i++ {
-		pk := obkeys[idxes[i]]
+	KeyMap, err := tc.Cn.ObserverKeyMap(b.Header.Height)
+	if err != nil {
+		return hash.HexToHash(""), err
+	}
+	signers := []key.Key{}
+	for _, pk := range obkeys {
+		if KeyMap[pk.PublicKey()] {
+			signers = append(signers, pk)
+		}
+	}
+	if len(signers) < len(KeyMap)/2+1 {
+		return hash.HexToHash(""), chain.ErrInvalidSignatureCount
+	}
+	idxes := rand.Perm(len(signers))
+	for i := 0; i < len(KeyMap)/2+1; i++ {
+		pk := signers[idxes[i]]
 		ObSig, err := pk.Sign(BlockSignHash)
 		if err != nil {
 			return hash.HexToHash(""), err
@@ -176,6 +199,43 @@ func (tc *TestContext) AddBlock(seconds uint64, txs []*types.Transaction, signer
 	return LastHash, nil
 }
 
+// MakeBeacon makes the beacon of the height by the vrf outputs of the beacon observers
+func (tc *TestContext) MakeBeacon(Height uint32) (*types.Beacon, error) {
+	keys, err := tc.Cn.BeaconObserverKeys(Height)
+	if err != nil {
+		return nil, err
+	}
+	return tc.MakeBeaconBy(Height, keys)
+}
+
+// MakeBeaconBy makes the beacon of the height by the vrf outputs of the observers of the keys in the order of the keys
+func (tc *TestContext) MakeBeaconBy(Height uint32, keys []common.PublicKey) (*types.Beacon, error) {
+	keyMap := map[common.PublicKey]key.Key{}
+	for _, pk := range obkeys {
+		keyMap[pk.PublicKey()] = pk
+	}
+	seed := types.BeaconSeed(tc.Cn.Provider().ChainID(), Height)
+	bn := &types.Beacon{
+		Height: Height,
+		Proofs: []*types.BeaconProof{},
+	}
+	for _, pubkey := range keys {
+		pk, has := keyMap[pubkey]
+		if !has {
+			return nil, chain.ErrInsufficientBeaconProofs
+		}
+		p, err := vrf.Prove(pk.PrivateKey(), seed)
+		if err != nil {
+			return nil, err
+		}
+		bn.Proofs = append(bn.Proofs, &types.BeaconProof{
+			PublicKey: pubkey,
+			Proof:     p,
+		})
+	}
+	return bn, nil
+}
+
 func (tc *TestContext) resetContext() {
 	tc.Ctx = tc.Cn.NewContext()
 }
@@ -2,6 +2,7 @@ package util
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"reflect"
 	"strings"
@@ -86,6 +87,35 @@ func (tc *TestContext) DeployContract(contType interface{}, contArgs io.WriterTo
 	return tokenAddr
 }
 
+// DeployWasmContract deploys the wasm code by the wasm deploy transaction and returns the address of it
+func (tc *TestContext) DeployWasmContract(mkey key.Key, code []byte, initArgs ...interface{}) (common.Address, error) {
+	args, err := chain.WasmDeployArgs(code, initArgs)
+	if err != nil {
+		return common.ZeroAddr, err
+	}
+	tx := &types.Transaction{
+		ChainID:   ChainID,
+		Version:   2,
+		Timestamp: tc.Ctx.LastTimestamp(),
+		To:        common.ZeroAddr,
+		Method:    "Deploy",
+		Args:      args,
+		VmType:    types.Wasm,
+	}
+	ins, err := tc.MultiSendTx([]*types.Transaction{tx}, []key.Key{mkey})
+	if err != nil {
+		return common.ZeroAddr, err
+	}
+	if len(ins) == 0 {
+		return common.ZeroAddr, errors.New("not deployed")
+	}
+	addr, ok := ins[0].(common.Address)
+	if !ok {
+		return common.ZeroAddr, errors.New("not deployed")
+	}
+	return addr, nil
+}
+
 func (tc *TestContext) MakeToken(name string, symbol string, amt string) common.Address {
 	tokenContArgs := &token.TokenContractConstruction{
 		Name:   name,
@@ -18,8 +18,10 @@ import (
 	"github.com/meverselabs/meverse/contract/external/deployer"
 	"github.com/meverselabs/meverse/contract/external/engin"
 	"github.com/meverselabs/meverse/contract/formulator"
+	"github.com/meverselabs/meverse/contract/forwarder"
 	"github.com/meverselabs/meverse/contract/gateway"
 	"github.com/meverselabs/meverse/contract/token"
+	"github.com/meverselabs/meverse/contract/wasm"
 	"github.com/meverselabs/meverse/contract/whitelist"
 	"github.com/meverselabs/meverse/core/chain"
 	"github.com/meverselabs/meverse/core/types"
@@ -85,6 +87,8 @@ func init() {
 	RegisterContractClass(&engin.EnginContract{}, "EnginContract")
 	RegisterContractClass(&deployer.DeployerContract{}, "DeployerContract")
 	RegisterContractClass(&mappfarm.FarmContract{}, "MappFarm")
+	RegisterContractClass(&wasm.WasmContract{}, "Wasm")
+	RegisterContractClass(&forwarder.ForwarderContract{}, "Forwarder")
 
 	for i := 0; i < 5; i++ {
 		pk, err := key.NewMemoryKeyFromString(ChainID, Obstrs[i])
@@ -122,6 +126,16 @@ func init() {
 	chain.SetVersion(1, 2)
 }
 
+// RegisterObserverKey makes the test chain sign the blocks with the key when it is the observer of the height
+func RegisterObserverKey(k key.Key) {
+	for _, pk := range obkeys {
+		if pk.PublicKey() == k.PublicKey() {
+			return
+		}
+	}
+	obkeys = append(obkeys, k)
+}
+
 func RegisterContractClass(cont types.Contract, className string) uint64 {
 	ClassID, err := types.RegisterContractType(cont)
 	if err != nil {
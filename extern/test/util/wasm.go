@@ -0,0 +1,268 @@
+package util
+
+import (
+	"bytes"
+)
+
+// wasm value types
+const (
+	WasmI32 = byte(0x7f)
+	WasmI64 = byte(0x7e)
+)
+
+// WasmImport is a function imported by the WasmModule
+type WasmImport struct {
+	Module  string
+	Name    string
+	Params  []byte
+	Results []byte
+}
+
+// WasmFunc is a function defined in the WasmModule
+// the index of it is counted after the imports
+type WasmFunc struct {
+	Export  string
+	Params  []byte
+	Results []byte
+	Locals  []byte
+	Body    []byte
+}
+
+// WasmData is a data segment of the WasmModule
+type WasmData struct {
+	Offset int32
+	Data   []byte
+}
+
+// WasmModule builds a small wasm binary for the tests without the wasm tool chain
+// it exports the memory as "memory" and the first global is a mutable i32
+type WasmModule struct {
+	Imports     []WasmImport
+	Funcs       []WasmFunc
+	MemoryPages uint32
+	Globals     []int32
+	Datas       []WasmData
+}
+
+// Bytes returns the binary of the module
+func (m *WasmModule) Bytes() []byte {
+	types := [][]byte{}
+	typeIndex := func(params, results []byte) uint32 {
+		bf := &bytes.Buffer{}
+		bf.WriteByte(0x60)
+		bf.Write(wasmVec(len(params), params))
+		bf.Write(wasmVec(len(results), results))
+		for i, t := range types {
+			if bytes.Equal(t, bf.Bytes()) {
+				return uint32(i)
+			}
+		}
+		types = append(types, bf.Bytes())
+		return uint32(len(types) - 1)
+	}
+
+	imports := &bytes.Buffer{}
+	for _, im := range m.Imports {
+		imports.Write(wasmName(im.Module))
+		imports.Write(wasmName(im.Name))
+		imports.WriteByte(0x00)
+		imports.Write(WasmU32(typeIndex(im.Params, im.Results)))
+	}
+
+	funcs := &bytes.Buffer{}
+	exports := &bytes.Buffer{}
+	exportCount := 1
+	exports.Write(wasmName("memory"))
+	exports.WriteByte(0x02)
+	exports.Write(WasmU32(0))
+	codes := &bytes.Buffer{}
+	for i, f := range m.Funcs {
+		funcs.Write(WasmU32(typeIndex(f.Params, f.Results)))
+		if f.Export != "" {
+			exports.Write(wasmName(f.Export))
+			exports.WriteByte(0x00)
+			exports.Write(WasmU32(uint32(len(m.Imports) + i)))
+			exportCount++
+		}
+		body := &bytes.Buffer{}
+		body.Write(WasmU32(uint32(len(f.Locals))))
+		for _, l := range f.Locals {
+			body.Write(WasmU32(1))
+			body.WriteByte(l)
+		}
+		body.Write(f.Body)
+		body.WriteByte(0x0b)
+		codes.Write(WasmU32(uint32(body.Len())))
+		codes.Write(body.Bytes())
+	}
+
+	globals := &bytes.Buffer{}
+	for _, g := range m.Globals {
+		globals.WriteByte(WasmI32)
+		globals.WriteByte(0x01)
+		globals.Write(WasmI32Const(g))
+		globals.WriteByte(0x0b)
+	}
+
+	datas := &bytes.Buffer{}
+	for _, d := range m.Datas {
+		datas.WriteByte(0x00)
+		datas.Write(WasmI32Const(d.Offset))
+		datas.WriteByte(0x0b)
+		datas.Write(wasmVec(len(d.Data), d.Data))
+	}
+
+	pages := m.MemoryPages
+	if pages == 0 {
+		pages = 1
+	}
+
+	bf := &bytes.Buffer{}
+	bf.Write([]byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00})
+	bf.Write(wasmSection(1, wasmVec(len(types), bytes.Join(types, nil))))
+	if len(m.Imports) > 0 {
+		bf.Write(wasmSection(2, wasmVec(len(m.Imports), imports.Bytes())))
+	}
+	bf.Write(wasmSection(3, wasmVec(len(m.Funcs), funcs.Bytes())))
+	bf.Write(wasmSection(5, wasmVec(1, append([]byte{0x00}, WasmU32(pages)...))))
+	if len(m.Globals) > 0 {
+		bf.Write(wasmSection(6, wasmVec(len(m.Globals), globals.Bytes())))
+	}
+	bf.Write(wasmSection(7, wasmVec(exportCount, exports.Bytes())))
+	bf.Write(wasmSection(10, wasmVec(len(m.Funcs), codes.Bytes())))
+	if len(m.Datas) > 0 {
+		bf.Write(wasmSection(11, wasmVec(len(m.Datas), datas.Bytes())))
+	}
+	return bf.Bytes()
+}
+
+// WasmBumpAlloc is the alloc function which moves the heap pointer of the first global
+func WasmBumpAlloc() WasmFunc {
+	return WasmFunc{
+		Export:  "alloc",
+		Params:  []byte{WasmI32},
+		Results: []byte{WasmI32},
+		Body: []byte{
+			0x23, 0x00, // global.get 0
+			0x23, 0x00, // global.get 0
+			0x20, 0x00, // local.get 0
+			0x6a,       // i32.add
+			0x24, 0x00, // global.set 0
+		},
+	}
+}
+
+// WasmU32 returns the unsigned leb128 encoding of the value
+func WasmU32(v uint32) []byte {
+	bs := []byte{}
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if v != 0 {
+			bs = append(bs, b|0x80)
+		} else {
+			return append(bs, b)
+		}
+	}
+}
+
+// WasmI32Const returns the i32.const instruction of the value
+func WasmI32Const(v int32) []byte {
+	return append([]byte{0x41}, wasmS64(int64(v))...)
+}
+
+// WasmI64Const returns the i64.const instruction of the value
+func WasmI64Const(v int64) []byte {
+	return append([]byte{0x42}, wasmS64(v)...)
+}
+
+// WasmCall returns the call instruction of the function index
+func WasmCall(idx uint32) []byte {
+	return append([]byte{0x10}, WasmU32(idx)...)
+}
+
+func wasmS64(v int64) []byte {
+	bs := []byte{}
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && b&0x40 == 0) || (v == -1 && b&0x40 != 0) {
+			return append(bs, b)
+		}
+		bs = append(bs, b|0x80)
+	}
+}
+
+func wasmName(s string) []byte {
+	return wasmVec(len(s), []byte(s))
+}
+
+func wasmVec(n int, body []byte) []byte {
+	return append(WasmU32(uint32(n)), body...)
+}
+
+func wasmSection(id byte, body []byte) []byte {
+	return append(append([]byte{id}, WasmU32(uint32(len(body)))...), body...)
+}
+
+// WasmStoreEngin returns the engin module which stores the args of invoke when they are not empty
+// and returns the stored args when it is invoked with the empty args
+func WasmStoreEngin() []byte {
+	key := int32(16)
+	m := &WasmModule{
+		Imports: []WasmImport{
+			{Module: "mev", Name: "contract_data", Params: []byte{WasmI32, WasmI32}, Results: []byte{WasmI64}},
+			{Module: "mev", Name: "set_contract_data", Params: []byte{WasmI32, WasmI32, WasmI32, WasmI32}},
+		},
+		Globals: []int32{1024},
+		Datas:   []WasmData{{Offset: key, Data: []byte("d")}},
+	}
+	body := []byte{
+		0x20, 0x03, // local.get 3
+		0x41, 0x02, // i32.const 2
+		0x4b,       // i32.gt_u
+		0x04, 0x7e, // if (result i64)
+	}
+	body = append(body, WasmI32Const(key)...)
+	body = append(body, WasmI32Const(1)...)
+	body = append(body, 0x20, 0x02, 0x20, 0x03) // local.get 2, local.get 3
+	body = append(body, WasmCall(1)...)
+	body = append(body, WasmI64Const(0)...)
+	body = append(body, 0x05) // else
+	body = append(body, WasmI32Const(key)...)
+	body = append(body, WasmI32Const(1)...)
+	body = append(body, WasmCall(0)...)
+	body = append(body, 0x0b) // end
+	m.Funcs = []WasmFunc{
+		WasmBumpAlloc(),
+		{Export: "init_contract", Params: []byte{WasmI32, WasmI32, WasmI32, WasmI32}, Results: []byte{WasmI64}, Body: WasmI64Const(0)},
+		{Export: "update_contract", Params: []byte{WasmI32, WasmI32}, Results: []byte{WasmI64}, Body: WasmI64Const(0)},
+		{Export: "invoke", Params: []byte{WasmI32, WasmI32, WasmI32, WasmI32}, Results: []byte{WasmI64}, Body: body},
+	}
+	return m.Bytes()
+}
+
+// WasmExecModule returns the contract module which passes the first argument of invoke to mev.exec
+// and returns the result of it
+func WasmExecModule() []byte {
+	body := []byte{
+		0x20, 0x02, // local.get 2
+		0x41, 0x01, // i32.const 1
+		0x6a,       // i32.add
+		0x20, 0x03, // local.get 3
+		0x41, 0x02, // i32.const 2
+		0x6b, // i32.sub
+	}
+	body = append(body, WasmCall(0)...)
+	m := &WasmModule{
+		Imports: []WasmImport{
+			{Module: "mev", Name: "exec", Params: []byte{WasmI32, WasmI32}, Results: []byte{WasmI64}},
+		},
+		Globals: []int32{1024},
+		Funcs: []WasmFunc{
+			WasmBumpAlloc(),
+			{Export: "invoke", Params: []byte{WasmI32, WasmI32, WasmI32, WasmI32}, Results: []byte{WasmI64}, Body: body},
+		},
+	}
+	return m.Bytes()
+}
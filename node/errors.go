@@ -21,4 +21,12 @@ var (
 	ErrNotExistObserverPeer         = errors.New("not exist observer peer")
 	ErrNotExistGeneratorPeer        = errors.New("not exist generator peer")
 	ErrActiveGeneratorTimout        = errors.New("timeout for active generator")
+	ErrBundleQueueFull              = errors.New("bundle queue full")
+	ErrExistBundle                  = errors.New("exist bundle")
+	ErrInvalidCommitment            = errors.New("invalid commitment")
+	ErrInvalidRevealOrder           = errors.New("invalid reveal order")
+	ErrFairOrderDisabled            = errors.New("fair order disabled")
+	ErrSealedTxQueueFull            = errors.New("sealed transaction queue full")
+	ErrExistSealedTx                = errors.New("exist sealed transaction")
+	ErrTooLargeSealedTx             = errors.New("too large sealed transaction")
 )
@@ -0,0 +1,292 @@
+package node
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/common/threshold"
+	"github.com/meverselabs/meverse/core/chain"
+	"github.com/meverselabs/meverse/core/types"
+)
+
+// MaxCommitCiphertexts is the limit of the ciphertexts committed by a block
+const MaxCommitCiphertexts = 256
+
+// MaxSealedTxSize is the limit of the ciphertext of a sealed transaction
+const MaxSealedTxSize = chain.MaxTransactionArgsSize + 1024
+
+// fairRevealWindow is the count of the recent reveals which are kept to check the inclusions
+const fairRevealWindow = 20
+
+// Commitment is the ordering of the sealed transactions which is fixed by the generator before the decryption
+//
+// the transactions of the commitment are included at the front of the next block in the committed order
+type Commitment struct {
+	Height      uint32
+	PrevHash    hash.Hash256
+	Ciphertexts []*threshold.Ciphertext
+}
+
+// Hash returns the hash of the commitment
+func (cm *Commitment) Hash() hash.Hash256 {
+	return bin.MustWriterToHash(cm)
+}
+
+func (cm *Commitment) WriteTo(w io.Writer) (int64, error) {
+	sw := bin.NewSumWriter()
+	if sum, err := sw.Uint32(w, cm.Height); err != nil {
+		return sum, err
+	}
+	if sum, err := sw.Hash256(w, cm.PrevHash); err != nil {
+		return sum, err
+	}
+	if sum, err := sw.Uint16(w, uint16(len(cm.Ciphertexts))); err != nil {
+		return sum, err
+	}
+	for _, ct := range cm.Ciphertexts {
+		if sum, err := sw.WriterTo(w, ct); err != nil {
+			return sum, err
+		}
+	}
+	return sw.Sum(), nil
+}
+
+func (cm *Commitment) ReadFrom(r io.Reader) (int64, error) {
+	sr := bin.NewSumReader()
+	if sum, err := sr.Uint32(r, &cm.Height); err != nil {
+		return sum, err
+	}
+	if sum, err := sr.Hash256(r, &cm.PrevHash); err != nil {
+		return sum, err
+	}
+	if Len, sum, err := sr.GetUint16(r); err != nil {
+		return sum, err
+	} else if Len > MaxCommitCiphertexts {
+		return sum, errors.WithStack(ErrInvalidCommitment)
+	} else {
+		cm.Ciphertexts = make([]*threshold.Ciphertext, 0, Len)
+		for i := uint16(0); i < Len; i++ {
+			ct := &threshold.Ciphertext{}
+			if sum, err := sr.ReaderFrom(r, ct); err != nil {
+				return sum, err
+			}
+			cm.Ciphertexts = append(cm.Ciphertexts, ct)
+		}
+	}
+	return sr.Sum(), nil
+}
+
+// Reveal is the commitment with the decryption shares of the observers which voted its block
+//
+// Shares[i] is the shares of an observer in the order of the ciphertexts
+type Reveal struct {
+	Commitment *Commitment
+	Shares     [][]*threshold.DecryptionShare
+}
+
+func (rv *Reveal) WriteTo(w io.Writer) (int64, error) {
+	sw := bin.NewSumWriter()
+	if sum, err := sw.WriterTo(w, rv.Commitment); err != nil {
+		return sum, err
+	}
+	if sum, err := sw.Uint16(w, uint16(len(rv.Shares))); err != nil {
+		return sum, err
+	}
+	for _, dss := range rv.Shares {
+		if sum, err := writeDecryptionShares(sw, w, dss); err != nil {
+			return sum, err
+		}
+	}
+	return sw.Sum(), nil
+}
+
+func (rv *Reveal) ReadFrom(r io.Reader) (int64, error) {
+	sr := bin.NewSumReader()
+	rv.Commitment = &Commitment{}
+	if sum, err := sr.ReaderFrom(r, rv.Commitment); err != nil {
+		return sum, err
+	}
+	if Len, sum, err := sr.GetUint16(r); err != nil {
+		return sum, err
+	} else {
+		rv.Shares = make([][]*threshold.DecryptionShare, 0, Len)
+		for i := uint16(0); i < Len; i++ {
+			dss, sum, err := readDecryptionShares(sr, r)
+			if err != nil {
+				return sum, err
+			}
+			rv.Shares = append(rv.Shares, dss)
+		}
+	}
+	return sr.Sum(), nil
+}
+
+func writeDecryptionShares(sw *bin.SumWriter, w io.Writer, dss []*threshold.DecryptionShare) (int64, error) {
+	if sum, err := sw.Uint16(w, uint16(len(dss))); err != nil {
+		return sum, err
+	}
+	for _, ds := range dss {
+		if sum, err := sw.WriterTo(w, ds); err != nil {
+			return sum, err
+		}
+	}
+	return sw.Sum(), nil
+}
+
+func readDecryptionShares(sr *bin.SumReader, r io.Reader) ([]*threshold.DecryptionShare, int64, error) {
+	Len, sum, err := sr.GetUint16(r)
+	if err != nil {
+		return nil, sum, err
+	}
+	if Len > MaxCommitCiphertexts {
+		return nil, sum, errors.WithStack(ErrInvalidCommitment)
+	}
+	dss := make([]*threshold.DecryptionShare, 0, Len)
+	for i := uint16(0); i < Len; i++ {
+		ds := &threshold.DecryptionShare{}
+		if sum, err := sr.ReaderFrom(r, ds); err != nil {
+			return nil, sum, err
+		}
+		dss = append(dss, ds)
+	}
+	return dss, sr.Sum(), nil
+}
+
+type revealedTx struct {
+	Tx  *types.Transaction
+	Sig common.Signature
+}
+
+type openedReveal struct {
+	reveal *Reveal
+	txs    []*revealedTx
+	txMap  map[hash.Hash256]int
+}
+
+// fairReveals keeps the opened reveals of the recent commitments
+//
+// the reveals are kept only in the memory of the node, so an observer which is restarted
+// does not check the order of the commitments which are revealed before the restart
+type fairReveals struct {
+	pk      *threshold.PublicKey
+	reveals map[uint32]*openedReveal
+}
+
+func newFairReveals(pk *threshold.PublicKey) *fairReveals {
+	return &fairReveals{
+		pk:      pk,
+		reveals: map[uint32]*openedReveal{},
+	}
+}
+
+// add opens the reveal in the committed order, the ciphertexts which are not decrypted or decoded are skipped
+func (fs *fairReveals) add(rv *Reveal) {
+	Height := rv.Commitment.Height
+	if _, has := fs.reveals[Height]; has {
+		return
+	}
+	or := &openedReveal{
+		reveal: rv,
+		txs:    []*revealedTx{},
+		txMap:  map[hash.Hash256]int{},
+	}
+	for i, ct := range rv.Commitment.Ciphertexts {
+		dss := make([]*threshold.DecryptionShare, 0, len(rv.Shares))
+		for _, v := range rv.Shares {
+			if len(v) == len(rv.Commitment.Ciphertexts) {
+				dss = append(dss, v[i])
+			}
+		}
+		bs, err := fs.pk.Combine(ct, dss)
+		if err != nil {
+			continue
+		}
+		tx, sig, err := chain.OpenTransaction(bs)
+		if err != nil {
+			continue
+		}
+		TxHash := tx.HashSig()
+		if _, has := or.txMap[TxHash]; has {
+			continue
+		}
+		or.txMap[TxHash] = len(or.txs)
+		or.txs = append(or.txs, &revealedTx{
+			Tx:  tx,
+			Sig: sig,
+		})
+	}
+	fs.reveals[Height] = or
+	for h := range fs.reveals {
+		if h+fairRevealWindow < Height {
+			delete(fs.reveals, h)
+		}
+	}
+}
+
+// txs returns the revealed transactions of the commitment of the height
+func (fs *fairReveals) txs(Height uint32) ([]*revealedTx, bool) {
+	or, has := fs.reveals[Height]
+	if !has {
+		return nil, false
+	}
+	return or.txs, true
+}
+
+// isRevealed returns the transaction is revealed by the kept commitments
+func (fs *fairReveals) isRevealed(TxHash hash.Hash256) bool {
+	for _, or := range fs.reveals {
+		if _, has := or.txMap[TxHash]; has {
+			return true
+		}
+	}
+	return false
+}
+
+// pending returns the reveals which should be included from the target height
+func (fs *fairReveals) pending(TargetHeight uint32) []*Reveal {
+	rvs := []*Reveal{}
+	if or, has := fs.reveals[TargetHeight-1]; has {
+		rvs = append(rvs, or.reveal)
+	}
+	return rvs
+}
+
+// checkOrder checks the revealed transactions of the previous commitment are at the front of the block in the committed order
+//
+// the guarantee is the order only, not the inclusion:
+// a revealed transaction can be omitted because a failed transaction is not included in the block
+// and the observer cannot tell a failed transaction from a withheld one without executing it,
+// but the omitted transaction cannot be included in the other blocks while its reveal is kept, so it cannot be moved behind the others
+func (fs *fairReveals) checkOrder(b *types.Block) error {
+	target := fs.reveals[b.Header.Height-1]
+	last := -1
+	ended := false
+	for _, tx := range b.Body.Transactions {
+		TxHash := tx.HashSig()
+		for _, or := range fs.reveals {
+			if or == target {
+				continue
+			}
+			if _, has := or.txMap[TxHash]; has {
+				return errors.WithStack(ErrInvalidRevealOrder)
+			}
+		}
+		if target == nil {
+			continue
+		}
+		idx, has := target.txMap[TxHash]
+		if !has {
+			ended = true
+			continue
+		}
+		if ended || idx <= last {
+			return errors.WithStack(ErrInvalidRevealOrder)
+		}
+		last = idx
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package node
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/common/key"
+	"github.com/meverselabs/meverse/common/threshold"
+	"github.com/meverselabs/meverse/core/chain"
+	"github.com/meverselabs/meverse/core/types"
+)
+
+func TestFairOrder(t *testing.T) {
+	ChainID := big.NewInt(0x1D5E)
+	pk, shares, err := threshold.Deal(2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	genKey, err := key.NewMemoryKey(ChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	userKey, err := key.NewMemoryKey(ChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the sealed transactions are committed by the generator before the decryption
+	txs := []*types.Transaction{}
+	cm := &Commitment{
+		Height:   10,
+		PrevHash: hash.Hash([]byte("prev")),
+	}
+	for i := 0; i < 3; i++ {
+		tx := &types.Transaction{
+			ChainID:   ChainID,
+			Timestamp: uint64(i + 1),
+			To:        common.HexToAddress("0x01"),
+			Method:    "Transfer",
+			Args:      bin.TypeWriteAll(common.HexToAddress("0x02"), amount.NewAmount(uint64(i+1), 0)),
+		}
+		sig, err := userKey.Sign(tx.HashSig())
+		if err != nil {
+			t.Fatal(err)
+		}
+		ct, err := chain.SealTransaction(pk, tx, sig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		txs = append(txs, tx)
+		cm.Ciphertexts = append(cm.Ciphertexts, ct)
+	}
+	cmSig, err := genKey.Sign(cm.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	gen := &BlockGenMessage{
+		Block: &types.Block{
+			Header: types.Header{
+				Height:    cm.Height,
+				PrevHash:  cm.PrevHash,
+				Generator: genKey.PublicKey().Address(),
+			},
+		},
+		Commitment:          cm,
+		CommitmentSignature: cmSig,
+	}
+
+	newObserver := func(share *threshold.SecretShare) *ObserverNode {
+		ob := &ObserverNode{ChainID: ChainID}
+		if err := ob.SetFairOrder(pk, share); err != nil {
+			t.Fatal(err)
+		}
+		return ob
+	}
+	ob := newObserver(shares[0])
+	if err := ob.validateFairOrder(gen); err != nil {
+		t.Fatal(err)
+	}
+
+	// the votes of the threshold count reveal the commitment
+	br := NewBlockRound()
+	br.BlockGenMessage = gen
+	for i, ss := range shares[:2] {
+		vt := &BlockVoteMessage{}
+		if i == 0 {
+			vt.DecryptionShares = ob.decryptionShares(gen)
+		} else {
+			vt.DecryptionShares = newObserver(ss).decryptionShares(gen)
+		}
+		k, _ := key.NewMemoryKey(ChainID)
+		br.BlockVoteMap[k.PublicKey()] = vt
+	}
+	rv := ob.buildReveal(br)
+	if rtxs, has := ob.fair.txs(cm.Height); !has || len(rtxs) != len(txs) {
+		t.Fatalf("commitment is not revealed %v", rtxs)
+	}
+	if len(ob.pendingReveals(cm.Height+1)) != 1 {
+		t.Fatal("reveal is not pending for the next block")
+	}
+
+	other := &types.Transaction{ChainID: ChainID, Timestamp: 100, Method: "Other"}
+	block := func(Height uint32, list ...*types.Transaction) *BlockGenMessage {
+		return &BlockGenMessage{Block: &types.Block{
+			Header: types.Header{Height: Height},
+			Body:   types.Body{Transactions: list},
+		}}
+	}
+	next := cm.Height + 1
+	cases := []struct {
+		name  string
+		block *BlockGenMessage
+		valid bool
+	}{
+		{"committed order", block(next, txs[0], txs[1], txs[2], other), true},
+		{"reordered", block(next, txs[1], txs[0], txs[2]), false},
+		{"other tx ahead", block(next, other, txs[0], txs[1], txs[2]), false},
+		{"revealed tx after other tx", block(next, txs[0], other, txs[1]), false},
+		{"omitted tx", block(next, txs[0], txs[2]), true},
+		{"revealed tx in the later block", block(next+1, txs[1]), false},
+	}
+	for _, c := range cases {
+		err := ob.validateFairOrder(c.block)
+		if c.valid && err != nil {
+			t.Errorf("%v: %v", c.name, err)
+		}
+		if !c.valid && errors.Cause(err) != ErrInvalidRevealOrder {
+			t.Errorf("%v: invalid reveal order expected got %v", c.name, err)
+		}
+	}
+
+	// the reveals are kept in the memory, so the observer which is restarted accepts the order until it gets the reveal again
+	restarted := newObserver(shares[0])
+	if err := restarted.validateFairOrder(block(next, txs[1], txs[0])); err != nil {
+		t.Fatalf("restarted observer checks the order without the reveal %v", err)
+	}
+	restarted.fair.add(rv)
+	if err := restarted.validateFairOrder(block(next, txs[1], txs[0])); errors.Cause(err) != ErrInvalidRevealOrder {
+		t.Fatalf("invalid reveal order expected got %v", err)
+	}
+}
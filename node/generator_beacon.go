@@ -0,0 +1,87 @@
+package node
+
+import (
+	"log"
+	"time"
+
+	"github.com/meverselabs/meverse/core/chain"
+	"github.com/meverselabs/meverse/core/types"
+)
+
+// addBeacon keeps the latest valid beacon from the observers
+func (fr *GeneratorNode) addBeacon(bn *types.Beacon) {
+	if bn == nil {
+		return
+	}
+
+	fr.beaconLock.Lock()
+	defer fr.beaconLock.Unlock()
+
+	if fr.beacon != nil && fr.beacon.Height >= bn.Height {
+		return
+	}
+	if _, err := fr.cn.VerifyBeacon(bn); err != nil {
+		if DEBUG {
+			log.Printf("AddBeacon %v %+v\n", bn.Height, err)
+		}
+		return
+	}
+	fr.beacon = bn
+}
+
+// setBeacon includes the beacon of the previous height to the block, the block is not finalized without it when it is required
+func (fr *GeneratorNode) setBeacon(bc *chain.BlockCreator, ctx *types.Context) {
+	fr.beaconLock.Lock()
+	bn := fr.beacon
+	fr.beaconLock.Unlock()
+
+	if bn == nil || bn.Height+1 != ctx.TargetHeight() {
+		return
+	}
+	if err := bc.SetBeacon(bn); err != nil {
+		if DEBUG {
+			log.Printf("SetBeacon %v %+v\n", bn.Height, err)
+		}
+	}
+}
+
+// hasBeacon returns the beacon of the height or a newer one is kept
+func (fr *GeneratorNode) hasBeacon(Height uint32) bool {
+	fr.beaconLock.Lock()
+	defer fr.beaconLock.Unlock()
+
+	return fr.beacon != nil && fr.beacon.Height >= Height
+}
+
+// waitBeacon waits the beacon of the previous block if the next block should include it
+//
+// the generator should be locked, it is unlocked while waiting
+func (fr *GeneratorNode) waitBeacon(prev *BlockGenMessage) {
+	if prev == nil || prev.Block.Header.Version < types.BeaconVersion {
+		return
+	}
+	Height := prev.Block.Header.Height
+	if fr.hasBeacon(Height) {
+		return
+	}
+	fr.Unlock()
+	defer fr.Lock()
+
+	timer := time.NewTimer(2 * BlockTime)
+	defer timer.Stop()
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-timer.C:
+			if DEBUG {
+				log.Println("Generatorlog", fr.key.PublicKey().Address().String(), "BeaconTimeout", Height)
+			}
+			return
+		case <-ticker.C:
+			if fr.hasBeacon(Height) {
+				return
+			}
+		}
+	}
+}
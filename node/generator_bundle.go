@@ -0,0 +1,156 @@
+package node
+
+import (
+	"log"
+
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/core/chain"
+	"github.com/meverselabs/meverse/core/types"
+)
+
+type bundleItem struct {
+	hash    hash.Hash256
+	bundle  *chain.Bundle
+	signers []common.Address
+	txMap   map[hash.Hash256]bool
+}
+
+// isBounded returns the bundle is kept until the bounds after a failure
+func (bi *bundleItem) isBounded() bool {
+	return bi.bundle.MaxHeight > 0 || bi.bundle.MaxTimestamp > 0
+}
+
+// AddBundle validates the bundle and queues it to be included at the front of the generated blocks
+//
+// the transactions of the bundle are not added to the transaction pool, so they are not propagated
+func (fr *GeneratorNode) AddBundle(bd *chain.Bundle) (hash.Hash256, error) {
+	if err := bd.Validate(); err != nil {
+		return hash.Hash256{}, err
+	}
+	cp := fr.cn.Provider()
+	if bd.IsExpired(cp.Height(), cp.LastTimestamp()) {
+		return hash.Hash256{}, errors.WithStack(chain.ErrInvalidBundleBound)
+	}
+
+	ctx := fr.cn.NewContext()
+	for _, tx := range bd.Transactions {
+		tx.VmType, tx.Method = types.GetTxType(ctx, tx)
+	}
+	signers, errs := fr.cn.TxVerifier().VerifyAll(bd.Transactions, bd.TransactionSignatures, 0)
+	for i, err := range errs {
+		if err != nil {
+			return hash.Hash256{}, &chain.BundleError{Index: i, Err: err}
+		}
+	}
+	bi := &bundleItem{
+		hash:    bd.Hash(),
+		bundle:  bd,
+		signers: signers,
+		txMap:   map[hash.Hash256]bool{},
+	}
+	for i, tx := range bd.Transactions {
+		tx.From = signers[i]
+		bi.txMap[tx.HashSig()] = true
+	}
+
+	fr.bundleLock.Lock()
+	defer fr.bundleLock.Unlock()
+
+	if len(fr.bundles) >= fr.Config.MaxBundles {
+		return hash.Hash256{}, errors.WithStack(ErrBundleQueueFull)
+	}
+	for _, v := range fr.bundles {
+		if v.hash == bi.hash {
+			return hash.Hash256{}, errors.WithStack(ErrExistBundle)
+		}
+	}
+	fr.bundles = append(fr.bundles, bi)
+	return bi.hash, nil
+}
+
+// BundleCount returns the count of the queued bundles
+func (fr *GeneratorNode) BundleCount() int {
+	fr.bundleLock.Lock()
+	defer fr.bundleLock.Unlock()
+
+	return len(fr.bundles)
+}
+
+// addBundles adds the target bundles to the block in the order of the arrival
+//
+// the included bundles are kept until their block is connected, a duplicated inclusion fails by the used time slot.
+// the failed bundles are kept until the bounds if they have
+func (fr *GeneratorNode) addBundles(bc *chain.BlockCreator, height uint32, timestamp uint64) types.Receipts {
+	fr.bundleLock.Lock()
+	defer fr.bundleLock.Unlock()
+
+	receipts := types.Receipts{}
+	remains := make([]*bundleItem, 0, len(fr.bundles))
+	for _, bi := range fr.bundles {
+		if fr.hasRevealedTx(bi) {
+			continue
+		}
+		if !bi.bundle.IsTarget(height, timestamp) {
+			if !bi.bundle.IsExpired(height, timestamp) {
+				remains = append(remains, bi)
+			}
+			continue
+		}
+		if rs, err := bc.AddBundle(bi.bundle, bi.signers); err != nil {
+			if DEBUG {
+				log.Printf("AddBundle %v %+v\n", bi.hash.String(), err)
+			}
+			if !bi.isBounded() {
+				continue
+			}
+		} else {
+			receipts = append(receipts, rs...)
+		}
+		if !bi.bundle.IsExpired(height, timestamp) {
+			remains = append(remains, bi)
+		}
+	}
+	fr.bundles = remains
+	return receipts
+}
+
+// hasRevealedTx returns the bundle has a revealed transaction, it cannot be included
+func (fr *GeneratorNode) hasRevealedTx(bi *bundleItem) bool {
+	for h := range bi.txMap {
+		if fr.isRevealedTx(h) {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanBundles removes the bundles included in the connected block
+func (fr *GeneratorNode) cleanBundles(b *types.Block) {
+	fr.bundleLock.Lock()
+	defer fr.bundleLock.Unlock()
+
+	if len(fr.bundles) == 0 {
+		return
+	}
+	TxHashes := map[hash.Hash256]bool{}
+	for _, tx := range b.Body.Transactions {
+		TxHashes[tx.HashSig()] = true
+	}
+	remains := make([]*bundleItem, 0, len(fr.bundles))
+	for _, bi := range fr.bundles {
+		included := false
+		for h := range bi.txMap {
+			if TxHashes[h] {
+				included = true
+				break
+			}
+		}
+		if !included {
+			remains = append(remains, bi)
+		}
+	}
+	fr.bundles = remains
+}
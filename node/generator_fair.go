@@ -0,0 +1,208 @@
+package node
+
+import (
+	"log"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/common/threshold"
+	"github.com/meverselabs/meverse/core/chain"
+	"github.com/meverselabs/meverse/core/types"
+)
+
+type sealedItem struct {
+	hash       hash.Hash256
+	ciphertext *threshold.Ciphertext
+	committed  uint32
+}
+
+// FairOrderKey returns the key to seal the transactions, it is nil when the fair ordering is disabled
+func (fr *GeneratorNode) FairOrderKey() *threshold.PublicKey {
+	return fr.Config.FairOrderKey
+}
+
+// AddSealedTx queues the sealed transaction to be committed in the order of the arrival
+//
+// the transaction is decrypted by the observers after its ordering is committed, so the generator cannot reorder it by the contents
+func (fr *GeneratorNode) AddSealedTx(ct *threshold.Ciphertext) (hash.Hash256, error) {
+	if fr.fair == nil {
+		return hash.Hash256{}, errors.WithStack(ErrFairOrderDisabled)
+	}
+	if len(ct.Data) > MaxSealedTxSize {
+		return hash.Hash256{}, errors.WithStack(ErrTooLargeSealedTx)
+	}
+	if err := ct.Validate(); err != nil {
+		return hash.Hash256{}, err
+	}
+	si := &sealedItem{
+		hash:       ct.Hash(),
+		ciphertext: ct,
+	}
+
+	fr.fairLock.Lock()
+	defer fr.fairLock.Unlock()
+
+	if len(fr.sealed) >= fr.Config.MaxSealedTxs {
+		return hash.Hash256{}, errors.WithStack(ErrSealedTxQueueFull)
+	}
+	for _, v := range fr.sealed {
+		if v.hash == si.hash {
+			return hash.Hash256{}, errors.WithStack(ErrExistSealedTx)
+		}
+	}
+	fr.sealed = append(fr.sealed, si)
+	return si.hash, nil
+}
+
+// SealedTxCount returns the count of the queued sealed transactions
+func (fr *GeneratorNode) SealedTxCount() int {
+	fr.fairLock.Lock()
+	defer fr.fairLock.Unlock()
+
+	return len(fr.sealed)
+}
+
+// addReveals opens the reveals from the observers and removes their ciphertexts from the queue
+func (fr *GeneratorNode) addReveals(rvs ...*Reveal) {
+	if fr.fair == nil || len(rvs) == 0 {
+		return
+	}
+
+	fr.fairLock.Lock()
+	defer fr.fairLock.Unlock()
+
+	for _, rv := range rvs {
+		fr.fair.add(rv)
+
+		committed := map[hash.Hash256]bool{}
+		for _, ct := range rv.Commitment.Ciphertexts {
+			committed[ct.Hash()] = true
+		}
+		remains := make([]*sealedItem, 0, len(fr.sealed))
+		for _, si := range fr.sealed {
+			if !committed[si.hash] {
+				remains = append(remains, si)
+			}
+		}
+		fr.sealed = remains
+	}
+}
+
+// hasReveal returns the reveal of the commitment of the height is opened
+func (fr *GeneratorNode) hasReveal(Height uint32) bool {
+	fr.fairLock.Lock()
+	defer fr.fairLock.Unlock()
+
+	_, has := fr.fair.txs(Height)
+	return has
+}
+
+// waitReveal waits the reveal of the previous block if the block has the commitment
+//
+// the generator should be locked, it is unlocked while waiting
+func (fr *GeneratorNode) waitReveal(prev *BlockGenMessage) {
+	if fr.fair == nil || prev == nil || prev.Commitment == nil {
+		return
+	}
+	Height := prev.Block.Header.Height
+	if fr.hasReveal(Height) {
+		return
+	}
+	fr.Unlock()
+	defer fr.Lock()
+
+	timer := time.NewTimer(2 * BlockTime)
+	defer timer.Stop()
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-timer.C:
+			if DEBUG {
+				log.Println("Generatorlog", fr.key.PublicKey().Address().String(), "RevealTimeout", Height)
+			}
+			return
+		case <-ticker.C:
+			if fr.hasReveal(Height) {
+				return
+			}
+		}
+	}
+}
+
+// isRevealedTx returns the transaction is revealed, it can be included only by the reveal
+func (fr *GeneratorNode) isRevealedTx(TxHash hash.Hash256) bool {
+	if fr.fair == nil {
+		return false
+	}
+
+	fr.fairLock.Lock()
+	defer fr.fairLock.Unlock()
+
+	return fr.fair.isRevealed(TxHash)
+}
+
+// addRevealedTxs adds the revealed transactions of the previous commitment at the front of the block in the committed order
+//
+// the failed transactions are omitted
+func (fr *GeneratorNode) addRevealedTxs(bc *chain.BlockCreator, ctx *types.Context, TargetHeight uint32) types.Receipts {
+	receipts := types.Receipts{}
+	if fr.fair == nil {
+		return receipts
+	}
+
+	fr.fairLock.Lock()
+	rtxs, _ := fr.fair.txs(TargetHeight - 1)
+	fr.fairLock.Unlock()
+
+	for _, rtx := range rtxs {
+		tx := rtx.Tx
+		tx.VmType, tx.Method = types.GetTxType(ctx, tx)
+		signer, err := fr.cn.TxVerifier().Verify(tx, rtx.Sig, 0)
+		if err != nil {
+			continue
+		}
+		if receipt, err := bc.UnsafeAddTx(tx.HashSig(), tx, rtx.Sig, signer); err != nil {
+			if DEBUG {
+				log.Printf("AddRevealedTx %v %+v\n", tx.HashSig().String(), err)
+			}
+		} else {
+			receipts = append(receipts, receipt)
+		}
+	}
+	return receipts
+}
+
+// commitSealedTxs makes the commitment of the queued ciphertexts in the order of the arrival
+//
+// the ciphertexts of a commitment which is not connected are committed again
+func (fr *GeneratorNode) commitSealedTxs(b *types.Block) *Commitment {
+	if fr.fair == nil {
+		return nil
+	}
+	Height := fr.cn.Provider().Height()
+
+	fr.fairLock.Lock()
+	defer fr.fairLock.Unlock()
+
+	var cm *Commitment
+	for _, si := range fr.sealed {
+		if si.committed > Height {
+			continue
+		}
+		if cm == nil {
+			cm = &Commitment{
+				Height:   b.Header.Height,
+				PrevHash: b.Header.PrevHash,
+			}
+		}
+		si.committed = b.Header.Height
+		cm.Ciphertexts = append(cm.Ciphertexts, si.ciphertext)
+		if len(cm.Ciphertexts) >= MaxCommitCiphertexts {
+			break
+		}
+	}
+	return cm
+}
@@ -138,7 +138,7 @@ func (ms *GeneratorNodeMesh) client(Address string, TargetPubKey common.PublicKe
 	if pubkey != TargetPubKey {
 		return errors.WithStack(common.ErrInvalidPublicKey)
 	}
-	if _, has := ms.netAddressMap[pubkey]; !has {
+	if !ms.fr.cn.IsObserverKey(pubkey, ms.fr.cn.Provider().Height()+1) {
 		return errors.WithStack(ErrInvalidObserverKey)
 	}
 
@@ -17,6 +17,7 @@ import (
 	"github.com/meverselabs/meverse/common/hash"
 	"github.com/meverselabs/meverse/common/key"
 	"github.com/meverselabs/meverse/common/queue"
+	"github.com/meverselabs/meverse/common/threshold"
 	"github.com/meverselabs/meverse/core/chain"
 	"github.com/meverselabs/meverse/core/prefix"
 	"github.com/meverselabs/meverse/core/txpool"
@@ -33,8 +34,13 @@ type genItem struct {
 }
 
 // GeneratorConfig defines configuration of the generator
+//
+// FairOrderKey enables the fair ordering of the sealed transactions, the observers hold its shares
 type GeneratorConfig struct {
 	MaxTransactionsPerBlock int
+	MaxBundles              int
+	MaxSealedTxs            int
+	FairOrderKey            *threshold.PublicKey
 }
 
 // GeneratorNode procudes a block by the consensus
@@ -74,6 +80,13 @@ type GeneratorNode struct {
 	closeLock          sync.RWMutex
 	generatorTimestamp uint64
 	generatorsChan     chan *p2p.ActiveGeneratorListMessage
+	bundleLock         sync.Mutex
+	bundles            []*bundleItem
+	fairLock           sync.Mutex
+	fair               *fairReveals
+	sealed             []*sealedItem
+	beaconLock         sync.Mutex
+	beacon             *types.Beacon
 	isClose            bool
 }
 
@@ -82,6 +95,12 @@ func NewGeneratorNode(ChainID *big.Int, Config *GeneratorConfig, cn *chain.Chain
 	if Config.MaxTransactionsPerBlock == 0 {
 		Config.MaxTransactionsPerBlock = 7000
 	}
+	if Config.MaxBundles == 0 {
+		Config.MaxBundles = 1000
+	}
+	if Config.MaxSealedTxs == 0 {
+		Config.MaxSealedTxs = 10000
+	}
 	fr := &GeneratorNode{
 		Config:         Config,
 		ChainID:        ChainID,
@@ -106,6 +125,9 @@ func NewGeneratorNode(ChainID *big.Int, Config *GeneratorConfig, cn *chain.Chain
 		batchCache:     gcache.New(500).LRU().Build(),
 		generatorsChan: make(chan *p2p.ActiveGeneratorListMessage, 1000),
 	}
+	if Config.FairOrderKey != nil {
+		fr.fair = newFairReveals(Config.FairOrderKey)
+	}
 	fr.ms = NewGeneratorNodeMesh(key, NetAddressMap, fr)
 	fr.nm = p2p.NewNodeMesh(fr.cn.Provider().ChainID(), ndkey, SeedNodeMap, fr, peerStorePath)
 	fr.txQ.AddGroupRepeat(6, 10*time.Second)
@@ -460,20 +482,11 @@ func (fr *GeneratorNode) AddTx(tx *types.Transaction, sig common.Signature) erro
 // PushTx pushes transaction
 func (fr *GeneratorNode) PushTx(tx *types.Transaction, sig common.Signature) error {
 	currentSlot := types.ToTimeSlot(fr.cn.Provider().LastTimestamp())
-	slot := types.ToTimeSlot(tx.Timestamp)
-	if currentSlot > 0 {
-		if slot < currentSlot-1 {
-			return errors.WithStack(types.ErrInvalidTransactionTimeSlot)
-		} else if slot > currentSlot+10 {
-			return errors.WithStack(types.ErrInvalidTransactionTimeSlot)
-		}
-	}
-
-	pubkey, err := common.RecoverPubkey(tx.ChainID, tx.Message(), sig)
+	signer, err := fr.cn.TxVerifier().Verify(tx, sig, currentSlot)
 	if err != nil {
 		return err
 	}
-	tx.From = pubkey.Address()
+	tx.From = signer
 
 	TxHash := tx.HashSig()
 	if !fr.txpool.IsExist(TxHash) {
@@ -493,13 +506,13 @@ func (fr *GeneratorNode) addTx(TxHash hash.Hash256, tx *types.Transaction, sig c
 	if fr.txpool.IsExist(TxHash) {
 		return errors.WithStack(txpool.ErrExistTransaction)
 	}
-	pubkey, err := common.RecoverPubkey(tx.ChainID, tx.Message(), sig)
+	signer, err := fr.cn.TxVerifier().Verify(tx, sig, 0)
 	if err != nil {
 		return err
 	}
 	// contract check
 
-	tx.From = pubkey.Address()
+	tx.From = signer
 	if err := fr.txpool.Push(TxHash, tx, sig, tx.From); err != nil {
 		return err
 	}
@@ -549,6 +562,7 @@ func (fr *GeneratorNode) addBlock(b *types.Block) error {
 }
 
 func (fr *GeneratorNode) cleanPool(b *types.Block) {
+	fr.cleanBundles(b)
 	for _, tx := range b.Body.Transactions {
 		TxHash := tx.HashSig()
 		fr.txpool.Remove(TxHash, tx)
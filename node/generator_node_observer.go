@@ -11,6 +11,7 @@ import (
 	"github.com/meverselabs/meverse/common/hash"
 	"github.com/meverselabs/meverse/core/chain"
 	"github.com/meverselabs/meverse/core/prefix"
+	"github.com/meverselabs/meverse/core/txpool"
 	"github.com/meverselabs/meverse/core/types"
 	"github.com/meverselabs/meverse/p2p"
 	"github.com/meverselabs/meverse/p2p/peer"
@@ -183,6 +184,9 @@ func (fr *GeneratorNode) handleObserverMessage(p peer.Peer, m interface{}, Retry
 		fr.lastReqMessage = msg
 		fr.lastReqLock.Unlock()
 
+		fr.addReveals(msg.Reveals...)
+		fr.addBeacon(msg.Beacon)
+
 		go func(ID string, req *BlockReqMessage) error {
 			fr.genLock.Lock()
 			defer fr.genLock.Unlock()
@@ -259,6 +263,10 @@ func (fr *GeneratorNode) handleObserverMessage(p peer.Peer, m interface{}, Retry
 		if msg.TargetHeight < TargetHeight {
 			return nil
 		}
+		if msg.Reveal != nil {
+			fr.addReveals(msg.Reveal)
+		}
+		fr.addBeacon(msg.Beacon)
 
 		fr.Lock()
 		if item, has := fr.lastGenItemMap[msg.TargetHeight]; has {
@@ -494,6 +502,31 @@ func (fr *GeneratorNode) updateByGenItem() {
 	}
 }
 
+// unsafePopTxs pops the transactions which are added to the block together, the revealed transactions are skipped
+//
+// the count is the execute workers of the chain by 4 to be speculated together, it should be called under the lock of the txpool
+func (fr *GeneratorNode) unsafePopTxs(currentSlot uint32, max int) []*txpool.PoolItem {
+	n := fr.cn.ExecuteWorkers() * 4
+	if n < 1 {
+		n = 1
+	}
+	if n > max {
+		n = max
+	}
+	items := []*txpool.PoolItem{}
+	for len(items) < n {
+		item := fr.txpool.UnsafePop(currentSlot)
+		if item == nil {
+			break
+		}
+		if fr.isRevealedTx(item.TxHash) {
+			continue
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
 func (fr *GeneratorNode) genBlock(ID string, msg *BlockReqMessage) error {
 	cp := fr.cn.Provider()
 
@@ -515,6 +548,7 @@ func (fr *GeneratorNode) genBlock(ID string, msg *BlockReqMessage) error {
 
 	MaxTxPerBlock := fr.Config.MaxTransactionsPerBlock
 	var lastHeader *types.Header
+	var lastGen *BlockGenMessage
 	ctx := fr.cn.NewContext()
 	failTxs := []*types.Transaction{}
 	failerrs := []error{}
@@ -543,36 +577,52 @@ func (fr *GeneratorNode) genBlock(ID string, msg *BlockReqMessage) error {
 			fr.Lock()
 		}
 
+		fr.waitReveal(lastGen)
+		fr.waitBeacon(lastGen)
+		fr.setBeacon(bc, ctx)
+
+		// the revealed transactions and the bundles are added at the front of the block
+		receipts := fr.addRevealedTxs(bc, ctx, ctx.TargetHeight())
+		receipts = append(receipts, fr.addBundles(bc, ctx.TargetHeight(), Timestamp)...)
+
 		timer := time.NewTimer(400 * time.Millisecond)
 
 		fr.txpool.Lock() // Prevent delaying from TxPool.Push
-		Count := 0
+		Count := len(receipts)
 		currentSlot := types.ToTimeSlot(Timestamp)
 
-		var receipts = types.Receipts{}
 	TxLoop:
 		for {
 			select {
 			case <-timer.C:
 				break TxLoop
 			default:
-				item := fr.txpool.UnsafePop(currentSlot)
-				if item == nil {
+				items := fr.unsafePopTxs(currentSlot, MaxTxPerBlock-Count+1)
+				if len(items) == 0 {
 					break TxLoop
 				}
-				if receipt, err := bc.UnsafeAddTx(item.TxHash, item.Transaction, item.Signature, item.Signer); err != nil {
-					if errors.Cause(err) != types.ErrUsedTimeSlot {
-						fmt.Printf("UnsafeAddTx %+v\n", err)
-						failTxs = append(failTxs, item.Transaction)
-						failerrs = append(failerrs, err)
+				TxHashes := make([]hash.Hash256, len(items))
+				txs := make([]*types.Transaction, len(items))
+				sigs := make([]common.Signature, len(items))
+				signers := make([]common.Address, len(items))
+				for j, item := range items {
+					TxHashes[j], txs[j], sigs[j], signers[j] = item.TxHash, item.Transaction, item.Signature, item.Signer
+				}
+				rs, errs := bc.UnsafeAddTxs(TxHashes, txs, sigs, signers)
+				for j, err := range errs {
+					if err != nil {
+						if errors.Cause(err) != types.ErrUsedTimeSlot {
+							fmt.Printf("UnsafeAddTx %+v\n", err)
+							failTxs = append(failTxs, txs[j])
+							failerrs = append(failerrs, err)
+						}
+						continue
 					}
-					continue
-				} else {
 					Count++
-					receipts = append(receipts, receipt)
-					if Count > MaxTxPerBlock {
-						break TxLoop
-					}
+					receipts = append(receipts, rs[j])
+				}
+				if Count > MaxTxPerBlock {
+					break TxLoop
 				}
 			}
 		}
@@ -592,6 +642,15 @@ func (fr *GeneratorNode) genBlock(ID string, msg *BlockReqMessage) error {
 		} else {
 			sm.GeneratorSignature = sig
 		}
+		if cm := fr.commitSealedTxs(b); cm != nil {
+			if sig, err := fr.key.Sign(cm.Hash()); err != nil {
+				return err
+			} else {
+				sm.Commitment = cm
+				sm.CommitmentSignature = sig
+			}
+		}
+		lastGen = sm
 		fr.ms.SendTo(ID, sm)
 
 		// log.Println("Generatorlog", fr.key.PublicKey().Address().String(), "Send.BlockGenMessage", sm.Block.Header.Height, len(sm.Block.Body.Transactions))
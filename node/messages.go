@@ -4,9 +4,13 @@ import (
 	"io"
 	"math/big"
 
+	"github.com/pkg/errors"
+
 	"github.com/meverselabs/meverse/common"
 	"github.com/meverselabs/meverse/common/bin"
 	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/common/threshold"
+	"github.com/meverselabs/meverse/common/vrf"
 	"github.com/meverselabs/meverse/core/types"
 	"github.com/meverselabs/meverse/p2p"
 )
@@ -174,11 +178,16 @@ func (s *RoundVoteAckMessage) ReadFrom(r io.Reader) (int64, error) {
 }
 
 // BlockReqMessage is a message for a block request
+//
+// Reveals is the reveals which should be included from the target height, it is omitted when it is empty
+// Beacon is the latest beacon of the observers, it is omitted when it is nil
 type BlockReqMessage struct {
 	PrevHash     hash.Hash256
 	TargetHeight uint32
 	TimeoutCount uint32
 	Generator    common.Address
+	Reveals      []*Reveal
+	Beacon       *types.Beacon
 }
 
 func (s *BlockReqMessage) TypeID() uint32 {
@@ -199,6 +208,21 @@ func (s *BlockReqMessage) WriteTo(w io.Writer) (int64, error) {
 	if sum, err := sw.Address(w, s.Generator); err != nil {
 		return sum, err
 	}
+	if len(s.Reveals) > 0 || s.Beacon != nil {
+		if sum, err := sw.Uint16(w, uint16(len(s.Reveals))); err != nil {
+			return sum, err
+		}
+		for _, v := range s.Reveals {
+			if sum, err := sw.WriterTo(w, v); err != nil {
+				return sum, err
+			}
+		}
+	}
+	if s.Beacon != nil {
+		if sum, err := sw.WriterTo(w, s.Beacon); err != nil {
+			return sum, err
+		}
+	}
 	return sw.Sum(), nil
 }
 
@@ -216,14 +240,41 @@ func (s *BlockReqMessage) ReadFrom(r io.Reader) (int64, error) {
 	if sum, err := sr.Address(r, &s.Generator); err != nil {
 		return sum, err
 	}
+	if Len, sum, err := sr.GetUint16(r); err != nil {
+		if errors.Cause(err) == io.EOF {
+			return sum, nil
+		}
+		return sum, err
+	} else {
+		s.Reveals = make([]*Reveal, 0, Len)
+		for i := uint16(0); i < Len; i++ {
+			v := &Reveal{}
+			if sum, err := sr.ReaderFrom(r, v); err != nil {
+				return sum, err
+			}
+			s.Reveals = append(s.Reveals, v)
+		}
+	}
+	bn := &types.Beacon{}
+	if sum, err := sr.ReaderFrom(r, bn); err != nil {
+		if errors.Cause(err) == io.EOF {
+			return sum, nil
+		}
+		return sum, err
+	}
+	s.Beacon = bn
 	return sr.Sum(), nil
 }
 
 // BlockGenMessage is a message for a block generation
+//
+// Commitment is the ordering of the sealed transactions signed by CommitmentSignature, it is omitted when it is nil
 type BlockGenMessage struct {
-	Block              *types.Block
-	GeneratorSignature common.Signature
-	IsReply            bool
+	Block               *types.Block
+	GeneratorSignature  common.Signature
+	IsReply             bool
+	Commitment          *Commitment
+	CommitmentSignature common.Signature
 }
 
 func (s *BlockGenMessage) TypeID() uint32 {
@@ -241,6 +292,14 @@ func (s *BlockGenMessage) WriteTo(w io.Writer) (int64, error) {
 	if sum, err := sw.Bool(w, s.IsReply); err != nil {
 		return sum, err
 	}
+	if s.Commitment != nil {
+		if sum, err := sw.WriterTo(w, s.Commitment); err != nil {
+			return sum, err
+		}
+		if sum, err := sw.Signature(w, s.CommitmentSignature); err != nil {
+			return sum, err
+		}
+	}
 	return sw.Sum(), nil
 }
 
@@ -256,16 +315,32 @@ func (s *BlockGenMessage) ReadFrom(r io.Reader) (int64, error) {
 	if sum, err := sr.Bool(r, &s.IsReply); err != nil {
 		return sum, err
 	}
+	cm := &Commitment{}
+	if sum, err := sr.ReaderFrom(r, cm); err != nil {
+		if errors.Cause(err) == io.EOF {
+			return sum, nil
+		}
+		return sum, err
+	}
+	s.Commitment = cm
+	if sum, err := sr.Signature(r, &s.CommitmentSignature); err != nil {
+		return sum, err
+	}
 	return sr.Sum(), nil
 }
 
 // BlockVoteMessage is message for a block vote
+//
+// DecryptionShares is the shares of the observer for the commitment of the block, it is omitted when it is empty
+// BeaconProof is the vrf output of the observer for the beacon of the height, it is omitted when it is nil
 type BlockVoteMessage struct {
 	TargetHeight       uint32
 	Header             *types.Header
 	GeneratorSignature common.Signature
 	ObserverSignature  common.Signature
 	IsReply            bool
+	DecryptionShares   []*threshold.DecryptionShare
+	BeaconProof        *vrf.Proof
 }
 
 func (s *BlockVoteMessage) TypeID() uint32 {
@@ -289,6 +364,16 @@ func (s *BlockVoteMessage) WriteTo(w io.Writer) (int64, error) {
 	if sum, err := sw.Bool(w, s.IsReply); err != nil {
 		return sum, err
 	}
+	if len(s.DecryptionShares) > 0 || s.BeaconProof != nil {
+		if sum, err := writeDecryptionShares(sw, w, s.DecryptionShares); err != nil {
+			return sum, err
+		}
+	}
+	if s.BeaconProof != nil {
+		if sum, err := sw.WriterTo(w, s.BeaconProof); err != nil {
+			return sum, err
+		}
+	}
 	return sw.Sum(), nil
 }
 
@@ -310,14 +395,34 @@ func (s *BlockVoteMessage) ReadFrom(r io.Reader) (int64, error) {
 	if sum, err := sr.Bool(r, &s.IsReply); err != nil {
 		return sum, err
 	}
+	if dss, sum, err := readDecryptionShares(sr, r); err != nil {
+		if errors.Cause(err) == io.EOF {
+			return sum, nil
+		}
+		return sum, err
+	} else if len(dss) > 0 {
+		s.DecryptionShares = dss
+	}
+	p := &vrf.Proof{}
+	if sum, err := sr.ReaderFrom(r, p); err != nil {
+		if errors.Cause(err) == io.EOF {
+			return sum, nil
+		}
+		return sum, err
+	}
+	s.BeaconProof = p
 	return sr.Sum(), nil
 }
 
 // BlockObSignMessage is a message for a block observer signatures
+//
+// Reveal is the reveal of the commitment of the block and Beacon is the beacon of the block, they are omitted when they are nil
 type BlockObSignMessage struct {
 	TargetHeight       uint32
 	BlockSign          *types.BlockSign
 	ObserverSignatures []common.Signature
+	Reveal             *Reveal
+	Beacon             *types.Beacon
 }
 
 func (s *BlockObSignMessage) TypeID() uint32 {
@@ -340,6 +445,21 @@ func (s *BlockObSignMessage) WriteTo(w io.Writer) (int64, error) {
 			return sum, err
 		}
 	}
+	if s.Reveal != nil || s.Beacon != nil {
+		if sum, err := sw.Bool(w, s.Reveal != nil); err != nil {
+			return sum, err
+		}
+		if s.Reveal != nil {
+			if sum, err := sw.WriterTo(w, s.Reveal); err != nil {
+				return sum, err
+			}
+		}
+	}
+	if s.Beacon != nil {
+		if sum, err := sw.WriterTo(w, s.Beacon); err != nil {
+			return sum, err
+		}
+	}
 	return sw.Sum(), nil
 }
 
@@ -364,6 +484,27 @@ func (s *BlockObSignMessage) ReadFrom(r io.Reader) (int64, error) {
 			s.ObserverSignatures = append(s.ObserverSignatures, v)
 		}
 	}
+	var hasReveal bool
+	if sum, err := sr.Bool(r, &hasReveal); err != nil {
+		if errors.Cause(err) == io.EOF {
+			return sum, nil
+		}
+		return sum, err
+	}
+	if hasReveal {
+		s.Reveal = &Reveal{}
+		if sum, err := sr.ReaderFrom(r, s.Reveal); err != nil {
+			return sum, err
+		}
+	}
+	bn := &types.Beacon{}
+	if sum, err := sr.ReaderFrom(r, bn); err != nil {
+		if errors.Cause(err) == io.EOF {
+			return sum, nil
+		}
+		return sum, err
+	}
+	s.Beacon = bn
 	return sr.Sum(), nil
 }
 
@@ -0,0 +1,90 @@
+package node
+
+import (
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/vrf"
+	"github.com/meverselabs/meverse/core/types"
+)
+
+// beaconProof returns the vrf output of the observer for the beacon of the height of the block gen
+func (ob *ObserverNode) beaconProof(gen *BlockGenMessage) *vrf.Proof {
+	bh := &gen.Block.Header
+	if bh.Version < types.BeaconVersion {
+		return nil
+	}
+	if ob.lastBeaconProof != nil && ob.lastProofHeight == bh.Height {
+		return ob.lastBeaconProof
+	}
+	p, err := vrf.Prove(ob.key.PrivateKey(), types.BeaconSeed(ob.ChainID, bh.Height))
+	if err != nil {
+		return nil
+	}
+	ob.lastProofHeight = bh.Height
+	ob.lastBeaconProof = p
+	return p
+}
+
+// blockVoteSignatures returns the observer signatures of the votes which connect the block, it is nil when the votes are not enough
+//
+// from the BeaconVersion, the block is connected by the votes of the beacon observers because the next block should include their beacon
+func (ob *ObserverNode) blockVoteSignatures(br *BlockRound, KeyMap map[common.PublicKey]bool) []common.Signature {
+	bh := &br.BlockGenMessage.Block.Header
+	if bh.Version < types.BeaconVersion {
+		if len(br.BlockVoteMap) < len(KeyMap)/2+1 {
+			return nil
+		}
+		sigs := []common.Signature{}
+		for _, vt := range br.BlockVoteMap {
+			sigs = append(sigs, vt.ObserverSignature)
+		}
+		return sigs
+	}
+	bn := ob.buildBeacon(br)
+	if bn == nil {
+		return nil
+	}
+	sigs := make([]common.Signature, 0, len(bn.Proofs))
+	for _, bp := range bn.Proofs {
+		sigs = append(sigs, br.BlockVoteMap[bp.PublicKey].ObserverSignature)
+	}
+	return sigs
+}
+
+// buildBeacon collects the vrf outputs of the beacon observers from the votes for the block, it is nil when one of them is not collected
+func (ob *ObserverNode) buildBeacon(br *BlockRound) *types.Beacon {
+	bh := &br.BlockGenMessage.Block.Header
+	if bh.Version < types.BeaconVersion {
+		return nil
+	}
+	keys, err := ob.cn.BeaconObserverKeys(bh.Height)
+	if err != nil {
+		return nil
+	}
+	seed := types.BeaconSeed(ob.ChainID, bh.Height)
+	bn := &types.Beacon{
+		Height: bh.Height,
+		Proofs: make([]*types.BeaconProof, 0, len(keys)),
+	}
+	for _, pubkey := range keys {
+		vt, has := br.BlockVoteMap[pubkey]
+		if !has || vt.BeaconProof == nil {
+			return nil
+		}
+		if _, err := vrf.Verify(pubkey, seed, vt.BeaconProof); err != nil {
+			return nil
+		}
+		bn.Proofs = append(bn.Proofs, &types.BeaconProof{
+			PublicKey: pubkey,
+			Proof:     vt.BeaconProof,
+		})
+	}
+	return bn
+}
+
+// pendingBeacon returns the latest beacon which can be included from the target height
+func (ob *ObserverNode) pendingBeacon(TargetHeight uint32) *types.Beacon {
+	if ob.lastBeacon == nil || ob.lastBeacon.Height >= TargetHeight {
+		return nil
+	}
+	return ob.lastBeacon
+}
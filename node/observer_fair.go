@@ -0,0 +1,104 @@
+package node
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/threshold"
+)
+
+// SetFairOrder enables the fair ordering with the key share of the observer
+//
+// all observers should have the key to check the order of the revealed transactions and to forward the reveals
+func (ob *ObserverNode) SetFairOrder(pk *threshold.PublicKey, share *threshold.SecretShare) error {
+	if err := pk.Validate(); err != nil {
+		return err
+	}
+	if share != nil && (share.Index < 1 || int(share.Index) > len(pk.Verifiers)) {
+		return errors.WithStack(threshold.ErrInvalidShareIndex)
+	}
+
+	ob.Lock()
+	defer ob.Unlock()
+
+	ob.fair = newFairReveals(pk)
+	ob.fairShare = share
+	return nil
+}
+
+// validateFairOrder checks the commitment of the block gen and the order of the revealed transactions in the block
+func (ob *ObserverNode) validateFairOrder(msg *BlockGenMessage) error {
+	if cm := msg.Commitment; cm != nil {
+		if cm.Height != msg.Block.Header.Height || cm.PrevHash != msg.Block.Header.PrevHash || len(cm.Ciphertexts) > MaxCommitCiphertexts {
+			return errors.WithStack(ErrInvalidCommitment)
+		}
+		for _, ct := range cm.Ciphertexts {
+			if err := ct.Validate(); err != nil {
+				return err
+			}
+		}
+		pubkey, err := common.RecoverPubkey(ob.ChainID, cm.Hash(), msg.CommitmentSignature)
+		if err != nil {
+			return err
+		}
+		if pubkey.Address() != msg.Block.Header.Generator {
+			return errors.WithStack(ErrInvalidCommitment)
+		}
+	}
+	if ob.fair != nil {
+		return ob.fair.checkOrder(msg.Block)
+	}
+	return nil
+}
+
+// decryptionShares returns the shares of the observer for the commitment of the block gen
+func (ob *ObserverNode) decryptionShares(gen *BlockGenMessage) []*threshold.DecryptionShare {
+	if ob.fairShare == nil || gen.Commitment == nil {
+		return nil
+	}
+	cm := gen.Commitment
+	h := cm.Hash()
+	if ob.lastSharesHash == h {
+		return ob.lastShares
+	}
+	dss := make([]*threshold.DecryptionShare, 0, len(cm.Ciphertexts))
+	for _, ct := range cm.Ciphertexts {
+		ds, err := ob.fairShare.Decrypt(ct)
+		if err != nil {
+			return nil
+		}
+		dss = append(dss, ds)
+	}
+	ob.lastSharesHash = h
+	ob.lastShares = dss
+	return dss
+}
+
+// buildReveal collects the decryption shares of the votes for the commitment of the block
+func (ob *ObserverNode) buildReveal(br *BlockRound) *Reveal {
+	cm := br.BlockGenMessage.Commitment
+	if cm == nil {
+		return nil
+	}
+	rv := &Reveal{
+		Commitment: cm,
+		Shares:     [][]*threshold.DecryptionShare{},
+	}
+	for _, vt := range br.BlockVoteMap {
+		if len(vt.DecryptionShares) == len(cm.Ciphertexts) {
+			rv.Shares = append(rv.Shares, vt.DecryptionShares)
+		}
+	}
+	if ob.fair != nil {
+		ob.fair.add(rv)
+	}
+	return rv
+}
+
+// pendingReveals returns the reveals which should be included from the target height
+func (ob *ObserverNode) pendingReveals(TargetHeight uint32) []*Reveal {
+	if ob.fair == nil {
+		return nil
+	}
+	return ob.fair.pending(TargetHeight)
+}
@@ -37,6 +37,11 @@ func NewObserverNodeMesh(key key.Key, NetAddressMap map[common.PublicKey]string,
 	return ms
 }
 
+// isObserverKey returns the key is the observer on the chain, the net address map only gives the address to connect
+func (ms *ObserverNodeMesh) isObserverKey(pubkey common.PublicKey) bool {
+	return ms.ob.cn.IsObserverKey(pubkey, ms.ob.cn.Provider().Height()+1)
+}
+
 // Run starts the observer mesh
 func (ms *ObserverNodeMesh) Run(BindAddress string) {
 	myPublicKey := ms.key.PublicKey()
@@ -202,7 +207,7 @@ func (ms *ObserverNodeMesh) client(Address string, TargetPubKey common.PublicKey
 	if pubkey != TargetPubKey {
 		return errors.WithStack(common.ErrInvalidPublicKey)
 	}
-	if _, has := ms.netAddressMap[pubkey]; !has {
+	if !ms.isObserverKey(pubkey) {
 		return errors.WithStack(ErrInvalidObserverKey)
 	}
 
@@ -240,7 +245,7 @@ func (ms *ObserverNodeMesh) server(BindAddress string) error {
 				log.Printf("[sendHandshake] %+v\n", err)
 				return
 			}
-			if _, has := ms.netAddressMap[PubKey]; !has {
+			if !ms.isObserverKey(PubKey) {
 				log.Println(ms.ob.obID, "ErrInvalidPublicKey", PubKey)
 				return
 			}
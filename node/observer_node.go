@@ -12,8 +12,11 @@ import (
 
 	"github.com/meverselabs/meverse/common"
 	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/common/hash"
 	"github.com/meverselabs/meverse/common/key"
 	"github.com/meverselabs/meverse/common/queue"
+	"github.com/meverselabs/meverse/common/threshold"
+	"github.com/meverselabs/meverse/common/vrf"
 	"github.com/meverselabs/meverse/core/chain"
 	"github.com/meverselabs/meverse/core/prefix"
 	"github.com/meverselabs/meverse/core/types"
@@ -39,7 +42,6 @@ type ObserverNode struct {
 	fs               *GeneratorService
 	cn               *chain.Chain
 	ct               chain.Committer
-	observerKeyMap   map[common.PublicKey]bool
 	round            *VoteRound
 	roundFirstTime   uint64
 	roundFirstHeight uint32
@@ -57,33 +59,35 @@ type ObserverNode struct {
 	isRunning        bool
 	closeLock        sync.RWMutex
 	isClose          bool
+	fair             *fairReveals
+	fairShare        *threshold.SecretShare
+	lastSharesHash   hash.Hash256
+	lastShares       []*threshold.DecryptionShare
+	lastProofHeight  uint32
+	lastBeaconProof  *vrf.Proof
+	lastBeacon       *types.Beacon
 
 	prevRoundEndTime int64 // FOR DEBUG
 }
 
 // NewObserverNode returns a ObserverNode
 func NewObserverNode(ChainID *big.Int, key key.Key, NetAddressMap map[common.PublicKey]string, cn *chain.Chain, obID string) *ObserverNode {
-	observerKeyMap := map[common.PublicKey]bool{}
-	for k := range NetAddressMap {
-		observerKeyMap[k] = true
-	}
 	ob := &ObserverNode{
-		obID:           obID,
-		ChainID:        ChainID,
-		key:            key,
-		cn:             cn,
-		ct:             chain.NewChainCommiter(cn),
-		observerKeyMap: observerKeyMap,
-		round:          NewVoteRound(cn.Provider().Height()+1, prefix.MaxBlocksPerGenerator),
-		ignoreMap:      map[common.Address]int64{},
-		myPublicKey:    key.PublicKey(),
-		statusMap:      map[string]*p2p.Status{},
-		blockQ:         queue.NewSortedQueue(),
-		messageQueue:   queue.NewQueue(),
-		recvChan:       make(chan *p2p.RecvMessageItem, 1000),
-		sendChan:       make(chan *p2p.SendMessageItem, 1000),
-		singleCache:    gcache.New(500).LRU().Build(),
-		batchCache:     gcache.New(500).LRU().Build(),
+		obID:         obID,
+		ChainID:      ChainID,
+		key:          key,
+		cn:           cn,
+		ct:           chain.NewChainCommiter(cn),
+		round:        NewVoteRound(cn.Provider().Height()+1, prefix.MaxBlocksPerGenerator),
+		ignoreMap:    map[common.Address]int64{},
+		myPublicKey:  key.PublicKey(),
+		statusMap:    map[string]*p2p.Status{},
+		blockQ:       queue.NewSortedQueue(),
+		messageQueue: queue.NewQueue(),
+		recvChan:     make(chan *p2p.RecvMessageItem, 1000),
+		sendChan:     make(chan *p2p.SendMessageItem, 1000),
+		singleCache:  gcache.New(500).LRU().Build(),
+		batchCache:   gcache.New(500).LRU().Build(),
 	}
 	ob.ms = NewObserverNodeMesh(key, NetAddressMap, ob)
 	ob.fs = NewGeneratorService(ob)
@@ -91,6 +95,11 @@ func NewObserverNode(ChainID *big.Int, key key.Key, NetAddressMap map[common.Pub
 	return ob
 }
 
+// observerKeyMap returns the observer keys of the current round height
+func (ob *ObserverNode) observerKeyMap() (map[common.PublicKey]bool, error) {
+	return ob.cn.ObserverKeyMap(ob.round.TargetHeight)
+}
+
 // Init initializes observer
 func (ob *ObserverNode) Init() error {
 	return nil
@@ -219,6 +228,10 @@ func (ob *ObserverNode) Run(BindObserver string, BindGenerator string) {
 				i++
 				item := v.(*messageItem)
 				ob.Lock()
+				if ob.isClose {
+					ob.Unlock()
+					break
+				}
 				if err := ob.handleObserverMessage(item.PublicKey, item.Message, item.Packet); err != nil {
 					if DEBUG {
 						switch errors.Cause(err) {
@@ -39,9 +39,15 @@ func (ob *ObserverNode) onObserverRecv(p peer.Peer, bs []byte) error {
 func (ob *ObserverNode) handleObserverMessage(SenderPublicKey common.PublicKey, m interface{}, raw []byte) error {
 	cp := ob.cn.Provider()
 
+	// the observer keys don't change while the message is handled in the round
+	KeyMap, err := ob.observerKeyMap()
+	if err != nil {
+		return err
+	}
+
 	switch msg := m.(type) {
 	case *RoundVoteMessage:
-		if !ob.observerKeyMap[SenderPublicKey] {
+		if !KeyMap[SenderPublicKey] {
 			return errors.WithStack(ErrInvalidObserverKey)
 		}
 
@@ -65,7 +71,7 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicKey common.PublicKey,
 			return errors.WithMessagef(err, "ob.cn.TopGenerator(msg.TimeoutCount) %v", msg.TimeoutCount)
 		}
 		if msg.Generator != Top {
-			return errors.WithMessagef(ErrInvalidVote, "if msg.Generator != Top.Address {", msg.Generator.String(), Top.String(), msg.TimeoutCount)
+			return errors.WithMessagef(ErrInvalidVote, "msg.Generator != Top, %v, %v, %v", msg.Generator.String(), Top.String(), msg.TimeoutCount)
 		}
 
 		//[check state]
@@ -96,7 +102,7 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicKey common.PublicKey,
 		if !msg.IsReply && SenderPublicKey != ob.myPublicKey {
 			ob.sendRoundVoteTo(SenderPublicKey)
 		}
-		if len(ob.round.RoundVoteMessageMap) >= len(ob.observerKeyMap)/2+2 {
+		if len(ob.round.RoundVoteMessageMap) >= len(KeyMap)/2+2 {
 			ob.round.RoundState = RoundVoteAckState
 			if ob.roundFirstTime == 0 {
 				ob.roundFirstTime = uint64(time.Now().UnixNano())
@@ -114,7 +120,7 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicKey common.PublicKey,
 		}
 	case *RoundVoteAckMessage:
 		//log.Println(ob.obID, cp.Height(), "RoundVoteAckMessage", ob.round.RoundState, (time.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
-		if !ob.observerKeyMap[SenderPublicKey] {
+		if !KeyMap[SenderPublicKey] {
 			return errors.WithStack(ErrInvalidObserverKey)
 		}
 
@@ -181,7 +187,7 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicKey common.PublicKey,
 			ob.sendRoundVoteAckTo(SenderPublicKey)
 		}
 
-		if len(ob.round.RoundVoteAckMessageMap) >= len(ob.observerKeyMap)/2+1 {
+		if len(ob.round.RoundVoteAckMessageMap) >= len(KeyMap)/2+1 {
 			var MinRoundVoteAck *RoundVoteAckMessage
 			PublicKeyCountMap := map[common.PublicKey]int{}
 			TimeoutCountMap := map[uint32]int{}
@@ -193,7 +199,7 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicKey common.PublicKey,
 				PublicKeyCount := PublicKeyCountMap[vt.PublicKey]
 				PublicKeyCount++
 				PublicKeyCountMap[vt.PublicKey] = PublicKeyCount
-				if TimeoutCount >= len(ob.observerKeyMap)/2+1 && PublicKeyCount >= len(ob.observerKeyMap)/2+1 {
+				if TimeoutCount >= len(KeyMap)/2+1 && PublicKeyCount >= len(KeyMap)/2+1 {
 					MinRoundVoteAck = vt
 					break
 				}
@@ -223,6 +229,8 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicKey common.PublicKey,
 						TargetHeight: ob.round.MinRoundVoteAck.TargetHeight,
 						TimeoutCount: ob.round.MinRoundVoteAck.TimeoutCount,
 						Generator:    ob.round.MinRoundVoteAck.Generator,
+						Reveals:      ob.pendingReveals(ob.round.MinRoundVoteAck.TargetHeight),
+						Beacon:       ob.pendingBeacon(ob.round.MinRoundVoteAck.TargetHeight),
 					}
 					ob.sendMessage(0, ob.round.MinRoundVoteAck.Generator, nm)
 					//ob.fs.SendTo(ob.round.MinRoundVoteAck.Generator, p2p.MessageToPacket(nm))
@@ -398,6 +406,13 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicKey common.PublicKey,
 			return errors.WithStack(ErrInvalidVote)
 		}
 
+		if err := ob.validateFairOrder(msg); err != nil {
+			if DEBUG {
+				log.Printf("ob BlockGenMessage %v %+v", msg.Block.Header.Generator.String(), err)
+			}
+			return err
+		}
+
 		ctx := ob.ct.NewContext()
 		var receipts = types.Receipts{}
 		if receipts, err = ob.ct.ExecuteBlockOnContext(msg.Block, ctx, nil); err != nil {
@@ -433,7 +448,7 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicKey common.PublicKey,
 			})
 		}
 	case *BlockGenRequestMessage:
-		if !ob.observerKeyMap[SenderPublicKey] {
+		if !KeyMap[SenderPublicKey] {
 			return errors.WithStack(ErrInvalidObserverKey)
 		}
 
@@ -480,7 +495,7 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicKey common.PublicKey,
 		if DEBUG {
 			log.Println(ob.obID, cp.Height(), bin.MustWriterToHash(msg.Header), "BlockVoteMessage", ob.round.RoundState, msg.Header.Height, (time.Now().UnixNano()-ob.prevRoundEndTime)/int64(time.Millisecond))
 		}
-		if !ob.observerKeyMap[SenderPublicKey] {
+		if !KeyMap[SenderPublicKey] {
 			return errors.WithStack(ErrInvalidObserverKey)
 		}
 
@@ -582,11 +597,7 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicKey common.PublicKey,
 		}
 
 		//[apply vote]
-		if len(br.BlockVoteMap) >= len(ob.observerKeyMap)/2+1 {
-			sigs := []common.Signature{}
-			for _, vt := range br.BlockVoteMap {
-				sigs = append(sigs, vt.ObserverSignature)
-			}
+		if sigs := ob.blockVoteSignatures(br, KeyMap); sigs != nil {
 
 			PastTime := uint64(time.Now().UnixNano()) - ob.roundFirstTime
 			ExpectedTime := uint64(msg.Header.Height-ob.roundFirstHeight) * uint64(BlockTime)
@@ -612,6 +623,11 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicKey common.PublicKey,
 			} else {
 				ob.broadcastStatus()
 			}
+			rv := ob.buildReveal(br)
+			bn := ob.buildBeacon(br)
+			if bn != nil {
+				ob.lastBeacon = bn
+			}
 			delete(ob.ignoreMap, ob.round.MinRoundVoteAck.Generator)
 
 			adjustMap := ob.adjustGeneratorMap()
@@ -632,6 +648,8 @@ func (ob *ObserverNode) handleObserverMessage(SenderPublicKey common.PublicKey,
 						GeneratorSignature: msg.GeneratorSignature,
 					},
 					ObserverSignatures: sigs,
+					Reveal:             rv,
+					Beacon:             bn,
 				}
 				bs := p2p.MessageToPacket(nm)
 				ob.sendMessagePacket(0, ob.round.MinRoundVoteAck.Generator, bs)
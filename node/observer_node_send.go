@@ -34,7 +34,11 @@ func (ob *ObserverNode) hasBlockVoteHalf(TargetHeight uint32) (bool, *BlockVoteM
 			for k, v := range br.BlockVoteMap {
 				mp[k] = v
 			}
-			if len(mp) >= len(ob.observerKeyMap)/2 {
+			KeyMap, err := ob.observerKeyMap()
+			if err != nil {
+				return false, nil
+			}
+			if len(mp) >= len(KeyMap)/2 {
 				for _, v := range mp {
 					return true, v
 				}
@@ -249,6 +253,8 @@ func (ob *ObserverNode) sendBlockVote(gen *BlockGenMessage) error {
 		Header:             &gen.Block.Header,
 		GeneratorSignature: gen.GeneratorSignature,
 		IsReply:            false,
+		DecryptionShares:   ob.decryptionShares(gen),
+		BeaconProof:        ob.beaconProof(gen),
 	}
 
 	s := &types.BlockSign{
@@ -288,6 +294,8 @@ func (ob *ObserverNode) sendBlockVoteTo(gen *BlockGenMessage, TargetPubKey commo
 		Header:             &gen.Block.Header,
 		GeneratorSignature: gen.GeneratorSignature,
 		IsReply:            true,
+		DecryptionShares:   ob.decryptionShares(gen),
+		BeaconProof:        ob.beaconProof(gen),
 	}
 
 	s := &types.BlockSign{
@@ -625,11 +625,11 @@ func (nd *Node) PushTx(tx *types.Transaction, sig common.Signature) error {
 	}
 
 	TxHash := tx.HashSig()
-	pubkey, err := common.RecoverPubkey(tx.ChainID, tx.Message(), sig)
+	signer, err := nd.cn.TxVerifier().Verify(tx, sig, 0)
 	if err != nil {
 		return err
 	}
-	tx.From = pubkey.Address()
+	tx.From = signer
 
 	if !nd.txpool.IsExist(TxHash) {
 		nd.txWaitQ.Push(TxHash, &TxMsgItem{
@@ -652,11 +652,11 @@ func (nd *Node) addTx(TxHash hash.Hash256, tx *types.Transaction, sig common.Sig
 	if nd.txpool.IsExist(TxHash) {
 		return errors.WithStack(txpool.ErrExistTransaction)
 	}
-	pubkey, err := common.RecoverPubkey(tx.ChainID, tx.Message(), sig)
+	signer, err := nd.cn.TxVerifier().Verify(tx, sig, 0)
 	if err != nil {
 		return err
 	}
-	tx.From = pubkey.Address()
+	tx.From = signer
 
 	{
 		_ctx := nd.cn.NewContext()
@@ -0,0 +1,113 @@
+package bundle
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/core/chain"
+	"github.com/meverselabs/meverse/service/apiserver"
+)
+
+// INode is the generator which includes the bundles
+type INode interface {
+	AddBundle(bd *chain.Bundle) (hash.Hash256, error)
+}
+
+type bundleApi struct {
+	api *apiserver.APIServer
+	cn  *chain.Chain
+	in  INode
+}
+
+// NewBundleApi sets the bundle methods to the api server
+//
+// sendBundle queues the bundle to the generator and callBundle simulates it on the next block,
+// the bundle is the hex string of chain.Bundle
+func NewBundleApi(api *apiserver.APIServer, cn *chain.Chain, in INode) {
+	b := &bundleApi{
+		api: api,
+		cn:  cn,
+		in:  in,
+	}
+
+	s, err := b.api.JRPC("bundle")
+	if err != nil {
+		panic(err)
+	}
+	s.Set("sendBundle", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+		bd, err := readBundle(arg)
+		if err != nil {
+			return nil, err
+		}
+		h, err := b.in.AddBundle(bd)
+		if err != nil {
+			return nil, err
+		}
+		return h.String(), nil
+	})
+	s.Set("callBundle", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+		bd, err := readBundle(arg)
+		if err != nil {
+			return nil, err
+		}
+		var Timestamp uint64
+		if arg.Len() > 1 {
+			if Timestamp, err = arg.Uint64(1); err != nil {
+				return nil, err
+			}
+		}
+		result, err := b.cn.CallBundle(bd, Timestamp)
+		if err != nil {
+			if be, ok := err.(*chain.BundleError); ok {
+				return map[string]interface{}{
+					"bundleHash": bd.Hash().String(),
+					"failIndex":  be.Index,
+					"error":      be.Err.Error(),
+				}, nil
+			}
+			return nil, err
+		}
+		txs := []interface{}{}
+		for i, TxHash := range result.TxHashes {
+			events := []interface{}{}
+			for _, en := range result.Events {
+				if int(en.Index) == i {
+					events = append(events, map[string]interface{}{
+						"type":   en.Type.String(),
+						"result": hex.EncodeToString(en.Result),
+					})
+				}
+			}
+			txs = append(txs, map[string]interface{}{
+				"txHash": TxHash.String(),
+				"logs":   result.Receipts[i].Logs,
+				"events": events,
+			})
+		}
+		return map[string]interface{}{
+			"bundleHash": bd.Hash().String(),
+			"height":     result.Height,
+			"txs":        txs,
+		}, nil
+	})
+}
+
+func readBundle(arg *apiserver.Argument) (*chain.Bundle, error) {
+	str, err := arg.String(0)
+	if err != nil {
+		return nil, err
+	}
+	bs, err := hex.DecodeString(strings.TrimPrefix(str, "0x"))
+	if err != nil {
+		return nil, err
+	}
+	bd := &chain.Bundle{}
+	if _, err := bd.ReadFrom(bytes.NewReader(bs)); err != nil {
+		return nil, errors.Wrap(err, "invalid bundle")
+	}
+	return bd, nil
+}
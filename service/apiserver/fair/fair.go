@@ -0,0 +1,66 @@
+package fair
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/common/threshold"
+	"github.com/meverselabs/meverse/service/apiserver"
+)
+
+// INode is the generator which commits the sealed transactions
+type INode interface {
+	FairOrderKey() *threshold.PublicKey
+	AddSealedTx(ct *threshold.Ciphertext) (hash.Hash256, error)
+}
+
+type fairApi struct {
+	api *apiserver.APIServer
+	in  INode
+}
+
+// NewFairApi sets the fair ordering methods to the api server
+//
+// publicKey returns the hex string of the key to seal the transactions and
+// sendSealedTx queues the hex string of the ciphertext made by chain.SealTransaction
+func NewFairApi(api *apiserver.APIServer, in INode) {
+	f := &fairApi{
+		api: api,
+		in:  in,
+	}
+
+	s, err := f.api.JRPC("fair")
+	if err != nil {
+		panic(err)
+	}
+	s.Set("publicKey", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+		pk := f.in.FairOrderKey()
+		if pk == nil {
+			return nil, errors.New("fair order disabled")
+		}
+		return pk.String(), nil
+	})
+	s.Set("sendSealedTx", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+		str, err := arg.String(0)
+		if err != nil {
+			return nil, err
+		}
+		bs, err := hex.DecodeString(strings.TrimPrefix(str, "0x"))
+		if err != nil {
+			return nil, err
+		}
+		ct := &threshold.Ciphertext{}
+		if _, err := ct.ReadFrom(bytes.NewReader(bs)); err != nil {
+			return nil, errors.Wrap(err, "invalid sealed transaction")
+		}
+		h, err := f.in.AddSealedTx(ct)
+		if err != nil {
+			return nil, err
+		}
+		return h.String(), nil
+	})
+}
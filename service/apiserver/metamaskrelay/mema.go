@@ -617,6 +617,10 @@ func getReceipt(tx *types.Transaction, b *types.Block, TxID itxsearch.TxID, m *m
 			"status":            "0x1", //TODO 성공 1 실패 0
 			"data":              map[string]string{},
 		}
+
+		if receipt, err = bloomservice.NativeReceipt(m.cn, b, TxID.Index); err != nil {
+			return nil, err
+		}
 	} else {
 		etx := new(etypes.Transaction)
 		if err := etx.UnmarshalBinary(tx.Args); err != nil {
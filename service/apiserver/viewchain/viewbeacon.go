@@ -0,0 +1,79 @@
+package viewchain
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/core/chain"
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/meverselabs/meverse/service/apiserver"
+)
+
+func (v *viewchain) setBeaconMethods(s *apiserver.JRPCSub) {
+	// beacon returns the beacon of the height with the observers which made it
+	s.Set("beacon", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+		height, err := arg.Uint32(0)
+		if err != nil {
+			return nil, err
+		}
+		bn, value, blockHeight, err := v.Beacon(height)
+		if err != nil {
+			return nil, err
+		}
+		observers := make([]common.PublicKey, 0, len(bn.Proofs))
+		for _, bp := range bn.Proofs {
+			observers = append(observers, bp.PublicKey)
+		}
+		return map[string]interface{}{
+			"height":    height,
+			"value":     value.String(),
+			"block":     blockHeight,
+			"observers": observers,
+		}, nil
+	})
+	// verifyBeacon checks the beacon value of the height by the vrf proofs of the observers
+	s.Set("verifyBeacon", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+		height, err := arg.Uint32(0)
+		if err != nil {
+			return nil, err
+		}
+		str, err := arg.String(1)
+		if err != nil {
+			return nil, err
+		}
+		_, value, _, err := v.Beacon(height)
+		if err != nil {
+			return false, nil
+		}
+		return value == hash.HexToHash(str), nil
+	})
+}
+
+// Beacon verifies the beacon of the height by the block which includes it and returns the value with the height of the block
+func (v *viewchain) Beacon(height uint32) (*types.Beacon, hash.Hash256, uint32, error) {
+	ctx := v.cn.NewContext()
+	blockHeight, has := ctx.BeaconBlock(height)
+	if !has {
+		return nil, hash.Hash256{}, 0, errors.New("beacon not found")
+	}
+	b, err := v.cn.Provider().Block(blockHeight)
+	if err != nil {
+		return nil, hash.Hash256{}, 0, err
+	}
+	bn, err := chain.BlockBeacon(b)
+	if err != nil {
+		return nil, hash.Hash256{}, 0, err
+	}
+	if bn == nil || bn.Height != height {
+		return nil, hash.Hash256{}, 0, errors.New("beacon not found")
+	}
+	value, err := v.cn.VerifyBeacon(bn)
+	if err != nil {
+		return nil, hash.Hash256{}, 0, err
+	}
+	if value != b.Header.Beacon {
+		return nil, hash.Hash256{}, 0, errors.WithStack(chain.ErrInvalidBeacon)
+	}
+	return bn, value, blockHeight, nil
+}
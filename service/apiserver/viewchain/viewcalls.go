@@ -0,0 +1,204 @@
+package viewchain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/core/ctypes"
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/meverselabs/meverse/service/apiserver"
+	"github.com/meverselabs/meverse/service/txsearch"
+	"github.com/meverselabs/meverse/service/txsearch/itxsearch"
+)
+
+func (v *viewchain) setCallMethods(s *apiserver.JRPCSub) {
+	// searchCalls returns the calls of the method of the contract by the method call index
+	s.Set("searchCalls", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+		cont, err := arg.String(0)
+		if err != nil {
+			return nil, errors.New("need contract address")
+		}
+		method, err := arg.String(1)
+		if err != nil {
+			return nil, errors.New("method not allow")
+		}
+		start, end, cursor, limit, err := v.callRangeArgs(arg, 2)
+		if err != nil {
+			return nil, err
+		}
+		refs, next, err := v.ts.MethodCalls(common.HexToAddress(cont), method, start, end, cursor, limit)
+		if err != nil {
+			return nil, err
+		}
+		return v.callPage(refs, next), nil
+	})
+	// searchCallsFrom returns the calls of the contract by the caller by the method call index
+	s.Set("searchCallsFrom", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+		cont, err := arg.String(0)
+		if err != nil {
+			return nil, errors.New("need contract address")
+		}
+		from, err := arg.String(1)
+		if err != nil {
+			return nil, errors.New("need from address")
+		}
+		start, end, cursor, limit, err := v.callRangeArgs(arg, 2)
+		if err != nil {
+			return nil, err
+		}
+		refs, next, err := v.ts.CallerCalls(common.HexToAddress(cont), common.HexToAddress(from), start, end, cursor, limit)
+		if err != nil {
+			return nil, err
+		}
+		return v.callPage(refs, next), nil
+	})
+}
+
+// callRangeArgs parses the startBlock, lastBlock, cursor and limit from the index
+func (v *viewchain) callRangeArgs(arg *apiserver.Argument, idx int) (uint32, uint32, string, int, error) {
+	startBlock, err := arg.Uint32(idx)
+	if err != nil {
+		return 0, 0, "", 0, errors.New("startBlock not allow")
+	}
+	th := v.st.TargetHeight()
+	lastBlock, err := arg.Uint32(idx + 1)
+	if err != nil {
+		lastBlockStr, _ := arg.String(idx + 1)
+		if lastBlockStr == "latest" {
+			lastBlock = th
+		} else {
+			return 0, 0, "", 0, errors.New("lastBlock not allow")
+		}
+	}
+	if startBlock > lastBlock {
+		startBlock, lastBlock = lastBlock, startBlock
+	}
+	if lastBlock > th {
+		lastBlock = th
+	}
+	if indexFrom := v.ts.CallIndexFrom(); startBlock < indexFrom {
+		return 0, 0, "", 0, fmt.Errorf("method calls are indexed from %v", indexFrom)
+	}
+	cursor, _ := arg.String(idx + 2)
+	limit, err := arg.Int(idx + 3)
+	if err != nil {
+		limit = 100
+	}
+	return startBlock, lastBlock, cursor, limit, nil
+}
+
+func (v *viewchain) callPage(refs []itxsearch.CallRef, next string) map[string]interface{} {
+	return map[string]interface{}{
+		"calls":  v.callMaps(refs, nil),
+		"cursor": next,
+	}
+}
+
+// searchIndexedCalls returns the calls of the search map between the heights by the method call index
+//
+// it is not paginated, so the calls over MaxCallListSize are rejected and should be queried by searchCalls
+func (v *viewchain) searchIndexedCalls(searchMap map[common.Address]map[string]bool, start, end uint32) ([]map[string]string, error) {
+	refs := []itxsearch.CallRef{}
+	for cont, methods := range searchMap {
+		for method := range methods {
+			rs, next, err := v.ts.MethodCalls(cont, method, start, end, "", txsearch.MaxCallListSize)
+			if err != nil {
+				return nil, err
+			}
+			refs = append(refs, rs...)
+			if next != "" || len(refs) > txsearch.MaxCallListSize {
+				return nil, errors.Errorf("too many calls (over %v), use searchCalls", txsearch.MaxCallListSize)
+			}
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Height != refs[j].Height {
+			return refs[i].Height < refs[j].Height
+		}
+		return refs[i].Event < refs[j].Event
+	})
+	return v.callMaps(refs, searchMap), nil
+}
+
+// callMaps loads the call history events of the refs, the events which are not in the search map are skipped if it is given
+func (v *viewchain) callMaps(refs []itxsearch.CallRef, searchMap map[common.Address]map[string]bool) []map[string]string {
+	txList := []map[string]string{}
+	var b *types.Block
+	for _, ref := range refs {
+		if b == nil || b.Header.Height != ref.Height {
+			var err error
+			if b, err = v.st.Block(ref.Height); err != nil {
+				b = nil
+				continue
+			}
+		}
+		if len(b.Body.Events) <= int(ref.Event) {
+			continue
+		}
+		e := b.Body.Events[ref.Event]
+		mc, ok := methodCallEvent(e)
+		if !ok {
+			continue
+		}
+		if searchMap != nil {
+			sm, ok := searchMap[mc.To]
+			if !ok {
+				continue
+			}
+			if _, ok := sm[strings.ToLower(mc.Method)]; !ok {
+				continue
+			}
+		}
+		if m, ok := callEventMap(b, e, mc); ok {
+			txList = append(txList, m)
+		}
+	}
+	return txList
+}
+
+func methodCallEvent(e *ctypes.Event) (*ctypes.MethodCallEvent, bool) {
+	if e.Type != ctypes.EventTagCallHistory {
+		return nil, false
+	}
+	mc := &ctypes.MethodCallEvent{}
+	if _, err := mc.ReadFrom(bytes.NewBuffer(e.Result)); err != nil {
+		return nil, false
+	}
+	return mc, true
+}
+
+func callEventMap(b *types.Block, e *ctypes.Event, mc *ctypes.MethodCallEvent) (map[string]string, bool) {
+	if len(b.Body.Transactions) <= int(e.Index) {
+		return nil, false
+	}
+	tx := b.Body.Transactions[e.Index]
+
+	m := map[string]string{
+		"Type":      "Event",
+		"Contract":  mc.To.String(),
+		"From":      mc.From.String(),
+		"TxFrom":    tx.From.String(),
+		"TxTo":      tx.To.String(),
+		"Method":    mc.Method,
+		"Height":    fmt.Sprintf("%v", b.Header.Height),
+		"Timestamp": fmt.Sprintf("%v", b.Header.Timestamp),
+		"Index":     fmt.Sprintf("%v", e.Index),
+		"Hash":      tx.Hash(b.Header.Height).String(),
+		"TXID":      types.TransactionID(b.Header.Height, e.Index),
+	}
+	args, err := json.Marshal(mc.Args)
+	if err == nil {
+		m["Args"] = string(args)
+	}
+	result, err := json.Marshal(mc.Result)
+	if err == nil {
+		m["Result"] = string(result)
+	}
+	return m, true
+}
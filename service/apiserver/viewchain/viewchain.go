@@ -20,7 +20,6 @@ import (
 	"github.com/meverselabs/meverse/contract/formulator"
 	"github.com/meverselabs/meverse/contract/token"
 	"github.com/meverselabs/meverse/core/chain"
-	"github.com/meverselabs/meverse/core/ctypes"
 	"github.com/meverselabs/meverse/core/types"
 	"github.com/meverselabs/meverse/service/apiserver"
 	"github.com/meverselabs/meverse/service/bloomservice"
@@ -207,6 +206,26 @@ func NewViewchain(api *apiserver.APIServer, ts itxsearch.ITxSearch, cn *chain.Ch
 		return ctx.IsContract(cont), nil
 	})
 
+	s.Set("contractAbi", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+		contract, err := arg.String(0)
+		if err != nil {
+			return nil, errors.New("need contract address")
+		}
+		addr, err := common.ParseAddress(contract)
+		if err != nil {
+			return nil, err
+		}
+		cont, err := v.cn.NewContext().Contract(addr)
+		if err != nil {
+			return nil, err
+		}
+		bs, err := types.ContractABIJSON(cont)
+		if err != nil {
+			return nil, err
+		}
+		return json.RawMessage(bs), nil
+	})
+
 	s.Set("call", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
 		contract, err := arg.String(0)
 		if err != nil {
@@ -307,7 +326,7 @@ func NewViewchain(api *apiserver.APIServer, ts itxsearch.ITxSearch, cn *chain.Ch
 			startBlock, lastBlock = lastBlock, startBlock
 		}
 		if lastBlock-startBlock > 1024 {
-			return nil, errors.New("search ragne less then 1024")
+			return nil, errors.New("search ragne less then 1024, use searchCalls for the wider range")
 		}
 		smap := map[common.Address]map[string]bool{}
 		for cont, methods := range searchMap {
@@ -333,7 +352,7 @@ func NewViewchain(api *apiserver.APIServer, ts itxsearch.ITxSearch, cn *chain.Ch
 			}
 		}
 
-		return v.Search(smap, startBlock, lastBlock), nil
+		return v.Search(smap, startBlock, lastBlock)
 	})
 
 	s.Set("search", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
@@ -365,7 +384,7 @@ func NewViewchain(api *apiserver.APIServer, ts itxsearch.ITxSearch, cn *chain.Ch
 			startBlock, lastBlock = lastBlock, startBlock
 		}
 		if lastBlock-startBlock > 1024 {
-			return nil, errors.New("search ragne less then 1024")
+			return nil, errors.New("search ragne less then 1024, use searchCalls for the wider range")
 		}
 		th := st.TargetHeight()
 		if startBlock > th || lastBlock > th {
@@ -394,7 +413,7 @@ func NewViewchain(api *apiserver.APIServer, ts itxsearch.ITxSearch, cn *chain.Ch
 			// methods = append(methods, methodStr)
 		}
 
-		return v.Search(smap, startBlock, lastBlock), nil
+		return v.Search(smap, startBlock, lastBlock)
 	})
 
 	s.Set("calcRewardPower", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
@@ -600,6 +619,15 @@ func NewViewchain(api *apiserver.APIServer, ts itxsearch.ITxSearch, cn *chain.Ch
 	s.Set("clientVersion", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
 		return GetVersion(), nil
 	})
+	s.Set("bloomReindexStatus", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+		if v.bs == nil {
+			return nil, errors.New("bloom service is not running")
+		}
+		return v.bs.ReindexStatus(), nil
+	})
+	v.setWasmMethods(s)
+	v.setBeaconMethods(s)
+	v.setCallMethods(s)
 }
 
 func (v *viewchain) getTokenBalanceOf(conAddr common.Address, addr common.Address) (string, error) {
@@ -637,7 +665,7 @@ func (v *viewchain) MultiCall(contract []string, from string, methods []string,
 }
 
 // map[common.Address]map[string]bool{}
-func (v *viewchain) Search(searchMap map[common.Address]map[string]bool, start, end uint32) (txList []map[string]string) {
+func (v *viewchain) Search(searchMap map[common.Address]map[string]bool, start, end uint32) (txList []map[string]string, err error) {
 	txList = []map[string]string{}
 
 	filterMap := map[string]interface{}{
@@ -697,19 +725,16 @@ func (v *viewchain) Search(searchMap map[common.Address]map[string]bool, start,
 		}
 	}
 
-	for i := start; i <= end; i++ {
+	indexFrom := v.ts.CallIndexFrom()
+	for i := start; i <= end && i < indexFrom; i++ {
 		b, err := v.st.Block(i)
 		if err != nil {
 			continue
 		}
 
 		for _, e := range b.Body.Events {
-			if e.Type != ctypes.EventTagCallHistory {
-				continue
-			}
-			bf := bytes.NewBuffer(e.Result)
-			mc := &ctypes.MethodCallEvent{}
-			if _, err := mc.ReadFrom(bf); err != nil {
+			mc, ok := methodCallEvent(e)
+			if !ok {
 				continue
 			}
 			sm, ok := searchMap[mc.To]
@@ -719,37 +744,22 @@ func (v *viewchain) Search(searchMap map[common.Address]map[string]bool, start,
 			if _, ok := sm[strings.ToLower(mc.Method)]; !ok {
 				continue
 			}
-			if len(b.Body.Transactions) <= int(e.Index) {
-				continue
-			}
-			tx := b.Body.Transactions[e.Index]
-
-			m := map[string]string{
-				"Type":      "Event",
-				"Contract":  mc.To.String(),
-				"From":      mc.From.String(),
-				"TxFrom":    tx.From.String(),
-				"TxTo":      tx.To.String(),
-				"Method":    mc.Method,
-				"Height":    fmt.Sprintf("%v", b.Header.Height),
-				"Timestamp": fmt.Sprintf("%v", b.Header.Timestamp),
-				"Index":     fmt.Sprintf("%v", e.Index),
-				"Hash":      tx.Hash(b.Header.Height).String(),
-				"TXID":      types.TransactionID(b.Header.Height, e.Index),
-			}
-			args, err := json.Marshal(mc.Args)
-			if err == nil {
-				m["Args"] = string(args)
-			}
-			result, err := json.Marshal(mc.Result)
-			if err == nil {
-				m["Result"] = string(result)
+			if m, ok := callEventMap(b, e, mc); ok {
+				txList = append(txList, m)
 			}
-
-			txList = append(txList, m)
 		}
 	}
-	return
+	if end >= indexFrom {
+		if start < indexFrom {
+			start = indexFrom
+		}
+		calls, err := v.searchIndexedCalls(searchMap, start, end)
+		if err != nil {
+			return nil, err
+		}
+		txList = append(txList, calls...)
+	}
+	return txList, nil
 }
 
 func (v *viewchain) Call(contract, from, method string, params []interface{}) (interface{}, error) {
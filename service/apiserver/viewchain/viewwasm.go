@@ -0,0 +1,170 @@
+package viewchain
+
+import (
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/contract/wasm"
+	"github.com/meverselabs/meverse/core/chain"
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/meverselabs/meverse/service/apiserver"
+)
+
+// WasmDeployMethod is the method name of the wasm deploy transaction
+const WasmDeployMethod = "Deploy"
+
+func (v *viewchain) setWasmMethods(s *apiserver.JRPCSub) {
+	s.Set("wasmDeployTx", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+		sCode, err := arg.String(0)
+		if err != nil {
+			return nil, errors.New("need wasm code")
+		}
+		code, err := hex.DecodeString(strings.TrimPrefix(sCode, "0x"))
+		if err != nil {
+			return nil, err
+		}
+		initArgs := []interface{}{}
+		if arg.Len() > 1 {
+			if initArgs, err = arg.Array(1); err != nil {
+				return nil, errors.New("init args not allow")
+			}
+		}
+		bs, err := chain.WasmDeployArgs(code, initArgs)
+		if err != nil {
+			return nil, err
+		}
+		tim := uint64(time.Now().UnixNano())
+		tx := v.wasmDeployTx(tim, bs)
+		var bsw []byte
+		if seq, err := arg.Uint64(2); err == nil {
+			tx.Seq = seq
+			tx.UseSeq = true
+			bsw = bin.TypeWriteAll(tim, bs, seq)
+		} else {
+			bsw = bin.TypeWriteAll(tim, bs)
+		}
+		return []interface{}{tx.HashSig().String(), hex.EncodeToString(bsw)}, nil
+	})
+	s.Set("sendWasmDeployTx", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+		ssig, err := arg.String(0)
+		if err != nil {
+			return nil, err
+		}
+		body, err := arg.String(1)
+		if err != nil {
+			return nil, err
+		}
+		sig, err := hex.DecodeString(ssig)
+		if err != nil {
+			return nil, err
+		}
+		bs, err := hex.DecodeString(body)
+		if err != nil {
+			return nil, err
+		}
+		is, err := bin.TypeReadAll(bs, -1)
+		if err != nil {
+			return nil, err
+		}
+		if len(is) < 2 {
+			return nil, errors.New("invalid parameter")
+		}
+		tim, ok := is[0].(uint64)
+		if !ok {
+			return nil, errors.New("converting error")
+		}
+		args, ok := is[1].([]byte)
+		if !ok {
+			return nil, errors.New("converting error")
+		}
+		tx := v.wasmDeployTx(tim, args)
+		if len(is) > 2 {
+			seq, ok := is[2].(uint64)
+			if !ok {
+				return nil, errors.New("invalid parameter")
+			}
+			tx.Seq = seq
+			tx.UseSeq = true
+		}
+		return tx.HashSig().String(), v.in.AddTx(tx, common.Signature(sig))
+	})
+	s.Set("wasmCall", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+		contract, err := arg.String(0)
+		if err != nil {
+			return nil, errors.New("need contract address")
+		}
+		method, err := arg.String(1)
+		if err != nil {
+			return nil, errors.New("method not allow")
+		}
+		param, err := arg.Array(2)
+		if err != nil {
+			return nil, errors.New("parameter not allow")
+		}
+		from, _ := arg.String(3)
+		cont, err := common.ParseAddress(contract)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := v.wasmContract(cont); err != nil {
+			return nil, err
+		}
+		output, gas, err := NewViewCaller(v.cn).Execute(cont, from, method, param)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"result": output,
+			"gas":    gas,
+		}, nil
+	})
+	s.Set("wasmCode", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+		contract, err := arg.String(0)
+		if err != nil {
+			return nil, errors.New("need contract address")
+		}
+		addr, err := common.ParseAddress(contract)
+		if err != nil {
+			return nil, err
+		}
+		cont, err := v.wasmContract(addr)
+		if err != nil {
+			return nil, err
+		}
+		cc := v.cn.NewContext().ContractLoader(addr)
+		return map[string]interface{}{
+			"codeHash": cont.CodeHash(cc).String(),
+			"size":     len(cont.Code(cc)),
+			"master":   cont.Master().String(),
+		}, nil
+	})
+}
+
+func (v *viewchain) wasmDeployTx(tim uint64, args []byte) *types.Transaction {
+	return &types.Transaction{
+		ChainID:   v.chainID,
+		Version:   2,
+		Timestamp: tim,
+		To:        common.ZeroAddr,
+		Method:    WasmDeployMethod,
+		Args:      args,
+		VmType:    types.Wasm,
+	}
+}
+
+func (v *viewchain) wasmContract(addr common.Address) (*wasm.WasmContract, error) {
+	c, err := v.cn.NewContext().Contract(addr)
+	if err != nil {
+		return nil, err
+	}
+	cont, ok := c.(*wasm.WasmContract)
+	if !ok {
+		return nil, errors.New("not wasm contract")
+	}
+	return cont, nil
+}
@@ -166,7 +166,9 @@ func (s *ZipContextService) ZipContext(savePath string) (filePath string, err er
 		return
 	} else {
 		defer func() {
-			err = os.RemoveAll(filepath.Dir(zipContextPath))
+			if rerr := os.RemoveAll(filepath.Dir(zipContextPath)); err == nil {
+				err = rerr
+			}
 		}()
 	}
 
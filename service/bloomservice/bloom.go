@@ -8,6 +8,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	etypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/meverselabs/meverse/common/bin"
 	"github.com/meverselabs/meverse/core/chain"
 	"github.com/meverselabs/meverse/core/ctypes"
 	"github.com/meverselabs/meverse/core/types"
@@ -237,6 +238,9 @@ func TxLogsBloom(cn *chain.Chain, b *types.Block, idx uint16, receipt *etypes.Re
 	if err != nil {
 		return etypes.Bloom{}, nil, err
 	}
+	if evs, err = skipNativeLogged(evs, receipt); err != nil {
+		return etypes.Bloom{}, nil, err
+	}
 	if evs != nil {
 		bloom, err = CreateEventBloom(cn.Provider(), evs)
 		if err != nil {
@@ -261,3 +265,58 @@ func TxLogsBloom(cn *chain.Chain, b *types.Block, idx uint16, receipt *etypes.Re
 	}
 	return bloom, logs, nil
 }
+
+// NativeReceipt returns the stored receipt of the non-evm transaction with the derived log fields
+func NativeReceipt(cn *chain.Chain, b *types.Block, idx uint16) (*etypes.Receipt, error) {
+	if b.Header.Height <= cn.Provider().InitHeight() {
+		return nil, nil
+	}
+	receipts, err := cn.Provider().Receipts(b.Header.Height)
+	if err != nil {
+		return nil, err
+	}
+	if int(idx) >= len(receipts) {
+		return nil, nil
+	}
+	receipt := receipts[idx]
+	deriveNativeLogs(receipt, &b.Header, b.Body.Transactions[idx], idx, 0)
+	return receipt, nil
+}
+
+// deriveNativeLogs sets the log fields of the non-evm transaction receipt from the block and transaction
+func deriveNativeLogs(receipt *etypes.Receipt, header *types.Header, tx *types.Transaction, idx uint16, offset int) {
+	bHash := bin.MustWriterToHash(header)
+	txHash := tx.Hash(header.Height)
+	for i, l := range receipt.Logs {
+		l.BlockNumber = uint64(header.Height)
+		l.BlockHash = bHash
+		l.TxHash = txHash
+		l.TxIndex = uint(idx)
+		l.Index = uint(offset + i)
+	}
+}
+
+// skipNativeLogged removes the call history events of the token methods
+// when the contract already wrote the token logs of them to the receipt
+func skipNativeLogged(evs []*ctypes.Event, receipt *etypes.Receipt) ([]*ctypes.Event, error) {
+	if receipt == nil || len(receipt.Logs) == 0 || len(evs) == 0 {
+		return evs, nil
+	}
+	logged := map[common.Address]bool{}
+	for _, l := range receipt.Logs {
+		logged[l.Address] = true
+	}
+	tokenMethods := convertMap["token.TokenContract"]
+	ret := []*ctypes.Event{}
+	for _, ev := range evs {
+		mc := &ctypes.MethodCallEvent{}
+		if _, err := mc.ReadFrom(bytes.NewReader(ev.Result)); err != nil {
+			return nil, err
+		}
+		if _, has := tokenMethods[mc.Method]; has && logged[mc.To] {
+			continue
+		}
+		ret = append(ret, ev)
+	}
+	return ret, nil
+}
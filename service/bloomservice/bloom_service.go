@@ -8,6 +8,7 @@ import (
 	"github.com/meverselabs/meverse/core/chain"
 	"github.com/meverselabs/meverse/core/types"
 	"github.com/meverselabs/meverse/ethereum/core/bloombits"
+	"github.com/meverselabs/meverse/service/reindex"
 )
 
 type BloomBitService struct {
@@ -18,6 +19,8 @@ type BloomBitService struct {
 
 	bloomRequests     chan chan *bloombits.Retrieval // Channel receiving bloom data retrieval requests
 	closeBloomHandler chan struct{}
+
+	reindex reindex.Progress
 }
 
 // NewBloomBitService starts a bloom service that performs bloom bit indexer and returns it
@@ -98,10 +101,13 @@ func (b *BloomBitService) OnBlockConnected(block *types.Block, loader types.Load
 		// c.lock.Lock()
 
 		// If processing succeeded and no reorgs occurred, mark the section completed
-		if err == nil && (section == 0 || oldHead == c.SectionHead(section-1)) {
+		// unless the reindex has marked it already
+		c.lock.Lock()
+		if err == nil && section == c.storedSections && (section == 0 || oldHead == c.SectionHead(section-1)) {
 			c.setSectionHead(section, newHead)
 			c.setValidSections(section + 1)
 		}
+		c.lock.Unlock()
 	}
 
 }
@@ -190,10 +190,16 @@ func (c *ChainIndexer) newHead(head uint64) {
 // held while processing, the continuity can be broken by a long reorg, in which
 // case the function returns with an error.
 func (c *ChainIndexer) processSection(section uint64, lastHead common.Hash) (common.Hash, error) {
+	return c.processSectionWith(c.backend, section, lastHead)
+}
+
+// processSectionWith processes an entire section by the given backend, so the
+// sections can be processed in parallel by the backends of their own.
+func (c *ChainIndexer) processSectionWith(backend ChainIndexerBackend, section uint64, lastHead common.Hash) (common.Hash, error) {
 	c.log.Trace("Processing new chain section", "section", section)
 
 	// Reset and partial processing
-	if err := c.backend.Reset(c.ctx, section, lastHead); err != nil {
+	if err := backend.Reset(c.ctx, section, lastHead); err != nil {
 		c.setValidSections(0)
 		return common.Hash{}, err
 	}
@@ -213,12 +219,12 @@ func (c *ChainIndexer) processSection(section uint64, lastHead common.Hash) (com
 		if header.PrevHash != lastHead {
 			return common.Hash{}, fmt.Errorf("chain reorged during section processing")
 		}
-		if err := c.backend.Process(c.ctx, block); err != nil {
+		if err := backend.Process(c.ctx, block); err != nil {
 			return common.Hash{}, err
 		}
 		lastHead = hash
 	}
-	if err := c.backend.Commit(); err != nil {
+	if err := backend.Commit(); err != nil {
 		return common.Hash{}, err
 	}
 	return lastHead, nil
@@ -427,24 +427,14 @@ func blockShortLogs(chain *chain.Chain, block *mtypes.Block) ([][]*types.Log, er
 	}
 	var logList [][]*types.Log
 	for i := uint16(0); i < uint16(len(receipts)); i++ {
-		tx := block.Body.Transactions[i]
-		if tx.VmType == mtypes.Evm {
-			// block.events -> logs(short)
-			logs, err := blockEventsShortLogs(chain, block, i)
-			if err != nil {
-				return nil, err
-			}
-			logList = append(logList, logs)
-			// receipt.logs
-			logList = append(logList, receipts[i].Logs)
-		} else {
-			// block.events -> logs(short)
-			logs, err := blockEventsShortLogs(chain, block, i)
-			if err != nil {
-				return nil, err
-			}
-			logList = append(logList, logs)
+		// block.events -> logs(short)
+		logs, err := blockEventsShortLogs(chain, block, i, receipts[i])
+		if err != nil {
+			return nil, err
 		}
+		logList = append(logList, logs)
+		// receipt.logs (evm, native contract logs)
+		logList = append(logList, receipts[i].Logs)
 	}
 
 	return logList, nil
@@ -466,7 +456,7 @@ func blockFullLogs(chain *chain.Chain, block *mtypes.Block) ([][]*types.Log, err
 		tx := block.Body.Transactions[i]
 		if tx.VmType == mtypes.Evm {
 			// block.events -> logs (full)
-			logs, err := blockEventsFullLogs(chain, block, i)
+			logs, err := blockEventsFullLogs(chain, block, i, receipts[i])
 			if err != nil {
 				return nil, err
 			}
@@ -484,11 +474,15 @@ func blockFullLogs(chain *chain.Chain, block *mtypes.Block) ([][]*types.Log, err
 			logList = append(logList, receipts[i].Logs)
 		} else {
 			// block.events -> logs (full)
-			logs, err := blockEventsFullLogs(chain, block, i)
+			logs, err := blockEventsFullLogs(chain, block, i, receipts[i])
 			if err != nil {
 				return nil, err
 			}
 			logList = append(logList, logs)
+
+			// native contract receipt.logs
+			deriveNativeLogs(receipts[i], header, tx, i, len(logs))
+			logList = append(logList, receipts[i].Logs)
 		}
 	}
 
@@ -496,12 +490,15 @@ func blockFullLogs(chain *chain.Chain, block *mtypes.Block) ([][]*types.Log, err
 }
 
 // eventShortLogs returns logs converted from transaction events (evm, non-evm)
-func blockEventsShortLogs(chain *chain.Chain, block *mtypes.Block, idx uint16) ([]*types.Log, error) {
+func blockEventsShortLogs(chain *chain.Chain, block *mtypes.Block, idx uint16, receipt *types.Receipt) ([]*types.Log, error) {
 
 	evs, err := FindCallHistoryEvents(block.Body.Events, idx)
 	if err != nil {
 		return nil, err
 	}
+	if evs, err = skipNativeLogged(evs, receipt); err != nil {
+		return nil, err
+	}
 
 	logs := []*types.Log{}
 	for j := 0; j < len(evs); j++ {
@@ -531,7 +528,7 @@ func blockEventsShortLogs(chain *chain.Chain, block *mtypes.Block, idx uint16) (
 }
 
 // eventFullLogs returns logs converted from transaction events (evm, non-evm)
-func blockEventsFullLogs(chain *chain.Chain, block *mtypes.Block, idx uint16) ([]*types.Log, error) {
+func blockEventsFullLogs(chain *chain.Chain, block *mtypes.Block, idx uint16, receipt *types.Receipt) ([]*types.Log, error) {
 
 	tx := block.Body.Transactions[idx]
 
@@ -540,6 +537,9 @@ func blockEventsFullLogs(chain *chain.Chain, block *mtypes.Block, idx uint16) ([
 	if err != nil {
 		return nil, err
 	}
+	if evs, err = skipNativeLogged(evs, receipt); err != nil {
+		return nil, err
+	}
 	logs, err := EventsToFullLogs(chain, header, tx, evs, idx)
 	if err != nil {
 		return nil, err
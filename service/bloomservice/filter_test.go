@@ -58,6 +58,15 @@ func (t *tsMock) TokenTxList(From common.Address, index, size int) ([]itxsearch.
 }
 func (t *tsMock) Reward(cont, rewarder common.Address) (*amount.Amount, error) { return nil, nil }
 
+// CallIndexFrom returns the max height, so the method calls are searched by the block scan
+func (t *tsMock) CallIndexFrom() uint32 { return ^uint32(0) }
+func (t *tsMock) MethodCalls(cont common.Address, method string, start, end uint32, cursor string, limit int) ([]itxsearch.CallRef, string, error) {
+	return nil, "", nil
+}
+func (t *tsMock) CallerCalls(cont common.Address, From common.Address, start, end uint32, cursor string, limit int) ([]itxsearch.CallRef, string, error) {
+	return nil, "", nil
+}
+
 func TestMutliTranactionFilter(t *testing.T) {
 	path := "_data"
 	chainID := big.NewInt(1337)
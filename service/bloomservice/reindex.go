@@ -0,0 +1,81 @@
+package bloomservice
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/meverselabs/meverse/service/reindex"
+)
+
+// Reindex rebuilds the bloom bits of the sections in the heights by the workers in the background while the blocks are connected
+//
+// only the completed sections are rebuilt and the progress counts the sections
+func (b *BloomBitService) Reindex(from, to uint32, workers int) error {
+	c := b.indexer
+	size := c.sectionSize
+
+	height := uint64(b.cn.Provider().Height())
+	var completed uint64
+	if height+1 >= c.confirmsReq {
+		completed = (height + 1 - c.confirmsReq) / size
+	}
+	first := uint64(from) / size
+	last := (uint64(to) + 1) / size
+	if last > completed {
+		last = completed
+	}
+	if first >= last {
+		return reindex.ErrInvalidRange
+	}
+	if err := b.reindex.Start("bloombits", uint32(first), uint32(last-1)); err != nil {
+		return err
+	}
+	go func() {
+		b.reindex.Finish(b.reindexSections(first, last, workers))
+	}()
+	return nil
+}
+
+// ReindexStatus returns the progress of the reindex
+func (b *BloomBitService) ReindexStatus() map[string]interface{} {
+	return b.reindex.Status()
+}
+
+func (b *BloomBitService) reindexSections(first, last uint64, workers int) error {
+	c := b.indexer
+	db := c.backend.(*BloomIndexer).db
+	provider := b.cn.Provider()
+	if err := reindex.Parallel(uint32(first), uint32(last-1), workers, func(i uint32) error {
+		section := uint64(i)
+		var lastHead common.Hash
+		if section > 0 {
+			_, h, err := blockAndHash(provider, c.initHeight, uint32(section*c.sectionSize-1))
+			if err != nil {
+				return err
+			}
+			lastHead = h
+		}
+		backend := &BloomIndexer{
+			cn:   b.cn,
+			size: b.size,
+			db:   db,
+		}
+		newHead, err := c.processSectionWith(backend, section, lastHead)
+		if err != nil {
+			return err
+		}
+		c.setSectionHead(section, newHead)
+		b.reindex.Add(1)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// the sections are valid when they are connected to the stored ones
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if first <= c.storedSections && last > c.storedSections {
+		c.setValidSections(last)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package bloomservice
+
+import (
+	"bytes"
+	"math/big"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/core/types"
+)
+
+// dumpBloomBits returns the whole entries of the bloom bits db
+func dumpBloomBits(b *BloomBitService) map[string]string {
+	m := map[string]string{}
+	iter := b.indexer.backend.(*BloomIndexer).db.NewIterator(nil, nil)
+	defer iter.Release()
+	for iter.Next() {
+		m[string(iter.Key())] = string(iter.Value())
+	}
+	return m
+}
+
+func waitReindex(t *testing.T, b *BloomBitService) {
+	for i := 0; i < 1000; i++ {
+		st := b.ReindexStatus()
+		if st["running"] == false {
+			if st["error"] != nil {
+				t.Fatalf("reindex is failed %v", st["error"])
+			}
+			if st["done"] != st["total"] {
+				t.Fatalf("reindex is done %v of %v", st["done"], st["total"])
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("reindex is not finished")
+}
+
+func TestBloomReindex(t *testing.T) {
+	path := "_data"
+	chainID := big.NewInt(1337)
+	version := uint16(1)
+
+	userKeys, err := getSingers(chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aliceKey, bobKey, charlieKey := userKeys[0], userKeys[1], userKeys[2]
+	alice, bob, charlie := aliceKey.PublicKey().Address(), bobKey.PublicKey().Address(), charlieKey.PublicKey().Address()
+
+	args := []interface{}{alice, bob, charlie}
+	tb, ret, err := prepare(path, true, chainID, version, alice, args, mevInitialize, &initContextInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tb.Close()
+
+	mev := ret[0].(common.Address)
+	ctx := tb.newContext()
+
+	if err := os.RemoveAll(bloomDataPath); err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(bloomDataPath)
+	bs, err := NewBloomBitService(tb.chain, bloomDataPath, bloomBitsBlocks, bloomConfirms)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 90; i++ {
+		txs := []*txWithSigner{}
+		if i%3 == 0 {
+			tx := &types.Transaction{
+				ChainID:   ctx.ChainID(),
+				Timestamp: uint64(time.Now().UnixNano()),
+				To:        mev,
+				Method:    "Transfer",
+				Args:      bin.TypeWriteAll(bob, amount.NewAmount(0, 1)),
+			}
+			txs = append(txs, &txWithSigner{tx, aliceKey})
+		}
+		block, err := tb.addBlock(ctx, txs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bs.OnBlockConnected(block, nil)
+		ctx = types.NewContext(tb.chain.Store())
+	}
+	_, sections := bs.BloomStatus()
+	if sections != 3 {
+		t.Fatalf("sections %v", sections)
+	}
+	crit := FilterQuery{FromBlock: big.NewInt(0), Topics: [][]common.Hash{{transferHash}}}
+	want, err := FilterLogs(tb.chain, &tsMock{}, bs, crit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the bloom bits are removed like the corrupted db and rebuilt by the reindex
+	before := dumpBloomBits(bs)
+	db := bs.indexer.backend.(*BloomIndexer).db
+	removed := 0
+	for k := range before {
+		if !bytes.HasPrefix([]byte(k), rawdb.BloomBitsIndexPrefix) {
+			if err := db.Delete([]byte(k)); err != nil {
+				t.Fatal(err)
+			}
+			removed++
+		}
+	}
+	if removed == 0 {
+		t.Fatal("bloom bits are not stored")
+	}
+
+	// the reindex is interrupted after the first section and resumed by the rest of the range
+	if err := bs.Reindex(0, uint32(bloomBitsBlocks)-1, 2); err != nil {
+		t.Fatal(err)
+	}
+	waitReindex(t, bs)
+	if err := bs.Reindex(uint32(bloomBitsBlocks), tb.chain.Provider().Height(), 2); err != nil {
+		t.Fatal(err)
+	}
+	waitReindex(t, bs)
+
+	if after := dumpBloomBits(bs); !reflect.DeepEqual(before, after) {
+		t.Fatalf("rebuilt bloom bits have %v entries, want %v", len(after), len(before))
+	}
+	got, err := FilterLogs(tb.chain, &tsMock{}, bs, crit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) || len(got) != 30 {
+		t.Fatalf("logs %v after the reindex, want %v", len(got), len(want))
+	}
+}
@@ -0,0 +1,8 @@
+package reindex
+
+import "errors"
+
+var (
+	ErrReindexRunning = errors.New("reindex is running")
+	ErrInvalidRange   = errors.New("invalid reindex range")
+)
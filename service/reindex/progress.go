@@ -0,0 +1,105 @@
+package reindex
+
+import (
+	"sync"
+	"time"
+)
+
+// Progress is the progress of the reindex of an index
+type Progress struct {
+	lock    sync.Mutex
+	target  string
+	from    uint32
+	to      uint32
+	done    uint32
+	running bool
+	err     error
+	begin   time.Time
+	end     time.Time
+}
+
+// Start starts the progress of the target, it returns ErrReindexRunning when the previous one is not finished
+func (p *Progress) Start(target string, from, to uint32) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.running {
+		return ErrReindexRunning
+	}
+	if from > to {
+		return ErrInvalidRange
+	}
+	p.target = target
+	p.from = from
+	p.to = to
+	p.done = 0
+	p.running = true
+	p.err = nil
+	p.begin = time.Now()
+	p.end = time.Time{}
+	return nil
+}
+
+// From returns the start of the range
+func (p *Progress) From() uint32 {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.from
+}
+
+// Extend extends the end of the range when the chain grows during the reindex
+func (p *Progress) Extend(to uint32) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if to > p.to {
+		p.to = to
+	}
+}
+
+// Add adds the count of the processed items
+func (p *Progress) Add(n uint32) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.done += n
+}
+
+// Finish finishes the progress with the error of the reindex
+func (p *Progress) Finish(err error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.running = false
+	p.err = err
+	p.end = time.Now()
+}
+
+// Status returns the status of the progress for the rpc
+func (p *Progress) Status() map[string]interface{} {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	m := map[string]interface{}{
+		"target":  p.target,
+		"from":    p.from,
+		"to":      p.to,
+		"done":    p.done,
+		"running": p.running,
+	}
+	if p.target != "" {
+		m["total"] = p.to - p.from + 1
+	}
+	if !p.begin.IsZero() {
+		end := p.end
+		if end.IsZero() {
+			end = time.Now()
+		}
+		m["elapsed"] = end.Sub(p.begin).String()
+	}
+	if p.err != nil {
+		m["error"] = p.err.Error()
+	}
+	return m
+}
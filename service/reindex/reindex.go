@@ -0,0 +1,92 @@
+package reindex
+
+import (
+	"sync"
+
+	"github.com/meverselabs/meverse/core/types"
+)
+
+// Parallel calls fn for every index between from and to by the workers, it stops at the first error
+func Parallel(from, to uint32, workers int, fn func(i uint32) error) error {
+	if from > to {
+		return ErrInvalidRange
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	var lock sync.Mutex
+	var firstErr error
+	next := uint64(from)
+	take := func() (uint32, bool) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if firstErr != nil || next > uint64(to) {
+			return 0, false
+		}
+		i := uint32(next)
+		next++
+		return i, true
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i, ok := take()
+				if !ok {
+					return
+				}
+				if err := fn(i); err != nil {
+					lock.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					lock.Unlock()
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// Blocks loads the blocks between from and to by the workers and calls fn in the order of the height
+func Blocks(provider types.Provider, from, to uint32, workers int, fn func(b *types.Block) error) error {
+	if from > to {
+		return ErrInvalidRange
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	batch := uint32(workers) * 16
+	for h := uint64(from); h <= uint64(to); h += uint64(batch) {
+		start := uint32(h)
+		end := start + batch - 1
+		if end < start || end > to {
+			end = to
+		}
+		bs := make([]*types.Block, end-start+1)
+		if err := Parallel(start, end, workers, func(i uint32) error {
+			b, err := provider.Block(i)
+			if err != nil {
+				return err
+			}
+			bs[i-start] = b
+			return nil
+		}); err != nil {
+			return err
+		}
+		for _, b := range bs {
+			if err := fn(b); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/meverselabs/meverse/extern/test/util"
+	"github.com/meverselabs/meverse/service/reindex"
+)
+
+func TestProgress(t *testing.T) {
+	var p reindex.Progress
+	if err := p.Start("index", 10, 5); err != reindex.ErrInvalidRange {
+		t.Fatalf("invalid range is started %v", err)
+	}
+	if err := p.Start("index", 10, 19); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Start("index", 10, 19); err != reindex.ErrReindexRunning {
+		t.Fatalf("running reindex is started again %v", err)
+	}
+	p.Add(5)
+	p.Extend(29)
+	p.Extend(20)
+	if p.From() != 10 {
+		t.Fatalf("from %v", p.From())
+	}
+
+	st := p.Status()
+	if st["running"] != true || st["done"] != uint32(5) || st["to"] != uint32(29) || st["total"] != uint32(20) {
+		t.Fatalf("invalid status %v", st)
+	}
+
+	p.Finish(errors.New("reopen failed"))
+	st = p.Status()
+	if st["running"] != false || st["error"] != "reopen failed" {
+		t.Fatalf("invalid status %v", st)
+	}
+
+	// the error of the previous one is cleared by the start
+	if err := p.Start("index", 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	p.Finish(nil)
+	if _, has := p.Status()["error"]; has {
+		t.Fatal("error of the previous reindex is remained")
+	}
+}
+
+func TestParallel(t *testing.T) {
+	var lock sync.Mutex
+	seen := map[uint32]int{}
+	if err := reindex.Parallel(3, 1000, 8, func(i uint32) error {
+		lock.Lock()
+		defer lock.Unlock()
+		seen[i]++
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != 998 {
+		t.Fatalf("indexes %v", len(seen))
+	}
+	for i := uint32(3); i <= 1000; i++ {
+		if seen[i] != 1 {
+			t.Fatalf("index %v is called %v times", i, seen[i])
+		}
+	}
+
+	// the workers stop at the first error
+	failed := errors.New("failed")
+	count := 0
+	if err := reindex.Parallel(1, 1000, 4, func(i uint32) error {
+		lock.Lock()
+		defer lock.Unlock()
+		count++
+		if i == 10 {
+			return failed
+		}
+		return nil
+	}); err != failed {
+		t.Fatalf("error %v is not the first error", err)
+	}
+	if count == 1000 {
+		t.Fatal("workers are not stopped by the error")
+	}
+	if err := reindex.Parallel(2, 1, 4, func(i uint32) error { return nil }); err != reindex.ErrInvalidRange {
+		t.Fatalf("invalid range is accepted %v", err)
+	}
+}
+
+func TestBlocks(t *testing.T) {
+	tc := util.NewTestContext()
+	tc.MustSkipBlock(40)
+	provider := tc.Cn.Provider()
+
+	next := uint32(2)
+	if err := reindex.Blocks(provider, 2, provider.Height(), 3, func(b *types.Block) error {
+		if b.Header.Height != next {
+			t.Fatalf("block %v is not in the order, want %v", b.Header.Height, next)
+		}
+		next++
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if next != provider.Height()+1 {
+		t.Fatalf("blocks are read to %v", next-1)
+	}
+
+	// the missing block stops the reading
+	if err := reindex.Blocks(provider, 2, provider.Height()+1, 3, func(b *types.Block) error {
+		return nil
+	}); err == nil {
+		t.Fatal("missing block is read")
+	}
+}
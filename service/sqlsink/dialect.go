@@ -0,0 +1,70 @@
+package sqlsink
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Dialect is the difference of the sql databases which the sink writes to
+//
+// the queries are written with ? and rebound by the dialect, the upserts use ON CONFLICT which both databases support
+type Dialect struct {
+	Name       string
+	AmountType string
+	numbered   bool
+}
+
+var (
+	// SQLite is the dialect of the sqlite3 driver
+	SQLite = &Dialect{Name: "sqlite", AmountType: "TEXT"}
+	// Postgres is the dialect of the postgres drivers
+	Postgres = &Dialect{Name: "postgres", AmountType: "NUMERIC(78,0)", numbered: true}
+)
+
+// DialectOf returns the dialect of the database/sql driver name
+func DialectOf(driver string) (*Dialect, error) {
+	switch driver {
+	case "sqlite", "sqlite3":
+		return SQLite, nil
+	case "postgres", "pgx":
+		return Postgres, nil
+	default:
+		return nil, ErrUnknownDialect
+	}
+}
+
+// rebind replaces the ? placeholders of the query to the numbered ones when the dialect uses them
+func (d *Dialect) rebind(query string) string {
+	if !d.numbered {
+		return query
+	}
+	var sb strings.Builder
+	n := 0
+	for _, c := range query {
+		if c == '?' {
+			n++
+			sb.WriteString("$")
+			sb.WriteString(strconv.Itoa(n))
+		} else {
+			sb.WriteRune(c)
+		}
+	}
+	return sb.String()
+}
+
+// upsert returns the insert query of the table which updates the columns when the keys conflict
+func (d *Dialect) upsert(table string, keys []string, cols []string) string {
+	all := append(append([]string{}, keys...), cols...)
+	marks := strings.TrimSuffix(strings.Repeat("?,", len(all)), ",")
+	sets := make([]string, 0, len(cols))
+	for _, c := range cols {
+		sets = append(sets, c+" = excluded."+c)
+	}
+	q := "INSERT INTO " + table + " (" + strings.Join(all, ", ") + ") VALUES (" + marks + ") ON CONFLICT (" + strings.Join(keys, ", ") + ")"
+	if len(sets) == 0 {
+		q += " DO NOTHING"
+	} else {
+		q += " DO UPDATE SET " + strings.Join(sets, ", ")
+	}
+	return d.rebind(q)
+}
@@ -0,0 +1,8 @@
+package sqlsink
+
+import "errors"
+
+var (
+	ErrUnknownDialect = errors.New("unknown sql dialect")
+	ErrNewerSchema    = errors.New("schema is newer than the sink")
+)
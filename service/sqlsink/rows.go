@@ -0,0 +1,185 @@
+package sqlsink
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"math/big"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/core/ctypes"
+	"github.com/meverselabs/meverse/core/types"
+)
+
+var (
+	blockCols    = []string{"hash", "prev_hash", "timestamp", "generator", "tx_count"}
+	txCols       = []string{"hash", "from_address", "to_address", "method", "vm_type", "seq", "timestamp"}
+	callCols     = []string{"tx_index", "contract", "from_address", "method", "args", "result", "error"}
+	transferCols = []string{"tx_index", "token", "from_address", "to_address", "amount"}
+	rewardCols   = []string{"amount"}
+	stateCols    = []string{"height", "hash"}
+)
+
+// writeBlock replaces the rows of the height and the heights after it by the rows of the block in a transaction
+func (s *SQLSink) writeBlock(b *types.Block) error {
+	d := s.dialect
+	height := b.Header.Height
+	bHash := bin.MustWriterToHash(&b.Header)
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	exec := func(q string, args ...interface{}) {
+		if err == nil {
+			_, err = tx.Exec(q, args...)
+		}
+	}
+
+	// the rows of the abandoned blocks are removed, so writing the same block again is also safe
+	for _, table := range heightTables {
+		exec(d.rebind("DELETE FROM "+table+" WHERE height >= ?"), int64(height))
+	}
+
+	exec(d.upsert("blocks", []string{"height"}, blockCols),
+		int64(height), bHash.String(), b.Header.PrevHash.String(), int64(b.Header.Timestamp), b.Header.Generator.String(), len(b.Body.Transactions))
+
+	txQuery := d.upsert("transactions", []string{"height", "tx_index"}, txCols)
+	for i, t := range b.Body.Transactions {
+		exec(txQuery, int64(height), i, t.Hash(height).String(), t.From.String(), t.To.String(), t.Method, int(t.VmType), int64(t.Seq), int64(t.Timestamp))
+	}
+
+	callQuery := d.upsert("method_calls", []string{"height", "event_index"}, callCols)
+	transferQuery := d.upsert("token_transfers", []string{"height", "event_index"}, transferCols)
+	rewardQuery := d.upsert("rewards", []string{"height", "event_index", "contract", "rewarder"}, rewardCols)
+	for i, en := range b.Body.Events {
+		switch en.Type {
+		case ctypes.EventTagCallHistory:
+			mc := &ctypes.MethodCallEvent{}
+			if _, err := mc.ReadFrom(bytes.NewReader(en.Result)); err != nil {
+				continue
+			}
+			exec(callQuery, int64(height), i, int(en.Index), mc.To.String(), mc.From.String(), mc.Method, toJSON(mc.Args), toJSON(mc.Result), mc.Error)
+			if From, To, am, ok := transferOf(mc); ok {
+				exec(transferQuery, int64(height), i, int(en.Index), mc.To.String(), From.String(), To.String(), am.Int.String())
+			}
+		case ctypes.EventTagReward:
+			mp := map[common.Address][]byte{}
+			if err := types.UnmarshalAddressBytesMap(en.Result, mp); err != nil {
+				continue
+			}
+			for cont, bs := range mp {
+				ma := map[common.Address]*amount.Amount{}
+				if err := types.UnmarshalAddressAmountMap(bs, ma); err != nil {
+					continue
+				}
+				for rewarder, am := range ma {
+					exec(rewardQuery, int64(height), i, cont.String(), rewarder.String(), am.Int.String())
+				}
+			}
+		}
+	}
+
+	exec(d.upsert("sync_state", []string{"id"}, stateCols), 1, int64(height), bHash.String())
+	if err != nil {
+		tx.Rollback()
+		return errors.WithStack(err)
+	}
+	if err := tx.Commit(); err != nil {
+		return errors.WithStack(err)
+	}
+	s.height = height
+	s.hash = bHash
+	return nil
+}
+
+// truncate removes the rows after the height
+func (s *SQLSink) truncate(height uint32) error {
+	d := s.dialect
+	tx, err := s.db.Begin()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for _, table := range heightTables {
+		if _, err := tx.Exec(d.rebind("DELETE FROM "+table+" WHERE height > ?"), int64(height)); err != nil {
+			tx.Rollback()
+			return errors.WithStack(err)
+		}
+	}
+	var hashStr string
+	if err := tx.QueryRow(d.rebind("SELECT hash FROM blocks WHERE height = ?"), int64(height)).Scan(&hashStr); err != nil && err != sql.ErrNoRows {
+		tx.Rollback()
+		return errors.WithStack(err)
+	}
+	if _, err := tx.Exec(d.upsert("sync_state", []string{"id"}, stateCols), 1, int64(height), hashStr); err != nil {
+		tx.Rollback()
+		return errors.WithStack(err)
+	}
+	if err := tx.Commit(); err != nil {
+		return errors.WithStack(err)
+	}
+	s.height = height
+	s.hash = hashOf(hashStr)
+	return nil
+}
+
+// transferOf returns the transfer of the token by the successful call
+func transferOf(mc *ctypes.MethodCallEvent) (common.Address, common.Address, *amount.Amount, bool) {
+	if mc.Error != "" {
+		return common.Address{}, common.Address{}, nil, false
+	}
+	switch strings.ToLower(mc.Method) {
+	case "transfer":
+		To, ok1 := toAddress(mc.Args, 0)
+		am, ok2 := toAmount(mc.Args, 1)
+		return mc.From, To, am, ok1 && ok2
+	case "transferfrom":
+		From, ok1 := toAddress(mc.Args, 0)
+		To, ok2 := toAddress(mc.Args, 1)
+		am, ok3 := toAmount(mc.Args, 2)
+		return From, To, am, ok1 && ok2 && ok3
+	default:
+		return common.Address{}, common.Address{}, nil, false
+	}
+}
+
+func toAddress(is []interface{}, index int) (common.Address, bool) {
+	if len(is) <= index {
+		return common.Address{}, false
+	}
+	switch v := is[index].(type) {
+	case common.Address:
+		return v, true
+	case string:
+		return common.HexToAddress(v), true
+	default:
+		return common.Address{}, false
+	}
+}
+
+func toAmount(is []interface{}, index int) (*amount.Amount, bool) {
+	if len(is) <= index {
+		return nil, false
+	}
+	switch v := is[index].(type) {
+	case *amount.Amount:
+		return v, v != nil
+	case *big.Int:
+		return &amount.Amount{Int: v}, v != nil
+	default:
+		return nil, false
+	}
+}
+
+func toJSON(v interface{}) string {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(bs)
+}
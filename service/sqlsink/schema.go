@@ -0,0 +1,113 @@
+package sqlsink
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// migrations are the schema changes in the order of the version, a released one must not be changed
+//
+// {amount} is replaced by the amount type of the dialect
+var migrations = [][]string{
+	{
+		`CREATE TABLE sync_state (
+			id INTEGER PRIMARY KEY,
+			height BIGINT NOT NULL,
+			hash TEXT NOT NULL
+		)`,
+		`CREATE TABLE blocks (
+			height BIGINT PRIMARY KEY,
+			hash TEXT NOT NULL,
+			prev_hash TEXT NOT NULL,
+			timestamp BIGINT NOT NULL,
+			generator TEXT NOT NULL,
+			tx_count INTEGER NOT NULL
+		)`,
+		`CREATE TABLE transactions (
+			height BIGINT NOT NULL,
+			tx_index INTEGER NOT NULL,
+			hash TEXT NOT NULL,
+			from_address TEXT NOT NULL,
+			to_address TEXT NOT NULL,
+			method TEXT NOT NULL,
+			vm_type INTEGER NOT NULL,
+			seq BIGINT NOT NULL,
+			timestamp BIGINT NOT NULL,
+			PRIMARY KEY (height, tx_index)
+		)`,
+		`CREATE INDEX transactions_hash ON transactions (hash)`,
+		`CREATE INDEX transactions_from ON transactions (from_address)`,
+		`CREATE TABLE method_calls (
+			height BIGINT NOT NULL,
+			event_index INTEGER NOT NULL,
+			tx_index INTEGER NOT NULL,
+			contract TEXT NOT NULL,
+			from_address TEXT NOT NULL,
+			method TEXT NOT NULL,
+			args TEXT NOT NULL,
+			result TEXT NOT NULL,
+			error TEXT NOT NULL,
+			PRIMARY KEY (height, event_index)
+		)`,
+		`CREATE INDEX method_calls_contract ON method_calls (contract, method)`,
+		`CREATE TABLE token_transfers (
+			height BIGINT NOT NULL,
+			event_index INTEGER NOT NULL,
+			tx_index INTEGER NOT NULL,
+			token TEXT NOT NULL,
+			from_address TEXT NOT NULL,
+			to_address TEXT NOT NULL,
+			amount {amount} NOT NULL,
+			PRIMARY KEY (height, event_index)
+		)`,
+		`CREATE INDEX token_transfers_from ON token_transfers (token, from_address)`,
+		`CREATE INDEX token_transfers_to ON token_transfers (token, to_address)`,
+		`CREATE TABLE rewards (
+			height BIGINT NOT NULL,
+			event_index INTEGER NOT NULL,
+			contract TEXT NOT NULL,
+			rewarder TEXT NOT NULL,
+			amount {amount} NOT NULL,
+			PRIMARY KEY (height, event_index, contract, rewarder)
+		)`,
+	},
+}
+
+// heightTables are the tables which have the rows of the blocks by the height
+var heightTables = []string{"rewards", "token_transfers", "method_calls", "transactions", "blocks"}
+
+// migrate applies the migrations after the version of the database
+func migrate(db *sql.DB, d *Dialect) error {
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY)`); err != nil {
+		return errors.WithStack(err)
+	}
+	var version int
+	if err := db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+		return errors.WithStack(err)
+	}
+	if version > len(migrations) {
+		return errors.WithStack(ErrNewerSchema)
+	}
+	for v := version + 1; v <= len(migrations); v++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		for _, q := range migrations[v-1] {
+			if _, err := tx.Exec(strings.ReplaceAll(q, "{amount}", d.AmountType)); err != nil {
+				tx.Rollback()
+				return errors.Wrapf(err, "migration %v", v)
+			}
+		}
+		if _, err := tx.Exec(d.rebind(`INSERT INTO schema_migrations (version) VALUES (?)`), v); err != nil {
+			tx.Rollback()
+			return errors.WithStack(err)
+		}
+		if err := tx.Commit(); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
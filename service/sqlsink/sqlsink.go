@@ -0,0 +1,171 @@
+package sqlsink
+
+import (
+	"database/sql"
+	"log"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/core/types"
+)
+
+// SQLSink writes the blocks, the transactions, the method calls, the token transfers and the rewards to the sql database
+//
+// the rows of a block are written in a transaction with the height of the sink, so it resumes from the height after the restart
+type SQLSink struct {
+	lock     sync.Mutex
+	db       *sql.DB
+	dialect  *Dialect
+	provider types.Provider
+	height   uint32
+	hash     hash.Hash256
+}
+
+// NewSQLSink migrates the schema of the database and returns the sink, the driver of the database should be registered by the caller
+func NewSQLSink(db *sql.DB, dialect *Dialect, provider types.Provider) (*SQLSink, error) {
+	if err := migrate(db, dialect); err != nil {
+		return nil, err
+	}
+	s := &SQLSink{
+		db:       db,
+		dialect:  dialect,
+		provider: provider,
+	}
+	var height int64
+	var hashStr string
+	if err := db.QueryRow(`SELECT height, hash FROM sync_state WHERE id = 1`).Scan(&height, &hashStr); err != nil && err != sql.ErrNoRows {
+		return nil, errors.WithStack(err)
+	}
+	s.height = uint32(height)
+	s.hash = hashOf(hashStr)
+	return s, nil
+}
+
+// Name returns the name of the service
+func (s *SQLSink) Name() string {
+	return "fleta.sqlsink"
+}
+
+// OnLoadChain called when the chain loaded
+func (s *SQLSink) OnLoadChain(loader types.Loader) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.syncTo(s.provider.Height())
+}
+
+// OnBlockConnected called when a block is connected to the chain
+func (s *SQLSink) OnBlockConnected(b *types.Block, loader types.Loader) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if b.Header.Height <= s.height {
+		// the block which is written already is skipped when the chain replays it
+		if stored, err := s.storedHash(b.Header.Height); err == nil && stored == bin.MustWriterToHash(&b.Header) {
+			return
+		}
+	}
+	// the rows are synced to the previous block when the block is not connected to the written ones,
+	// so the missing blocks and the blocks of the new branch are written before it
+	if prev := b.Header.Height - 1; prev > s.provider.InitHeight() {
+		stored := s.hash
+		if prev != s.height {
+			var err error
+			if stored, err = s.storedHash(prev); err != nil {
+				log.Printf("sqlsink: %+v\n", err)
+				return
+			}
+		}
+		if stored != b.Header.PrevHash {
+			if err := s.syncTo(prev); err != nil {
+				log.Printf("sqlsink: %+v\n", err)
+				return
+			}
+		}
+	}
+	if err := s.writeBlock(b); err != nil {
+		log.Printf("sqlsink: %+v\n", err)
+	}
+}
+
+// OnTransactionInPoolExpired called when a transaction in pool is expired
+func (s *SQLSink) OnTransactionInPoolExpired(txs []*types.Transaction) {}
+
+// OnTransactionFail called when the tx fail
+func (s *SQLSink) OnTransactionFail(height uint32, txs []*types.Transaction, err []error) {}
+
+// Height returns the height of the last block written to the database
+func (s *SQLSink) Height() uint32 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.height
+}
+
+// syncTo writes the blocks of the provider to the height, the rows after it are removed when the sink is ahead of the chain
+func (s *SQLSink) syncTo(height uint32) error {
+	if s.height > height {
+		if err := s.truncate(height); err != nil {
+			return err
+		}
+	}
+	if err := s.rewind(s.height); err != nil {
+		return err
+	}
+	start := s.height + 1
+	if init := s.provider.InitHeight(); start <= init {
+		start = init + 1
+	}
+	for h := start; h <= height; h++ {
+		b, err := s.provider.Block(h)
+		if err != nil {
+			return err
+		}
+		if err := s.writeBlock(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rewind moves the sink back to the last height which has the same hash with the chain
+func (s *SQLSink) rewind(height uint32) error {
+	h := height
+	for h > s.provider.InitHeight() {
+		stored, err := s.storedHash(h)
+		if err != nil {
+			return err
+		}
+		chainHash, err := s.provider.Hash(h)
+		if err != nil {
+			return err
+		}
+		if stored == chainHash {
+			break
+		}
+		h--
+	}
+	if h < s.height {
+		return s.truncate(h)
+	}
+	return nil
+}
+
+// storedHash returns the hash of the block of the height in the database
+func (s *SQLSink) storedHash(height uint32) (hash.Hash256, error) {
+	var hashStr string
+	if err := s.db.QueryRow(s.dialect.rebind(`SELECT hash FROM blocks WHERE height = ?`), int64(height)).Scan(&hashStr); err != nil && err != sql.ErrNoRows {
+		return hash.Hash256{}, errors.WithStack(err)
+	}
+	return hashOf(hashStr), nil
+}
+
+func hashOf(str string) hash.Hash256 {
+	if str == "" {
+		return hash.Hash256{}
+	}
+	return hash.HexToHash(str)
+}
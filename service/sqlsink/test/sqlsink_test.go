@@ -0,0 +1,288 @@
+package test
+
+import (
+	"database/sql"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/common/hash"
+	"github.com/meverselabs/meverse/core/ctypes"
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/meverselabs/meverse/service/sqlsink"
+)
+
+var (
+	token    = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	sender   = common.HexToAddress("0x2000000000000000000000000000000000000002")
+	receiver = common.HexToAddress("0x3000000000000000000000000000000000000003")
+)
+
+// chainMock is the chain of the blocks which can be replaced by the other branch
+type chainMock struct {
+	types.Provider
+	blocks []*types.Block
+}
+
+func (c *chainMock) Height() uint32     { return uint32(len(c.blocks)) }
+func (c *chainMock) InitHeight() uint32 { return 0 }
+
+func (c *chainMock) Block(height uint32) (*types.Block, error) {
+	if height == 0 || height > c.Height() {
+		return nil, errors.Errorf("block %v is not exist", height)
+	}
+	return c.blocks[height-1], nil
+}
+
+func (c *chainMock) Hash(height uint32) (hash.Hash256, error) {
+	b, err := c.Block(height)
+	if err != nil {
+		return hash.Hash256{}, err
+	}
+	return bin.MustWriterToHash(&b.Header), nil
+}
+
+// add appends the block of the branch which has the transfers
+func (c *chainMock) add(branch byte, transfers int) *types.Block {
+	height := c.Height() + 1
+	var prev hash.Hash256
+	if height > 1 {
+		prev, _ = c.Hash(height - 1)
+	}
+	b := &types.Block{
+		Header: types.Header{
+			ChainID:   big.NewInt(1),
+			Height:    height,
+			PrevHash:  prev,
+			Timestamp: uint64(height)*1000 + uint64(branch),
+			Generator: common.Address{branch},
+		},
+	}
+	for i := 0; i < transfers; i++ {
+		b.Body.Transactions = append(b.Body.Transactions, &types.Transaction{
+			ChainID:   big.NewInt(1),
+			Timestamp: b.Header.Timestamp + uint64(i),
+			To:        token,
+			Method:    "Transfer",
+			From:      sender,
+		})
+		mc := &ctypes.MethodCallEvent{
+			From:   sender,
+			To:     token,
+			Method: "Transfer",
+			Args:   []interface{}{receiver, amount.NewAmount(uint64(branch)+1, uint64(i))},
+		}
+		bs, _, err := bin.WriterToBytes(mc)
+		if err != nil {
+			panic(err)
+		}
+		b.Body.Events = append(b.Body.Events, &ctypes.Event{Index: uint16(i), Type: ctypes.EventTagCallHistory, Result: bs})
+	}
+	c.blocks = append(c.blocks, b)
+	return b
+}
+
+// cut removes the blocks after the height
+func (c *chainMock) cut(height uint32) {
+	c.blocks = c.blocks[:height]
+}
+
+func openDB(t *testing.T, path string) *sql.DB {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func count(t *testing.T, db *sql.DB, query string, args ...interface{}) int {
+	var n int
+	if err := db.QueryRow(query, args...).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+// checkRows checks the rows of the database are the rows of the blocks of the chain
+func checkRows(t *testing.T, db *sql.DB, cn *chainMock) {
+	if n := count(t, db, `SELECT COUNT(*) FROM blocks`); n != len(cn.blocks) {
+		t.Fatalf("blocks %v, want %v", n, len(cn.blocks))
+	}
+	calls := 0
+	for _, b := range cn.blocks {
+		var hashStr string
+		if err := db.QueryRow(`SELECT hash FROM blocks WHERE height = ?`, b.Header.Height).Scan(&hashStr); err != nil {
+			t.Fatal(err)
+		}
+		if want := bin.MustWriterToHash(&b.Header); hashStr != want.String() {
+			t.Fatalf("block %v is %v, want %v", b.Header.Height, hashStr, want)
+		}
+		for i, tx := range b.Body.Transactions {
+			if n := count(t, db, `SELECT COUNT(*) FROM transactions WHERE height = ? AND tx_index = ? AND hash = ?`, b.Header.Height, i, tx.Hash(b.Header.Height).String()); n != 1 {
+				t.Fatalf("tx %v of %v is not written", i, b.Header.Height)
+			}
+		}
+		calls += len(b.Body.Events)
+	}
+	if n := count(t, db, `SELECT COUNT(*) FROM method_calls`); n != calls {
+		t.Fatalf("method calls %v, want %v", n, calls)
+	}
+	if n := count(t, db, `SELECT COUNT(*) FROM token_transfers WHERE token = ? AND from_address = ? AND to_address = ?`, token.String(), sender.String(), receiver.String()); n != calls {
+		t.Fatalf("transfers %v, want %v", n, calls)
+	}
+	var height int64
+	var hashStr string
+	if err := db.QueryRow(`SELECT height, hash FROM sync_state WHERE id = 1`).Scan(&height, &hashStr); err != nil {
+		t.Fatal(err)
+	}
+	last, _ := cn.Hash(cn.Height())
+	if uint32(height) != cn.Height() || hashStr != last.String() {
+		t.Fatalf("sync state %v %v, want %v %v", height, hashStr, cn.Height(), last)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sink.db")
+	db := openDB(t, path)
+	defer db.Close()
+
+	cn := &chainMock{}
+	if _, err := sqlsink.NewSQLSink(db, sqlsink.SQLite, cn); err != nil {
+		t.Fatal(err)
+	}
+	version := count(t, db, `SELECT MAX(version) FROM schema_migrations`)
+	if version < 1 {
+		t.Fatalf("schema version %v", version)
+	}
+	for _, table := range []string{"sync_state", "blocks", "transactions", "method_calls", "token_transfers", "rewards"} {
+		count(t, db, `SELECT COUNT(*) FROM `+table)
+	}
+
+	// the migrated schema is not migrated again
+	if _, err := sqlsink.NewSQLSink(db, sqlsink.SQLite, cn); err != nil {
+		t.Fatal(err)
+	}
+	if n := count(t, db, `SELECT COUNT(*) FROM schema_migrations`); n != version {
+		t.Fatalf("migrations %v, want %v", n, version)
+	}
+
+	if _, err := db.Exec(`INSERT INTO schema_migrations (version) VALUES (?)`, version+1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sqlsink.NewSQLSink(db, sqlsink.SQLite, cn); errors.Cause(err) != sqlsink.ErrNewerSchema {
+		t.Fatalf("newer schema is opened %v", err)
+	}
+}
+
+func TestResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sink.db")
+	cn := &chainMock{}
+	for i := 0; i < 5; i++ {
+		cn.add(0, i%3)
+	}
+
+	db := openDB(t, path)
+	s, err := sqlsink.NewSQLSink(db, sqlsink.SQLite, cn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.OnLoadChain(nil); err != nil {
+		t.Fatal(err)
+	}
+	if s.Height() != 5 {
+		t.Fatalf("height %v", s.Height())
+	}
+	checkRows(t, db, cn)
+	db.Close()
+
+	// the sink resumes from the stored height after the restart
+	for i := 0; i < 3; i++ {
+		cn.add(0, 2)
+	}
+	db = openDB(t, path)
+	defer db.Close()
+	s, err = sqlsink.NewSQLSink(db, sqlsink.SQLite, cn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Height() != 5 {
+		t.Fatalf("stored height %v", s.Height())
+	}
+	if err := s.OnLoadChain(nil); err != nil {
+		t.Fatal(err)
+	}
+	checkRows(t, db, cn)
+
+	// the connected block is written and the replayed one is skipped
+	s.OnBlockConnected(cn.add(0, 1), nil)
+	s.OnBlockConnected(cn.blocks[len(cn.blocks)-1], nil)
+	if s.Height() != cn.Height() {
+		t.Fatalf("height %v, want %v", s.Height(), cn.Height())
+	}
+	checkRows(t, db, cn)
+
+	// the missing blocks are written before the connected one
+	cn.add(0, 1)
+	s.OnBlockConnected(cn.add(0, 2), nil)
+	checkRows(t, db, cn)
+}
+
+func TestReorg(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sink.db")
+	db := openDB(t, path)
+	defer db.Close()
+
+	cn := &chainMock{}
+	s, err := sqlsink.NewSQLSink(db, sqlsink.SQLite, cn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 6; i++ {
+		s.OnBlockConnected(cn.add(0, 2), nil)
+	}
+	checkRows(t, db, cn)
+
+	// the longer branch from the height 4 is connected by its last block
+	cn.cut(4)
+	cn.add(1, 1)
+	cn.add(1, 3)
+	s.OnBlockConnected(cn.add(1, 1), nil)
+	if s.Height() != 7 {
+		t.Fatalf("height %v after the reorg", s.Height())
+	}
+	checkRows(t, db, cn)
+
+	// the shorter branch from the height 3 is connected block by block
+	cn.cut(3)
+	s.OnBlockConnected(cn.add(2, 2), nil)
+	s.OnBlockConnected(cn.add(2, 1), nil)
+	if s.Height() != 5 {
+		t.Fatalf("height %v after the reorg", s.Height())
+	}
+	checkRows(t, db, cn)
+
+	// the rows ahead of the chain are truncated when the chain is loaded
+	cn.cut(2)
+	if err := s.OnLoadChain(nil); err != nil {
+		t.Fatal(err)
+	}
+	if s.Height() != 2 {
+		t.Fatalf("height %v after the truncate", s.Height())
+	}
+	checkRows(t, db, cn)
+
+	// the chain which is replaced under the stored height is rewound to the common block
+	cn.cut(1)
+	cn.add(3, 2)
+	cn.add(3, 2)
+	if err := s.OnLoadChain(nil); err != nil {
+		t.Fatal(err)
+	}
+	checkRows(t, db, cn)
+}
@@ -0,0 +1,110 @@
+package stream
+
+import (
+	"context"
+	"sync"
+)
+
+// Broker is the destination of the envelopes, Kafka or NATS client implements it
+//
+// Publish should return nil only when the broker has accepted the envelopes,
+// the stream publishes them again after the error, so the delivery is at-least-once.
+// the ctx is canceled when the stream is closed, so Publish should not wait after it
+type Broker interface {
+	Publish(ctx context.Context, envs []*Envelope) error
+}
+
+// MemoryBroker is the in-process broker which delivers the envelopes to the subscriptions
+type MemoryBroker struct {
+	lock   sync.Mutex
+	subs   map[*Subscription]bool
+	closed bool
+	quit   chan struct{}
+}
+
+// NewMemoryBroker returns a MemoryBroker
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{
+		subs: map[*Subscription]bool{},
+		quit: make(chan struct{}),
+	}
+}
+
+// Subscription receives the envelopes which are matched with the filter
+type Subscription struct {
+	C      chan *Envelope
+	filter Filter
+	broker *MemoryBroker
+	quit   chan struct{}
+	once   sync.Once
+}
+
+// Subscribe returns a subscription of the filter, the publish waits until the buffer of the subscription has space,
+// the broker is closed or the ctx of the publish is done
+func (mb *MemoryBroker) Subscribe(filter Filter, size int) *Subscription {
+	mb.lock.Lock()
+	defer mb.lock.Unlock()
+
+	sub := &Subscription{
+		C:      make(chan *Envelope, size),
+		filter: filter,
+		broker: mb,
+		quit:   make(chan struct{}),
+	}
+	mb.subs[sub] = true
+	return sub
+}
+
+// Unsubscribe stops the subscription
+func (sub *Subscription) Unsubscribe() {
+	sub.once.Do(func() {
+		close(sub.quit)
+		sub.broker.lock.Lock()
+		delete(sub.broker.subs, sub)
+		sub.broker.lock.Unlock()
+	})
+}
+
+// Publish delivers the envelopes to the subscriptions which match them
+//
+// the envelopes delivered before the ctx is done or the broker is closed are not taken back, the stream publishes them again
+func (mb *MemoryBroker) Publish(ctx context.Context, envs []*Envelope) error {
+	mb.lock.Lock()
+	if mb.closed {
+		mb.lock.Unlock()
+		return ErrBrokerClosed
+	}
+	subs := make([]*Subscription, 0, len(mb.subs))
+	for sub := range mb.subs {
+		subs = append(subs, sub)
+	}
+	mb.lock.Unlock()
+
+	for _, env := range envs {
+		for _, sub := range subs {
+			if !sub.filter.Match(env) {
+				continue
+			}
+			select {
+			case sub.C <- env:
+			case <-sub.quit:
+			case <-mb.quit:
+				return ErrBrokerClosed
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	return nil
+}
+
+// Close closes the broker, the publish returns ErrBrokerClosed after it
+func (mb *MemoryBroker) Close() {
+	mb.lock.Lock()
+	defer mb.lock.Unlock()
+
+	if !mb.closed {
+		mb.closed = true
+		close(mb.quit)
+	}
+}
@@ -0,0 +1,181 @@
+package stream
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/core/ctypes"
+	"github.com/meverselabs/meverse/core/types"
+)
+
+// topics of the envelopes
+const (
+	TopicBlock   = "block"
+	TopicReceipt = "receipt"
+	TopicEvent   = "event"
+	TopicCall    = "call"
+	TopicReward  = "reward"
+)
+
+// Envelope is the message which is published to the broker
+//
+// the ID is same when the envelope is delivered again, so the consumer can drop the duplicated one by it
+type Envelope struct {
+	ID       string          `json:"id"`
+	Topic    string          `json:"topic"`
+	Height   uint32          `json:"height"`
+	Index    uint16          `json:"index"`
+	Contract string          `json:"contract,omitempty"`
+	Method   string          `json:"method,omitempty"`
+	Payload  json.RawMessage `json:"payload"`
+}
+
+func newEnvelope(topic string, height uint32, index uint16, payload interface{}) (*Envelope, error) {
+	bs, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{
+		ID:      fmt.Sprintf("%v:%v:%v", height, topic, index),
+		Topic:   topic,
+		Height:  height,
+		Index:   index,
+		Payload: bs,
+	}, nil
+}
+
+// blockEnvelopes returns the envelopes of the block in the order of the block, the receipts, the events
+func blockEnvelopes(b *types.Block, receipts types.Receipts) ([]*Envelope, error) {
+	height := b.Header.Height
+	envs := []*Envelope{}
+
+	txHashes := make([]string, 0, len(b.Body.Transactions))
+	for _, tx := range b.Body.Transactions {
+		txHashes = append(txHashes, tx.Hash(height).String())
+	}
+	env, err := newEnvelope(TopicBlock, height, 0, map[string]interface{}{
+		"hash":         bin.MustWriterToHash(&b.Header).String(),
+		"prevHash":     b.Header.PrevHash.String(),
+		"timestamp":    b.Header.Timestamp,
+		"generator":    b.Header.Generator.String(),
+		"transactions": txHashes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	envs = append(envs, env)
+
+	for i, r := range receipts {
+		if r == nil {
+			continue
+		}
+		env, err := newEnvelope(TopicReceipt, height, uint16(i), r)
+		if err != nil {
+			return nil, err
+		}
+		envs = append(envs, env)
+	}
+
+	for i, en := range b.Body.Events {
+		env, err := newEnvelope(TopicEvent, height, uint16(i), map[string]interface{}{
+			"type":    en.Type.String(),
+			"txIndex": en.Index,
+			"result":  hex.EncodeToString(en.Result),
+		})
+		if err != nil {
+			return nil, err
+		}
+		envs = append(envs, env)
+
+		switch en.Type {
+		case ctypes.EventTagCallHistory:
+			mc := &ctypes.MethodCallEvent{}
+			if _, err := mc.ReadFrom(bytes.NewReader(en.Result)); err != nil {
+				continue
+			}
+			env, err := newEnvelope(TopicCall, height, uint16(i), map[string]interface{}{
+				"txIndex": en.Index,
+				"from":    mc.From.String(),
+				"to":      mc.To.String(),
+				"method":  mc.Method,
+				"args":    mc.Args,
+				"result":  mc.Result,
+				"error":   mc.Error,
+			})
+			if err != nil {
+				return nil, err
+			}
+			env.Contract = mc.To.String()
+			env.Method = mc.Method
+			envs = append(envs, env)
+		case ctypes.EventTagReward:
+			mp := map[common.Address][]byte{}
+			if err := types.UnmarshalAddressBytesMap(en.Result, mp); err != nil {
+				continue
+			}
+			rewards := map[string]map[string]string{}
+			for cont, bs := range mp {
+				ma := map[common.Address]*amount.Amount{}
+				if err := types.UnmarshalAddressAmountMap(bs, ma); err != nil {
+					continue
+				}
+				m := map[string]string{}
+				for rewarder, am := range ma {
+					m[rewarder.String()] = am.String()
+				}
+				rewards[cont.String()] = m
+			}
+			env, err := newEnvelope(TopicReward, height, uint16(i), rewards)
+			if err != nil {
+				return nil, err
+			}
+			envs = append(envs, env)
+		}
+	}
+	return envs, nil
+}
+
+// Filter selects the envelopes of the subscription, the empty field matches all
+type Filter struct {
+	Topics    []string
+	Contracts []common.Address
+	Methods   []string
+}
+
+// Match returns true when the envelope is selected by the filter
+func (f *Filter) Match(env *Envelope) bool {
+	if len(f.Topics) > 0 && !containsFold(f.Topics, env.Topic) {
+		return false
+	}
+	if len(f.Contracts) > 0 {
+		found := false
+		for _, c := range f.Contracts {
+			if strings.EqualFold(c.String(), env.Contract) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.Methods) > 0 && !containsFold(f.Methods, env.Method) {
+		return false
+	}
+	return true
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,9 @@
+package stream
+
+import "errors"
+
+var (
+	ErrBrokerClosed  = errors.New("broker closed")
+	ErrStreamClosed  = errors.New("stream closed")
+	ErrInvalidHeight = errors.New("invalid height")
+)
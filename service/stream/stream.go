@@ -0,0 +1,236 @@
+package stream
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb"
+
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/core/types"
+)
+
+var tagCursor = []byte{0x01}
+
+const (
+	minBackoff = 100 * time.Millisecond
+	maxBackoff = 30 * time.Second
+)
+
+// Stream publishes the envelopes of the connected blocks to the broker
+//
+// the blocks are read from the provider after the cursor in the background, so a slow broker does not block the chain.
+// the cursor moves after all envelopes of a block are published, so the block is published again after a failure or a restart
+type Stream struct {
+	lock     sync.Mutex
+	db       *leveldb.DB
+	broker   Broker
+	provider types.Provider
+	cursor   uint32
+	gen      uint64
+	notify   chan struct{}
+	quit     chan struct{}
+	done     chan struct{}
+	ctx      context.Context
+	cancel   context.CancelFunc
+	running  bool
+}
+
+// NewStream returns a Stream which keeps the cursor in the path, the new cursor starts at the height of the provider
+func NewStream(path string, broker Broker, provider types.Provider) (*Stream, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	s := &Stream{
+		db:       db,
+		broker:   broker,
+		provider: provider,
+		notify:   make(chan struct{}, 1),
+		quit:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	bs, err := db.Get(tagCursor, nil)
+	if err == nil && len(bs) == 4 {
+		s.cursor = bin.Uint32(bs)
+	} else if err == leveldb.ErrNotFound {
+		s.cursor = provider.Height()
+		if err := s.saveCursor(s.cursor); err != nil {
+			db.Close()
+			return nil, err
+		}
+	} else {
+		db.Close()
+		return nil, errors.WithStack(err)
+	}
+	return s, nil
+}
+
+// Name returns the name of the service
+func (s *Stream) Name() string {
+	return "fleta.stream"
+}
+
+// OnLoadChain called when the chain loaded
+func (s *Stream) OnLoadChain(loader types.Loader) error {
+	s.Start()
+	return nil
+}
+
+// OnBlockConnected called when a block is connected to the chain
+func (s *Stream) OnBlockConnected(b *types.Block, loader types.Loader) {
+	s.wake()
+}
+
+// OnTransactionInPoolExpired called when a transaction in pool is expired
+func (s *Stream) OnTransactionInPoolExpired(txs []*types.Transaction) {}
+
+// OnTransactionFail called when the tx fail
+func (s *Stream) OnTransactionFail(height uint32, txs []*types.Transaction, err []error) {}
+
+// Start starts the publisher, it is started by OnLoadChain when the stream is added to the chain
+func (s *Stream) Start() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.running {
+		return
+	}
+	s.running = true
+	go s.run()
+	s.wake()
+}
+
+// Close stops the publisher and closes the cursor db
+func (s *Stream) Close() error {
+	s.lock.Lock()
+	running := s.running
+	s.running = false
+	s.lock.Unlock()
+
+	// the publish waiting the broker is canceled
+	s.cancel()
+	if running {
+		close(s.quit)
+		<-s.done
+	}
+	return s.db.Close()
+}
+
+// Cursor returns the height of the last published block
+func (s *Stream) Cursor() uint32 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.cursor
+}
+
+// Replay publishes the blocks from the height again
+func (s *Stream) Replay(from uint32) error {
+	if from == 0 || from > s.provider.Height()+1 {
+		return errors.WithStack(ErrInvalidHeight)
+	}
+
+	s.lock.Lock()
+	s.gen++
+	s.cursor = from - 1
+	err := s.saveCursor(s.cursor)
+	s.lock.Unlock()
+
+	s.wake()
+	return err
+}
+
+func (s *Stream) wake() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Stream) run() {
+	defer close(s.done)
+
+	backoff := time.Duration(0)
+	for {
+		var retry <-chan time.Time
+		if backoff > 0 {
+			retry = time.After(backoff)
+		}
+		select {
+		case <-s.quit:
+			return
+		case <-s.notify:
+		case <-retry:
+		}
+
+		if err := s.publishAll(); err != nil {
+			log.Printf("stream: %+v\n", err)
+			if backoff == 0 {
+				backoff = minBackoff
+			} else if backoff *= 2; backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+		} else {
+			backoff = 0
+		}
+	}
+}
+
+// publishAll publishes the blocks after the cursor to the height of the provider
+func (s *Stream) publishAll() error {
+	for {
+		select {
+		case <-s.quit:
+			return ErrStreamClosed
+		default:
+		}
+
+		s.lock.Lock()
+		height := s.cursor + 1
+		gen := s.gen
+		s.lock.Unlock()
+
+		if height > s.provider.Height() {
+			return nil
+		}
+		b, err := s.provider.Block(height)
+		if err != nil {
+			return err
+		}
+		// the block is published again with the receipts, the envelopes of the logs are not skipped
+		receipts, err := s.provider.Receipts(height)
+		if err != nil {
+			return err
+		}
+		envs, err := blockEnvelopes(b, receipts)
+		if err != nil {
+			return err
+		}
+		if err := s.broker.Publish(s.ctx, envs); err != nil {
+			return err
+		}
+
+		s.lock.Lock()
+		// the replay has moved the cursor while the block is published
+		if gen == s.gen {
+			s.cursor = height
+			err = s.saveCursor(height)
+		}
+		s.lock.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Stream) saveCursor(height uint32) error {
+	if err := s.db.Put(tagCursor, bin.Uint32Bytes(height), nil); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
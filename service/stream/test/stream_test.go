@@ -0,0 +1,208 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/meverselabs/meverse/extern/test/util"
+	"github.com/meverselabs/meverse/service/stream"
+)
+
+// flakyBroker fails the publishes until the count of the failures is reached
+type flakyBroker struct {
+	sync.Mutex
+	*stream.MemoryBroker
+	fails     int
+	publishes int
+}
+
+func (fb *flakyBroker) Publish(ctx context.Context, envs []*stream.Envelope) error {
+	fb.Lock()
+	if fb.fails > 0 {
+		fb.fails--
+		fb.Unlock()
+		return errors.New("broker unavailable")
+	}
+	fb.publishes++
+	fb.Unlock()
+	return fb.MemoryBroker.Publish(ctx, envs)
+}
+
+// flakyProvider fails the receipts until the count of the failures is reached
+type flakyProvider struct {
+	sync.Mutex
+	types.Provider
+	fails int
+}
+
+func (fp *flakyProvider) Receipts(height uint32) (types.Receipts, error) {
+	fp.Lock()
+	defer fp.Unlock()
+
+	if fp.fails > 0 {
+		fp.fails--
+		return nil, errors.New("receipts unavailable")
+	}
+	return fp.Provider.Receipts(height)
+}
+
+func receive(t *testing.T, sub *stream.Subscription) *stream.Envelope {
+	select {
+	case env := <-sub.C:
+		return env
+	case <-time.After(5 * time.Second):
+		t.Fatal("envelope is not delivered")
+	}
+	return nil
+}
+
+func TestStream(t *testing.T) {
+	path := "_stream"
+	os.RemoveAll(path)
+	defer os.RemoveAll(path)
+
+	tc := util.NewTestContext()
+	mb := stream.NewMemoryBroker()
+	fb := &flakyBroker{MemoryBroker: mb, fails: 2}
+	sub := mb.Subscribe(stream.Filter{
+		Topics:    []string{stream.TopicCall},
+		Contracts: []common.Address{tc.MainToken},
+		Methods:   []string{"Transfer"},
+	}, 10)
+	defer sub.Unsubscribe()
+
+	st, err := stream.NewStream(path, fb, tc.Cn.Provider())
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := st.Cursor()
+	if start != tc.Cn.Provider().Height() {
+		t.Fatalf("cursor %v is not the height", start)
+	}
+	st.Start()
+
+	tc.MustSendTx(util.AdminKey, tc.MainToken, "Transfer", util.Users[0], amount.NewAmount(1, 0))
+	st.OnBlockConnected(nil, nil)
+
+	// the block is published again after the failures of the broker
+	env := receive(t, sub)
+	if env.Method != "Transfer" || env.Height != tc.Cn.Provider().Height() {
+		t.Fatalf("invalid envelope %v %v", env.Method, env.Height)
+	}
+	for i := 0; st.Cursor() != env.Height && i < 100; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if st.Cursor() != env.Height {
+		t.Fatalf("cursor %v is not moved to %v", st.Cursor(), env.Height)
+	}
+
+	// the cursor is kept after the restart
+	if err := st.Close(); err != nil {
+		t.Fatal(err)
+	}
+	st, err = stream.NewStream(path, fb, tc.Cn.Provider())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	if st.Cursor() != env.Height {
+		t.Fatalf("cursor %v is not restored", st.Cursor())
+	}
+	st.Start()
+
+	// the replay delivers the same envelope again
+	if err := st.Replay(start + 1); err != nil {
+		t.Fatal(err)
+	}
+	if replayed := receive(t, sub); replayed.ID != env.ID {
+		t.Fatalf("replayed envelope %v is not %v", replayed.ID, env.ID)
+	}
+	if err := st.Replay(0); err == nil {
+		t.Fatal("replay from zero is allowed")
+	}
+}
+
+func TestStreamReceiptsFailure(t *testing.T) {
+	path := "_stream_receipts"
+	os.RemoveAll(path)
+	defer os.RemoveAll(path)
+
+	tc := util.NewTestContext()
+	mb := stream.NewMemoryBroker()
+	fb := &flakyBroker{MemoryBroker: mb}
+	fp := &flakyProvider{Provider: tc.Cn.Provider(), fails: 2}
+	sub := mb.Subscribe(stream.Filter{}, 100)
+	defer sub.Unsubscribe()
+
+	st, err := stream.NewStream(path, fb, fp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	st.Start()
+
+	tc.MustSendTx(util.AdminKey, tc.MainToken, "Transfer", util.Users[0], amount.NewAmount(1, 0))
+	st.OnBlockConnected(nil, nil)
+
+	// the block is not published until the receipts are read
+	env := receive(t, sub)
+	if env.Height != tc.Cn.Provider().Height() {
+		t.Fatalf("invalid envelope height %v", env.Height)
+	}
+	fp.Lock()
+	fails := fp.fails
+	fp.Unlock()
+	if fails != 0 {
+		t.Fatal("block is published without the receipts")
+	}
+	fb.Lock()
+	publishes := fb.publishes
+	fb.Unlock()
+	if publishes != 1 {
+		t.Fatalf("block is published %v times", publishes)
+	}
+}
+
+func TestMemoryBrokerFullSubscription(t *testing.T) {
+	mb := stream.NewMemoryBroker()
+	sub := mb.Subscribe(stream.Filter{}, 0)
+	defer sub.Unsubscribe()
+
+	envs := []*stream.Envelope{{ID: "1", Topic: stream.TopicCall}}
+	publish := func(ctx context.Context) error {
+		ch := make(chan error, 1)
+		go func() {
+			ch <- mb.Publish(ctx, envs)
+		}()
+		select {
+		case err := <-ch:
+			return err
+		case <-time.After(5 * time.Second):
+			t.Fatal("publish is blocked by the full subscription")
+		}
+		return nil
+	}
+
+	// the publish waiting the subscription returns when the ctx is done
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := publish(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("publish returns %v, not the deadline", err)
+	}
+
+	// and when the broker is closed
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		mb.Close()
+	}()
+	if err := publish(context.Background()); err != stream.ErrBrokerClosed {
+		t.Fatalf("publish returns %v, not the closed broker", err)
+	}
+}
@@ -0,0 +1,135 @@
+package txsearch
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/core/ctypes"
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/meverselabs/meverse/service/txsearch/itxsearch"
+)
+
+// MaxCallListSize is the limit of the method calls returned by a query
+const MaxCallListSize = 1000
+
+// methodCallPrefix returns the key prefix of the calls of the method of the contract
+//
+// the method is prefixed by its length, so a method is not the prefix of the other method
+func methodCallPrefix(cont common.Address, method string) []byte {
+	method = strings.ToLower(method)
+	bs := make([]byte, 0, 1+common.AddressLength+1+len(method))
+	bs = append(bs, tagCallMethod)
+	bs = append(bs, cont[:]...)
+	bs = append(bs, uint8(len(method)))
+	return append(bs, method...)
+}
+
+// callerCallPrefix returns the key prefix of the calls of the contract by the caller
+func callerCallPrefix(cont common.Address, From common.Address) []byte {
+	return addr41Key(tagCallFrom, cont, From)
+}
+
+func callKey(prefix []byte, height uint32, event uint16) []byte {
+	bs := make([]byte, 0, len(prefix)+6)
+	bs = append(bs, prefix...)
+	return append(bs, types.TransactionIDBytes(height, event)...)
+}
+
+// saveCalls indexes the call history events of the block by the method and by the caller
+func (t *TxSearch) saveCalls(batch *leveldb.Batch, b *types.Block) {
+	for i, en := range b.Body.Events {
+		if en.Type != ctypes.EventTagCallHistory {
+			continue
+		}
+		mc := &ctypes.MethodCallEvent{}
+		if _, err := mc.ReadFrom(bytes.NewReader(en.Result)); err != nil {
+			continue
+		}
+		if len(mc.Method) <= 255 {
+			batch.Put(callKey(methodCallPrefix(mc.To, mc.Method), b.Header.Height, uint16(i)), nil)
+		}
+		batch.Put(callKey(callerCallPrefix(mc.To, mc.From), b.Header.Height, uint16(i)), nil)
+	}
+}
+
+// CallIndexFrom returns the first height of the method call index, the blocks before it are not indexed
+func (t *TxSearch) CallIndexFrom() uint32 {
+	bs, err := t.DB().Get([]byte{tagCallIndexFrom}, nil)
+	if err != nil || len(bs) != 4 {
+		return t.Height() + 1
+	}
+	return bin.Uint32(bs)
+}
+
+func (t *TxSearch) setCallIndexFrom(h uint32) error {
+	return t.DB().Put([]byte{tagCallIndexFrom}, bin.Uint32Bytes(h), nil)
+}
+
+// MethodCalls returns the calls of the method of the contract between the heights in the order of the height
+//
+// the cursor is the position of the last call of the previous page, the next cursor is empty at the end
+func (t *TxSearch) MethodCalls(cont common.Address, method string, start, end uint32, cursor string, limit int) ([]itxsearch.CallRef, string, error) {
+	if len(method) > 255 {
+		return []itxsearch.CallRef{}, "", nil
+	}
+	return t.callList(methodCallPrefix(cont, method), start, end, cursor, limit)
+}
+
+// CallerCalls returns the calls of the contract by the caller between the heights in the order of the height
+func (t *TxSearch) CallerCalls(cont common.Address, From common.Address, start, end uint32, cursor string, limit int) ([]itxsearch.CallRef, string, error) {
+	return t.callList(callerCallPrefix(cont, From), start, end, cursor, limit)
+}
+
+func (t *TxSearch) callList(prefix []byte, start, end uint32, cursor string, limit int) ([]itxsearch.CallRef, string, error) {
+	if limit <= 0 || limit > MaxCallListSize {
+		limit = MaxCallListSize
+	}
+	if start > end {
+		start, end = end, start
+	}
+	r := &util.Range{
+		Start: callKey(prefix, start, 0),
+		Limit: callKey(prefix, end, 0xFFFF),
+	}
+	r.Limit = append(r.Limit, 0)
+	if cursor != "" {
+		bs, err := hex.DecodeString(cursor)
+		if err != nil || len(bs) != 6 {
+			return nil, "", errors.WithStack(ErrInvalidCursor)
+		}
+		after := append(append(append([]byte{}, prefix...), bs...), 0)
+		if bytes.Compare(after, r.Start) > 0 {
+			r.Start = after
+		}
+	}
+
+	refs := []itxsearch.CallRef{}
+	iter := t.DB().NewIterator(r, nil)
+	defer iter.Release()
+	for iter.Next() {
+		if len(refs) >= limit {
+			last := refs[len(refs)-1]
+			return refs, hex.EncodeToString(types.TransactionIDBytes(last.Height, last.Event)), nil
+		}
+		key := iter.Key()
+		height, event, err := types.ParseTransactionIDBytes(key[len(key)-6:])
+		if err != nil {
+			continue
+		}
+		refs = append(refs, itxsearch.CallRef{
+			Height: height,
+			Event:  event,
+		})
+	}
+	if err := iter.Error(); err != nil {
+		return nil, "", errors.WithStack(err)
+	}
+	return refs, "", nil
+}
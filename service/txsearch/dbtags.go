@@ -37,6 +37,11 @@ var (
 	//bridge
 	tagBridge = byte(0x60)
 
+	//method call
+	tagCallMethod    = byte(0x70)
+	tagCallFrom      = byte(0x71)
+	tagCallIndexFrom = byte(0x72)
+
 	//etc
 )
 
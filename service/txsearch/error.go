@@ -31,3 +31,7 @@ func (e *ErrIsNotNextBlock) Error() string {
 }
 
 var ErrFailTx = errors.New("failtx")
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+var ErrUnknownReindexTag = errors.New("unknown reindex tag")
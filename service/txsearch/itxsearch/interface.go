@@ -17,4 +17,7 @@ type ITxSearch interface {
 	AddressTxList(From common.Address, index, size int) ([]TxList, error)
 	TokenTxList(From common.Address, index, size int) ([]TxList, error)
 	Reward(cont, rewarder common.Address) (*amount.Amount, error)
+	CallIndexFrom() uint32
+	MethodCalls(cont common.Address, method string, start, end uint32, cursor string, limit int) ([]CallRef, string, error)
+	CallerCalls(cont common.Address, From common.Address, start, end uint32, cursor string, limit int) ([]CallRef, string, error)
 }
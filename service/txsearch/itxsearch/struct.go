@@ -47,3 +47,9 @@ type BloomInterface interface {
 	CreateEventBloom(ctx *types.Context, events []*ctypes.Event) (etypes.Bloom, error)
 	EventsToLogs(chain *chain.Chain, header *mtypes.Header, tx *mtypes.Transaction, evs []*ctypes.Event, idx int) ([]*etypes.Log, error)
 }
+
+// CallRef is the position of the call history event in the block
+type CallRef struct {
+	Height uint32
+	Event  uint16
+}
@@ -31,7 +31,7 @@ import (
 )
 
 func (t *TxSearch) BlockHeight(bh hash.Hash256) (uint32, error) {
-	v, err := t.db.Get(append([]byte{tagBlockHash}, bh[:]...), nil)
+	v, err := t.DB().Get(append([]byte{tagBlockHash}, bh[:]...), nil)
 	if err != nil {
 		return 0, errors.WithStack(err)
 	}
@@ -51,13 +51,13 @@ func (t *TxSearch) Block(i uint32) (*types.Block, error) {
 }
 
 func (t *TxSearch) TxIndex(th hash.Hash256) (itxsearch.TxID, error) {
-	v, err := t.db.Get(append([]byte{tagTxHash}, th[:]...), nil)
+	v, err := t.DB().Get(append([]byte{tagTxHash}, th[:]...), nil)
 	if err != nil && errors.Cause(err) != leveldb.ErrNotFound {
 		return itxsearch.TxID{}, errors.WithStack(err)
 	}
 
 	if len(v) == 0 {
-		v, err = t.db.Get(toTxFailKey(th), nil)
+		v, err = t.DB().Get(toTxFailKey(th), nil)
 		if err != nil {
 			return itxsearch.TxID{}, errors.WithStack(err)
 		} else if len(v) > 0 {
@@ -94,7 +94,7 @@ func (t *TxSearch) Reward(cont, rewarder common.Address) (*amount.Amount, error)
 	key[0] = tagEventReward
 	copy(key[1:], cont[:])
 	copy(key[21:], rewarder[:])
-	bs, err := t.db.Get(key, nil)
+	bs, err := t.DB().Get(key, nil)
 	if err != nil && err != leveldb.ErrNotFound {
 		return nil, err
 	}
@@ -129,7 +129,7 @@ func (t *TxSearch) DailyReward(cont, rewarder common.Address, index int) (map[st
 	copy(from[41:], bs)
 
 	ams := map[string]*amount.Amount{}
-	iter := t.db.NewIterator(&util.Range{Start: from, Limit: to}, nil)
+	iter := t.DB().NewIterator(&util.Range{Start: from, Limit: to}, nil)
 	for iter.Next() {
 		bs := iter.Value()
 		am := amount.NewAmountFromBytes(bs)
@@ -239,17 +239,11 @@ func (t *TxSearch) Tx(height uint32, index uint16) (map[string]interface{}, erro
 
 func (t *TxSearch) getLogs(tx *types.Transaction, b *types.Block, TxID itxsearch.TxID, bHash ecommon.Hash) ([]*etypes.Log, string, error) {
 	if tx.VmType != types.Evm {
-		evs, err := bloomservice.FindCallHistoryEvents(b.Body.Events, TxID.Index)
-
-		if err != nil {
-			return nil, "", err
-		}
-		blm, err := bloomservice.CreateEventBloom(t.cn.Provider(), evs)
+		receipt, err := bloomservice.NativeReceipt(t.cn, b, TxID.Index)
 		if err != nil {
 			return nil, "", err
 		}
-
-		logs, err := bloomservice.EventsToFullLogs(t.cn, &b.Header, tx, evs, TxID.Index)
+		blm, logs, err := bloomservice.TxLogsBloom(t.cn, b, TxID.Index, receipt)
 		if err != nil {
 			return nil, "", err
 		}
@@ -314,7 +308,7 @@ func (t *TxSearch) TxSize() uint64 {
 	aik[0] = tagID
 	copy(aik[1:], n[:])
 
-	bs, _ := t.db.Get(aik[:], nil)
+	bs, _ := t.DB().Get(aik[:], nil)
 	if len(bs) != 8 {
 		bs = make([]byte, 8)
 	}
@@ -325,7 +319,7 @@ func (t *TxSearch) TxList(index int, size int) ([]itxsearch.TxList, error) {
 	n := common.Address{}
 	tlen, from, to := t.getRange(tagID, n[:], index, size)
 	txs := make([]itxsearch.TxList, tlen)
-	iter := t.db.NewIterator(&util.Range{Start: from, Limit: to}, nil)
+	iter := t.DB().NewIterator(&util.Range{Start: from, Limit: to}, nil)
 	var i int
 	for iter.Next() {
 		i++
@@ -358,7 +352,7 @@ func (t *TxSearch) AddressTxList(From common.Address, index, size int) ([]itxsea
 	tlen, from, to := t.getRange(tagAddress, From[:], index, size)
 	txs := make([]itxsearch.TxList, tlen)
 
-	iter := t.db.NewIterator(&util.Range{Start: from, Limit: to}, nil)
+	iter := t.DB().NewIterator(&util.Range{Start: from, Limit: to}, nil)
 	var i int
 	for iter.Next() {
 		i++
@@ -411,7 +405,7 @@ func (t *TxSearch) TokenTxList(From common.Address, index, size int) ([]itxsearc
 	tlen, from, to := t.getRange(tagDefault, From[:], index, size)
 	txs := make([]itxsearch.TxList, tlen)
 
-	iter := t.db.NewIterator(&util.Range{Start: from, Limit: to}, nil)
+	iter := t.DB().NewIterator(&util.Range{Start: from, Limit: to}, nil)
 	var i int
 	for iter.Next() {
 		i++
@@ -457,7 +451,7 @@ func (*TxSearch) txDateSet(bs []byte, paramCount int) ([]interface{}, string, ui
 func (t *TxSearch) TransferTxList(token, From common.Address, index, size int) ([]itxsearch.TxList, error) {
 	tlen, fromKey, toKey := t.getRange41(tagTransfer, append(token[:], From[:]...), index, size)
 
-	iter := t.db.NewIterator(&util.Range{Start: fromKey, Limit: toKey}, nil)
+	iter := t.DB().NewIterator(&util.Range{Start: fromKey, Limit: toKey}, nil)
 
 	txs := make([]itxsearch.TxList, tlen)
 	var i int
@@ -513,7 +507,7 @@ func (t *TxSearch) getRange41(b byte, From []byte, index, size int) (uint64, []b
 }
 
 func (t *TxSearch) _getRange(aik []byte, From []byte, index, size int) (uint64, []byte, []byte) {
-	bs, _ := t.db.Get(aik, nil)
+	bs, _ := t.DB().Get(aik, nil)
 	if len(bs) != 8 {
 		bs = make([]byte, 8)
 	}
@@ -548,7 +542,7 @@ func (t *TxSearch) TokenOutList(height uint32) (interface{}, error) {
 	binary.BigEndian.PutUint32(hbs, height)
 	min := append([]byte{tagTokenOut}, hbs...)
 
-	iter := t.db.NewIterator(&util.Range{Start: min, Limit: max}, nil)
+	iter := t.DB().NewIterator(&util.Range{Start: min, Limit: max}, nil)
 
 	txs := []map[string]string{}
 	for iter.Next() {
@@ -594,7 +588,7 @@ func (t *TxSearch) TokenLeaveList(height uint32) (interface{}, error) {
 	binary.BigEndian.PutUint32(hbs, height)
 	min := append([]byte{tagTokenLeave}, hbs...)
 
-	iter := t.db.NewIterator(&util.Range{Start: min, Limit: max}, nil)
+	iter := t.DB().NewIterator(&util.Range{Start: min, Limit: max}, nil)
 
 	txs := []map[string]string{}
 	for iter.Next() {
@@ -658,7 +652,7 @@ func (t *TxSearch) BridgeTxList(contStr common.Address, height uint32, to string
 	min := append([]byte{tagBridge}, contStr[:]...)
 	min = append(min, hbs...)
 
-	iter := t.db.NewIterator(&util.Range{Start: min, Limit: max}, nil)
+	iter := t.DB().NewIterator(&util.Range{Start: min, Limit: max}, nil)
 
 	txs := []map[string]interface{}{}
 	for iter.Next() {
@@ -712,7 +706,7 @@ func (t *TxSearch) BridgeTxList(contStr common.Address, height uint32, to string
 		}
 
 		var events []interface{}
-		v, _ := t.db.Get(append([]byte{tagEvent}, TxID...), nil)
+		v, _ := t.DB().Get(append([]byte{tagEvent}, TxID...), nil)
 		if len(v) == 2 {
 			index := bin.Uint16(v)
 			if len(b.Body.Events) < int(index) {
@@ -780,6 +774,9 @@ func (*TxSearch) makeReturnValue(tx *types.Transaction, data []interface{}, res
 		returnValues["_summary"] = arrayToSliceHex(data[4])
 		returnValues["_to"] = tx.To
 		returnValues["_from"] = tx.From
+		if toChain, ok := data[3].(string); ok {
+			returnValues["_toChain"] = toChain
+		}
 	case "sendfromgateway", "sendtorouterfromgateway":
 		if len(data) > 0 {
 			if token, ok := data[0].(common.Address); ok {
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	etypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
 
 	"github.com/meverselabs/meverse/common"
 	"github.com/meverselabs/meverse/common/amount"
@@ -33,6 +34,9 @@ type addrIndexKey [21]byte
 type addr41IndexKey [41]byte
 
 func (t *TxSearch) ReadBlock(b *types.Block) (err error) {
+	t.readLock.Lock()
+	defer t.readLock.Unlock()
+
 	batch := new(leveldb.Batch)
 	defer func() {
 		if err == nil {
@@ -40,11 +44,12 @@ func (t *TxSearch) ReadBlock(b *types.Block) (err error) {
 				plog("setHeight", b.Header.Height, "txlen", txLen)
 				txLen = 0
 			}
-			t.setHeight(b.Header.Height)
+			// the height is written with the index of the block, so the index is resumed at the height after a crash
+			batch.Put([]byte{tagHeight}, bin.Uint32Bytes(b.Header.Height))
 		}
-		err := t.db.Write(batch, nil)
-		if err != nil {
-			panic(err)
+		// the db is closed when the reopen of the reindex is failed, so the error is returned
+		if werr := t.DB().Write(batch, nil); werr != nil && err == nil {
+			err = errors.WithStack(werr)
 		}
 	}()
 
@@ -102,6 +107,7 @@ func (t *TxSearch) ReadBlock(b *types.Block) (err error) {
 			}
 		}
 	}
+	t.saveCalls(batch, b)
 
 	for _, rb := range l {
 		batch.Put(append([]byte{rb.Hkey}, rb.key...), rb.value)
@@ -141,7 +147,7 @@ func (t *TxSearch) saveReward(batch *leveldb.Batch, cont, rewarder common.Addres
 	key[0] = tagEventReward
 	copy(key[1:], cont[:])
 	copy(key[21:], rewarder[:])
-	bs, err := t.db.Get(key, nil)
+	bs, err := t.DB().Get(key, nil)
 	if err != nil && err != leveldb.ErrNotFound {
 		return
 	}
@@ -160,7 +166,7 @@ func (t *TxSearch) saveDailyReward(batch *leveldb.Batch, cont, rewarder common.A
 	binary.BigEndian.PutUint32(bs, days)
 	copy(key[41:], bs[:])
 
-	bs, err := t.db.Get(key, nil)
+	bs, err := t.DB().Get(key, nil)
 	if err != nil && err != leveldb.ErrNotFound {
 		return
 	}
@@ -329,7 +335,7 @@ func (t *TxSearch) Push(indexMap map[addrIndexKey]uint64, batch *leveldb.Batch,
 	var index uint64
 	var ok bool
 	if index, ok = indexMap[aik]; !ok {
-		bs, _ := t.db.Get(aik[:], nil)
+		bs, _ := t.DB().Get(aik[:], nil)
 		if len(bs) != 8 {
 			bs = make([]byte, 8)
 		}
@@ -349,7 +355,7 @@ func (t *TxSearch) Push41(indexMap map[addr41IndexKey]uint64, batch *leveldb.Bat
 	var index uint64
 	var ok bool
 	if index, ok = indexMap[aik]; !ok {
-		bs, _ := t.db.Get(aik[:], nil)
+		bs, _ := t.DB().Get(aik[:], nil)
 		if len(bs) != 8 {
 			bs = make([]byte, 8)
 		}
@@ -0,0 +1,204 @@
+package txsearch
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb"
+
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/meverselabs/meverse/service/reindex"
+)
+
+// ReindexTags are the tags which can be rebuilt in place, the blocks of them are indexed independently
+var ReindexTags = []string{"block", "tx", "call"}
+
+// Reindex rebuilds the index in the background while the blocks are connected
+//
+// the tag in ReindexTags is rebuilt in place between the heights by the workers,
+// the empty tag rebuilds the whole index to the new db from the init height and replaces the db when it catches up the chain
+func (t *TxSearch) Reindex(tag string, from, to uint32, workers int) error {
+	if tag == "" {
+		from = t.initHeight + 1
+		if from < 2 {
+			from = 2
+		}
+		to = t.st.Height()
+		if to < from {
+			to = from
+		}
+		if err := t.reindex.Start("txsearch", from, to); err != nil {
+			return err
+		}
+		go func() {
+			t.reindex.Finish(t.rebuild(workers))
+		}()
+		return nil
+	}
+
+	found := false
+	for _, v := range ReindexTags {
+		if v == tag {
+			found = true
+		}
+	}
+	if !found {
+		return errors.WithStack(ErrUnknownReindexTag)
+	}
+	if from < 1 {
+		from = 1
+	}
+	if h := t.Height(); to > h {
+		to = h
+	}
+	if err := t.reindex.Start("txsearch:"+tag, from, to); err != nil {
+		return err
+	}
+	go func() {
+		t.reindex.Finish(t.reindexTag(tag, from, to, workers))
+	}()
+	return nil
+}
+
+// ReindexStatus returns the progress of the reindex
+func (t *TxSearch) ReindexStatus() map[string]interface{} {
+	return t.reindex.Status()
+}
+
+func (t *TxSearch) reindexTag(tag string, from, to uint32, workers int) error {
+	if err := reindex.Parallel(from, to, workers, func(h uint32) error {
+		b, err := t.st.Block(h)
+		if err != nil {
+			return err
+		}
+		batch := new(leveldb.Batch)
+		switch tag {
+		case "block":
+			bHash := bin.MustWriterToHash(&b.Header)
+			batch.Put(append([]byte{tagBlockHash}, bHash[:]...), bin.Uint32Bytes(h))
+		case "tx":
+			for i, tx := range b.Body.Transactions {
+				batch.Put(append([]byte{tagTxHash}, tx.Hash(h).Bytes()...), types.TransactionIDBytes(h, uint16(i)))
+			}
+		case "call":
+			t.saveCalls(batch, b)
+		}
+		if err := t.DB().Write(batch, nil); err != nil {
+			return errors.WithStack(err)
+		}
+		t.reindex.Add(1)
+		return nil
+	}); err != nil {
+		return err
+	}
+	if tag == "call" {
+		// the blocks after the index start are indexed already, so the start is lowered when the range reaches it
+		if indexFrom := t.CallIndexFrom(); from < indexFrom && to+1 >= indexFrom {
+			return t.setCallIndexFrom(from)
+		}
+	}
+	return nil
+}
+
+// rebuild builds the whole index to the new db and replaces the current db by it
+func (t *TxSearch) rebuild(workers int) error {
+	// the db of the interrupted rebuild is resumed from its height, it is built again when it cannot be opened
+	path := t.path + ".reindex"
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		if err := os.RemoveAll(path); err != nil {
+			return errors.WithStack(err)
+		}
+		if db, err = leveldb.OpenFile(path, nil); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	nt := &TxSearch{
+		db:         db,
+		path:       path,
+		initHeight: t.initHeight,
+		st:         t.st,
+		cn:         t.cn,
+	}
+	if err := nt.initDB(nt.st, nt.initHeight); err != nil {
+		db.Close()
+		return err
+	}
+	if h, from := nt.Height(), t.reindex.From(); h >= from {
+		t.reindex.Extend(h)
+		t.reindex.Add(h - from + 1)
+	}
+	for {
+		if to := t.st.Height(); nt.Height() < to {
+			t.reindex.Extend(to)
+			if err := reindex.Blocks(t.st, nt.Height()+1, to, workers, func(b *types.Block) error {
+				if err := nt.ReadBlock(b); err != nil {
+					return err
+				}
+				t.reindex.Add(1)
+				return nil
+			}); err != nil {
+				db.Close()
+				return err
+			}
+		}
+		if replaced, err := t.replaceDB(nt); err != nil || replaced {
+			return err
+		}
+	}
+}
+
+// replaceDB replaces the db by the rebuilt one when it is not behind the current one
+func (t *TxSearch) replaceDB(nt *TxSearch) (bool, error) {
+	t.readLock.Lock()
+	defer t.readLock.Unlock()
+
+	if nt.Height() < t.Height() {
+		return false, nil
+	}
+	if err := nt.db.Close(); err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	t.dbLock.Lock()
+	defer t.dbLock.Unlock()
+
+	old := t.path + ".old"
+	if err := os.RemoveAll(old); err != nil {
+		return false, errors.WithStack(err)
+	}
+	if err := t.db.Close(); err != nil {
+		return false, errors.WithStack(err)
+	}
+	if err := os.Rename(t.path, old); err != nil {
+		return false, t.reopenDB(err)
+	}
+	if err := os.Rename(nt.path, t.path); err != nil {
+		os.Rename(old, t.path)
+		return false, t.reopenDB(err)
+	}
+	if err := t.reopenDB(nil); err != nil {
+		return false, err
+	}
+	os.RemoveAll(old)
+	return true, nil
+}
+
+// reopenDB opens the db of the path again and returns the cause of the reopen
+//
+// the failure of the reopen is returned to the progress of the reindex, the index is not served until the node restarts
+func (t *TxSearch) reopenDB(cause error) error {
+	db, err := leveldb.OpenFile(t.path, nil)
+	if err != nil {
+		if cause != nil {
+			return errors.Wrapf(err, "reopen the db after %v", cause)
+		}
+		return errors.Wrap(err, "reopen the db")
+	}
+	t.db = db
+	if cause != nil {
+		return errors.WithStack(cause)
+	}
+	return nil
+}
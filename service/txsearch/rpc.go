@@ -25,6 +25,9 @@ func (t *TxSearch) SetupApi() error {
 	s.Set("version", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
 		return "v1.0.3", nil
 	})
+	s.Set("reindexStatus", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+		return t.ReindexStatus(), nil
+	})
 	s.Set("blocks", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
 		index, _ := arg.Int(0)
 		return t.BlockList(index), nil
@@ -306,7 +309,7 @@ func (t *TxSearch) TagSize(tag byte, arg *apiserver.Argument) (uint64, error) {
 	aik[0] = tag
 	copy(aik[1:], addr[:])
 
-	bs, _ := t.db.Get(aik[:], nil)
+	bs, _ := t.DB().Get(aik[:], nil)
 	if len(bs) != 8 {
 		bs = make([]byte, 8)
 	}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/common/key"
+	"github.com/meverselabs/meverse/core/ctypes"
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/meverselabs/meverse/extern/test/util"
+	"github.com/meverselabs/meverse/service/apiserver"
+	"github.com/meverselabs/meverse/service/txsearch"
+	"github.com/meverselabs/meverse/service/txsearch/itxsearch"
+)
+
+// syncIndex reads the blocks which are not indexed yet
+func syncIndex(t *testing.T, tc *util.TestContext, ts *txsearch.TxSearch) {
+	st := tc.Cn.Store()
+	for ts.Height() < st.Height() {
+		b, err := st.Block(ts.Height() + 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ts.ReadBlock(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// sendTransfers sends the transfers of the main token in a block
+func sendTransfers(t *testing.T, tc *util.TestContext, count int) {
+	txs := []*types.Transaction{}
+	keys := []key.Key{}
+	for i := 0; i < count; i++ {
+		tx, err := tc.MakeTx(tc.MainToken, "Transfer", util.Users[0], amount.NewAmount(uint64(i+1), 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		txs = append(txs, tx)
+		keys = append(keys, util.AdminKey)
+	}
+	if _, err := tc.MultiSendTx(txs, keys); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// pages follows the cursor until the end and returns the refs and the count of the pages
+func pages(t *testing.T, list func(cursor string) ([]itxsearch.CallRef, string, error), limit int) ([]itxsearch.CallRef, int) {
+	refs := []itxsearch.CallRef{}
+	count := 0
+	cursor := ""
+	for {
+		rs, next, err := list(cursor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rs) > limit {
+			t.Fatalf("page size %v is over the limit %v", len(rs), limit)
+		}
+		refs = append(refs, rs...)
+		count++
+		if next == "" {
+			return refs, count
+		}
+		cursor = next
+	}
+}
+
+func checkCalls(t *testing.T, tc *util.TestContext, refs []itxsearch.CallRef, method string, From common.Address) {
+	for i, ref := range refs {
+		if i > 0 {
+			prev := refs[i-1]
+			if prev.Height > ref.Height || (prev.Height == ref.Height && prev.Event >= ref.Event) {
+				t.Fatalf("refs are not in the order %v %v", prev, ref)
+			}
+		}
+		b, err := tc.Cn.Store().Block(ref.Height)
+		if err != nil {
+			t.Fatal(err)
+		}
+		en := b.Body.Events[ref.Event]
+		if en.Type != ctypes.EventTagCallHistory {
+			t.Fatalf("ref %v is not the call history", ref)
+		}
+		mc := &ctypes.MethodCallEvent{}
+		if _, err := mc.ReadFrom(bytes.NewReader(en.Result)); err != nil {
+			t.Fatal(err)
+		}
+		if mc.To != tc.MainToken || mc.Method != method || mc.From != From {
+			t.Fatalf("ref %v is the call %v.%v by %v", ref, mc.To, mc.Method, mc.From)
+		}
+	}
+}
+
+func TestCallPaging(t *testing.T) {
+	path := "_txsearch"
+	os.RemoveAll(path)
+	defer os.RemoveAll(path)
+
+	tc := util.NewTestContext()
+	ts := txsearch.NewTxSearch(path, apiserver.NewAPIServer(), tc.Cn.Store(), tc.Cn, 0)
+	defer ts.DB().Close()
+
+	start := tc.Cn.Store().Height() + 1
+	sendTransfers(t, tc, 3)
+	sendTransfers(t, tc, 1)
+	sendTransfers(t, tc, 2)
+	syncIndex(t, tc, ts)
+	end := tc.Cn.Store().Height()
+
+	all, next, err := ts.MethodCalls(tc.MainToken, "Transfer", start, end, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 6 || next != "" {
+		t.Fatalf("method calls %v, cursor %v", len(all), next)
+	}
+	checkCalls(t, tc, all, "Transfer", util.Admin)
+
+	// the page boundaries are in the middle of the blocks
+	refs, count := pages(t, func(cursor string) ([]itxsearch.CallRef, string, error) {
+		return ts.MethodCalls(tc.MainToken, "transfer", start, end, cursor, 2)
+	}, 2)
+	if count != 3 || len(refs) != len(all) {
+		t.Fatalf("method calls %v in pages %v", len(refs), count)
+	}
+	for i := range refs {
+		if refs[i] != all[i] {
+			t.Fatalf("paged ref %v is not %v", refs[i], all[i])
+		}
+	}
+
+	refs, count = pages(t, func(cursor string) ([]itxsearch.CallRef, string, error) {
+		return ts.CallerCalls(tc.MainToken, util.Admin, start, end, cursor, 4)
+	}, 4)
+	if count != 2 || len(refs) != len(all) {
+		t.Fatalf("caller calls %v in pages %v", len(refs), count)
+	}
+	checkCalls(t, tc, refs, "Transfer", util.Admin)
+
+	// the range of the heights is inclusive
+	if rs, _, err := ts.MethodCalls(tc.MainToken, "Transfer", end, end, "", 0); err != nil {
+		t.Fatal(err)
+	} else if len(rs) != 2 {
+		t.Fatalf("method calls %v at the last height", len(rs))
+	}
+	if rs, _, err := ts.CallerCalls(tc.MainToken, util.Users[0], start, end, "", 0); err != nil {
+		t.Fatal(err)
+	} else if len(rs) != 0 {
+		t.Fatalf("caller calls %v of the other caller", len(rs))
+	}
+	if _, _, err := ts.MethodCalls(tc.MainToken, "Transfer", start, end, "zz", 2); err == nil {
+		t.Fatal("invalid cursor is accepted")
+	}
+}
+
+func TestCallIndexFrom(t *testing.T) {
+	path := "_txsearch"
+	os.RemoveAll(path)
+	defer os.RemoveAll(path)
+
+	tc := util.NewTestContext()
+	sendTransfers(t, tc, 1)
+
+	// the index is started at the height like a node which has the blocks before the method call index
+	height := tc.Cn.Store().Height()
+	ts := txsearch.NewTxSearch(path, apiserver.NewAPIServer(), tc.Cn.Store(), tc.Cn, height)
+	defer ts.DB().Close()
+	if ts.CallIndexFrom() != height+1 {
+		t.Fatalf("call index from %v, want %v", ts.CallIndexFrom(), height+1)
+	}
+
+	sendTransfers(t, tc, 2)
+	syncIndex(t, tc, ts)
+	if ts.CallIndexFrom() != height+1 {
+		t.Fatalf("call index from %v is moved", ts.CallIndexFrom())
+	}
+
+	refs, _, err := ts.MethodCalls(tc.MainToken, "Transfer", 1, tc.Cn.Store().Height(), "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(refs) != 2 {
+		t.Fatalf("method calls %v, want the calls after the index from", len(refs))
+	}
+	for _, ref := range refs {
+		if ref.Height < ts.CallIndexFrom() {
+			t.Fatalf("call at %v is before the index from", ref.Height)
+		}
+	}
+	checkCalls(t, tc, refs, "Transfer", util.Admin)
+}
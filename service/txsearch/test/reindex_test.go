@@ -0,0 +1,186 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/meverselabs/meverse/common/bin"
+	"github.com/meverselabs/meverse/extern/test/util"
+	"github.com/meverselabs/meverse/service/apiserver"
+	"github.com/meverselabs/meverse/service/txsearch"
+)
+
+func removeIndex(path string) {
+	os.RemoveAll(path)
+	os.RemoveAll(path + ".reindex")
+	os.RemoveAll(path + ".old")
+}
+
+// dumpIndex returns the whole entries of the index db
+func dumpIndex(ts *txsearch.TxSearch) map[string]string {
+	m := map[string]string{}
+	iter := ts.DB().NewIterator(nil, nil)
+	defer iter.Release()
+	for iter.Next() {
+		m[string(iter.Key())] = string(iter.Value())
+	}
+	return m
+}
+
+// waitReindex waits the end of the reindex and fails by the error of it
+func waitReindex(t *testing.T, ts *txsearch.TxSearch) map[string]interface{} {
+	for i := 0; i < 1000; i++ {
+		st := ts.ReindexStatus()
+		if st["running"] == false {
+			if st["error"] != nil {
+				t.Fatalf("reindex is failed %v", st["error"])
+			}
+			return st
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("reindex is not finished")
+	return nil
+}
+
+func TestRebuild(t *testing.T) {
+	path := "_txsearch"
+	removeIndex(path)
+	defer removeIndex(path)
+
+	tc := util.NewTestContext()
+	sendTransfers(t, tc, 2)
+	sendTransfers(t, tc, 1)
+	ts := txsearch.NewTxSearch(path, apiserver.NewAPIServer(), tc.Cn.Store(), tc.Cn, 0)
+	defer func() { ts.DB().Close() }()
+
+	before := dumpIndex(ts)
+	if err := ts.Reindex("", 0, 0, 4); err != nil {
+		t.Fatal(err)
+	}
+	st := waitReindex(t, ts)
+	if st["done"] != st["total"] {
+		t.Fatalf("reindex is done %v of %v", st["done"], st["total"])
+	}
+	if after := dumpIndex(ts); !reflect.DeepEqual(before, after) {
+		t.Fatalf("rebuilt index has %v entries, want %v", len(after), len(before))
+	}
+	if _, err := os.Stat(path + ".reindex"); !os.IsNotExist(err) {
+		t.Fatal("rebuilt db is remained")
+	}
+
+	// the blocks are indexed to the replaced db
+	sendTransfers(t, tc, 1)
+	syncIndex(t, tc, ts)
+	if ts.Height() != tc.Cn.Store().Height() {
+		t.Fatalf("height %v after the rebuild", ts.Height())
+	}
+}
+
+func TestReindexTags(t *testing.T) {
+	path := "_txsearch"
+	removeIndex(path)
+	defer removeIndex(path)
+	fullPath := "_txsearch/full"
+	partPath := "_txsearch/part"
+
+	tc := util.NewTestContext()
+	sendTransfers(t, tc, 3)
+	sendTransfers(t, tc, 2)
+	height := tc.Cn.Store().Height()
+
+	full := txsearch.NewTxSearch(fullPath, apiserver.NewAPIServer(), tc.Cn.Store(), tc.Cn, 0)
+	defer full.DB().Close()
+	// the blocks before the init height are not indexed
+	part := txsearch.NewTxSearch(partPath, apiserver.NewAPIServer(), tc.Cn.Store(), tc.Cn, height)
+	defer func() { part.DB().Close() }()
+
+	if err := part.Reindex("unknown", 1, height, 4); err == nil {
+		t.Fatal("unknown tag is reindexed")
+	}
+	for _, tag := range txsearch.ReindexTags {
+		if err := part.Reindex(tag, 1, height, 4); err != nil {
+			t.Fatal(err)
+		}
+		waitReindex(t, part)
+	}
+
+	for h := uint32(1); h <= height; h++ {
+		b, err := tc.Cn.Store().Block(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bh, err := part.BlockHeight(bin.MustWriterToHash(&b.Header)); err != nil || bh != h {
+			t.Fatalf("block height %v %v, want %v", bh, err, h)
+		}
+		for _, tx := range b.Body.Transactions {
+			id, err := part.TxIndex(tx.Hash(h))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want, _ := full.TxIndex(tx.Hash(h)); id != want {
+				t.Fatalf("tx index %v, want %v", id, want)
+			}
+		}
+	}
+
+	if part.CallIndexFrom() != 1 {
+		t.Fatalf("call index from %v after the reindex", part.CallIndexFrom())
+	}
+	want, _, err := full.MethodCalls(tc.MainToken, "Transfer", 1, height, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _, err := part.MethodCalls(tc.MainToken, "Transfer", 1, height, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(want) != 5 || !reflect.DeepEqual(got, want) {
+		t.Fatalf("reindexed calls %v, want %v", got, want)
+	}
+}
+
+func TestRebuildResume(t *testing.T) {
+	path := "_txsearch"
+	removeIndex(path)
+	defer removeIndex(path)
+
+	tc := util.NewTestContext()
+	sendTransfers(t, tc, 2)
+
+	// the rebuild is interrupted at the height
+	partial := txsearch.NewTxSearch(path+".reindex", apiserver.NewAPIServer(), tc.Cn.Store(), tc.Cn, 0)
+	height := partial.Height()
+	marker := []byte("resumed")
+	if err := partial.DB().Put(marker, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	partial.DB().Close()
+
+	sendTransfers(t, tc, 1)
+	sendTransfers(t, tc, 3)
+	ts := txsearch.NewTxSearch(path, apiserver.NewAPIServer(), tc.Cn.Store(), tc.Cn, 0)
+	defer func() { ts.DB().Close() }()
+	before := dumpIndex(ts)
+
+	if err := ts.Reindex("", 0, 0, 4); err != nil {
+		t.Fatal(err)
+	}
+	st := waitReindex(t, ts)
+	if st["done"] != st["total"] {
+		t.Fatalf("reindex is done %v of %v", st["done"], st["total"])
+	}
+	if ts.Height() <= height {
+		t.Fatalf("height %v is not after the interrupted height %v", ts.Height(), height)
+	}
+	after := dumpIndex(ts)
+	if _, has := after[string(marker)]; !has {
+		t.Fatal("rebuild is not resumed from the interrupted db")
+	}
+	delete(after, string(marker))
+	if !reflect.DeepEqual(before, after) {
+		t.Fatalf("resumed index has %v entries, want %v", len(after), len(before))
+	}
+}
@@ -2,6 +2,7 @@ package txsearch
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/pkg/errors"
 	"github.com/syndtr/goleveldb/leveldb"
@@ -10,6 +11,7 @@ import (
 	"github.com/meverselabs/meverse/core/chain"
 	"github.com/meverselabs/meverse/core/types"
 	"github.com/meverselabs/meverse/service/apiserver"
+	"github.com/meverselabs/meverse/service/reindex"
 )
 
 var TAG = "TXSEARCH"
@@ -20,10 +22,15 @@ func plog(str ...interface{}) {
 }
 
 type TxSearch struct {
-	db  *leveldb.DB
-	st  *chain.Store
-	cn  *chain.Chain
-	api *apiserver.APIServer
+	db         *leveldb.DB
+	dbLock     sync.RWMutex
+	readLock   sync.Mutex
+	path       string
+	initHeight uint32
+	st         *chain.Store
+	cn         *chain.Chain
+	api        *apiserver.APIServer
+	reindex    reindex.Progress
 }
 
 func NewTxSearch(Path string, api *apiserver.APIServer, st *chain.Store, cn *chain.Chain, initHeight uint32) *TxSearch {
@@ -33,10 +40,12 @@ func NewTxSearch(Path string, api *apiserver.APIServer, st *chain.Store, cn *cha
 	}
 
 	t := &TxSearch{
-		db:  db,
-		st:  st,
-		cn:  cn,
-		api: api,
+		db:         db,
+		path:       Path,
+		initHeight: initHeight,
+		st:         st,
+		cn:         cn,
+		api:        api,
 	}
 
 	if err := t.initFromStore(st, initHeight); err != nil {
@@ -71,18 +80,13 @@ func (t *TxSearch) OnTransactionInPoolExpired(txs []*types.Transaction) {
 // OnTransactionFail called when the tx fail
 func (t *TxSearch) OnTransactionFail(height uint32, txs []*types.Transaction, err []error) {
 	for i, tx := range txs {
-		t.db.Put(toTxFailKey(tx.Hash(height)), bin.TypeWriteAll(height, err[i].Error()), nil)
+		t.DB().Put(toTxFailKey(tx.Hash(height)), bin.TypeWriteAll(height, err[i].Error()), nil)
 	}
 }
 
 func (t *TxSearch) initFromStore(st *chain.Store, initHeight uint32) error {
-	if !t.isInitDB() {
-		t.db.Put([]byte{tagInitHeight}, bin.Uint32Bytes(st.InitHeight()), nil)
-		t.setHeight(1)
-		t.setInitDB()
-	}
-	if t.Height() < initHeight {
-		t.setHeight(initHeight)
+	if err := t.initDB(st, initHeight); err != nil {
+		return err
 	}
 	plog(t.Height(), st.Height())
 	for t.Height() < st.Height() {
@@ -98,8 +102,35 @@ func (t *TxSearch) initFromStore(st *chain.Store, initHeight uint32) error {
 	return nil
 }
 
+func (t *TxSearch) initDB(st *chain.Store, initHeight uint32) error {
+	if !t.isInitDB() {
+		t.DB().Put([]byte{tagInitHeight}, bin.Uint32Bytes(st.InitHeight()), nil)
+		t.setHeight(1)
+		t.setInitDB()
+	}
+	if t.Height() < initHeight {
+		t.setHeight(initHeight)
+	}
+	if has, err := t.DB().Has([]byte{tagCallIndexFrom}, nil); err != nil {
+		return err
+	} else if !has {
+		if err := t.setCallIndexFrom(t.Height() + 1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DB returns the index db, it is replaced when the whole index is rebuilt by the reindex
+func (t *TxSearch) DB() *leveldb.DB {
+	t.dbLock.RLock()
+	defer t.dbLock.RUnlock()
+
+	return t.db
+}
+
 func (t *TxSearch) Height() uint32 {
-	bs, err := t.db.Get([]byte{tagHeight}, nil)
+	bs, err := t.DB().Get([]byte{tagHeight}, nil)
 	if err != nil {
 		plog("Cannot getHeight")
 		return 0
@@ -108,7 +139,7 @@ func (t *TxSearch) Height() uint32 {
 }
 
 func (t *TxSearch) setHeight(h uint32) error {
-	err := t.db.Put([]byte{tagHeight}, bin.Uint32Bytes(h), nil)
+	err := t.DB().Put([]byte{tagHeight}, bin.Uint32Bytes(h), nil)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		return &ErrCannotSetHeight{err, h}
@@ -117,7 +148,7 @@ func (t *TxSearch) setHeight(h uint32) error {
 }
 
 func (t *TxSearch) isInitDB() bool {
-	bs, err := t.db.Get([]byte{tagInitDB}, nil)
+	bs, err := t.DB().Get([]byte{tagInitDB}, nil)
 	if err != nil {
 		return false
 	}
@@ -125,7 +156,7 @@ func (t *TxSearch) isInitDB() bool {
 }
 
 func (t *TxSearch) setInitDB() error {
-	if err := t.db.Put([]byte{tagInitDB}, []byte{1}, nil); err != nil {
+	if err := t.DB().Put([]byte{tagInitDB}, []byte{1}, nil); err != nil {
 		return errors.WithStack(err)
 	}
 	return nil
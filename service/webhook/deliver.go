@@ -0,0 +1,292 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/meverselabs/meverse/common/bin"
+)
+
+// headers of the posts
+const (
+	HeaderSignature = "X-Meverse-Signature"
+	HeaderTimestamp = "X-Meverse-Timestamp"
+	HeaderDelivery  = "X-Meverse-Delivery"
+)
+
+type delivery struct {
+	webhook  string
+	id       string
+	body     []byte
+	attempts int
+}
+
+// DeadLetter is the payload which is not delivered after the attempts
+type DeadLetter struct {
+	Key      string          `json:"key"`
+	ID       string          `json:"id"`
+	Webhook  string          `json:"webhook"`
+	URL      string          `json:"url"`
+	Payload  json.RawMessage `json:"payload"`
+	Attempts int             `json:"attempts"`
+	Error    string          `json:"error"`
+	Time     int64           `json:"time"`
+}
+
+// Sign returns the signature of the body at the timestamp by the secret of the webhook
+//
+// the receiver computes the hmac-sha256 of the timestamp header, a dot and the body to verify the signature header
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *WebhookService) enqueue(w *Webhook, p *Payload) {
+	body, err := json.Marshal(p)
+	if err != nil {
+		logf("%v %+v\n", p.ID, err)
+		return
+	}
+	s.push(&delivery{
+		webhook: w.ID,
+		id:      p.ID,
+		body:    body,
+	})
+}
+
+// push puts the delivery to the queue, it is kept to be stored by Close when the service is closed
+func (s *WebhookService) push(d *delivery) {
+	s.retryLock.Lock()
+	defer s.retryLock.Unlock()
+
+	s.pushLocked(d)
+}
+
+func (s *WebhookService) pushLocked(d *delivery) {
+	if s.closed {
+		s.pending = append(s.pending, d)
+		return
+	}
+	select {
+	case s.queue <- d:
+	default:
+		s.deadLetter(d, errors.New("delivery queue is full"))
+	}
+}
+
+func (s *WebhookService) worker() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-s.quit:
+			return
+		case d := <-s.queue:
+			s.deliver(d)
+		}
+	}
+}
+
+// deliver posts the payload and schedules the retry with the backoff when it fails
+func (s *WebhookService) deliver(d *delivery) {
+	w, has := s.webhook(d.webhook)
+	if !has {
+		return
+	}
+	d.attempts++
+	err := s.post(w, d)
+	if err == nil {
+		return
+	}
+	if d.attempts >= s.cfg.MaxAttempts {
+		s.deadLetter(d, err)
+		return
+	}
+	wait := s.cfg.Backoff << uint(d.attempts-1)
+
+	s.retryLock.Lock()
+	defer s.retryLock.Unlock()
+
+	if s.closed {
+		s.pending = append(s.pending, d)
+		return
+	}
+	s.retries[d] = time.AfterFunc(wait, func() {
+		s.retryLock.Lock()
+		defer s.retryLock.Unlock()
+
+		// the retry which is taken by Close is stored by it
+		if _, has := s.retries[d]; has {
+			delete(s.retries, d)
+			s.pushLocked(d)
+		}
+	})
+}
+
+func (s *WebhookService) post(w *Webhook, d *delivery) error {
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(d.body))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderDelivery, d.id)
+	req.Header.Set(HeaderSignature, Sign(w.Secret, timestamp, d.body))
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("status %v", res.StatusCode)
+	}
+	return nil
+}
+
+// pendingDelivery is the stored delivery which is not finished when the service is closed
+type pendingDelivery struct {
+	Webhook  string          `json:"webhook"`
+	ID       string          `json:"id"`
+	Payload  json.RawMessage `json:"payload"`
+	Attempts int             `json:"attempts"`
+}
+
+// storePending stores the deliveries which are delivered again by loadPending
+func (s *WebhookService) storePending(ds []*delivery) error {
+	batch := new(leveldb.Batch)
+	for i, d := range ds {
+		bs, err := json.Marshal(&pendingDelivery{
+			Webhook:  d.webhook,
+			ID:       d.id,
+			Payload:  d.body,
+			Attempts: d.attempts,
+		})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		key := append(append([]byte{tagPending}, bin.Uint32Bytes(uint32(i))...), d.id...)
+		batch.Put(key, bs)
+	}
+	if err := s.db.Write(batch, nil); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// loadPending moves the stored deliveries to the queue, the attempts of them are kept
+func (s *WebhookService) loadPending() error {
+	batch := new(leveldb.Batch)
+	ds := []*delivery{}
+	iter := s.db.NewIterator(util.BytesPrefix([]byte{tagPending}), nil)
+	for iter.Next() {
+		batch.Delete(append([]byte{}, iter.Key()...))
+		pd := &pendingDelivery{}
+		if err := json.Unmarshal(iter.Value(), pd); err != nil {
+			continue
+		}
+		ds = append(ds, &delivery{
+			webhook:  pd.Webhook,
+			id:       pd.ID,
+			body:     pd.Payload,
+			attempts: pd.Attempts,
+		})
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := s.db.Write(batch, nil); err != nil {
+		return errors.WithStack(err)
+	}
+	for _, d := range ds {
+		s.push(d)
+	}
+	return nil
+}
+
+func (s *WebhookService) deadLetter(d *delivery, cause error) {
+	var url string
+	if w, has := s.webhook(d.webhook); has {
+		url = w.URL
+	}
+	now := time.Now()
+	key := append(append([]byte{tagDeadLetter}, bin.Uint64Bytes(uint64(now.UnixNano()))...), d.id...)
+	dl := &DeadLetter{
+		Key:      hex.EncodeToString(key[1:]),
+		ID:       d.id,
+		Webhook:  d.webhook,
+		URL:      url,
+		Payload:  d.body,
+		Attempts: d.attempts,
+		Error:    cause.Error(),
+		Time:     now.Unix(),
+	}
+	bs, err := json.Marshal(dl)
+	if err != nil {
+		logf("%v %+v\n", d.id, err)
+		return
+	}
+	if err := s.db.Put(key, bs, nil); err != nil {
+		logf("%v %+v\n", d.id, err)
+	}
+}
+
+// DeadLetters returns the payloads which are not delivered in the order of the time
+func (s *WebhookService) DeadLetters() ([]*DeadLetter, error) {
+	list := []*DeadLetter{}
+	iter := s.db.NewIterator(util.BytesPrefix([]byte{tagDeadLetter}), nil)
+	defer iter.Release()
+	for iter.Next() {
+		dl := &DeadLetter{}
+		if err := json.Unmarshal(iter.Value(), dl); err != nil {
+			continue
+		}
+		list = append(list, dl)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return list, nil
+}
+
+// Redeliver removes the dead letter of the key and posts it again
+func (s *WebhookService) Redeliver(key string) error {
+	kbs, err := hex.DecodeString(key)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	k := append([]byte{tagDeadLetter}, kbs...)
+	bs, err := s.db.Get(k, nil)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	dl := &DeadLetter{}
+	if err := json.Unmarshal(bs, dl); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := s.db.Delete(k, nil); err != nil {
+		return errors.WithStack(err)
+	}
+	s.push(&delivery{
+		webhook: dl.Webhook,
+		id:      dl.ID,
+		body:    dl.Payload,
+	})
+	return nil
+}
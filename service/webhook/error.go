@@ -0,0 +1,11 @@
+package webhook
+
+import "errors"
+
+var (
+	ErrWebhookNotFound = errors.New("webhook not found")
+	ErrInvalidURL      = errors.New("invalid webhook url")
+	ErrEmptyFilter     = errors.New("webhook filter needs an address or a contract")
+	ErrInvalidKind     = errors.New("invalid webhook kind")
+	ErrUnauthorized    = errors.New("unauthorized")
+)
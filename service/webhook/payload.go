@@ -0,0 +1,163 @@
+package webhook
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/core/ctypes"
+	"github.com/meverselabs/meverse/core/types"
+)
+
+// Payload is the json body which is posted to the webhook
+//
+// the ID is same when the payload is posted again, so the receiver can drop the duplicated one by it
+type Payload struct {
+	ID        string        `json:"id"`
+	Webhook   string        `json:"webhook"`
+	Kind      string        `json:"kind"`
+	Height    uint32        `json:"height"`
+	TxIndex   uint16        `json:"txIndex"`
+	TxHash    string        `json:"txHash"`
+	Timestamp uint64        `json:"timestamp"`
+	Contract  string        `json:"contract"`
+	Method    string        `json:"method"`
+	From      string        `json:"from"`
+	To        string        `json:"to,omitempty"`
+	Amount    string        `json:"amount,omitempty"`
+	Args      []interface{} `json:"args,omitempty"`
+	Result    []interface{} `json:"result,omitempty"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// blockPayloads returns the payloads of the calls and the transfers in the block which are matched with the webhook
+func blockPayloads(w *Webhook, b *types.Block) []*Payload {
+	ps := []*Payload{}
+	height := b.Header.Height
+	for i, en := range b.Body.Events {
+		if en.Type != ctypes.EventTagCallHistory {
+			continue
+		}
+		mc := &ctypes.MethodCallEvent{}
+		if _, err := mc.ReadFrom(bytes.NewReader(en.Result)); err != nil {
+			continue
+		}
+		var txHash string
+		if int(en.Index) < len(b.Body.Transactions) {
+			txHash = b.Body.Transactions[en.Index].Hash(height).String()
+		}
+		base := Payload{
+			Webhook:   w.ID,
+			Height:    height,
+			TxIndex:   en.Index,
+			TxHash:    txHash,
+			Timestamp: b.Header.Timestamp,
+			Contract:  mc.To.String(),
+			Method:    mc.Method,
+		}
+
+		f := &w.Filter
+		if f.hasKind(KindCall) && f.hasContract(mc.To) && f.hasMethod(mc.Method) && f.hasAddress(mc.From) {
+			p := base
+			p.ID = fmt.Sprintf("%v:%v:%v:%v", w.ID, height, KindCall, i)
+			p.Kind = KindCall
+			p.From = mc.From.String()
+			p.Args = mc.Args
+			p.Result = mc.Result
+			p.Error = mc.Error
+			ps = append(ps, &p)
+		}
+		if From, To, am, ok := transferOf(mc); ok && f.hasKind(KindTransfer) && f.hasContract(mc.To) && f.hasAddress(From, To) {
+			p := base
+			p.ID = fmt.Sprintf("%v:%v:%v:%v", w.ID, height, KindTransfer, i)
+			p.Kind = KindTransfer
+			p.From = From.String()
+			p.To = To.String()
+			p.Amount = am.String()
+			ps = append(ps, &p)
+		}
+	}
+	return ps
+}
+
+// failPayloads returns the payloads of the failed txs which are matched with the webhook
+func failPayloads(w *Webhook, height uint32, txs []*types.Transaction, errs []error) []*Payload {
+	ps := []*Payload{}
+	f := &w.Filter
+	if !f.hasKind(KindFail) {
+		return ps
+	}
+	for i, tx := range txs {
+		if !f.hasContract(tx.To) || !f.hasMethod(tx.Method) || !f.hasAddress(tx.From, tx.To) {
+			continue
+		}
+		txHash := tx.Hash(height).String()
+		p := &Payload{
+			ID:        fmt.Sprintf("%v:%v:%v:%v", w.ID, height, KindFail, txHash),
+			Webhook:   w.ID,
+			Kind:      KindFail,
+			Height:    height,
+			TxHash:    txHash,
+			Timestamp: tx.Timestamp,
+			Contract:  tx.To.String(),
+			Method:    tx.Method,
+			From:      tx.From.String(),
+		}
+		if i < len(errs) && errs[i] != nil {
+			p.Error = errs[i].Error()
+		}
+		ps = append(ps, p)
+	}
+	return ps
+}
+
+// transferOf returns the transfer of the token by the successful call
+func transferOf(mc *ctypes.MethodCallEvent) (common.Address, common.Address, *amount.Amount, bool) {
+	if mc.Error != "" {
+		return common.Address{}, common.Address{}, nil, false
+	}
+	switch strings.ToLower(mc.Method) {
+	case "transfer":
+		To, ok1 := toAddress(mc.Args, 0)
+		am, ok2 := toAmount(mc.Args, 1)
+		return mc.From, To, am, ok1 && ok2
+	case "transferfrom":
+		From, ok1 := toAddress(mc.Args, 0)
+		To, ok2 := toAddress(mc.Args, 1)
+		am, ok3 := toAmount(mc.Args, 2)
+		return From, To, am, ok1 && ok2 && ok3
+	default:
+		return common.Address{}, common.Address{}, nil, false
+	}
+}
+
+func toAddress(is []interface{}, index int) (common.Address, bool) {
+	if len(is) <= index {
+		return common.Address{}, false
+	}
+	switch v := is[index].(type) {
+	case common.Address:
+		return v, true
+	case string:
+		return common.HexToAddress(v), true
+	default:
+		return common.Address{}, false
+	}
+}
+
+func toAmount(is []interface{}, index int) (*amount.Amount, bool) {
+	if len(is) <= index {
+		return nil, false
+	}
+	switch v := is[index].(type) {
+	case *amount.Amount:
+		return v, v != nil
+	case *big.Int:
+		return &amount.Amount{Int: v}, v != nil
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,122 @@
+package webhook
+
+import (
+	"crypto/subtle"
+
+	"github.com/pkg/errors"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/service/apiserver"
+)
+
+func (s *WebhookService) setupApi(api *apiserver.APIServer) error {
+	rpc, err := api.JRPC("webhook")
+	if err != nil {
+		return err
+	}
+
+	// every method takes the api key as the first argument
+	auth := func(arg *apiserver.Argument) error {
+		key, err := arg.String(0)
+		if err != nil || subtle.ConstantTimeCompare([]byte(key), []byte(s.cfg.APIKey)) != 1 {
+			return ErrUnauthorized
+		}
+		return nil
+	}
+
+	// register(apiKey, url, filter, confirmations) returns the webhook with the secret which is shown only once
+	rpc.Set("register", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+		if err := auth(arg); err != nil {
+			return nil, err
+		}
+		url, err := arg.String(1)
+		if err != nil {
+			return nil, errors.New("need url")
+		}
+		m, err := arg.Map(2)
+		if err != nil {
+			return nil, errors.New("need filter")
+		}
+		w := &Webhook{
+			URL: url,
+			Filter: Filter{
+				Addresses: toAddresses(m["addresses"]),
+				Contracts: toAddresses(m["contracts"]),
+				Methods:   toStrings(m["methods"]),
+				Kinds:     toStrings(m["kinds"]),
+			},
+		}
+		if confirmations, err := arg.Uint32(3); err == nil {
+			w.Confirmations = confirmations
+		}
+		return s.Register(w)
+	})
+	rpc.Set("list", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+		if err := auth(arg); err != nil {
+			return nil, err
+		}
+		list := []Webhook{}
+		for _, w := range s.Webhooks() {
+			v := *w
+			v.Secret = ""
+			list = append(list, v)
+		}
+		return list, nil
+	})
+	rpc.Set("delete", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+		if err := auth(arg); err != nil {
+			return nil, err
+		}
+		id, err := arg.String(1)
+		if err != nil {
+			return nil, errors.New("need id")
+		}
+		if err := s.Delete(id); err != nil {
+			return nil, err
+		}
+		return true, nil
+	})
+	rpc.Set("deadLetters", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+		if err := auth(arg); err != nil {
+			return nil, err
+		}
+		return s.DeadLetters()
+	})
+	rpc.Set("redeliver", func(ID interface{}, arg *apiserver.Argument) (interface{}, error) {
+		if err := auth(arg); err != nil {
+			return nil, err
+		}
+		key, err := arg.String(1)
+		if err != nil {
+			return nil, errors.New("need key")
+		}
+		if err := s.Redeliver(key); err != nil {
+			return nil, err
+		}
+		return true, nil
+	})
+	return nil
+}
+
+func toStrings(v interface{}) []string {
+	list := []string{}
+	switch vs := v.(type) {
+	case string:
+		list = append(list, vs)
+	case []interface{}:
+		for _, i := range vs {
+			if s, ok := i.(string); ok {
+				list = append(list, s)
+			}
+		}
+	}
+	return list
+}
+
+func toAddresses(v interface{}) []common.Address {
+	list := []common.Address{}
+	for _, s := range toStrings(v) {
+		list = append(list, common.HexToAddress(s))
+	}
+	return list
+}
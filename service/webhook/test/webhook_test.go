@@ -0,0 +1,236 @@
+package test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/meverselabs/meverse/common"
+	"github.com/meverselabs/meverse/common/amount"
+	"github.com/meverselabs/meverse/extern/test/util"
+	"github.com/meverselabs/meverse/service/webhook"
+)
+
+// receiver is a local stand-in of the endpoint which fails the posts until the count of the failures is reached
+type receiver struct {
+	sync.Mutex
+	secret   string
+	fails    int
+	attempts int
+	payloads chan *webhook.Payload
+}
+
+func (rv *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	rv.Lock()
+	rv.attempts++
+	if rv.fails > 0 {
+		rv.fails--
+		rv.Unlock()
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	secret := rv.secret
+	rv.Unlock()
+
+	if webhook.Sign(secret, r.Header.Get(webhook.HeaderTimestamp), body) != r.Header.Get(webhook.HeaderSignature) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	p := &webhook.Payload{}
+	if err := json.Unmarshal(body, p); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	rv.payloads <- p
+}
+
+func TestWebhook(t *testing.T) {
+	path := "_webhook"
+	os.RemoveAll(path)
+	defer os.RemoveAll(path)
+
+	rv := &receiver{fails: 2, payloads: make(chan *webhook.Payload, 10)}
+	srv := httptest.NewServer(rv)
+	defer srv.Close()
+
+	tc := util.NewTestContext()
+	ws, err := webhook.NewWebhookService(path, nil, tc.Cn.Provider(), webhook.Config{
+		MaxAttempts: 3,
+		Backoff:     10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	if _, err := ws.Register(&webhook.Webhook{URL: srv.URL}); err == nil {
+		t.Fatal("webhook without the filter is registered")
+	}
+	w, err := ws.Register(&webhook.Webhook{
+		URL: srv.URL,
+		Filter: webhook.Filter{
+			Addresses: []common.Address{util.Users[0]},
+			Kinds:     []string{webhook.KindTransfer},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rv.Lock()
+	rv.secret = w.Secret
+	rv.Unlock()
+
+	// the transfer is delivered after the failures of the endpoint
+	tc.MustSendTx(util.AdminKey, tc.MainToken, "Transfer", util.Users[0], amount.NewAmount(1, 0))
+	b, err := tc.Cn.Provider().Block(tc.Cn.Provider().Height())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws.OnBlockConnected(b, nil)
+
+	var p *webhook.Payload
+	select {
+	case p = <-rv.payloads:
+	case <-time.After(5 * time.Second):
+		t.Fatal("payload is not delivered")
+	}
+	if p.Kind != webhook.KindTransfer || p.Webhook != w.ID || p.To != util.Users[0].String() || p.Height != b.Header.Height {
+		t.Fatalf("invalid payload %+v", p)
+	}
+	rv.Lock()
+	if rv.attempts != 3 {
+		t.Fatalf("attempts %v is not 3", rv.attempts)
+	}
+	rv.Unlock()
+
+	// the payload goes to the dead letters after the attempts
+	rv.Lock()
+	rv.fails = 3
+	rv.Unlock()
+	ws.OnBlockConnected(b, nil)
+	var dls []*webhook.DeadLetter
+	for i := 0; i < 500 && len(dls) == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+		if dls, err = ws.DeadLetters(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(dls) != 1 || dls[0].ID != p.ID || dls[0].Attempts != 3 {
+		t.Fatalf("invalid dead letters %+v", dls)
+	}
+
+	// the redelivered dead letter reaches the endpoint
+	if err := ws.Redeliver(dls[0].Key); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case rp := <-rv.payloads:
+		if rp.ID != p.ID {
+			t.Fatalf("redelivered payload %v is not %v", rp.ID, p.ID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("dead letter is not redelivered")
+	}
+	if dls, _ := ws.DeadLetters(); len(dls) != 0 {
+		t.Fatalf("dead letter is not removed %+v", dls)
+	}
+
+	if err := ws.Delete(w.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(ws.Webhooks()) != 0 {
+		t.Fatal("webhook is not deleted")
+	}
+}
+
+func TestWebhookRestart(t *testing.T) {
+	path := "_webhook"
+	os.RemoveAll(path)
+	defer os.RemoveAll(path)
+
+	rv := &receiver{fails: 1, payloads: make(chan *webhook.Payload, 10)}
+	srv := httptest.NewServer(rv)
+	defer srv.Close()
+
+	tc := util.NewTestContext()
+	cfg := webhook.Config{
+		MaxAttempts: 3,
+		Backoff:     200 * time.Millisecond,
+	}
+	ws, err := webhook.NewWebhookService(path, nil, tc.Cn.Provider(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := ws.Register(&webhook.Webhook{
+		URL: srv.URL,
+		Filter: webhook.Filter{
+			Addresses: []common.Address{util.Users[0]},
+			Kinds:     []string{webhook.KindTransfer},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rv.Lock()
+	rv.secret = w.Secret
+	rv.Unlock()
+
+	tc.MustSendTx(util.AdminKey, tc.MainToken, "Transfer", util.Users[0], amount.NewAmount(1, 0))
+	b, err := tc.Cn.Provider().Block(tc.Cn.Provider().Height())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws.OnBlockConnected(b, nil)
+
+	attempts := func() int {
+		rv.Lock()
+		defer rv.Unlock()
+		return rv.attempts
+	}
+	for i := 0; i < 500 && attempts() == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if attempts() != 1 {
+		t.Fatalf("attempts %v before the close", attempts())
+	}
+
+	// the retry is not posted after the close
+	if err := ws.Close(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * cfg.Backoff)
+	if attempts() != 1 {
+		t.Fatalf("retry is posted after the close, attempts %v", attempts())
+	}
+
+	// the stored retry is delivered when the service is started again
+	ws, err = webhook.NewWebhookService(path, nil, tc.Cn.Provider(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	select {
+	case p := <-rv.payloads:
+		if p.Webhook != w.ID || p.Height != b.Header.Height {
+			t.Fatalf("invalid payload %+v", p)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("stored retry is not delivered")
+	}
+	if attempts() != 2 {
+		t.Fatalf("attempts %v after the restart", attempts())
+	}
+	if dls, _ := ws.DeadLetters(); len(dls) != 0 {
+		t.Fatalf("retry goes to the dead letters %+v", dls)
+	}
+}
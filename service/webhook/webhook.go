@@ -0,0 +1,94 @@
+package webhook
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/meverselabs/meverse/common"
+)
+
+// kinds of the notifications
+const (
+	KindCall     = "call"
+	KindTransfer = "transfer"
+	KindFail     = "fail"
+)
+
+// Filter selects the activities of the webhook, the empty field matches all
+//
+// the addresses are the caller of the call, the sender or the receiver of the transfer and the sender or the target of the failed tx
+type Filter struct {
+	Addresses []common.Address `json:"addresses,omitempty"`
+	Contracts []common.Address `json:"contracts,omitempty"`
+	Methods   []string         `json:"methods,omitempty"`
+	Kinds     []string         `json:"kinds,omitempty"`
+}
+
+// Webhook is the registered callback, the payloads are signed by the secret
+type Webhook struct {
+	ID            string `json:"id"`
+	URL           string `json:"url"`
+	Secret        string `json:"secret,omitempty"`
+	Filter        Filter `json:"filter"`
+	Confirmations uint32 `json:"confirmations"`
+	Created       int64  `json:"created"`
+}
+
+// Validate checks the url and the filter of the webhook
+func (w *Webhook) Validate() error {
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidURL
+	}
+	if len(w.Filter.Addresses) == 0 && len(w.Filter.Contracts) == 0 {
+		return ErrEmptyFilter
+	}
+	for _, k := range w.Filter.Kinds {
+		if k != KindCall && k != KindTransfer && k != KindFail {
+			return ErrInvalidKind
+		}
+	}
+	return nil
+}
+
+func (f *Filter) hasKind(kind string) bool {
+	return len(f.Kinds) == 0 || containsFold(f.Kinds, kind)
+}
+
+func (f *Filter) hasMethod(method string) bool {
+	return len(f.Methods) == 0 || containsFold(f.Methods, method)
+}
+
+func (f *Filter) hasContract(cont common.Address) bool {
+	return len(f.Contracts) == 0 || containsAddress(f.Contracts, cont)
+}
+
+func (f *Filter) hasAddress(addrs ...common.Address) bool {
+	if len(f.Addresses) == 0 {
+		return true
+	}
+	for _, addr := range addrs {
+		if containsAddress(f.Addresses, addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAddress(list []common.Address, addr common.Address) bool {
+	for _, v := range list {
+		if v == addr {
+			return true
+		}
+	}
+	return false
+}
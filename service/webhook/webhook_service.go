@@ -0,0 +1,275 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/meverselabs/meverse/core/types"
+	"github.com/meverselabs/meverse/service/apiserver"
+)
+
+var (
+	tagWebhook    = byte(0x01)
+	tagDeadLetter = byte(0x02)
+	tagPending    = byte(0x03)
+)
+
+// Config is the delivery setting of the webhook service
+type Config struct {
+	APIKey      string        // the key of the rpc, the rpc is not served when it is empty
+	Workers     int           // the count of the concurrent posts
+	MaxAttempts int           // the payload goes to the dead letters after the attempts
+	Backoff     time.Duration // the wait before the first retry, it doubles for the next retry
+	Timeout     time.Duration // the timeout of a post
+}
+
+// WebhookService posts the signed payloads of the activities of the registered webhooks
+type WebhookService struct {
+	lock     sync.Mutex
+	db       *leveldb.DB
+	provider types.Provider
+	cfg      Config
+	client   *http.Client
+	hooks    map[string]*Webhook
+	queue    chan *delivery
+	quit     chan struct{}
+	wg       sync.WaitGroup
+
+	retryLock sync.Mutex
+	retries   map[*delivery]*time.Timer
+	pending   []*delivery
+	closed    bool
+}
+
+// NewWebhookService returns a WebhookService which keeps the webhooks and the dead letters in the path
+func NewWebhookService(path string, api *apiserver.APIServer, provider types.Provider, cfg Config) (*WebhookService, error) {
+	if cfg.Workers < 1 {
+		cfg.Workers = 4
+	}
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 5
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = time.Second
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	s := &WebhookService{
+		db:       db,
+		provider: provider,
+		cfg:      cfg,
+		client:   &http.Client{Timeout: cfg.Timeout},
+		hooks:    map[string]*Webhook{},
+		queue:    make(chan *delivery, 1024),
+		quit:     make(chan struct{}),
+		retries:  map[*delivery]*time.Timer{},
+	}
+
+	iter := db.NewIterator(util.BytesPrefix([]byte{tagWebhook}), nil)
+	for iter.Next() {
+		w := &Webhook{}
+		if err := json.Unmarshal(iter.Value(), w); err != nil {
+			continue
+		}
+		s.hooks[w.ID] = w
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		db.Close()
+		return nil, errors.WithStack(err)
+	}
+	if err := s.loadPending(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	for i := 0; i < cfg.Workers; i++ {
+		s.wg.Add(1)
+		go s.worker()
+	}
+	if api != nil && cfg.APIKey != "" {
+		if err := s.setupApi(api); err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Name returns the name of the service
+func (s *WebhookService) Name() string {
+	return "fleta.webhook"
+}
+
+// OnLoadChain called when the chain loaded
+func (s *WebhookService) OnLoadChain(loader types.Loader) error {
+	return nil
+}
+
+// OnBlockConnected called when a block is connected to the chain
+//
+// the block of the confirmation depth of each webhook is notified, so the depth 0 notifies the connected block
+func (s *WebhookService) OnBlockConnected(b *types.Block, loader types.Loader) {
+	blocks := map[uint32]*types.Block{b.Header.Height: b}
+	for _, w := range s.Webhooks() {
+		if w.Confirmations >= b.Header.Height {
+			continue
+		}
+		height := b.Header.Height - w.Confirmations
+		target, has := blocks[height]
+		if !has {
+			var err error
+			if target, err = s.provider.Block(height); err != nil {
+				continue
+			}
+			blocks[height] = target
+		}
+		for _, p := range blockPayloads(w, target) {
+			s.enqueue(w, p)
+		}
+	}
+}
+
+// OnTransactionInPoolExpired called when a transaction in pool is expired
+func (s *WebhookService) OnTransactionInPoolExpired(txs []*types.Transaction) {}
+
+// OnTransactionFail called when the tx fail
+func (s *WebhookService) OnTransactionFail(height uint32, txs []*types.Transaction, errs []error) {
+	for _, w := range s.Webhooks() {
+		for _, p := range failPayloads(w, height, txs, errs) {
+			s.enqueue(w, p)
+		}
+	}
+}
+
+// Close stops the deliveries and closes the db
+//
+// the deliveries which wait for the retry or are in the queue are stored and delivered again when the service is started
+func (s *WebhookService) Close() error {
+	s.retryLock.Lock()
+	s.closed = true
+	for d, timer := range s.retries {
+		timer.Stop()
+		s.pending = append(s.pending, d)
+	}
+	s.retries = map[*delivery]*time.Timer{}
+	s.retryLock.Unlock()
+
+	close(s.quit)
+	s.wg.Wait()
+
+	s.retryLock.Lock()
+	for len(s.queue) > 0 {
+		s.pending = append(s.pending, <-s.queue)
+	}
+	err := s.storePending(s.pending)
+	s.pending = nil
+	s.retryLock.Unlock()
+
+	if cerr := s.db.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Register registers the webhook and returns it with the id and the secret
+func (s *WebhookService) Register(w *Webhook) (*Webhook, error) {
+	if err := w.Validate(); err != nil {
+		return nil, err
+	}
+	id, err := randomHex(8)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	nw := *w
+	nw.ID = id
+	nw.Secret = secret
+	nw.Created = time.Now().Unix()
+
+	bs, err := json.Marshal(&nw)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.db.Put(append([]byte{tagWebhook}, id...), bs, nil); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	s.hooks[id] = &nw
+	r := nw
+	return &r, nil
+}
+
+// Delete removes the webhook
+func (s *WebhookService) Delete(id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, has := s.hooks[id]; !has {
+		return errors.WithStack(ErrWebhookNotFound)
+	}
+	if err := s.db.Delete(append([]byte{tagWebhook}, id...), nil); err != nil {
+		return errors.WithStack(err)
+	}
+	delete(s.hooks, id)
+	return nil
+}
+
+// Webhooks returns the registered webhooks in the order of the registration
+func (s *WebhookService) Webhooks() []*Webhook {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	list := make([]*Webhook, 0, len(s.hooks))
+	for _, w := range s.hooks {
+		list = append(list, w)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Created != list[j].Created {
+			return list[i].Created < list[j].Created
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+func (s *WebhookService) webhook(id string) (*Webhook, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	w, has := s.hooks[id]
+	return w, has
+}
+
+func randomHex(n int) (string, error) {
+	bs := make([]byte, n)
+	if _, err := rand.Read(bs); err != nil {
+		return "", errors.WithStack(err)
+	}
+	return hex.EncodeToString(bs), nil
+}
+
+func logf(format string, args ...interface{}) {
+	log.Printf("webhook: "+format, args...)
+}
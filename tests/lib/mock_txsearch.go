@@ -83,3 +83,12 @@ func (t *TsMock) TokenTxList(From common.Address, index, size int) ([]itxsearch.
 	return nil, nil
 }
 func (t *TsMock) Reward(cont, rewarder common.Address) (*amount.Amount, error) { return nil, nil }
+
+// CallIndexFrom returns the max height, so the method calls are searched by the block scan
+func (t *TsMock) CallIndexFrom() uint32 { return ^uint32(0) }
+func (t *TsMock) MethodCalls(cont common.Address, method string, start, end uint32, cursor string, limit int) ([]itxsearch.CallRef, string, error) {
+	return nil, "", nil
+}
+func (t *TsMock) CallerCalls(cont common.Address, From common.Address, start, end uint32, cursor string, limit int) ([]itxsearch.CallRef, string, error) {
+	return nil, "", nil
+}
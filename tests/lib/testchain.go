@@ -8,6 +8,7 @@ import (
 	"math/rand"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/meverselabs/meverse/common"
@@ -164,6 +165,14 @@ func registerContracts() map[string]uint64 {
 	registerContractClass(&erc20wrapper.Erc20WrapperContract{}, "Erc20Wrapper", ClassMap)
 	registerContractClass(&notformulator.NotFormulatorContract{}, "NotFormulatorContract", ClassMap)
 
+	// the contracts which do not share a state out of the context are executed in parallel
+	types.SetConcurrentContract(&token.TokenContract{}, token.AdditiveData()...)
+	types.SetConcurrentContract(&factory.FactoryContract{})
+	types.SetConcurrentContract(&router.RouterContract{})
+	types.SetConcurrentContract(&trade.UniSwap{})
+	types.SetConcurrentContract(&trade.StableSwap{})
+	types.SetConcurrentContract(&nft721.NFT721Contract{})
+
 	return ClassMap
 }
 
@@ -201,6 +210,14 @@ type TestBlockChain struct {
 	rpcapi          *apiserver.APIServer
 	Ts              itxsearch.ITxSearch
 	Bs              *bloomservice.BloomBitService
+	pendingLock     sync.Mutex
+	pendingTxs      []*pendingTx
+}
+
+// pendingTx is the transaction sent by the json rpc which is included in the next block
+type pendingTx struct {
+	tx  *types.Transaction
+	sig common.Signature
 }
 
 func NewTestBlockChain(path string, deletePath bool, chainID *big.Int, version uint16, chainAdmin common.Address, genesisInitFunc func(*types.Context, map[string]uint64) error, cfg *InitContextInfo) *TestBlockChain {
@@ -333,8 +350,6 @@ func NewTestBlockChainWithGenesis(path string, deletePath bool, chainID *big.Int
 
 	// rpc
 	rpcapi := apiserver.NewAPIServer()
-	metamaskrelay.NewMetamaskRelay(rpcapi, ts, bs, cn, nil)
-	viewchain.NewViewchain(rpcapi, ts, cn, st, bs, nil)
 	tb := &TestBlockChain{
 		Path:            path,
 		ChainID:         chainID,
@@ -350,6 +365,8 @@ func NewTestBlockChainWithGenesis(path string, deletePath bool, chainID *big.Int
 		Ts:              ts,
 		Bs:              bs,
 	}
+	metamaskrelay.NewMetamaskRelay(rpcapi, ts, bs, cn, tb)
+	viewchain.NewViewchain(rpcapi, ts, cn, st, bs, tb)
 
 	return tb, nil
 }
@@ -400,12 +417,27 @@ func NewTestBlockChainWithGenesis(path string, deletePath bool, chainID *big.Int
 func (tb *TestBlockChain) AddBlock(txs []*TxWithSigner) (*types.Block, error) {
 	TimeoutCount := uint32(0)
 	Generator, err := tb.Chain.TopGenerator(TimeoutCount)
+	if err != nil {
+		return nil, err
+	}
 	ctx := types.NewContext(tb.Chain.Store())
 	Timestamp := uint64(time.Now().UnixNano()) + tb.StepMiliSeconds*1000000
 
 	bc := chain.NewBlockCreator(tb.Chain, ctx, Generator, TimeoutCount, Timestamp, 0)
 	var receipts = types.Receipts{}
 
+	tb.pendingLock.Lock()
+	pendings := tb.pendingTxs
+	tb.pendingTxs = nil
+	tb.pendingLock.Unlock()
+	for _, p := range pendings {
+		if receipt, err := bc.AddTx(p.tx, p.sig); err != nil {
+			return nil, err
+		} else {
+			receipts = append(receipts, receipt)
+		}
+	}
+
 	for _, tx := range txs {
 		sig, err := tx.Signer.Sign(tx.Tx.Message())
 		if err != nil {
@@ -417,7 +449,75 @@ func (tb *TestBlockChain) AddBlock(txs []*TxWithSigner) (*types.Block, error) {
 			receipts = append(receipts, receipt)
 		}
 	}
+	return tb.connectBlock(bc, Generator, receipts)
+}
+
+// AddSpeculatedBlock adds a block containing the transactions which are added together by UnsafeAddTxs
+func (tb *TestBlockChain) AddSpeculatedBlock(txs []*TxWithSigner) (*types.Block, error) {
+	TimeoutCount := uint32(0)
+	Generator, err := tb.Chain.TopGenerator(TimeoutCount)
+	if err != nil {
+		return nil, err
+	}
+	ctx := types.NewContext(tb.Chain.Store())
+	Timestamp := uint64(time.Now().UnixNano()) + tb.StepMiliSeconds*1000000
+
+	bc := chain.NewBlockCreator(tb.Chain, ctx, Generator, TimeoutCount, Timestamp, 0)
+	TxHashes := make([]hash.Hash256, len(txs))
+	ts := make([]*types.Transaction, len(txs))
+	sigs := make([]common.Signature, len(txs))
+	signers := make([]common.Address, len(txs))
+	for i, tx := range txs {
+		sig, err := tx.Signer.Sign(tx.Tx.Message())
+		if err != nil {
+			return nil, err
+		}
+		tx.Tx.From = tx.Signer.PublicKey().Address()
+		TxHashes[i], ts[i], sigs[i], signers[i] = tx.Tx.HashSig(), tx.Tx, sig, tx.Tx.From
+	}
+	rs, errs := bc.UnsafeAddTxs(TxHashes, ts, sigs, signers)
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tb.connectBlock(bc, Generator, rs)
+}
+
+// AddBundleBlock adds a block containing the bundles, the error of each bundle is returned at the same index
+func (tb *TestBlockChain) AddBundleBlock(bundles []*chain.Bundle) (*types.Block, []error, error) {
+	TimeoutCount := uint32(0)
+	Generator, err := tb.Chain.TopGenerator(TimeoutCount)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx := types.NewContext(tb.Chain.Store())
+	Timestamp := uint64(time.Now().UnixNano()) + tb.StepMiliSeconds*1000000
+
+	bc := chain.NewBlockCreator(tb.Chain, ctx, Generator, TimeoutCount, Timestamp, 0)
+	var receipts = types.Receipts{}
+	errs := make([]error, len(bundles))
+	for i, bd := range bundles {
+		for _, tx := range bd.Transactions {
+			tx.VmType, tx.Method = types.GetTxType(ctx, tx)
+		}
+		signers, verrs := tb.Chain.TxVerifier().VerifyAll(bd.Transactions, bd.TransactionSignatures, 0)
+		for _, err := range verrs {
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		if rs, err := bc.AddBundle(bd, signers); err != nil {
+			errs[i] = err
+		} else {
+			receipts = append(receipts, rs...)
+		}
+	}
+	b, err := tb.connectBlock(bc, Generator, receipts)
+	return b, errs, err
+}
 
+func (tb *TestBlockChain) connectBlock(bc *chain.BlockCreator, Generator common.Address, receipts types.Receipts) (*types.Block, error) {
 	b, err := bc.Finalize(0, receipts)
 	if err != nil {
 		return nil, err
@@ -471,6 +571,27 @@ func (tb *TestBlockChain) MustAddBlock(txs []*TxWithSigner) *types.Block {
 	return b
 }
 
+// AddTx adds the transaction sent by the json rpc, it is included in the next block
+func (tb *TestBlockChain) AddTx(tx *types.Transaction, sig common.Signature) error {
+	if tx.ChainID == nil || tx.ChainID.Cmp(tb.ChainID) != 0 {
+		return errors.New("invalid chain id")
+	}
+	tb.pendingLock.Lock()
+	defer tb.pendingLock.Unlock()
+
+	tb.pendingTxs = append(tb.pendingTxs, &pendingTx{tx: tx, sig: sig})
+	return nil
+}
+
+// ActiveGenerators returns the generators of the test chain
+func (tb *TestBlockChain) ActiveGenerators() ([]common.Address, error) {
+	gens := []common.Address{}
+	for addr := range tb.FrKeyMap {
+		gens = append(gens, addr)
+	}
+	return gens, nil
+}
+
 // newContext calls chain.NewContext()
 func (tb *TestBlockChain) HandleJRPC(req *apiserver.JRPCRequest) interface{} {
 	return tb.rpcapi.HandleJRPC(req)